@@ -18,17 +18,84 @@ limitations under the License.
 package v1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // APIServerSpec defines the desired state of Tigera API server.
 type APIServerSpec struct {
+	// Replicas is the number of API server pods to run.
+	// If not specified, the Installation controlPlaneReplicas is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// AuditPolicy configures audit logging for the projectcalico.org/v3 APIs.
+	// This is only supported for Calico Enterprise.
+	// +optional
+	AuditPolicy *APIServerAuditPolicy `json:"auditPolicy,omitempty"`
+
+	// QueryServer configures the query server that runs alongside the API server.
+	// This is only supported for Calico Enterprise.
+	// +optional
+	QueryServer *QueryServerSpec `json:"queryServer,omitempty"`
+
+	// LogLevel is the log level of the API server and query server.
+	// Default: Info
+	// +optional
+	// +kubebuilder:validation:Enum=Error;Warning;Info;Debug
+	LogLevel *APIServerLogLevel `json:"logLevel,omitempty"`
+
+	// NodeSelector is the node selector of the API server pods.
+	// If specified, this replaces the Installation controlPlaneNodeSelector.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are the tolerations of the API server pods.
+	// If specified, this replaces the Installation controlPlaneTolerations and the default master toleration.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity is the affinity of the API server pods.
+	// If not specified, the pods are spread across nodes when there is more than one replica.
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+}
+
+// APIServerAuditPolicy configures the audit policy of the API server.
+type APIServerAuditPolicy struct {
+	// ConfigMapName is the name of a ConfigMap in the tigera-operator namespace with an audit.k8s.io Policy
+	// in its "config" key. The rules of this policy are evaluated before the rules of the default policy.
+	ConfigMapName string `json:"configMapName"`
+}
+
+// QueryServerSpec configures the query server.
+type QueryServerSpec struct {
+	// ExtraArgs are additional command line flags passed to the query server.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
 }
 
+// APIServerLogLevel is the log level of the API server.
+type APIServerLogLevel string
+
+const (
+	APIServerLogLevelError   APIServerLogLevel = "Error"
+	APIServerLogLevelWarning APIServerLogLevel = "Warning"
+	APIServerLogLevelInfo    APIServerLogLevel = "Info"
+	APIServerLogLevelDebug   APIServerLogLevel = "Debug"
+)
+
 // APIServerStatus defines the observed state of Tigera API server.
 type APIServerStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -58,6 +125,16 @@ type APIServerList struct {
 	Items           []APIServer `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the APIServer.
+func (a *APIServer) GetConditions() []metav1.Condition {
+	return a.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the APIServer.
+func (a *APIServer) SetConditions(conditions []metav1.Condition) {
+	a.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&APIServer{}, &APIServerList{})
 }
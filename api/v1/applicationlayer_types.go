@@ -63,6 +63,12 @@ type LogCollectionSpec struct {
 type ApplicationLayerStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -87,6 +93,16 @@ type ApplicationLayerList struct {
 	Items           []ApplicationLayer `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the ApplicationLayer.
+func (a *ApplicationLayer) GetConditions() []metav1.Condition {
+	return a.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the ApplicationLayer.
+func (a *ApplicationLayer) SetConditions(conditions []metav1.Condition) {
+	a.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&ApplicationLayer{}, &ApplicationLayerList{})
 }
@@ -64,6 +64,12 @@ type AuthenticationSpec struct {
 type AuthenticationStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // AuthenticationOIDC is the configuration needed to setup OIDC.
@@ -242,6 +248,16 @@ type AuthenticationList struct {
 	Items           []Authentication `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the Authentication.
+func (a *Authentication) GetConditions() []metav1.Condition {
+	return a.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the Authentication.
+func (a *Authentication) SetConditions(conditions []metav1.Condition) {
+	a.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&Authentication{}, &AuthenticationList{})
 }
@@ -28,6 +28,12 @@ type ComplianceSpec struct {
 type ComplianceStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -55,6 +61,16 @@ type ComplianceList struct {
 	Items           []Compliance `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the Compliance.
+func (c *Compliance) GetConditions() []metav1.Condition {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the Compliance.
+func (c *Compliance) SetConditions(conditions []metav1.Condition) {
+	c.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&Compliance{}, &ComplianceList{})
 }
@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EgressGatewaySpec defines the desired state of EgressGateway
+type EgressGatewaySpec struct {
+	// Replicas is the number of egress gateway pods.
+	// Default: 1
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// IPPool is the dedicated IP pool that the egress gateway pods are assigned their addresses from. The IP pool
+	// is created by the operator and named egress-gateway.<namespace>.<name>. No other pods are assigned addresses
+	// from it.
+	IPPool EgressGatewayIPPool `json:"ipPool"`
+
+	// Labels are added to the egress gateway pods. Client pods and namespaces select their egress gateways by these
+	// labels with the egress.projectcalico.org/selector annotation.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// LogSeverity is the log severity above which the egress gateway logs are sent to the stdout.
+	// Default: Info
+	// +optional
+	// +kubebuilder:validation:Enum=Trace;Debug;Info;Warn;Error;Fatal
+	LogSeverity *string `json:"logSeverity,omitempty"`
+
+	// NodeSelector restricts the nodes that the egress gateway pods are scheduled on.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are the tolerations of the egress gateway pods.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// Resources are the compute resources of the egress gateway container.
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// FailureDetection configures the probes that the egress gateway pods use to detect that they can no longer
+	// forward traffic. A pod that fails its probes reports not ready, so that client pods stop using it.
+	// +optional
+	FailureDetection *EgressGatewayFailureDetection `json:"failureDetection,omitempty"`
+}
+
+// EgressGatewayIPPool is the dedicated IP pool of an egress gateway.
+type EgressGatewayIPPool struct {
+	// CIDR of the IP pool, which must be an IPv4 CIDR that does not overlap with any other IP pool.
+	CIDR string `json:"cidr"`
+
+	// BlockSize is the CIDR prefix length of the address blocks that are allocated to nodes from the IP pool.
+	// Default: 31
+	// +optional
+	// +kubebuilder:validation:Minimum=20
+	// +kubebuilder:validation:Maximum=32
+	BlockSize *int32 `json:"blockSize,omitempty"`
+}
+
+// EgressGatewayFailureDetection configures the failure detection of the egress gateway pods.
+type EgressGatewayFailureDetection struct {
+	// HealthTimeoutDataStoreSeconds is the period after which an egress gateway pod that cannot reach the datastore
+	// reports not ready.
+	// Default: 90
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	HealthTimeoutDataStoreSeconds *int32 `json:"healthTimeoutDataStoreSeconds,omitempty"`
+
+	// ICMPProbe probes IP addresses with ICMP pings. The pod reports not ready when all of them fail.
+	// +optional
+	ICMPProbe *EgressGatewayICMPProbe `json:"icmpProbe,omitempty"`
+
+	// HTTPProbe probes URLs with HTTP GET requests. The pod reports not ready when all of them fail.
+	// +optional
+	HTTPProbe *EgressGatewayHTTPProbe `json:"httpProbe,omitempty"`
+}
+
+// EgressGatewayICMPProbe configures the ICMP probes of the egress gateway pods.
+type EgressGatewayICMPProbe struct {
+	// IPs are the IP addresses to probe.
+	IPs []string `json:"ips"`
+
+	// IntervalSeconds is the period between the probes.
+	// Default: 5
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+
+	// TimeoutSeconds is the period after which a probed IP address that does not respond is considered failed.
+	// Default: 15
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// EgressGatewayHTTPProbe configures the HTTP probes of the egress gateway pods.
+type EgressGatewayHTTPProbe struct {
+	// URLs are the URLs to probe.
+	URLs []string `json:"urls"`
+
+	// IntervalSeconds is the period between the probes.
+	// Default: 10
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+
+	// TimeoutSeconds is the period after which a probed URL that does not respond is considered failed.
+	// Default: 30
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// EgressGatewayStatus defines the observed state of EgressGateway
+type EgressGatewayStatus struct {
+	// State provides user-readable status.
+	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the egress gateway. An egress gateway may be
+	// one or more of Ready, Progressing or Degraded. An egress gateway with an invalid spec is reported as Degraded
+	// on its own, while the other egress gateways are still rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced
+
+// EgressGateway deploys egress gateways, which forward the traffic of the client pods that select them to
+// destinations outside of the cluster from their own addresses. Only supported for Calico Enterprise.
+type EgressGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EgressGatewaySpec   `json:"spec,omitempty"`
+	Status EgressGatewayStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EgressGatewayList contains a list of EgressGateway
+type EgressGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EgressGateway `json:"items"`
+}
+
+// GetConditions returns the conditions reported on the status of the EgressGateway.
+func (e *EgressGateway) GetConditions() []metav1.Condition {
+	return e.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the EgressGateway.
+func (e *EgressGateway) SetConditions(conditions []metav1.Condition) {
+	e.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&EgressGateway{}, &EgressGatewayList{})
+}
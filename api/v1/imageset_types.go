@@ -37,6 +37,19 @@ type Image struct {
 	// Digest is the image identifier that will be used for the Image.
 	// The field should not include a leading `@` and must be prefixed with `sha256:`.
 	Digest string `json:"digest"`
+
+	// Registry overrides the registry configured on the Installation for this image only,
+	// allowing individual images to be pulled from a different mirror.
+	// The value must end with a slash, for example `quay.io/`.
+	// +optional
+	Registry string `json:"registry,omitempty"`
+
+	// Repository overrides the repository (image path and name) for this image only.
+	// When specified, the ImagePath and ImagePrefix configured on the Installation are
+	// not applied to this image.
+	// For example, `mirror/calico-node` results in `<registry>mirror/calico-node@<digest>`.
+	// +optional
+	Repository string `json:"repository,omitempty"`
 }
 
 // +kubebuilder:object:root=true
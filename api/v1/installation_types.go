@@ -22,6 +22,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // InstallationSpec defines configuration for a Calico or Calico Enterprise installation.
@@ -145,8 +146,354 @@ type InstallationSpec struct {
 	// NonPrivileged configures Calico to be run in non-privileged containers as non-root users where possible.
 	// +optional
 	NonPrivileged *NonPrivilegedType `json:"nonPrivileged,omitempty"`
+
+	// CalicoWindowsUpgrade configures how the operator upgrades Calico for Windows nodes.
+	// +optional
+	CalicoWindowsUpgrade *CalicoWindowsUpgradeSpec `json:"calicoWindowsUpgrade,omitempty"`
+
+	// ImageVerification configures the operator to verify the signatures of the images it deploys.
+	// When specified, components are not rolled out until all of their images have been verified.
+	// +optional
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+
+	// PodDisruptionBudgets configures the PodDisruptionBudgets of the control plane components. By default, a
+	// PodDisruptionBudget allowing one unavailable pod is created for Typha and for each control plane component
+	// that runs more than one replica.
+	// +optional
+	PodDisruptionBudgets *PodDisruptionBudgets `json:"podDisruptionBudgets,omitempty"`
+
+	// ComponentNetworkPolicies configures the operator to render Calico network policies, in the allow-tigera tier,
+	// that only allow the traffic the Calico Enterprise components need. The tier is created once the API server
+	// is available. Only supported for Calico Enterprise.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	ComponentNetworkPolicies *ComponentNetworkPoliciesType `json:"componentNetworkPolicies,omitempty"`
+
+	// KubeControllers configures the Calico Kubernetes controllers. The settings are applied to the
+	// calico-kube-controllers deployment and to the default KubeControllersConfiguration.
+	// +optional
+	KubeControllers *KubeControllersSpec `json:"kubeControllers,omitempty"`
+
+	// Felix configures the Felix settings that are most commonly tuned. The settings are applied to the
+	// default FelixConfiguration, and changes made to them directly on the FelixConfiguration are reverted.
+	// +optional
+	Felix *FelixSpec `json:"felix,omitempty"`
+
+	// CalicoNodePools overrides the calico-node configuration on the nodes selected by each pool. A separate
+	// calico-node DaemonSet, named calico-node-<name>, is rendered for each pool and the calico-node DaemonSet
+	// no longer runs on the nodes of the pools.
+	// +optional
+	CalicoNodePools []CalicoNodePool `json:"calicoNodePools,omitempty"`
+
+	// HostProtection configures the protection of the hosts of the cluster by Calico security policy. The
+	// protection is enabled by spec.kubeControllers and spec.felix, see HostProtectionSpec.
+	// +optional
+	HostProtection *HostProtectionSpec `json:"hostProtection,omitempty"`
+}
+
+// HostProtectionSpec configures the protection of the hosts by Calico security policy. It does not enable the
+// protection itself: the automatic host endpoints are controlled by
+// spec.kubeControllers.controllers.node.autoHostEndpoints, and the failsafe ports that stay open on the hosts by
+// spec.felix.failsafeInboundHostPorts and spec.felix.failsafeOutboundHostPorts.
+type HostProtectionSpec struct {
+	// ReachabilityCheck controls whether the automatic host endpoints are only enabled once the failsafe ports keep
+	// the API server and, when BGP is enabled, the BGP peers reachable. While the check fails, the Installation is
+	// reported as degraded and the automatic host endpoints are disabled, even if they were enabled before.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	ReachabilityCheck *HostProtectionCheckState `json:"reachabilityCheck,omitempty"`
+}
+
+// HostProtectionCheckState specifies whether a host protection check is enabled.
+//
+// One of: Enabled, Disabled
+type HostProtectionCheckState string
+
+const (
+	HostProtectionCheckEnabled  HostProtectionCheckState = "Enabled"
+	HostProtectionCheckDisabled HostProtectionCheckState = "Disabled"
+)
+
+// CalicoNodePool overrides the calico-node configuration on a set of nodes.
+type CalicoNodePool struct {
+	// Name of the pool. It must be a valid DNS label of at most 51 characters.
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the pool by their labels. The node selectors of the pools must be
+	// mutually exclusive: every pair of pools must select different values for at least one label. The operator
+	// labels the nodes of the pool with operator.tigera.io/calico-node-pool, by which their calico-node pods are
+	// scheduled.
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// MTU overrides spec.calicoNetwork.mtu on the nodes of the pool, including in their CNI configuration.
+	// +optional
+	MTU *int32 `json:"mtu,omitempty"`
+
+	// NodeAddressAutodetectionV4 overrides spec.calicoNetwork.nodeAddressAutodetectionV4 on the nodes of the pool.
+	// +optional
+	NodeAddressAutodetectionV4 *NodeAddressAutodetection `json:"nodeAddressAutodetectionV4,omitempty"`
+
+	// NodeAddressAutodetectionV6 overrides spec.calicoNetwork.nodeAddressAutodetectionV6 on the nodes of the pool.
+	// +optional
+	NodeAddressAutodetectionV6 *NodeAddressAutodetection `json:"nodeAddressAutodetectionV6,omitempty"`
+
+	// Resources overrides the calico-node resource requirements of spec.componentResources on the nodes of the pool.
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Tolerations replaces the calico-node tolerations, which tolerate all taints by default, on the nodes of the pool.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+}
+
+// KubeControllersSpec configures the Calico Kubernetes controllers.
+type KubeControllersSpec struct {
+	// LogSeverityScreen is the log severity above which logs are sent to the stdout.
+	// Default: Info
+	// +optional
+	// +kubebuilder:validation:Enum=Debug;Info;Warning;Error;Fatal
+	LogSeverityScreen *string `json:"logSeverityScreen,omitempty"`
+
+	// Controllers configures the individual controllers.
+	// +optional
+	Controllers *KubeControllersControllers `json:"controllers,omitempty"`
+}
+
+// KubeControllersControllers configures the individual Calico Kubernetes controllers.
+type KubeControllersControllers struct {
+	// Node configures the node controller, which cleans up the resources of deleted nodes, garbage collects
+	// leaked IP addresses and manages automatic host endpoints.
+	// +optional
+	Node *KubeControllersNodeController `json:"node,omitempty"`
+
+	// Service configures the service controller. Only supported for Calico Enterprise.
+	// +optional
+	Service *KubeControllersController `json:"service,omitempty"`
+
+	// FederatedServices configures the federated services controller. Only supported for Calico Enterprise.
+	// +optional
+	FederatedServices *KubeControllersController `json:"federatedServices,omitempty"`
+}
+
+// KubeControllersController configures a Calico Kubernetes controller.
+type KubeControllersController struct {
+	// State enables or disables the controller.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	State *KubeControllerState `json:"state,omitempty"`
+}
+
+// KubeControllersNodeController configures the node controller.
+type KubeControllersNodeController struct {
+	// State enables or disables the controller.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	State *KubeControllerState `json:"state,omitempty"`
+
+	// ReconcilerPeriod is the period to perform reconciliation with the Calico datastore.
+	// Default: 5m
+	// +optional
+	ReconcilerPeriod *metav1.Duration `json:"reconcilerPeriod,omitempty"`
+
+	// LeakGracePeriod is the period after which IP addresses that are allocated but not in use by a pod are
+	// garbage collected. Set to 0 to disable the garbage collection.
+	// Default: 15m
+	// +optional
+	LeakGracePeriod *metav1.Duration `json:"leakGracePeriod,omitempty"`
+
+	// AutoHostEndpoints configures the automatic creation of a host endpoint for each node. Unless
+	// spec.hostProtection.reachabilityCheck is Disabled, the host endpoints are only enabled once the failsafe ports
+	// keep the API server and, when BGP is enabled, the BGP peers reachable.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	AutoHostEndpoints *KubeControllerState `json:"autoHostEndpoints,omitempty"`
+}
+
+// KubeControllerState specifies whether a Calico Kubernetes controller or one of its features is enabled.
+//
+// One of: Enabled, Disabled
+type KubeControllerState string
+
+const (
+	KubeControllerEnabled  KubeControllerState = "Enabled"
+	KubeControllerDisabled KubeControllerState = "Disabled"
+)
+
+// FelixSpec configures Felix. Settings that are not specified are left as they are on the default
+// FelixConfiguration.
+type FelixSpec struct {
+	// LogSeverityScreen is the log severity above which logs are sent to the stdout.
+	// Default: Info
+	// +optional
+	// +kubebuilder:validation:Enum=Debug;Info;Warning;Error;Fatal
+	LogSeverityScreen *string `json:"logSeverityScreen,omitempty"`
+
+	// IptablesBackend specifies which backend of iptables Felix uses.
+	// Default: Legacy
+	// +optional
+	// +kubebuilder:validation:Enum=Legacy;NFT
+	IptablesBackend *string `json:"iptablesBackend,omitempty"`
+
+	// IptablesRefreshInterval is the period at which Felix re-checks the iptables state to make sure that no
+	// other process has accidentally broken Calico's rules. Set to 0 to disable the refresh.
+	// Default: 90s
+	// +optional
+	IptablesRefreshInterval *metav1.Duration `json:"iptablesRefreshInterval,omitempty"`
+
+	// RouteTableRange is the range of routing table indices that Felix may use.
+	// Default: 1-250
+	// +optional
+	RouteTableRange *FelixRouteTableRange `json:"routeTableRange,omitempty"`
+
+	// BPFKubeProxyIptablesCleanup controls whether Felix cleans up the iptables rules of kube-proxy when the
+	// BPF dataplane replaces it. Disable it when kube-proxy is still running alongside the BPF dataplane.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	BPFKubeProxyIptablesCleanup *FelixFeatureState `json:"bpfKubeProxyIptablesCleanup,omitempty"`
+
+	// BPFExternalServiceMode controls, in BPF mode, how connections from outside the cluster to services are
+	// forwarded to remote workloads. With Tunnel, the traffic is tunneled to the node of the workload. With DSR,
+	// the return traffic is sent directly from the node of the workload.
+	// Default: Tunnel
+	// +optional
+	// +kubebuilder:validation:Enum=Tunnel;DSR
+	BPFExternalServiceMode *string `json:"bpfExternalServiceMode,omitempty"`
+
+	// FailsafeInboundHostPorts are the ports that Felix always allows incoming traffic to on host endpoints,
+	// irrespective of the security policy.
+	// +optional
+	FailsafeInboundHostPorts *[]FelixProtoPort `json:"failsafeInboundHostPorts,omitempty"`
+
+	// FailsafeOutboundHostPorts are the ports that Felix always allows outgoing traffic to from host endpoints,
+	// irrespective of the security policy.
+	// +optional
+	FailsafeOutboundHostPorts *[]FelixProtoPort `json:"failsafeOutboundHostPorts,omitempty"`
+
+	// HealthTimeoutOverrides overrides the timeouts after which the internal components of Felix are
+	// reported as unhealthy. They are passed to calico-node through its environment, so they take precedence
+	// over the FelixConfiguration. Requires Calico v3.25 or Calico Enterprise v3.16 or later.
+	// +optional
+	HealthTimeoutOverrides []FelixHealthTimeoutOverride `json:"healthTimeoutOverrides,omitempty"`
+}
+
+// FelixRouteTableRange is a range of routing table indices.
+type FelixRouteTableRange struct {
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=250
+	Min int `json:"min"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=250
+	Max int `json:"max"`
+}
+
+// FelixProtoPort is a protocol and port pair.
+type FelixProtoPort struct {
+	// +kubebuilder:validation:Enum=TCP;UDP
+	Protocol string `json:"protocol"`
+
+	Port uint16 `json:"port"`
+}
+
+// FelixHealthTimeoutOverride overrides the health timeout of an internal component of Felix.
+type FelixHealthTimeoutOverride struct {
+	// Name is the name of the component, as reported in the Felix health report, e.g. InternalDataplaneMainLoop.
+	Name string `json:"name"`
+
+	// Timeout after which the component is reported as unhealthy.
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// FelixFeatureState specifies whether a Felix feature is enabled.
+//
+// One of: Enabled, Disabled
+type FelixFeatureState string
+
+const (
+	FelixFeatureEnabled  FelixFeatureState = "Enabled"
+	FelixFeatureDisabled FelixFeatureState = "Disabled"
+)
+
+// PodDisruptionBudgets configures the PodDisruptionBudgets of the control plane components.
+type PodDisruptionBudgets struct {
+	// Disabled removes the PodDisruptionBudgets of all control plane components.
+	// Default: false
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Components overrides the PodDisruptionBudgets of individual components.
+	// +optional
+	Components []ComponentPodDisruptionBudget `json:"components,omitempty"`
 }
 
+// ComponentPodDisruptionBudget configures the PodDisruptionBudget of a single component. At most one of
+// MinAvailable and MaxUnavailable may be set. A budget is never created for a component running a single
+// replica, other than Typha.
+type ComponentPodDisruptionBudget struct {
+	// Name is the name of the component's Deployment.
+	// +kubebuilder:validation:Enum=calico-typha;calico-apiserver;tigera-apiserver;tigera-manager;tigera-dex;tigera-secure-es-gateway
+	Name string `json:"name"`
+
+	// Disabled removes the PodDisruptionBudget of this component.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// MinAvailable is the number or percentage of pods that must remain available during a disruption.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that may be unavailable during a disruption.
+	// Default: 1
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ImageVerification configures verification of cosign-style image signatures. The signatures
+// are read from the registry each image is pulled from, using the tag derived from the image
+// digest, e.g. `sha256-<digest>.sig`.
+type ImageVerification struct {
+	// PublicKeysSecretName is the name of a Secret in the tigera-operator namespace containing the
+	// PEM encoded public keys used to verify image signatures, one key per data entry. An image is
+	// verified if it has a signature matching any of the keys.
+	PublicKeysSecretName string `json:"publicKeysSecretName"`
+}
+
+// CalicoWindowsUpgradeSpec configures how the operator upgrades Calico for Windows nodes.
+// An individual node can be excluded from upgrades by annotating it with
+// projectcalico.org/windows-upgrade-paused=true.
+type CalicoWindowsUpgradeSpec struct {
+	// NodeUpgradeTimeout is the maximum time a node is given to complete its upgrade and become ready.
+	// Nodes that exceed this time are marked as failed and are not retried until the
+	// projectcalico.org/windows-upgrade label is removed from them. The timeout restarts when a
+	// paused node is resumed.
+	// Default: 30m
+	// +optional
+	NodeUpgradeTimeout *metav1.Duration `json:"nodeUpgradeTimeout,omitempty"`
+
+	// CordonAndDrain specifies whether each node is cordoned and drained of its pods before its
+	// upgrade starts. Nodes are uncordoned once they have been upgraded and are ready.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	CordonAndDrain *CordonAndDrainType `json:"cordonAndDrain,omitempty"`
+}
+
+// CordonAndDrainType specifies whether nodes are cordoned and drained before an upgrade.
+//
+// One of: Enabled, Disabled
+type CordonAndDrainType string
+
+const (
+	CordonAndDrainEnabled  CordonAndDrainType = "Enabled"
+	CordonAndDrainDisabled CordonAndDrainType = "Disabled"
+)
+
 // TyphaAffinity allows configuration of node affinitiy characteristics for Typha pods.
 type TyphaAffinity struct {
 	// NodeAffinity describes node affinity scheduling rules for typha.
@@ -231,6 +578,16 @@ const (
 	NonPrivilegedDisabled NonPrivilegedType = "Disabled"
 )
 
+// ComponentNetworkPoliciesType specifies whether the operator renders network policies for the components.
+//
+// One of: Enabled, Disabled
+type ComponentNetworkPoliciesType string
+
+const (
+	ComponentNetworkPoliciesEnabled  ComponentNetworkPoliciesType = "Enabled"
+	ComponentNetworkPoliciesDisabled ComponentNetworkPoliciesType = "Disabled"
+)
+
 // ContainerIPForwardingType specifies whether the CNI config for container ip forwarding is enabled.
 type ContainerIPForwardingType string
 
@@ -309,7 +666,8 @@ const (
 type CalicoNetworkSpec struct {
 	// LinuxDataplane is used to select the dataplane used for Linux nodes. In particular, it
 	// causes the operator to add required mounts and environment variables for the particular dataplane.
-	// If not specified, iptables mode is used.
+	// If not specified, iptables mode is used. The operator disables kube-proxy for the BPF dataplane,
+	// except on AKS, EKS and GKE, where kube-proxy must be disabled through the provider first.
 	// Default: Iptables
 	// +optional
 	// +kubebuilder:validation:Enum=Iptables;BPF;VPP
@@ -320,8 +678,10 @@ type CalicoNetworkSpec struct {
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	BGP *BGPOption `json:"bgp,omitempty"`
 
-	// IPPools contains a list of IP pools to create if none exist. At most one IP pool of each
-	// address family may be specified. If omitted, a single pool will be configured if needed.
+	// IPPools contains a list of IP pools to create if none exist. More than one IP pool of each
+	// address family may be specified only with Calico CNI and Calico IPAM. If omitted, a single pool
+	// will be configured if needed. The IPPool of an IP pool that is removed is disabled, and deleted
+	// once no IPAM blocks are left in it.
 	// +optional
 	IPPools []IPPool `json:"ipPools,omitempty"`
 
@@ -353,13 +713,171 @@ type CalicoNetworkSpec struct {
 	// +kubebuilder:validation:Enum=None;Multus
 	MultiInterfaceMode *MultiInterfaceMode `json:"multiInterfaceMode,omitempty"`
 
+	// Multus configures the integration with Multus. Valid only when multiInterfaceMode is Multus, in which case the
+	// operator reports the calico TigeraStatus degraded, and does not create the NetworkAttachmentDefinitions, until
+	// Multus is installed.
+	// +optional
+	Multus *MultusSpec `json:"multus,omitempty"`
+
 	// ContainerIPForwarding configures whether ip forwarding will be enabled for containers in the CNI configuration.
 	// Default: Disabled
 	// +optional
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	ContainerIPForwarding *ContainerIPForwardingType `json:"containerIPForwarding,omitempty"`
+
+	// WireGuard configures WireGuard encryption of the traffic between nodes. When MTU is specified, it is used as
+	// the MTU of the WireGuard devices. Otherwise, the MTU auto-detection accounts for the WireGuard header.
+	// +optional
+	WireGuard *WireGuardSpec `json:"wireGuard,omitempty"`
+
+	// ServiceAdvertisement configures the Service addresses that the nodes advertise to their BGP peers. Valid only
+	// when BGP is enabled.
+	// +optional
+	ServiceAdvertisement *ServiceAdvertisementSpec `json:"serviceAdvertisement,omitempty"`
+
+	// VPP configures the VPP dataplane, whose calico-vpp-node DaemonSet the operator renders when set. Valid only
+	// when linuxDataplane is VPP. If omitted, the VPP dataplane components must be installed separately.
+	// +optional
+	VPP *VPPSpec `json:"vpp,omitempty"`
+}
+
+// VPPSpec configures the VPP dataplane, which takes over the uplink interface of each node.
+type VPPSpec struct {
+	// UplinkInterface is the name of the host interface that VPP takes over on each node, for example eth0.
+	UplinkInterface string `json:"uplinkInterface"`
+
+	// Driver is the driver that VPP uses for the uplink interface. If omitted, VPP picks the best available driver
+	// for the interface. The DPDK driver requires the interface to be bound to a DPDK compatible kernel driver.
+	// +optional
+	// +kubebuilder:validation:Enum=AFPacket;AFXDP;AVF;DPDK;RDMA;Virtio;VMXNET3
+	Driver *VPPDriver `json:"driver,omitempty"`
+
+	// Hugepages is the number of 2Mi hugepages that the VPP container requests. Hugepages must be
+	// preallocated on the nodes. Set to 0 to run VPP without hugepages, which is not supported by all drivers.
+	// Default: 256
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Hugepages *int32 `json:"hugepages,omitempty"`
+
+	// CPUPinning pins the VPP threads to CPUs of the nodes. If omitted, VPP runs a single main thread that
+	// is not pinned.
+	// +optional
+	CPUPinning *VPPCPUPinning `json:"cpuPinning,omitempty"`
+
+	// ServiceCIDRs are the CIDRs of the Service cluster IPs, which VPP load balances itself.
+	// Default: 10.96.0.0/12
+	// +optional
+	ServiceCIDRs []string `json:"serviceCIDRs,omitempty"`
+}
+
+// VPPDriver specifies the driver of the VPP uplink interface.
+//
+// One of: AFPacket, AFXDP, AVF, DPDK, RDMA, Virtio, VMXNET3
+type VPPDriver string
+
+const (
+	VPPDriverAFPacket VPPDriver = "AFPacket"
+	VPPDriverAFXDP    VPPDriver = "AFXDP"
+	VPPDriverAVF      VPPDriver = "AVF"
+	VPPDriverDPDK     VPPDriver = "DPDK"
+	VPPDriverRDMA     VPPDriver = "RDMA"
+	VPPDriverVirtio   VPPDriver = "Virtio"
+	VPPDriverVMXNET3  VPPDriver = "VMXNET3"
+)
+
+// VPPCPUPinning pins the VPP threads to CPUs, which should be isolated from the other processes of the nodes.
+type VPPCPUPinning struct {
+	// MainCore is the CPU that the VPP main thread is pinned to.
+	MainCore int32 `json:"mainCore"`
+
+	// WorkerCores is the list of CPUs that VPP runs a worker thread on each of, in the Linux CPU list format,
+	// for example 2-3,6. If omitted, VPP processes packets on the main thread.
+	// +optional
+	WorkerCores string `json:"workerCores,omitempty"`
+}
+
+// ServiceAdvertisementSpec configures the advertisement of Service addresses over BGP, which is reconciled into the
+// default BGPConfiguration. The Service CIDRs that are not specified are left as they are in the BGPConfiguration.
+type ServiceAdvertisementSpec struct {
+	// ClusterIPCIDRs are the CIDRs of the Service cluster IPs to advertise, which should match the service cluster
+	// IP range of the cluster.
+	// +optional
+	ClusterIPCIDRs []string `json:"clusterIPCIDRs,omitempty"`
+
+	// ExternalIPCIDRs are the CIDRs that the external IPs of the Services to advertise must be in.
+	// +optional
+	ExternalIPCIDRs []string `json:"externalIPCIDRs,omitempty"`
+
+	// LoadBalancerIPPool is the IP pool that kube-controllers assigns the IPs of all the LoadBalancer Services from.
+	// Its CIDR is advertised. If omitted, kube-controllers does not assign LoadBalancer IPs. Requires Calico v3.28
+	// or Calico Enterprise v3.20 or later.
+	// +optional
+	LoadBalancerIPPool *LoadBalancerIPPool `json:"loadBalancerIPPool,omitempty"`
+}
+
+// LoadBalancerIPPool is the IP pool of the LoadBalancer Service IPs.
+type LoadBalancerIPPool struct {
+	// Name is the name of the IPPool resource created for the IP pool, which must not be the name of one of
+	// spec.calicoNetwork.ipPools.
+	// Default: loadbalancer-ippool
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CIDR contains the address range of the IP pool in classless inter-domain routing format.
+	CIDR string `json:"cidr"`
+}
+
+// WireGuardSpec configures WireGuard encryption for each IP address family. WireGuard encryption is only ready on
+// the nodes whose kernel supports WireGuard, which is reported in the status of the calico TigeraStatus.
+type WireGuardSpec struct {
+	// IPv4 enables or disables WireGuard encryption of the IPv4 traffic between nodes.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	IPv4 *WireGuardState `json:"ipv4,omitempty"`
+
+	// IPv6 enables or disables WireGuard encryption of the IPv6 traffic between nodes.
+	// Enabling it requires Calico v3.23 or Calico Enterprise v3.14 or later.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	IPv6 *WireGuardState `json:"ipv6,omitempty"`
+}
+
+// WireGuardState specifies whether WireGuard encryption is enabled.
+//
+// One of: Enabled, Disabled
+type WireGuardState string
+
+const (
+	WireGuardEnabled  WireGuardState = "Enabled"
+	WireGuardDisabled WireGuardState = "Disabled"
+)
+
+// MultusSpec configures the integration with Multus, which attaches additional interfaces to the pods that request
+// them through the k8s.v1.cni.cncf.io/networks annotation.
+type MultusSpec struct {
+	// NetworkAttachmentDefinitions configures whether a NetworkAttachmentDefinition is created for each IP pool with
+	// the Manual assignment mode. The NetworkAttachmentDefinition is named after the IP pool and created in the default
+	// namespace, so that pods of any namespace can attach an additional interface with an address from the IP pool by
+	// referencing default/<IP pool name>. At least one IP pool must be Manual when enabled.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	NetworkAttachmentDefinitions *NetworkAttachmentDefinitionsState `json:"networkAttachmentDefinitions,omitempty"`
 }
 
+// NetworkAttachmentDefinitionsState specifies whether the operator creates the NetworkAttachmentDefinitions of the
+// IP pools.
+//
+// One of: Enabled, Disabled
+type NetworkAttachmentDefinitionsState string
+
+const (
+	NetworkAttachmentDefinitionsEnabled  NetworkAttachmentDefinitionsState = "Enabled"
+	NetworkAttachmentDefinitionsDisabled NetworkAttachmentDefinitionsState = "Disabled"
+)
+
 // NodeAddressAutodetection provides configuration options for auto-detecting node addresses. At most one option
 // can be used. If no detection option is specified, then IP auto detection will be disabled for this address family and IPs
 // must be specified directly on the Node resource.
@@ -461,7 +979,23 @@ func (nt NATOutgoingType) String() string {
 
 const NodeSelectorDefault string = "all()"
 
+// IPPoolAssignmentMode specifies whether addresses are assigned from an IP pool automatically.
+//
+// One of: Automatic, Manual
+type IPPoolAssignmentMode string
+
+const (
+	IPPoolAssignmentAutomatic IPPoolAssignmentMode = "Automatic"
+	IPPoolAssignmentManual    IPPoolAssignmentMode = "Manual"
+)
+
 type IPPool struct {
+	// Name is the name of the IPPool resource created for the IP Pool. It is required when more than one IP Pool
+	// of an IP version is specified.
+	// Default: default-ipv4-ippool (first IPv4 pool), default-ipv6-ippool (first IPv6 pool)
+	// +optional
+	Name string `json:"name,omitempty"`
+
 	// CIDR contains the address range for the IP Pool in classless inter-domain routing format.
 	CIDR string `json:"cidr"`
 
@@ -488,6 +1022,15 @@ type IPPool struct {
 	// Default: 26 (IPv4), 122 (IPv6)
 	// +optional
 	BlockSize *int32 `json:"blockSize,omitempty"`
+
+	// AssignmentMode specifies whether pod addresses are assigned from the IP Pool automatically. Addresses are only
+	// assigned from a Manual IP Pool when it is requested explicitly, for example through the
+	// cni.projectcalico.org/ipv4pools annotation of a namespace or pod. At least one IP Pool of each IP version must be
+	// Automatic.
+	// Default: Automatic
+	// +optional
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	AssignmentMode *IPPoolAssignmentMode `json:"assignmentMode,omitempty"`
 }
 
 // CNIPluginType describes the type of CNI plugin used.
@@ -604,8 +1147,20 @@ type InstallationStatus struct {
 	// Computed is the final installation including overlaid resources.
 	// +optional
 	Computed *InstallationSpec `json:"computed,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out. The FelixConfigurationModified condition reports manual edits
+	// of the FelixConfiguration settings that are managed through spec.felix.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// FelixConfigurationModified is the type of the Installation condition that is true when settings of the default
+// FelixConfiguration that are managed through spec.felix were edited directly, and have been reverted. It is reset
+// once the Installation is updated.
+const FelixConfigurationModified = "FelixConfigurationModified"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
@@ -632,6 +1187,16 @@ type InstallationList struct {
 	Items           []Installation `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the Installation.
+func (i *Installation) GetConditions() []metav1.Condition {
+	return i.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the Installation.
+func (i *Installation) SetConditions(conditions []metav1.Condition) {
+	i.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&Installation{}, &InstallationList{})
 }
@@ -33,6 +33,12 @@ type IntrusionDetectionSpec struct {
 type IntrusionDetectionStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -75,6 +81,16 @@ type IntrusionDetectionComponentResource struct {
 	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements"`
 }
 
+// GetConditions returns the conditions reported on the status of the IntrusionDetection.
+func (i *IntrusionDetection) GetConditions() []metav1.Condition {
+	return i.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the IntrusionDetection.
+func (i *IntrusionDetection) SetConditions(conditions []metav1.Condition) {
+	i.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&IntrusionDetection{}, &IntrusionDetectionList{})
 }
@@ -159,6 +159,12 @@ type EksCloudwatchLogsSpec struct {
 type LogCollectorStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -187,6 +193,16 @@ type LogCollectorList struct {
 	Items           []LogCollector `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the LogCollector.
+func (l *LogCollector) GetConditions() []metav1.Condition {
+	return l.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the LogCollector.
+func (l *LogCollector) SetConditions(conditions []metav1.Condition) {
+	l.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&LogCollector{}, &LogCollectorList{})
 }
@@ -66,6 +66,12 @@ type LogStorageStatus struct {
 	// KibanaHash represents the current revision and configuration of the installed Kibana dashboard. This
 	// is an opaque string which can be monitored for changes to perform actions when Kibana is modified.
 	KibanaHash string `json:"kibanaHash,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // Nodes defines the configuration for a set of identical Elasticsearch cluster nodes, each of type master, data, and ingest.
@@ -188,6 +194,16 @@ func (ls LogStorage) Replicas() int {
 	return int(*ls.Spec.Indices.Replicas)
 }
 
+// GetConditions returns the conditions reported on the status of the LogStorage.
+func (l *LogStorage) GetConditions() []metav1.Condition {
+	return l.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the LogStorage.
+func (l *LogStorage) SetConditions(conditions []metav1.Condition) {
+	l.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&LogStorage{}, &LogStorageList{})
 }
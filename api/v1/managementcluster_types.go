@@ -27,8 +27,94 @@ type ManagementClusterSpec struct {
 	// Valid examples are: "0.0.0.0:31000", "example.com:32000", "[::1]:32500"
 	// +optional
 	Address string `json:"address,omitempty"`
+
+	// TunnelService configures the operator to expose the tunnel that managed clusters connect to. If not
+	// specified, the tunnel must be exposed by other means.
+	// +optional
+	TunnelService *ManagementClusterTunnelService `json:"tunnelService,omitempty"`
+
+	// TunnelCARotation stages a rotation of the CA that the tunnels between the managed clusters and this cluster
+	// are secured with. The phases must be applied in order:
+	//  - Publish: a new CA is generated, while the current one is still presented to the managed clusters. Both CAs
+	//    are trusted from this phase on. Their bundle is published under the key management-cluster.crt of the
+	//    ConfigMap tigera-management-cluster-ca-bundle in the tigera-operator namespace and must be distributed to the
+	//    managed clusters, in the management-cluster.crt key of their tigera-managed-cluster-connection secret.
+	//  - Reissue: the new CA signs the certificates of managed clusters and is presented to them. Managed cluster
+	//    certificates are re-issued by recreating their ManagedCluster resources and applying the new manifests.
+	//    Both CAs are still trusted, so managed clusters that hold a certificate signed by the current CA keep
+	//    connecting until then.
+	//  - Retire: the new CA replaces the current one, which is no longer trusted. Managed clusters whose certificate
+	//    was not re-issued can no longer connect. This phase is rejected unless the rotation went through the
+	//    Reissue phase, and can be left in place until the next rotation.
+	// +optional
+	// +kubebuilder:validation:Enum=Publish;Reissue;Retire
+	TunnelCARotation *TunnelCARotationPhase `json:"tunnelCARotation,omitempty"`
+
+	// ManagedClusters registers the managed clusters that connect to this cluster. For each of them, the operator
+	// creates the ManagedCluster resource, issues the credentials of its guardian and publishes them under the key
+	// manifest.yaml of the secret tigera-managed-cluster-<name> in the tigera-operator namespace, along with the
+	// ManagementClusterConnection to apply on the managed cluster. The credentials are re-issued when the tunnel
+	// CA rotation reaches the Reissue phase. Removing a managed cluster from the list deletes its ManagedCluster
+	// resource and its secret. Requires the address to be set.
+	// +optional
+	ManagedClusters []ManagedClusterRegistration `json:"managedClusters,omitempty"`
+}
+
+// ManagedClusterRegistration is a managed cluster registered from the management cluster.
+type ManagedClusterRegistration struct {
+	// Name of the ManagedCluster resource.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+}
+
+// ManagementClusterTunnelService configures how the tunnel is exposed to the managed clusters.
+type ManagementClusterTunnelService struct {
+	// Type of the exposure. With LoadBalancer and NodePort, a Service of that type is rendered. With Ingress, a
+	// ClusterIP Service is rendered along with an Ingress routing Host to it. The tunnel is mutually authenticated,
+	// so the ingress controller must pass TLS through, which is typically configured with annotations.
+	// +kubebuilder:validation:Enum=LoadBalancer;NodePort;Ingress
+	Type TunnelServiceType `json:"type"`
+
+	// NodePort is the port the tunnel is exposed on when the type is NodePort. If not specified, one is allocated.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	NodePort *int32 `json:"nodePort,omitempty"`
+
+	// Host is the host name the Ingress routes to the tunnel. Required when the type is Ingress.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// IngressClassName is the class of the Ingress when the type is Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Annotations are added to the Service, or to the Ingress when the type is Ingress.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// TunnelServiceType is the way the tunnel is exposed.
+// One of: LoadBalancer, NodePort, Ingress
+type TunnelServiceType string
+
+const (
+	TunnelServiceLoadBalancer TunnelServiceType = "LoadBalancer"
+	TunnelServiceNodePort     TunnelServiceType = "NodePort"
+	TunnelServiceIngress      TunnelServiceType = "Ingress"
+)
+
+// TunnelCARotationPhase is a phase of the rotation of the tunnel CA.
+// One of: Publish, Reissue, Retire
+type TunnelCARotationPhase string
+
+const (
+	TunnelCARotationPublish TunnelCARotationPhase = "Publish"
+	TunnelCARotationReissue TunnelCARotationPhase = "Reissue"
+	TunnelCARotationRetire  TunnelCARotationPhase = "Retire"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
@@ -26,6 +26,60 @@ type ManagementClusterConnectionSpec struct {
 	// should be able to access this address. This field is used by managed clusters only.
 	// +optional
 	ManagementClusterAddr string `json:"managementClusterAddr,omitempty"`
+
+	// FailoverAddrs is an ordered list of addresses of standby management clusters. When the managed cluster has
+	// not been able to reach the address it is connecting to for a few minutes, the operator moves it to the next
+	// address, starting with managementClusterAddr and going back to it after the last failover address.
+	// +optional
+	FailoverAddrs []string `json:"failoverAddrs,omitempty"`
+
+	// Proxy configures the managed cluster to reach the management cluster through an HTTP CONNECT proxy.
+	// +optional
+	Proxy *ManagementClusterConnectionProxy `json:"proxy,omitempty"`
+}
+
+// ManagementClusterConnectionProxy configures the HTTP proxy the tunnel to the management cluster is opened
+// through. If the proxy presents a certificate that is not signed by a well-known CA, the CA certificate must
+// be stored under the key tls.crt of the secret tigera-guardian-proxy-ca in the tigera-operator namespace. If
+// the proxy requires authentication, the credentials must be stored under the keys username and password of the
+// secret tigera-guardian-proxy-credentials in the tigera-operator namespace. They are inserted in the proxy URL as
+// they are, so any character reserved in URLs must be percent-encoded.
+type ManagementClusterConnectionProxy struct {
+	// URL of the proxy. Ex.: "http://proxy.example.com:3128". The scheme must be http or https.
+	URL string `json:"url"`
+}
+
+// ManagementClusterConnectionStatus defines the observed state of ManagementClusterConnection
+type ManagementClusterConnectionStatus struct {
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Tunnel reports the health of the tunnel to the management cluster.
+	// +optional
+	Tunnel *ManagementClusterTunnelStatus `json:"tunnel,omitempty"`
+}
+
+// ManagementClusterTunnelStatus reports the health of the tunnel to the management cluster, as seen by the
+// readiness of guardian, which is only ready while its tunnel is established.
+type ManagementClusterTunnelStatus struct {
+	// Connected is true while the tunnel to the management cluster is established.
+	Connected bool `json:"connected"`
+
+	// LastError is the most recent error reported while establishing or maintaining the tunnel.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// LastConnectTime is the time the tunnel was most recently established.
+	// +optional
+	LastConnectTime *metav1.Time `json:"lastConnectTime,omitempty"`
+
+	// Addr is the address of the management cluster the managed cluster is connecting to, either
+	// managementClusterAddr or one of the failoverAddrs.
+	// +optional
+	Addr string `json:"addr,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -38,7 +92,8 @@ type ManagementClusterConnection struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ManagementClusterConnectionSpec `json:"spec,omitempty"`
+	Spec   ManagementClusterConnectionSpec   `json:"spec,omitempty"`
+	Status ManagementClusterConnectionStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -50,6 +105,16 @@ type ManagementClusterConnectionList struct {
 	Items           []ManagementClusterConnection `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the ManagementClusterConnection.
+func (m *ManagementClusterConnection) GetConditions() []metav1.Condition {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the ManagementClusterConnection.
+func (m *ManagementClusterConnection) SetConditions(conditions []metav1.Condition) {
+	m.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&ManagementClusterConnection{}, &ManagementClusterConnectionList{})
 }
@@ -35,6 +35,12 @@ type ManagerStatus struct {
 
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // Auth defines authentication configuration.
@@ -89,6 +95,16 @@ type ManagerList struct {
 	Items           []Manager `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the Manager.
+func (m *Manager) GetConditions() []metav1.Condition {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the Manager.
+func (m *Manager) SetConditions(conditions []metav1.Condition) {
+	m.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&Manager{}, &ManagerList{})
 }
@@ -28,6 +28,12 @@ type MonitorSpec struct {
 type MonitorStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Conditions represents the latest observed set of conditions for the component. A component may be one or
+	// more of Ready, Progressing or Degraded. The observedGeneration of each condition is the generation of this
+	// resource that was most recently rolled out.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -53,6 +59,16 @@ type MonitorList struct {
 	Items           []Monitor `json:"items"`
 }
 
+// GetConditions returns the conditions reported on the status of the Monitor.
+func (m *Monitor) GetConditions() []metav1.Condition {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions reported on the status of the Monitor.
+func (m *Monitor) SetConditions(conditions []metav1.Condition) {
+	m.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&Monitor{}, &MonitorList{})
 }
@@ -33,6 +33,115 @@ type TigeraStatusStatus struct {
 	// Conditions represents the latest observed set of conditions for this component. A component may be one or more of
 	// Available, Progressing, or Degraded.
 	Conditions []TigeraStatusCondition `json:"conditions"`
+
+	// ObservedGeneration is the generation of the custom resource that owns this component (for example, the
+	// Installation) that was most recently rendered by the operator. A change to that resource has been fully
+	// rolled out once ObservedGeneration matches its generation and the component is Available.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Version is the Calico or Calico Enterprise version most recently rendered by the operator.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Workloads reports the rollout status of each workload that makes up this component.
+	// +optional
+	Workloads []TigeraStatusWorkload `json:"workloads,omitempty"`
+
+	// WireGuard reports the readiness of WireGuard encryption on the nodes, when it is enabled.
+	// +optional
+	WireGuard *TigeraStatusWireGuard `json:"wireGuard,omitempty"`
+
+	// Dataplane reports the progress of switching calico-node to the Linux dataplane of the Installation, which
+	// includes disabling kube-proxy for the eBPF dataplane and enabling it again when switching back.
+	// +optional
+	Dataplane *TigeraStatusDataplane `json:"dataplane,omitempty"`
+}
+
+// DataplanePhase is a phase of switching the Linux dataplane.
+//
+// One of: ConfiguringAPIServerEndpoint, EnablingKubeProxy, RollingOutCalicoNode, DisablingKubeProxy, Complete
+type DataplanePhase string
+
+const (
+	// DataplanePhaseConfiguringAPIServerEndpoint means that the eBPF dataplane is waiting for the address of the API
+	// server to be configured, since the eBPF dataplane cannot reach it through the kubernetes service without
+	// kube-proxy.
+	DataplanePhaseConfiguringAPIServerEndpoint DataplanePhase = "ConfiguringAPIServerEndpoint"
+
+	// DataplanePhaseEnablingKubeProxy means that kube-proxy is being enabled again before calico-node leaves the
+	// eBPF dataplane.
+	DataplanePhaseEnablingKubeProxy DataplanePhase = "EnablingKubeProxy"
+
+	// DataplanePhaseRollingOutCalicoNode means that calico-node is being rolled out with the dataplane.
+	DataplanePhaseRollingOutCalicoNode DataplanePhase = "RollingOutCalicoNode"
+
+	// DataplanePhaseDisablingKubeProxy means that kube-proxy is being disabled since the eBPF dataplane replaces it.
+	DataplanePhaseDisablingKubeProxy DataplanePhase = "DisablingKubeProxy"
+
+	// DataplanePhaseComplete means that the switch to the dataplane is complete.
+	DataplanePhaseComplete DataplanePhase = "Complete"
+)
+
+// TigeraStatusDataplane reports the progress of switching the Linux dataplane.
+type TigeraStatusDataplane struct {
+	// LinuxDataplane is the Linux dataplane being switched to.
+	LinuxDataplane LinuxDataplaneOption `json:"linuxDataplane"`
+
+	// Phase is the current phase of the switch.
+	Phase DataplanePhase `json:"phase"`
+
+	// Message explains what the current phase is waiting for.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// TigeraStatusWireGuard reports the readiness of WireGuard encryption on the nodes.
+type TigeraStatusWireGuard struct {
+	// Desired is the number of nodes that WireGuard encryption should be ready on.
+	Desired int32 `json:"desired"`
+
+	// Ready is the number of nodes that WireGuard encryption is ready on, for each enabled address family.
+	Ready int32 `json:"ready"`
+
+	// NotReadyNodes lists up to 20 of the nodes that WireGuard encryption is not ready on, for example because their
+	// kernel does not support WireGuard. Traffic to and from these nodes is not encrypted.
+	// +optional
+	NotReadyNodes []string `json:"notReadyNodes,omitempty"`
+}
+
+// TigeraStatusWorkload represents the rollout status of a DaemonSet, Deployment, StatefulSet or CronJob.
+type TigeraStatusWorkload struct {
+	// The kind of workload. May be DaemonSet, Deployment, StatefulSet or CronJob.
+	Kind string `json:"kind"`
+
+	// The namespace of the workload.
+	Namespace string `json:"namespace"`
+
+	// The name of the workload.
+	Name string `json:"name"`
+
+	// Desired is the number of pods that should be running. It is not reported for CronJobs.
+	Desired int32 `json:"desired"`
+
+	// Ready is the number of ready pods. It is not reported for CronJobs.
+	Ready int32 `json:"ready"`
+
+	// Updated is the number of pods running the latest revision of the workload. It is not reported for CronJobs.
+	Updated int32 `json:"updated"`
+
+	// LastScheduleTime is the last time a job of the CronJob was scheduled. It is only reported for CronJobs.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is the last time a job of the CronJob completed successfully. It is only reported
+	// for CronJobs.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// FailingPods lists the names of the pods of this workload that are failing.
+	// +optional
+	FailingPods []string `json:"failingPods,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -74,6 +183,10 @@ const (
 
 	// Degraded means the component is not operating as desired and user action is required.
 	ComponentDegraded StatusConditionType = "Degraded"
+
+	// Ready indicates that the component is healthy. It is the equivalent of Available on the
+	// status of the CR that configures the component.
+	ComponentReady StatusConditionType = "Ready"
 )
 
 // TigeraStatusCondition represents a condition attached to a particular component.
@@ -23,7 +23,9 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -31,8 +33,8 @@ func (in *APIServer) DeepCopyInto(out *APIServer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServer.
@@ -53,6 +55,21 @@ func (in *APIServer) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAuditPolicy) DeepCopyInto(out *APIServerAuditPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAuditPolicy.
+func (in *APIServerAuditPolicy) DeepCopy() *APIServerAuditPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAuditPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServerList) DeepCopyInto(out *APIServerList) {
 	*out = *in
@@ -88,6 +105,45 @@ func (in *APIServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServerSpec) DeepCopyInto(out *APIServerSpec) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AuditPolicy != nil {
+		in, out := &in.AuditPolicy, &out.AuditPolicy
+		*out = new(APIServerAuditPolicy)
+		**out = **in
+	}
+	if in.QueryServer != nil {
+		in, out := &in.QueryServer, &out.QueryServer
+		*out = new(QueryServerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(APIServerLogLevel)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerSpec.
@@ -103,6 +159,13 @@ func (in *APIServerSpec) DeepCopy() *APIServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServerStatus) DeepCopyInto(out *APIServerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerStatus.
@@ -171,7 +234,7 @@ func (in *AmazonCloudIntegration) DeepCopyInto(out *AmazonCloudIntegration) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudIntegration.
@@ -252,6 +315,13 @@ func (in *AmazonCloudIntegrationSpec) DeepCopy() *AmazonCloudIntegrationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AmazonCloudIntegrationStatus) DeepCopyInto(out *AmazonCloudIntegrationStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudIntegrationStatus.
@@ -270,7 +340,7 @@ func (in *ApplicationLayer) DeepCopyInto(out *ApplicationLayer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationLayer.
@@ -351,6 +421,13 @@ func (in *ApplicationLayerSpec) DeepCopy() *ApplicationLayerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationLayerStatus) DeepCopyInto(out *ApplicationLayerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationLayerStatus.
@@ -384,7 +461,7 @@ func (in *Authentication) DeepCopyInto(out *Authentication) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Authentication.
@@ -545,6 +622,13 @@ func (in *AuthenticationSpec) DeepCopy() *AuthenticationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthenticationStatus) DeepCopyInto(out *AuthenticationStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticationStatus.
@@ -622,11 +706,31 @@ func (in *CalicoNetworkSpec) DeepCopyInto(out *CalicoNetworkSpec) {
 		*out = new(MultiInterfaceMode)
 		**out = **in
 	}
+	if in.Multus != nil {
+		in, out := &in.Multus, &out.Multus
+		*out = new(MultusSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ContainerIPForwarding != nil {
 		in, out := &in.ContainerIPForwarding, &out.ContainerIPForwarding
 		*out = new(ContainerIPForwardingType)
 		**out = **in
 	}
+	if in.WireGuard != nil {
+		in, out := &in.WireGuard, &out.WireGuard
+		*out = new(WireGuardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAdvertisement != nil {
+		in, out := &in.ServiceAdvertisement, &out.ServiceAdvertisement
+		*out = new(ServiceAdvertisementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VPP != nil {
+		in, out := &in.VPP, &out.VPP
+		*out = new(VPPSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNetworkSpec.
@@ -639,6 +743,80 @@ func (in *CalicoNetworkSpec) DeepCopy() *CalicoNetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNodePool) DeepCopyInto(out *CalicoNodePool) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeAddressAutodetectionV4 != nil {
+		in, out := &in.NodeAddressAutodetectionV4, &out.NodeAddressAutodetectionV4
+		*out = new(NodeAddressAutodetection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeAddressAutodetectionV6 != nil {
+		in, out := &in.NodeAddressAutodetectionV6, &out.NodeAddressAutodetectionV6
+		*out = new(NodeAddressAutodetection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNodePool.
+func (in *CalicoNodePool) DeepCopy() *CalicoNodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoNodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoWindowsUpgradeSpec) DeepCopyInto(out *CalicoWindowsUpgradeSpec) {
+	*out = *in
+	if in.NodeUpgradeTimeout != nil {
+		in, out := &in.NodeUpgradeTimeout, &out.NodeUpgradeTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CordonAndDrain != nil {
+		in, out := &in.CordonAndDrain, &out.CordonAndDrain
+		*out = new(CordonAndDrainType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoWindowsUpgradeSpec.
+func (in *CalicoWindowsUpgradeSpec) DeepCopy() *CalicoWindowsUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoWindowsUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateManagement) DeepCopyInto(out *CertificateManagement) {
 	*out = *in
@@ -665,7 +843,7 @@ func (in *Compliance) DeepCopyInto(out *Compliance) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Compliance.
@@ -736,6 +914,13 @@ func (in *ComplianceSpec) DeepCopy() *ComplianceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComplianceStatus) DeepCopyInto(out *ComplianceStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceStatus.
@@ -749,162 +934,213 @@ func (in *ComplianceStatus) DeepCopy() *ComplianceStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentResource) DeepCopyInto(out *ComponentResource) {
+func (in *ComponentPodDisruptionBudget) DeepCopyInto(out *ComponentPodDisruptionBudget) {
 	*out = *in
-	if in.ResourceRequirements != nil {
-		in, out := &in.ResourceRequirements, &out.ResourceRequirements
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentResource.
-func (in *ComponentResource) DeepCopy() *ComponentResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPodDisruptionBudget.
+func (in *ComponentPodDisruptionBudget) DeepCopy() *ComponentPodDisruptionBudget {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentResource)
+	out := new(ComponentPodDisruptionBudget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EksCloudwatchLogsSpec) DeepCopyInto(out *EksCloudwatchLogsSpec) {
+func (in *ComponentResource) DeepCopyInto(out *ComponentResource) {
 	*out = *in
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EksCloudwatchLogsSpec.
-func (in *EksCloudwatchLogsSpec) DeepCopy() *EksCloudwatchLogsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentResource.
+func (in *ComponentResource) DeepCopy() *ComponentResource {
 	if in == nil {
 		return nil
 	}
-	out := new(EksCloudwatchLogsSpec)
+	out := new(ComponentResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GroupSearch) DeepCopyInto(out *GroupSearch) {
+func (in *EgressGateway) DeepCopyInto(out *EgressGateway) {
 	*out = *in
-	if in.UserMatchers != nil {
-		in, out := &in.UserMatchers, &out.UserMatchers
-		*out = make([]UserMatch, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSearch.
-func (in *GroupSearch) DeepCopy() *GroupSearch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGateway.
+func (in *EgressGateway) DeepCopy() *EgressGateway {
 	if in == nil {
 		return nil
 	}
-	out := new(GroupSearch)
+	out := new(EgressGateway)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EgressGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPAMSpec) DeepCopyInto(out *IPAMSpec) {
+func (in *EgressGatewayFailureDetection) DeepCopyInto(out *EgressGatewayFailureDetection) {
 	*out = *in
+	if in.HealthTimeoutDataStoreSeconds != nil {
+		in, out := &in.HealthTimeoutDataStoreSeconds, &out.HealthTimeoutDataStoreSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ICMPProbe != nil {
+		in, out := &in.ICMPProbe, &out.ICMPProbe
+		*out = new(EgressGatewayICMPProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPProbe != nil {
+		in, out := &in.HTTPProbe, &out.HTTPProbe
+		*out = new(EgressGatewayHTTPProbe)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMSpec.
-func (in *IPAMSpec) DeepCopy() *IPAMSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGatewayFailureDetection.
+func (in *EgressGatewayFailureDetection) DeepCopy() *EgressGatewayFailureDetection {
 	if in == nil {
 		return nil
 	}
-	out := new(IPAMSpec)
+	out := new(EgressGatewayFailureDetection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPPool) DeepCopyInto(out *IPPool) {
+func (in *EgressGatewayHTTPProbe) DeepCopyInto(out *EgressGatewayHTTPProbe) {
 	*out = *in
-	if in.BlockSize != nil {
-		in, out := &in.BlockSize, &out.BlockSize
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
 		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
-func (in *IPPool) DeepCopy() *IPPool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGatewayHTTPProbe.
+func (in *EgressGatewayHTTPProbe) DeepCopy() *EgressGatewayHTTPProbe {
 	if in == nil {
 		return nil
 	}
-	out := new(IPPool)
+	out := new(EgressGatewayHTTPProbe)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *EgressGatewayICMPProbe) DeepCopyInto(out *EgressGatewayICMPProbe) {
 	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
-func (in *Image) DeepCopy() *Image {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGatewayICMPProbe.
+func (in *EgressGatewayICMPProbe) DeepCopy() *EgressGatewayICMPProbe {
 	if in == nil {
 		return nil
 	}
-	out := new(Image)
+	out := new(EgressGatewayICMPProbe)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSet) DeepCopyInto(out *ImageSet) {
+func (in *EgressGatewayIPPool) DeepCopyInto(out *EgressGatewayIPPool) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.BlockSize != nil {
+		in, out := &in.BlockSize, &out.BlockSize
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSet.
-func (in *ImageSet) DeepCopy() *ImageSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGatewayIPPool.
+func (in *EgressGatewayIPPool) DeepCopy() *EgressGatewayIPPool {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSet)
+	out := new(EgressGatewayIPPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ImageSet) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSetList) DeepCopyInto(out *ImageSetList) {
+func (in *EgressGatewayList) DeepCopyInto(out *EgressGatewayList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ImageSet, len(*in))
+		*out = make([]EgressGateway, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSetList.
-func (in *ImageSetList) DeepCopy() *ImageSetList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGatewayList.
+func (in *EgressGatewayList) DeepCopy() *EgressGatewayList {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSetList)
+	out := new(EgressGatewayList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ImageSetList) DeepCopyObject() runtime.Object {
+func (in *EgressGatewayList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -912,21 +1148,397 @@ func (in *ImageSetList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSetSpec) DeepCopyInto(out *ImageSetSpec) {
+func (in *EgressGatewaySpec) DeepCopyInto(out *EgressGatewaySpec) {
 	*out = *in
-	if in.Images != nil {
-		in, out := &in.Images, &out.Images
-		*out = make([]Image, len(*in))
-		copy(*out, *in)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSetSpec.
-func (in *ImageSetSpec) DeepCopy() *ImageSetSpec {
-	if in == nil {
-		return nil
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
 	}
-	out := new(ImageSetSpec)
+	in.IPPool.DeepCopyInto(&out.IPPool)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LogSeverity != nil {
+		in, out := &in.LogSeverity, &out.LogSeverity
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureDetection != nil {
+		in, out := &in.FailureDetection, &out.FailureDetection
+		*out = new(EgressGatewayFailureDetection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGatewaySpec.
+func (in *EgressGatewaySpec) DeepCopy() *EgressGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressGatewayStatus) DeepCopyInto(out *EgressGatewayStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressGatewayStatus.
+func (in *EgressGatewayStatus) DeepCopy() *EgressGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EksCloudwatchLogsSpec) DeepCopyInto(out *EksCloudwatchLogsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EksCloudwatchLogsSpec.
+func (in *EksCloudwatchLogsSpec) DeepCopy() *EksCloudwatchLogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EksCloudwatchLogsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FelixHealthTimeoutOverride) DeepCopyInto(out *FelixHealthTimeoutOverride) {
+	*out = *in
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixHealthTimeoutOverride.
+func (in *FelixHealthTimeoutOverride) DeepCopy() *FelixHealthTimeoutOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(FelixHealthTimeoutOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FelixProtoPort) DeepCopyInto(out *FelixProtoPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixProtoPort.
+func (in *FelixProtoPort) DeepCopy() *FelixProtoPort {
+	if in == nil {
+		return nil
+	}
+	out := new(FelixProtoPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FelixRouteTableRange) DeepCopyInto(out *FelixRouteTableRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixRouteTableRange.
+func (in *FelixRouteTableRange) DeepCopy() *FelixRouteTableRange {
+	if in == nil {
+		return nil
+	}
+	out := new(FelixRouteTableRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FelixSpec) DeepCopyInto(out *FelixSpec) {
+	*out = *in
+	if in.LogSeverityScreen != nil {
+		in, out := &in.LogSeverityScreen, &out.LogSeverityScreen
+		*out = new(string)
+		**out = **in
+	}
+	if in.IptablesBackend != nil {
+		in, out := &in.IptablesBackend, &out.IptablesBackend
+		*out = new(string)
+		**out = **in
+	}
+	if in.IptablesRefreshInterval != nil {
+		in, out := &in.IptablesRefreshInterval, &out.IptablesRefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RouteTableRange != nil {
+		in, out := &in.RouteTableRange, &out.RouteTableRange
+		*out = new(FelixRouteTableRange)
+		**out = **in
+	}
+	if in.BPFKubeProxyIptablesCleanup != nil {
+		in, out := &in.BPFKubeProxyIptablesCleanup, &out.BPFKubeProxyIptablesCleanup
+		*out = new(FelixFeatureState)
+		**out = **in
+	}
+	if in.BPFExternalServiceMode != nil {
+		in, out := &in.BPFExternalServiceMode, &out.BPFExternalServiceMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailsafeInboundHostPorts != nil {
+		in, out := &in.FailsafeInboundHostPorts, &out.FailsafeInboundHostPorts
+		*out = new([]FelixProtoPort)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]FelixProtoPort, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.FailsafeOutboundHostPorts != nil {
+		in, out := &in.FailsafeOutboundHostPorts, &out.FailsafeOutboundHostPorts
+		*out = new([]FelixProtoPort)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]FelixProtoPort, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.HealthTimeoutOverrides != nil {
+		in, out := &in.HealthTimeoutOverrides, &out.HealthTimeoutOverrides
+		*out = make([]FelixHealthTimeoutOverride, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixSpec.
+func (in *FelixSpec) DeepCopy() *FelixSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FelixSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSearch) DeepCopyInto(out *GroupSearch) {
+	*out = *in
+	if in.UserMatchers != nil {
+		in, out := &in.UserMatchers, &out.UserMatchers
+		*out = make([]UserMatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSearch.
+func (in *GroupSearch) DeepCopy() *GroupSearch {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSearch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostProtectionSpec) DeepCopyInto(out *HostProtectionSpec) {
+	*out = *in
+	if in.ReachabilityCheck != nil {
+		in, out := &in.ReachabilityCheck, &out.ReachabilityCheck
+		*out = new(HostProtectionCheckState)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostProtectionSpec.
+func (in *HostProtectionSpec) DeepCopy() *HostProtectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostProtectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMSpec) DeepCopyInto(out *IPAMSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMSpec.
+func (in *IPAMSpec) DeepCopy() *IPAMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPool) DeepCopyInto(out *IPPool) {
+	*out = *in
+	if in.BlockSize != nil {
+		in, out := &in.BlockSize, &out.BlockSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AssignmentMode != nil {
+		in, out := &in.AssignmentMode, &out.AssignmentMode
+		*out = new(IPPoolAssignmentMode)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
+func (in *IPPool) DeepCopy() *IPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Image) DeepCopyInto(out *Image) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
+func (in *Image) DeepCopy() *Image {
+	if in == nil {
+		return nil
+	}
+	out := new(Image)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSet) DeepCopyInto(out *ImageSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSet.
+func (in *ImageSet) DeepCopy() *ImageSet {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSetList) DeepCopyInto(out *ImageSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSetList.
+func (in *ImageSetList) DeepCopy() *ImageSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSetSpec) DeepCopyInto(out *ImageSetSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]Image, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSetSpec.
+func (in *ImageSetSpec) DeepCopy() *ImageSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerification)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1080,6 +1692,48 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		*out = new(NonPrivilegedType)
 		**out = **in
 	}
+	if in.CalicoWindowsUpgrade != nil {
+		in, out := &in.CalicoWindowsUpgrade, &out.CalicoWindowsUpgrade
+		*out = new(CalicoWindowsUpgradeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerification)
+		**out = **in
+	}
+	if in.PodDisruptionBudgets != nil {
+		in, out := &in.PodDisruptionBudgets, &out.PodDisruptionBudgets
+		*out = new(PodDisruptionBudgets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentNetworkPolicies != nil {
+		in, out := &in.ComponentNetworkPolicies, &out.ComponentNetworkPolicies
+		*out = new(ComponentNetworkPoliciesType)
+		**out = **in
+	}
+	if in.KubeControllers != nil {
+		in, out := &in.KubeControllers, &out.KubeControllers
+		*out = new(KubeControllersSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Felix != nil {
+		in, out := &in.Felix, &out.Felix
+		*out = new(FelixSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CalicoNodePools != nil {
+		in, out := &in.CalicoNodePools, &out.CalicoNodePools
+		*out = make([]CalicoNodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostProtection != nil {
+		in, out := &in.HostProtection, &out.HostProtection
+		*out = new(HostProtectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationSpec.
@@ -1100,6 +1754,13 @@ func (in *InstallationStatus) DeepCopyInto(out *InstallationStatus) {
 		*out = new(InstallationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationStatus.
@@ -1118,7 +1779,7 @@ func (in *IntrusionDetection) DeepCopyInto(out *IntrusionDetection) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetection.
@@ -1216,6 +1877,13 @@ func (in *IntrusionDetectionSpec) DeepCopy() *IntrusionDetectionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntrusionDetectionStatus) DeepCopyInto(out *IntrusionDetectionStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetectionStatus.
@@ -1228,6 +1896,131 @@ func (in *IntrusionDetectionStatus) DeepCopy() *IntrusionDetectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllersController) DeepCopyInto(out *KubeControllersController) {
+	*out = *in
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(KubeControllerState)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersController.
+func (in *KubeControllersController) DeepCopy() *KubeControllersController {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllersController)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllersControllers) DeepCopyInto(out *KubeControllersControllers) {
+	*out = *in
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(KubeControllersNodeController)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(KubeControllersController)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FederatedServices != nil {
+		in, out := &in.FederatedServices, &out.FederatedServices
+		*out = new(KubeControllersController)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersControllers.
+func (in *KubeControllersControllers) DeepCopy() *KubeControllersControllers {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllersControllers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllersNodeController) DeepCopyInto(out *KubeControllersNodeController) {
+	*out = *in
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(KubeControllerState)
+		**out = **in
+	}
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LeakGracePeriod != nil {
+		in, out := &in.LeakGracePeriod, &out.LeakGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AutoHostEndpoints != nil {
+		in, out := &in.AutoHostEndpoints, &out.AutoHostEndpoints
+		*out = new(KubeControllerState)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersNodeController.
+func (in *KubeControllersNodeController) DeepCopy() *KubeControllersNodeController {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllersNodeController)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllersSpec) DeepCopyInto(out *KubeControllersSpec) {
+	*out = *in
+	if in.LogSeverityScreen != nil {
+		in, out := &in.LogSeverityScreen, &out.LogSeverityScreen
+		*out = new(string)
+		**out = **in
+	}
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = new(KubeControllersControllers)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersSpec.
+func (in *KubeControllersSpec) DeepCopy() *KubeControllersSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllersSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerIPPool) DeepCopyInto(out *LoadBalancerIPPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerIPPool.
+func (in *LoadBalancerIPPool) DeepCopy() *LoadBalancerIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogCollectionSpec) DeepCopyInto(out *LogCollectionSpec) {
 	*out = *in
@@ -1264,7 +2057,7 @@ func (in *LogCollector) DeepCopyInto(out *LogCollector) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollector.
@@ -1350,6 +2143,13 @@ func (in *LogCollectorSpec) DeepCopy() *LogCollectorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogCollectorStatus) DeepCopyInto(out *LogCollectorStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorStatus.
@@ -1368,7 +2168,7 @@ func (in *LogStorage) DeepCopyInto(out *LogStorage) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorage.
@@ -1480,22 +2280,44 @@ func (in *LogStorageSpec) DeepCopy() *LogStorageSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LogStorageSpec)
+	out := new(LogStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogStorageStatus) DeepCopyInto(out *LogStorageStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageStatus.
+func (in *LogStorageStatus) DeepCopy() *LogStorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogStorageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogStorageStatus) DeepCopyInto(out *LogStorageStatus) {
+func (in *ManagedClusterRegistration) DeepCopyInto(out *ManagedClusterRegistration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageStatus.
-func (in *LogStorageStatus) DeepCopy() *LogStorageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterRegistration.
+func (in *ManagedClusterRegistration) DeepCopy() *ManagedClusterRegistration {
 	if in == nil {
 		return nil
 	}
-	out := new(LogStorageStatus)
+	out := new(ManagedClusterRegistration)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1505,7 +2327,7 @@ func (in *ManagementCluster) DeepCopyInto(out *ManagementCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementCluster.
@@ -1531,7 +2353,8 @@ func (in *ManagementClusterConnection) DeepCopyInto(out *ManagementClusterConnec
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnection.
@@ -1584,9 +2407,34 @@ func (in *ManagementClusterConnectionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterConnectionProxy) DeepCopyInto(out *ManagementClusterConnectionProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnectionProxy.
+func (in *ManagementClusterConnectionProxy) DeepCopy() *ManagementClusterConnectionProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterConnectionProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementClusterConnectionSpec) DeepCopyInto(out *ManagementClusterConnectionSpec) {
 	*out = *in
+	if in.FailoverAddrs != nil {
+		in, out := &in.FailoverAddrs, &out.FailoverAddrs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ManagementClusterConnectionProxy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnectionSpec.
@@ -1599,6 +2447,33 @@ func (in *ManagementClusterConnectionSpec) DeepCopy() *ManagementClusterConnecti
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterConnectionStatus) DeepCopyInto(out *ManagementClusterConnectionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tunnel != nil {
+		in, out := &in.Tunnel, &out.Tunnel
+		*out = new(ManagementClusterTunnelStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnectionStatus.
+func (in *ManagementClusterConnectionStatus) DeepCopy() *ManagementClusterConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementClusterList) DeepCopyInto(out *ManagementClusterList) {
 	*out = *in
@@ -1634,6 +2509,21 @@ func (in *ManagementClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementClusterSpec) DeepCopyInto(out *ManagementClusterSpec) {
 	*out = *in
+	if in.TunnelService != nil {
+		in, out := &in.TunnelService, &out.TunnelService
+		*out = new(ManagementClusterTunnelService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TunnelCARotation != nil {
+		in, out := &in.TunnelCARotation, &out.TunnelCARotation
+		*out = new(TunnelCARotationPhase)
+		**out = **in
+	}
+	if in.ManagedClusters != nil {
+		in, out := &in.ManagedClusters, &out.ManagedClusters
+		*out = make([]ManagedClusterRegistration, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterSpec.
@@ -1646,6 +2536,57 @@ func (in *ManagementClusterSpec) DeepCopy() *ManagementClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterTunnelService) DeepCopyInto(out *ManagementClusterTunnelService) {
+	*out = *in
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterTunnelService.
+func (in *ManagementClusterTunnelService) DeepCopy() *ManagementClusterTunnelService {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterTunnelService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterTunnelStatus) DeepCopyInto(out *ManagementClusterTunnelStatus) {
+	*out = *in
+	if in.LastConnectTime != nil {
+		in, out := &in.LastConnectTime, &out.LastConnectTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterTunnelStatus.
+func (in *ManagementClusterTunnelStatus) DeepCopy() *ManagementClusterTunnelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterTunnelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Manager) DeepCopyInto(out *Manager) {
 	*out = *in
@@ -1733,6 +2674,13 @@ func (in *ManagerStatus) DeepCopyInto(out *ManagerStatus) {
 		*out = new(Auth)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerStatus.
@@ -1751,7 +2699,7 @@ func (in *Monitor) DeepCopyInto(out *Monitor) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitor.
@@ -1822,6 +2770,13 @@ func (in *MonitorSpec) DeepCopy() *MonitorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitorStatus) DeepCopyInto(out *MonitorStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorStatus.
@@ -1834,6 +2789,26 @@ func (in *MonitorStatus) DeepCopy() *MonitorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultusSpec) DeepCopyInto(out *MultusSpec) {
+	*out = *in
+	if in.NetworkAttachmentDefinitions != nil {
+		in, out := &in.NetworkAttachmentDefinitions, &out.NetworkAttachmentDefinitions
+		*out = new(NetworkAttachmentDefinitionsState)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultusSpec.
+func (in *MultusSpec) DeepCopy() *MultusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MultusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeAddressAutodetection) DeepCopyInto(out *NodeAddressAutodetection) {
 	*out = *in
@@ -1953,6 +2928,48 @@ func (in *Nodes) DeepCopy() *Nodes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgets) DeepCopyInto(out *PodDisruptionBudgets) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentPodDisruptionBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgets.
+func (in *PodDisruptionBudgets) DeepCopy() *PodDisruptionBudgets {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryServerSpec) DeepCopyInto(out *QueryServerSpec) {
+	*out = *in
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryServerSpec.
+func (in *QueryServerSpec) DeepCopy() *QueryServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Retention) DeepCopyInto(out *Retention) {
 	*out = *in
@@ -2003,6 +3020,36 @@ func (in *S3StoreSpec) DeepCopy() *S3StoreSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAdvertisementSpec) DeepCopyInto(out *ServiceAdvertisementSpec) {
+	*out = *in
+	if in.ClusterIPCIDRs != nil {
+		in, out := &in.ClusterIPCIDRs, &out.ClusterIPCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalIPCIDRs != nil {
+		in, out := &in.ExternalIPCIDRs, &out.ExternalIPCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerIPPool != nil {
+		in, out := &in.LoadBalancerIPPool, &out.LoadBalancerIPPool
+		*out = new(LoadBalancerIPPool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAdvertisementSpec.
+func (in *ServiceAdvertisementSpec) DeepCopy() *ServiceAdvertisementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAdvertisementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SplunkStoreSpec) DeepCopyInto(out *SplunkStoreSpec) {
 	*out = *in
@@ -2086,6 +3133,21 @@ func (in *TigeraStatusCondition) DeepCopy() *TigeraStatusCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusDataplane) DeepCopyInto(out *TigeraStatusDataplane) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusDataplane.
+func (in *TigeraStatusDataplane) DeepCopy() *TigeraStatusDataplane {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusDataplane)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TigeraStatusList) DeepCopyInto(out *TigeraStatusList) {
 	*out = *in
@@ -2143,6 +3205,23 @@ func (in *TigeraStatusStatus) DeepCopyInto(out *TigeraStatusStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]TigeraStatusWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WireGuard != nil {
+		in, out := &in.WireGuard, &out.WireGuard
+		*out = new(TigeraStatusWireGuard)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dataplane != nil {
+		in, out := &in.Dataplane, &out.Dataplane
+		*out = new(TigeraStatusDataplane)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusStatus.
@@ -2155,6 +3234,54 @@ func (in *TigeraStatusStatus) DeepCopy() *TigeraStatusStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusWireGuard) DeepCopyInto(out *TigeraStatusWireGuard) {
+	*out = *in
+	if in.NotReadyNodes != nil {
+		in, out := &in.NotReadyNodes, &out.NotReadyNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusWireGuard.
+func (in *TigeraStatusWireGuard) DeepCopy() *TigeraStatusWireGuard {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusWireGuard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusWorkload) DeepCopyInto(out *TigeraStatusWorkload) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailingPods != nil {
+		in, out := &in.FailingPods, &out.FailingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusWorkload.
+func (in *TigeraStatusWorkload) DeepCopy() *TigeraStatusWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TyphaAffinity) DeepCopyInto(out *TyphaAffinity) {
 	*out = *in
@@ -2204,3 +3331,78 @@ func (in *UserSearch) DeepCopy() *UserSearch {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPCPUPinning) DeepCopyInto(out *VPPCPUPinning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPCPUPinning.
+func (in *VPPCPUPinning) DeepCopy() *VPPCPUPinning {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPCPUPinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPSpec) DeepCopyInto(out *VPPSpec) {
+	*out = *in
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(VPPDriver)
+		**out = **in
+	}
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CPUPinning != nil {
+		in, out := &in.CPUPinning, &out.CPUPinning
+		*out = new(VPPCPUPinning)
+		**out = **in
+	}
+	if in.ServiceCIDRs != nil {
+		in, out := &in.ServiceCIDRs, &out.ServiceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPSpec.
+func (in *VPPSpec) DeepCopy() *VPPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WireGuardSpec) DeepCopyInto(out *WireGuardSpec) {
+	*out = *in
+	if in.IPv4 != nil {
+		in, out := &in.IPv4, &out.IPv4
+		*out = new(WireGuardState)
+		**out = **in
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(WireGuardState)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WireGuardSpec.
+func (in *WireGuardSpec) DeepCopy() *WireGuardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WireGuardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -59,6 +59,18 @@ var (
 		Version: "{{ .Version }}",
 		Image:   "{{ .Image }}",
 	}
+{{- end }}
+{{ with index .Components "calicovpp/vpp"}}
+	ComponentCalicoVPP = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
+{{ with index .Components "calicovpp/agent"}}
+	ComponentCalicoVPPAgent = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
 {{- end }}
 	ComponentOperatorInit = component{
 		Version: version.VERSION,
@@ -74,5 +86,7 @@ var (
 		ComponentOperatorInit,
 		ComponentCalicoAPIServer,
 		ComponentWindows,
+		ComponentCalicoVPP,
+		ComponentCalicoVPPAgent,
 	}
 )
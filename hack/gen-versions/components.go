@@ -42,6 +42,8 @@ var defaultImages = map[string]string{
 	"key-cert-provisioner":       "tigera/key-cert-provisioner",
 	"calico/apiserver":           "calico/apiserver",
 	"calico/windows-upgrade":     "calico/windows-upgrade",
+	"calicovpp/vpp":              "calicovpp/vpp",
+	"calicovpp/agent":            "calicovpp/agent",
 }
 
 var ignoredImages = map[string]struct{}{
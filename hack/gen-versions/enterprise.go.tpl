@@ -261,6 +261,12 @@ var (
 		Version: "{{ .Version }}",
 		Image:   "{{ .Image }}",
 	}
+{{- end }}
+{{ with index .Components "egress-gateway" }}
+	ComponentEgressGateway = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
 {{- end }}
 	EnterpriseComponents = []component{
 		ComponentAPIServer,
@@ -304,5 +310,6 @@ var (
 		ComponentESGateway,
 		ComponentTigeraWindows,
 		ComponentDikastes,
+		ComponentEgressGateway,
 	}
 )
@@ -26,6 +26,7 @@ import (
 
 	"github.com/cloudflare/cfssl/log"
 	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -48,6 +49,7 @@ import (
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
 	"github.com/tigera/operator/pkg/crds"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/version"
@@ -84,6 +86,8 @@ func main() {
 	var urlOnlyKubeconfig string
 	var showVersion bool
 	var printImages string
+	var generateImageSet string
+	var imageSetSource string
 	var printCalicoCRDs string
 	var printEnterpriseCRDs string
 	var sgSetup bool
@@ -97,6 +101,10 @@ func main() {
 		"Show version information")
 	flag.StringVar(&printImages, "print-images", "",
 		"Print the default images the operator could deploy and exit. Possible values: list")
+	flag.StringVar(&generateImageSet, "generate-imageset", "",
+		"Print an ImageSet for the specified variant with the digests of the images found in --imageset-source then exit. Possible values: calico, enterprise")
+	flag.StringVar(&imageSetSource, "imageset-source", "",
+		"Where --generate-imageset reads image digests from. Possible values: oci:<path to an OCI image layout>, <registry mirror, e.g. myregistry.com/mirror/>")
 	flag.StringVar(&printCalicoCRDs, "print-calico-crds", "",
 		"Print the Calico CRDs the operator has bundled then exit. Possible values: all, <crd prefix>. If a value other than 'all' is specified, the first CRD with a prefix of the specified value will be printed.")
 	flag.StringVar(&printEnterpriseCRDs, "print-enterprise-crds", "",
@@ -133,6 +141,13 @@ func main() {
 		fmt.Println("Invalid option for --print-images flag", printImages)
 		os.Exit(1)
 	}
+	if generateImageSet != "" {
+		if err := showImageSet(generateImageSet, imageSetSource); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	if printCalicoCRDs != "" {
 		if err := showCRDs(operatorv1.Calico, printCalicoCRDs); err != nil {
 			fmt.Println(err)
@@ -209,8 +224,11 @@ func main() {
 		// for the LicenseKey. We should test again in the future to see if the cache issue is fixed
 		// and we can remove this. Here is a link to the upstream issue
 		// https://github.com/kubernetes-sigs/controller-runtime/issues/1316
+		// The installation controller reads only the kubernetes Endpoints, which doesn't warrant caching
+		// every Endpoints in the cluster.
 		ClientDisableCacheFor: []client.Object{
 			&v3.LicenseKey{},
+			&corev1.Endpoints{},
 		},
 	})
 	if err != nil {
@@ -258,12 +276,28 @@ func main() {
 		kubernetesVersion = &common.VersionInfo{Major: 1, Minor: 18}
 	}
 
+	usePSP, err := utils.SupportsPodSecurityPolicies(clientset)
+	if err != nil {
+		log.Error(err, "Unable to determine if PodSecurityPolicies are supported, falling back to the Kubernetes version")
+		usePSP = kubernetesVersion.ProvidesPodSecurityPolicyAPI()
+	}
+	setupLog.WithValues("supported", usePSP).Info("Checking if PodSecurityPolicies are supported")
+
+	usePolicyV1PDB, err := utils.SupportsPolicyV1PodDisruptionBudgets(clientset)
+	if err != nil {
+		log.Error(err, "Unable to determine if policy/v1 PodDisruptionBudgets are supported, falling back to the Kubernetes version")
+		usePolicyV1PDB = kubernetesVersion.ProvidesPolicyV1PodDisruptionBudgetAPI()
+	}
+	setupLog.WithValues("supported", usePolicyV1PDB).Info("Checking if policy/v1 PodDisruptionBudgets are supported")
+
 	options := options.AddOptions{
 		DetectedProvider:    provider,
 		EnterpriseCRDExists: enterpriseCRDExists,
 		AmazonCRDExists:     amazonCRDExists,
 		ClusterDomain:       clusterDomain,
 		KubernetesVersion:   kubernetesVersion,
+		UsePSP:              usePSP,
+		UsePolicyV1PDB:      usePolicyV1PDB,
 		ManageCRDs:          manageCRDs,
 		ShutdownContext:     sigHandler,
 	}
@@ -330,6 +364,37 @@ func metricsAddr() string {
 	return fmt.Sprintf("%s:%s", metricsHost, metricsPort)
 }
 
+func showImageSet(variant, source string) error {
+	var v operatorv1.ProductVariant
+	switch strings.ToLower(variant) {
+	case "calico":
+		v = operatorv1.Calico
+	case "enterprise":
+		v = operatorv1.TigeraSecureEnterprise
+	default:
+		return fmt.Errorf("Invalid option for --generate-imageset flag %s", variant)
+	}
+	if source == "" {
+		return fmt.Errorf("--imageset-source must be specified with --generate-imageset")
+	}
+
+	src, err := imageset.NewDigestSource(source)
+	if err != nil {
+		return err
+	}
+	is, err := imageset.GenerateImageSet(v, src)
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(is)
+	if err != nil {
+		return fmt.Errorf("Failed to Marshal %s: %v", is.Name, err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
 func showCRDs(variant operatorv1.ProductVariant, outputType string) error {
 	first := true
 	for _, v := range crds.GetCRDs(variant) {
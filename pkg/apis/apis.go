@@ -20,9 +20,11 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
 	configv1 "github.com/openshift/api/config/v1"
+	ocsoperatorv1 "github.com/openshift/api/operator/v1"
 	ocsv1 "github.com/openshift/api/security/v1"
 	tigera "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	nadv1 "github.com/tigera/operator/pkg/apis/k8s.cni.cncf.io/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	aggregator "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/scheme"
@@ -39,6 +41,7 @@ func AddToScheme(s *runtime.Scheme) error {
 func init() {
 	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
 	AddToSchemes = append(AddToSchemes, configv1.Install)
+	AddToSchemes = append(AddToSchemes, ocsoperatorv1.Install)
 	AddToSchemes = append(AddToSchemes, aggregator.AddToScheme)
 	AddToSchemes = append(AddToSchemes, apiextensions.AddToScheme)
 	AddToSchemes = append(AddToSchemes, tigera.AddToScheme)
@@ -47,4 +50,5 @@ func init() {
 	AddToSchemes = append(AddToSchemes, kbv1.SchemeBuilder.AddToScheme)
 	AddToSchemes = append(AddToSchemes, policyv1beta1.SchemeBuilder.AddToScheme)
 	AddToSchemes = append(AddToSchemes, crdv1.SchemeBuilder.AddToScheme)
+	AddToSchemes = append(AddToSchemes, nadv1.SchemeBuilder.AddToScheme)
 }
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	KindBGPConfiguration     = "BGPConfiguration"
+	KindBGPConfigurationList = "BGPConfigurationList"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BGPConfiguration contains the configuration for any BGP routing.
+type BGPConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the BGPConfiguration.
+	Spec BGPConfigurationSpec `json:"spec,omitempty"`
+}
+
+// BGPConfigurationSpec contains the values of the BGP configuration. Only the fields that the operator manages are
+// listed, the others are left as they are by patches.
+type BGPConfigurationSpec struct {
+	// ServiceClusterIPs are the CIDR blocks from which service cluster IPs are allocated.
+	// If specified, Calico will advertise these blocks, as well as any cluster IPs within them.
+	ServiceClusterIPs []ServiceClusterIPBlock `json:"serviceClusterIPs,omitempty"`
+
+	// ServiceExternalIPs are the CIDR blocks for Kubernetes Service External IPs.
+	// Kubernetes Service ExternalIPs will only be advertised if they are within one of these blocks.
+	ServiceExternalIPs []ServiceExternalIPBlock `json:"serviceExternalIPs,omitempty"`
+
+	// ServiceLoadBalancerIPs are the CIDR blocks for Kubernetes Service LoadBalancer IPs.
+	// Kubernetes Service status.LoadBalancer.Ingress IPs will only be advertised if they are within one of these blocks.
+	ServiceLoadBalancerIPs []ServiceLoadBalancerIPBlock `json:"serviceLoadBalancerIPs,omitempty"`
+}
+
+// ServiceClusterIPBlock represents a single allowed ClusterIP CIDR block.
+type ServiceClusterIPBlock struct {
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// ServiceExternalIPBlock represents a single allowed External IP CIDR block.
+type ServiceExternalIPBlock struct {
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// ServiceLoadBalancerIPBlock represents a single allowed LoadBalancer IP CIDR block.
+type ServiceLoadBalancerIPBlock struct {
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BGPConfigurationList contains a list of BGPConfiguration resources.
+type BGPConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []BGPConfiguration `json:"items"`
+}
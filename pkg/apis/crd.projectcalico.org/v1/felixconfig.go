@@ -245,6 +245,10 @@ type FelixConfigurationSpec struct {
 	// like Application layer policy. [Default: Empty]
 	PolicySyncPathPrefix string `json:"policySyncPathPrefix,omitempty"`
 
+	// EgressIPSupport defines the support mode for the egress IP function: Disabled, EnabledPerNamespace, or
+	// EnabledPerNamespaceOrPerPod, where the per-pod egress annotations override the namespace ones. [Default: Disabled]
+	EgressIPSupport string `json:"egressIPSupport,omitempty"`
+
 	// UsageReportingEnabled reports anonymous Calico version number and cluster size to projectcalico.org. Logs warnings returned by the usage
 	// server. For example, if a significant security vulnerability has been discovered in the version of Calico being used. [Default: true]
 	UsageReportingEnabled *bool `json:"usageReportingEnabled,omitempty"`
@@ -59,8 +59,20 @@ type IPPoolSpec struct {
 
 	// Allows IPPool to allocate for a specific node by label selector.
 	NodeSelector string `json:"nodeSelector,omitempty" validate:"omitempty,selector"`
+
+	// AllowedUse controls what the IP pool will be used for. If not specified or empty, defaults to
+	// ["Tunnel", "Workload"] for back-compatibility.
+	AllowedUses []IPPoolAllowedUse `json:"allowedUses,omitempty"`
 }
 
+type IPPoolAllowedUse string
+
+const (
+	IPPoolAllowedUseWorkload     IPPoolAllowedUse = "Workload"
+	IPPoolAllowedUseTunnel       IPPoolAllowedUse = "Tunnel"
+	IPPoolAllowedUseLoadBalancer IPPoolAllowedUse = "LoadBalancer"
+)
+
 type VXLANMode string
 
 const (
@@ -28,8 +28,40 @@ type KubeControllersConfiguration struct {
 
 // KubeControllersConfigurationSpec contains the values of the Kubernetes controllers configuration.
 type KubeControllersConfigurationSpec struct {
+	// LogSeverityScreen is the log severity above which logs are sent to the stdout. [Default: Info]
+	LogSeverityScreen string `json:"logSeverityScreen,omitempty"`
+
 	// PrometheusMetricsPort is the TCP port that the Prometheus metrics server should bind to. Set to 0 to disable. [Default: 9094]
 	PrometheusMetricsPort *int `json:"prometheusMetricsPort,omitempty"`
+
+	// Controllers enables and configures individual Kubernetes controllers
+	Controllers ControllersConfig `json:"controllers"`
+}
+
+// ControllersConfig enables and configures individual Kubernetes controllers
+type ControllersConfig struct {
+	// Node enables and configures the node controller. Enabled by default, set to nil to disable.
+	Node *NodeControllerConfig `json:"node,omitempty"`
+}
+
+// NodeControllerConfig configures the node controller, which automatically cleans up configuration
+// for nodes that no longer exist. Optionally, it can create host endpoints for all Kubernetes nodes.
+type NodeControllerConfig struct {
+	// ReconcilerPeriod is the period to perform reconciliation with the Calico datastore. [Default: 5m]
+	ReconcilerPeriod *metav1.Duration `json:"reconcilerPeriod,omitempty"`
+
+	// HostEndpoint controls syncing nodes to host endpoints. Disabled by default, set to nil to disable.
+	HostEndpoint *AutoHostEndpointConfig `json:"hostEndpoint,omitempty"`
+
+	// LeakGracePeriod is the period used by the controller to determine if an IP address has been leaked.
+	// Set to 0 to disable IP garbage collection. [Default: 15m]
+	LeakGracePeriod *metav1.Duration `json:"leakGracePeriod,omitempty"`
+}
+
+// AutoHostEndpointConfig configures the automatic creation of host endpoints.
+type AutoHostEndpointConfig struct {
+	// AutoCreate enables automatic creation of host endpoints for every node. [Default: Disabled]
+	AutoCreate string `json:"autoCreate,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -36,10 +36,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&IPPool{},
 		&IPPoolList{},
+		&IPAMBlock{},
+		&IPAMBlockList{},
 		&FelixConfiguration{},
 		&FelixConfigurationList{},
 		&KubeControllersConfiguration{},
 		&KubeControllersConfigurationList{},
+		&BGPConfiguration{},
+		&BGPConfigurationList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -27,6 +27,129 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoHostEndpointConfig) DeepCopyInto(out *AutoHostEndpointConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoHostEndpointConfig.
+func (in *AutoHostEndpointConfig) DeepCopy() *AutoHostEndpointConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoHostEndpointConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPConfiguration) DeepCopyInto(out *BGPConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPConfiguration.
+func (in *BGPConfiguration) DeepCopy() *BGPConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPConfigurationList) DeepCopyInto(out *BGPConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BGPConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPConfigurationList.
+func (in *BGPConfigurationList) DeepCopy() *BGPConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPConfigurationSpec) DeepCopyInto(out *BGPConfigurationSpec) {
+	*out = *in
+	if in.ServiceClusterIPs != nil {
+		in, out := &in.ServiceClusterIPs, &out.ServiceClusterIPs
+		*out = make([]ServiceClusterIPBlock, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceExternalIPs != nil {
+		in, out := &in.ServiceExternalIPs, &out.ServiceExternalIPs
+		*out = make([]ServiceExternalIPBlock, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceLoadBalancerIPs != nil {
+		in, out := &in.ServiceLoadBalancerIPs, &out.ServiceLoadBalancerIPs
+		*out = make([]ServiceLoadBalancerIPBlock, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPConfigurationSpec.
+func (in *BGPConfigurationSpec) DeepCopy() *BGPConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllersConfig) DeepCopyInto(out *ControllersConfig) {
+	*out = *in
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(NodeControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllersConfig.
+func (in *ControllersConfig) DeepCopy() *ControllersConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllersConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FelixConfiguration) DeepCopyInto(out *FelixConfiguration) {
 	*out = *in
@@ -431,12 +554,90 @@ func (in *FelixConfigurationSpec) DeepCopy() *FelixConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMBlock) DeepCopyInto(out *IPAMBlock) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMBlock.
+func (in *IPAMBlock) DeepCopy() *IPAMBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IPAMBlock) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMBlockList) DeepCopyInto(out *IPAMBlockList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IPAMBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMBlockList.
+func (in *IPAMBlockList) DeepCopy() *IPAMBlockList {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMBlockList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IPAMBlockList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMBlockSpec) DeepCopyInto(out *IPAMBlockSpec) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMBlockSpec.
+func (in *IPAMBlockSpec) DeepCopy() *IPAMBlockSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMBlockSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPPool) DeepCopyInto(out *IPPool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
@@ -492,6 +693,11 @@ func (in *IPPoolList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPPoolSpec) DeepCopyInto(out *IPPoolSpec) {
 	*out = *in
+	if in.AllowedUses != nil {
+		in, out := &in.AllowedUses, &out.AllowedUses
+		*out = make([]IPPoolAllowedUse, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolSpec.
@@ -570,6 +776,7 @@ func (in *KubeControllersConfigurationSpec) DeepCopyInto(out *KubeControllersCon
 		*out = new(int)
 		**out = **in
 	}
+	in.Controllers.DeepCopyInto(&out.Controllers)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersConfigurationSpec.
@@ -582,6 +789,36 @@ func (in *KubeControllersConfigurationSpec) DeepCopy() *KubeControllersConfigura
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeControllerConfig) DeepCopyInto(out *NodeControllerConfig) {
+	*out = *in
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HostEndpoint != nil {
+		in, out := &in.HostEndpoint, &out.HostEndpoint
+		*out = new(AutoHostEndpointConfig)
+		**out = **in
+	}
+	if in.LeakGracePeriod != nil {
+		in, out := &in.LeakGracePeriod, &out.LeakGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeControllerConfig.
+func (in *NodeControllerConfig) DeepCopy() *NodeControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtoPort) DeepCopyInto(out *ProtoPort) {
 	*out = *in
@@ -611,3 +848,48 @@ func (in *RouteTableRange) DeepCopy() *RouteTableRange {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClusterIPBlock) DeepCopyInto(out *ServiceClusterIPBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClusterIPBlock.
+func (in *ServiceClusterIPBlock) DeepCopy() *ServiceClusterIPBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClusterIPBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceExternalIPBlock) DeepCopyInto(out *ServiceExternalIPBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExternalIPBlock.
+func (in *ServiceExternalIPBlock) DeepCopy() *ServiceExternalIPBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceExternalIPBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceLoadBalancerIPBlock) DeepCopyInto(out *ServiceLoadBalancerIPBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceLoadBalancerIPBlock.
+func (in *ServiceLoadBalancerIPBlock) DeepCopy() *ServiceLoadBalancerIPBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceLoadBalancerIPBlock)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,6 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// +k8s:deepcopy-gen=package,register
+// +groupName=k8s.cni.cncf.io
+
+package v1
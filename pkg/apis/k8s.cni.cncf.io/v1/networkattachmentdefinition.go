@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	KindNetworkAttachmentDefinition     = "NetworkAttachmentDefinition"
+	KindNetworkAttachmentDefinitionList = "NetworkAttachmentDefinitionList"
+
+	// NetworkAttachmentDefinitionCRDName is the name of the CRD that Multus installs for the
+	// NetworkAttachmentDefinition resource.
+	NetworkAttachmentDefinitionCRDName = "network-attachment-definitions.k8s.cni.cncf.io"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkAttachmentDefinition contains information about a NetworkAttachmentDefinition resource of Multus,
+// which defines an additional network that pods can attach to.
+type NetworkAttachmentDefinition struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the NetworkAttachmentDefinition.
+	Spec NetworkAttachmentDefinitionSpec `json:"spec,omitempty"`
+}
+
+// NetworkAttachmentDefinitionSpec contains the specification for a NetworkAttachmentDefinition resource.
+type NetworkAttachmentDefinitionSpec struct {
+	// Config is the CNI configuration of the network, in JSON.
+	Config string `json:"config,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkAttachmentDefinitionList contains a list of NetworkAttachmentDefinition resources.
+type NetworkAttachmentDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []NetworkAttachmentDefinition `json:"items"`
+}
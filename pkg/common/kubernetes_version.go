@@ -59,3 +59,18 @@ func (v *VersionInfo) ProvidesCertV1API() bool {
 	}
 	return false
 }
+
+// ProvidesPodSecurityPolicyAPI returns if policy/v1beta1 PodSecurityPolicies are supported given the current k8s
+// version. The API was removed in v1.25.
+func (v *VersionInfo) ProvidesPodSecurityPolicyAPI() bool {
+	if v != nil && (v.Major > 1 || (v.Major == 1 && v.Minor >= 25)) {
+		return false
+	}
+	return true
+}
+
+// ProvidesPolicyV1PodDisruptionBudgetAPI returns if policy/v1 PodDisruptionBudgets are supported given the current k8s
+// version. The API was added in v1.21.
+func (v *VersionInfo) ProvidesPolicyV1PodDisruptionBudgetAPI() bool {
+	return v != nil && (v.Major > 1 || (v.Major == 1 && v.Minor >= 21))
+}
@@ -74,3 +74,17 @@ var _ = Describe("Test get Kubernetes version", func() {
 		Expect(err).To(Equal(fmt.Errorf("failed to parse k8s minor version: %s", invalidMinor)))
 	})
 })
+
+var _ = Describe("Test Kubernetes version API support", func() {
+	It("should provide the PodSecurityPolicy API before v1.25", func() {
+		Expect((&VersionInfo{Major: 1, Minor: 24}).ProvidesPodSecurityPolicyAPI()).To(BeTrue())
+		Expect((&VersionInfo{Major: 1, Minor: 25}).ProvidesPodSecurityPolicyAPI()).To(BeFalse())
+		Expect((&VersionInfo{Major: 2, Minor: 0}).ProvidesPodSecurityPolicyAPI()).To(BeFalse())
+	})
+
+	It("should provide the policy/v1 PodDisruptionBudget API from v1.21", func() {
+		Expect((&VersionInfo{Major: 1, Minor: 20}).ProvidesPolicyV1PodDisruptionBudgetAPI()).To(BeFalse())
+		Expect((&VersionInfo{Major: 1, Minor: 21}).ProvidesPolicyV1PodDisruptionBudgetAPI()).To(BeTrue())
+		Expect((&VersionInfo{Major: 2, Minor: 0}).ProvidesPolicyV1PodDisruptionBudgetAPI()).To(BeTrue())
+	})
+})
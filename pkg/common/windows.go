@@ -34,6 +34,11 @@ const (
 	CalicoWindowsUpgradeVolumePath            = `c:\CalicoUpgrade`
 	CalicoWindowsUpgradeLabel                 = "projectcalico.org/windows-upgrade"
 	CalicoWindowsUpgradeLabelInProgress       = "in-progress"
+	CalicoWindowsUpgradeLabelDraining         = "draining"
+	CalicoWindowsUpgradeLabelFailed           = "failed"
+	CalicoWindowsUpgradeStartedAnnotation     = "projectcalico.org/windows-upgrade-started"
+	CalicoWindowsUpgradePausedAnnotation      = "projectcalico.org/windows-upgrade-paused"
+	CalicoWindowsUpgradeCordonedAnnotation    = "projectcalico.org/windows-upgrade-cordoned"
 	CalicoVersionAnnotation                   = "projectcalico.org/version"
 	CalicoVariantAnnotation                   = "projectcalico.org/variant"
 	CalicoWindowsUpgradeTaintKey              = "projectcalico.org/windows-upgrade"
@@ -53,6 +53,16 @@ var (
 		Version: "master",
 		Image:   "calico/windows-upgrade",
 	}
+
+	ComponentCalicoVPP = component{
+		Version: "master",
+		Image:   "calicovpp/vpp",
+	}
+
+	ComponentCalicoVPPAgent = component{
+		Version: "master",
+		Image:   "calicovpp/agent",
+	}
 	ComponentOperatorInit = component{
 		Version: version.VERSION,
 		Image:   "tigera/operator",
@@ -67,5 +77,7 @@ var (
 		ComponentOperatorInit,
 		ComponentCalicoAPIServer,
 		ComponentWindows,
+		ComponentCalicoVPP,
+		ComponentCalicoVPPAgent,
 	}
 )
@@ -221,6 +221,11 @@ var (
 		Version: "master",
 		Image:   "tigera/calico-windows-upgrade",
 	}
+
+	ComponentEgressGateway = component{
+		Version: "master",
+		Image:   "tigera/egress-gateway",
+	}
 	EnterpriseComponents = []component{
 		ComponentAPIServer,
 		ComponentComplianceBenchmarker,
@@ -263,5 +268,6 @@ var (
 		ComponentESGateway,
 		ComponentTigeraWindows,
 		ComponentDikastes,
+		ComponentEgressGateway,
 	}
 )
@@ -125,4 +125,35 @@ var _ = Describe("test GetReference", func() {
 			Entry("a CSR init image correctly", ComponentCSRInitContainer, "userpath/key-cert-provisioner", "@sha256:tigerakeycertprovisionerhash"),
 		)
 	})
+	Context("with an ImageSet with per-image overrides", func() {
+		DescribeTable("should render",
+			func(c component, expected string) {
+				is := &op.ImageSet{
+					Spec: op.ImageSetSpec{
+						Images: []op.Image{
+							{Image: "calico/node", Digest: "sha256:caliconodehash", Registry: "mirror.io/"},
+							{Image: "tigera/cnx-node", Digest: "sha256:tigeracnxnodehash", Repository: "mirrored/cnx-node"},
+							{Image: "tigera/operator", Digest: "sha256:tigeraoperatorhash", Registry: "mirror.io/", Repository: "tigera-operator"},
+							{Image: "tigera/key-cert-provisioner", Digest: "sha256:tigerakeycertprovisionerhash"},
+						},
+					},
+				}
+				Expect(GetReference(c, "quay.io/extra/", "userpath", "", is)).To(Equal(expected))
+			},
+			Entry("an image with a registry override", ComponentCalicoNode, "mirror.io/userpath/node@sha256:caliconodehash"),
+			Entry("an image with a repository override", ComponentTigeraNode, "quay.io/extra/mirrored/cnx-node@sha256:tigeracnxnodehash"),
+			Entry("an image with both overrides", ComponentOperatorInit, "mirror.io/tigera-operator@sha256:tigeraoperatorhash"),
+			Entry("an image without overrides", ComponentCSRInitContainer, "quay.io/extra/userpath/key-cert-provisioner@sha256:tigerakeycertprovisionerhash"),
+		)
+	})
+	Context("with an ImageSet entry without a digest", func() {
+		It("should render the version tag", func() {
+			is := &op.ImageSet{
+				Spec: op.ImageSetSpec{
+					Images: []op.Image{{Image: "calico/node"}},
+				},
+			}
+			Expect(GetReference(ComponentCalicoNode, "quay.io/extra/", "userpath", "", is)).To(Equal(fmt.Sprintf("quay.io/extra/userpath/node:%s", ComponentCalicoNode.Version)))
+		})
+	})
 })
@@ -40,7 +40,9 @@ func GetReference(c component, registry, imagePath, imagePrefix string, is *oper
 			ComponentCalicoKubeControllers,
 			ComponentFlexVolume,
 			ComponentCalicoAPIServer,
-			ComponentWindows:
+			ComponentWindows,
+			ComponentCalicoVPP,
+			ComponentCalicoVPPAgent:
 
 			registry = CalicoRegistry
 		case ComponentOperatorInit:
@@ -66,6 +68,19 @@ func GetReference(c component, registry, imagePath, imagePrefix string, is *oper
 
 	for _, img := range is.Spec.Images {
 		if img.Image == c.Image {
+			// An ImageSet entry may carry its own registry and repository, which take
+			// precedence over the values configured on the Installation.
+			if img.Registry != "" {
+				registry = img.Registry
+			}
+			if img.Repository != "" {
+				image = img.Repository
+			}
+			// Entries without a digest keep the version tag. ImageSets created by users
+			// always have a digest; the operator omits it when it only overrides some images.
+			if img.Digest == "" {
+				return fmt.Sprintf("%s%s:%s", registry, image, c.Version), nil
+			}
 			return fmt.Sprintf("%s%s@%s", registry, image, img.Digest), nil
 		}
 	}
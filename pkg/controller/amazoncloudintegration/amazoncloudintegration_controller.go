@@ -136,7 +136,7 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 
@@ -163,6 +163,7 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 		r.SetDegraded("Error querying installation", err, reqLogger)
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 	if variant != operatorv1.TigeraSecureEnterprise {
 		r.SetDegraded(fmt.Sprintf("Waiting for network to be %s", operatorv1.TigeraSecureEnterprise), fmt.Errorf(""), reqLogger)
 		return reconcile.Result{}, nil
@@ -198,7 +199,7 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 		return reconcile.Result{}, err
 	}
 
-	err = imageset.ApplyImageSet(ctx, r.client, variant, component)
+	err = imageset.ApplyImageSet(ctx, r.client, variant, network, component)
 	if err != nil {
 		r.SetDegraded("Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
@@ -209,6 +210,9 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 		return reconcile.Result{}, err
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(instance.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
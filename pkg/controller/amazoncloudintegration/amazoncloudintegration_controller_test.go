@@ -68,9 +68,11 @@ var _ = Describe("amazoncloudintegration controller tests", func() {
 		mockStatus.On("AddStatefulSets", mock.Anything).Return()
 		mockStatus.On("AddCronJobs", mock.Anything)
 		mockStatus.On("IsAvailable").Return(true)
-		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("OnCRFound", mock.Anything).Return()
 		mockStatus.On("ClearDegraded")
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("SetObservedGeneration", mock.Anything)
+		mockStatus.On("SetRenderedVariant", mock.Anything)
 
 		Expect(cli.Create(ctx, &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
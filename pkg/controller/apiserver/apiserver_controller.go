@@ -17,6 +17,8 @@ package apiserver
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/tigera/operator/pkg/common"
@@ -24,7 +26,10 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -41,6 +46,7 @@ import (
 	"github.com/tigera/operator/pkg/controller/utils/imageset"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 )
 
 var log = logf.Log.WithName("controller_apiserver")
@@ -61,6 +67,8 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileAPISe
 		enterpriseCRDsExist: opts.EnterpriseCRDExists,
 		status:              status.New(mgr.GetClient(), "apiserver", opts.KubernetesVersion),
 		clusterDomain:       opts.ClusterDomain,
+		usePSP:              opts.UsePSP,
+		usePolicyV1PDB:      opts.UsePolicyV1PDB,
 	}
 	r.status.Run(opts.ShutdownContext)
 	return r
@@ -90,6 +98,11 @@ func add(mgr manager.Manager, r *ReconcileAPIServer) error {
 		return fmt.Errorf("apiserver-controller failed to watch ConfigMap %s: %w", render.K8sSvcEndpointConfigMapName, err)
 	}
 
+	// Watch all ConfigMaps in the operator namespace, since the audit policy ConfigMap is named by the APIServer.
+	if err = utils.AddConfigMapWatch(c, "", common.OperatorNamespace()); err != nil {
+		return fmt.Errorf("apiserver-controller failed to watch ConfigMaps: %w", err)
+	}
+
 	if r.amazonCRDExists {
 		err = c.Watch(&source.Kind{Type: &operatorv1.AmazonCloudIntegration{}}, &handler.EnqueueRequestForObject{})
 		if err != nil {
@@ -116,6 +129,9 @@ func add(mgr manager.Manager, r *ReconcileAPIServer) error {
 				return fmt.Errorf("apiserver-controller failed to watch the Secret resource: %v", err)
 			}
 		}
+		if err = utils.AddSecretsWatch(c, render.VoltronTunnelNextSecretName, common.OperatorNamespace()); err != nil {
+			return fmt.Errorf("apiserver-controller failed to watch the Secret resource: %v", err)
+		}
 
 		// Watch for changes to authentication
 		err = c.Watch(&source.Kind{Type: &operatorv1.Authentication{}}, &handler.EnqueueRequestForObject{})
@@ -158,6 +174,8 @@ type ReconcileAPIServer struct {
 	enterpriseCRDsExist bool
 	status              status.StatusManager
 	clusterDomain       string
+	usePSP              bool
+	usePolicyV1PDB      bool
 }
 
 // Reconcile reads that state of the cluster for a APIServer object and makes changes based on the state read
@@ -180,7 +198,7 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 		reqLogger.Error(err, fmt.Sprintf("An error occurred when querying the APIServer resource: %s", msg))
 		return reconcile.Result{}, err
 	}
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
 	// Query for the installation object.
@@ -193,12 +211,18 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 	if variant == "" {
 		r.status.SetDegraded(fmt.Sprintf("Waiting for Installation to be ready"), "")
 		return reconcile.Result{}, nil
 	}
 	ns := rmeta.APIServerNamespace(variant)
 
+	if err = validateAPIServer(instance, variant); err != nil {
+		r.status.SetDegraded("Invalid APIServer configuration", err.Error())
+		return reconcile.Result{}, nil
+	}
+
 	// We need separate certificates for OSS vs Enterprise.
 	secretName := render.ProjectCalicoApiServerTLSSecretName(network.Variant)
 	operatorManagedApiserverSecret := true
@@ -245,10 +269,21 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 
 	// Query enterprise-only data.
 	var tunnelCASecret *v1.Secret
+	var nextTunnelCASecret *v1.Secret
+	var retiredTunnelCA bool
 	var amazon *operatorv1.AmazonCloudIntegration
 	var managementCluster *operatorv1.ManagementCluster
 	var managementClusterConnection *operatorv1.ManagementClusterConnection
+	var auditPolicy *v1.ConfigMap
 	if variant == operatorv1.TigeraSecureEnterprise {
+		if instance.Spec.AuditPolicy != nil {
+			auditPolicy, err = getAuditPolicy(ctx, r.client, instance.Spec.AuditPolicy.ConfigMapName)
+			if err != nil {
+				r.status.SetDegraded("Error reading audit policy ConfigMap", err.Error())
+				return reconcile.Result{}, err
+			}
+		}
+
 		managementCluster, err = utils.GetManagementCluster(ctx, r.client)
 		if err != nil {
 			log.Error(err, "Error reading ManagementCluster")
@@ -282,6 +317,31 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 				r.status.SetDegraded("Error validating TLS certificate", err.Error())
 				return reconcile.Result{}, err
 			}
+
+			nextTunnelCASecret, err = utils.ValidateCertPair(r.client,
+				common.OperatorNamespace(),
+				render.VoltronTunnelNextSecretName,
+				render.VoltronTunnelSecretKeyName,
+				render.VoltronTunnelSecretCertName,
+			)
+			if err != nil {
+				log.Error(err, "Invalid TLS Cert")
+				r.status.SetDegraded("Error validating TLS certificate", err.Error())
+				return reconcile.Result{}, err
+			}
+
+			tunnelCASecret, nextTunnelCASecret, retiredTunnelCA, err = rotateTunnelCA(managementCluster, tunnelCASecret, nextTunnelCASecret)
+			if err != nil {
+				log.Error(err, "Invalid tunnel CA rotation")
+				r.status.SetDegraded("Invalid tunnel CA rotation", err.Error())
+				return reconcile.Result{}, err
+			}
+
+			if err = validateManagedClusters(managementCluster); err != nil {
+				log.Error(err, "Invalid managed cluster registrations")
+				r.status.SetDegraded("Invalid managed cluster registrations", err.Error())
+				return reconcile.Result{}, err
+			}
 		}
 
 		if r.amazonCRDExists {
@@ -306,6 +366,10 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	if tlsSecret != nil && operatorManagedApiserverSecret {
 		components = append(components, render.NewPassthrough(tlsSecret))
 	}
+	if retiredTunnelCA {
+		// The new CA replaces the current one. The secret it was published in is deleted once this is done.
+		components = append(components, render.NewPassthrough(tunnelCASecret))
+	}
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
 
@@ -313,7 +377,7 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	reqLogger.V(3).Info("rendering components")
 
 	apiServerCfg := render.APIServerConfiguration{
-		K8SServiceEndpoint:          k8sapi.Endpoint,
+		K8SServiceEndpoint:          k8sapi.Endpoint(),
 		Installation:                network,
 		ForceHostNetwork:            false,
 		ManagementCluster:           managementCluster,
@@ -323,7 +387,12 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 		PullSecrets:                 pullSecrets,
 		Openshift:                   r.provider == operatorv1.ProviderOpenShift,
 		TunnelCASecret:              tunnelCASecret,
+		NextTunnelCASecret:          nextTunnelCASecret,
 		ClusterDomain:               r.clusterDomain,
+		APIServer:                   &instance.Spec,
+		AuditPolicy:                 auditPolicy,
+		UsePSP:                      r.usePSP,
+		UsePolicyV1PDB:              r.usePolicyV1PDB,
 	}
 
 	component, err := render.APIServer(&apiServerCfg)
@@ -396,7 +465,14 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 		components = append(components, pc)
 	}
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, components...); err != nil {
+	tier, err := utils.AllowTigeraTier(ctx, r.client, network, components...)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, err
+	}
+	components = append(components, render.NetworkPolicies(network, tier, components...))
+
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, network, components...); err != nil {
 		log.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
@@ -408,6 +484,17 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 			return reconcile.Result{}, err
 		}
 	}
+	if retiredTunnelCA {
+		next := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.VoltronTunnelNextSecretName, Namespace: common.OperatorNamespace()}}
+		if err := r.client.Delete(ctx, next); err != nil && !errors.IsNotFound(err) {
+			r.status.SetDegraded("Error deleting the retired tunnel CA", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(instance.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
@@ -417,6 +504,30 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// The allow-tigera tier is served by the API server, so it can only be created now that the API server is
+	// available. The other controllers render their policies once they see it.
+	if networkpolicy.Enabled(network) && !tier.Exists {
+		if err := handler.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(networkpolicy.Tier()), r.status); err != nil {
+			r.status.SetDegraded("Error creating allow-tigera tier", err.Error())
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// The ManagedCluster resources are also served by the API server. The tunnel CA they are signed by is the one
+	// given to the API server, which generates it on the first reconcile.
+	if managementCluster != nil {
+		registration, err := r.managedClusterRegistration(ctx, managementCluster, apiServerCfg.TunnelCASecret, nextTunnelCASecret)
+		if err != nil {
+			r.status.SetDegraded("Error registering the managed clusters", err.Error())
+			return reconcile.Result{}, err
+		}
+		if err := handler.CreateOrUpdateOrDelete(ctx, registration, r.status); err != nil {
+			r.status.SetDegraded("Error registering the managed clusters", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Everything is available - update the CRD status.
 	instance.Status.State = operatorv1.TigeraStatusReady
 	if err = r.client.Status().Update(ctx, instance); err != nil {
@@ -424,3 +535,132 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 	return reconcile.Result{}, nil
 }
+
+// validateAPIServer checks that the APIServer only configures what is supported for the variant.
+func validateAPIServer(instance *operatorv1.APIServer, variant operatorv1.ProductVariant) error {
+	if variant == operatorv1.TigeraSecureEnterprise {
+		return nil
+	}
+	if instance.Spec.AuditPolicy != nil {
+		return fmt.Errorf("auditPolicy is only supported for %s", operatorv1.TigeraSecureEnterprise)
+	}
+	if instance.Spec.QueryServer != nil {
+		return fmt.Errorf("queryServer is only supported for %s", operatorv1.TigeraSecureEnterprise)
+	}
+	return nil
+}
+
+// getAuditPolicy returns the ConfigMap with the audit policy, which must be in the operator namespace.
+func getAuditPolicy(ctx context.Context, cli client.Client, name string) (*v1.ConfigMap, error) {
+	cm := &v1.ConfigMap{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: name, Namespace: common.OperatorNamespace()}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("audit policy ConfigMap %s not found in namespace %s", name, common.OperatorNamespace())
+		}
+		return nil, err
+	}
+	if _, ok := cm.Data[render.AuditPolicyConfigMapKey]; !ok {
+		return nil, fmt.Errorf("audit policy ConfigMap %s does not have a %q key", name, render.AuditPolicyConfigMapKey)
+	}
+	return cm, nil
+}
+
+// rotateTunnelCA returns the current and the next tunnel CA for the phase of the tunnel CA rotation configured on the
+// ManagementCluster. The next CA is generated when the rotation is published, and marked once it is reissued. When the
+// rotation is retired, the next CA is returned as the current one, renamed after the current CA secret, and retired is
+// true: the caller then replaces the current CA secret and deletes the next one. A next CA that was never reissued
+// cannot be retired, since the managed clusters would still hold certificates signed by the current CA.
+func rotateTunnelCA(mc *operatorv1.ManagementCluster, current, next *v1.Secret) (*v1.Secret, *v1.Secret, bool, error) {
+	if mc.Spec.TunnelCARotation == nil {
+		return current, next, false, nil
+	}
+	switch *mc.Spec.TunnelCARotation {
+	case operatorv1.TunnelCARotationPublish:
+		if next == nil {
+			next = render.CreateVoltronTunnelSecret(render.VoltronTunnelNextSecretName)
+		}
+	case operatorv1.TunnelCARotationReissue:
+		if next == nil {
+			return nil, nil, false, fmt.Errorf("the tunnel CA rotation must be in the %s phase before the %s phase",
+				operatorv1.TunnelCARotationPublish, operatorv1.TunnelCARotationReissue)
+		}
+		if next.Annotations[render.TunnelCAReissuedAnnotation] != "true" {
+			next = next.DeepCopy()
+			if next.Annotations == nil {
+				next.Annotations = map[string]string{}
+			}
+			next.Annotations[render.TunnelCAReissuedAnnotation] = "true"
+		}
+	case operatorv1.TunnelCARotationRetire:
+		if next != nil {
+			if next.Annotations[render.TunnelCAReissuedAnnotation] != "true" {
+				return nil, nil, false, fmt.Errorf("the tunnel CA rotation must be in the %s phase before the %s phase",
+					operatorv1.TunnelCARotationReissue, operatorv1.TunnelCARotationRetire)
+			}
+			retired := next.DeepCopy()
+			retired.TypeMeta = metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"}
+			retired.ObjectMeta = metav1.ObjectMeta{Name: render.VoltronTunnelSecretName, Namespace: common.OperatorNamespace()}
+			return retired, nil, true, nil
+		}
+	}
+	return current, next, false, nil
+}
+
+// validateManagedClusters validates the managed clusters registered on the ManagementCluster.
+func validateManagedClusters(mc *operatorv1.ManagementCluster) error {
+	if len(mc.Spec.ManagedClusters) == 0 {
+		return nil
+	}
+	if mc.Spec.Address == "" {
+		return fmt.Errorf("spec.address is required to register managed clusters")
+	}
+	names := map[string]bool{}
+	for _, c := range mc.Spec.ManagedClusters {
+		// The name is also used as a label value and in the name of the secret of the cluster.
+		if errs := validation.IsDNS1123Label(c.Name); len(errs) > 0 {
+			return fmt.Errorf("managed cluster name %q is invalid: %s", c.Name, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsDNS1123Subdomain(render.ManagedClusterSecretName(c.Name)); len(errs) > 0 {
+			return fmt.Errorf("managed cluster name %q is invalid: %s", c.Name, strings.Join(errs, ", "))
+		}
+		if names[c.Name] {
+			return fmt.Errorf("managed cluster %s is registered more than once", c.Name)
+		}
+		names[c.Name] = true
+	}
+	return nil
+}
+
+// managedClusterRegistration returns the component registering the managed clusters of the ManagementCluster. The
+// credentials of the clusters that are already registered are reused, unless they need to be re-issued, and the
+// clusters that are no longer registered are deleted.
+func (r *ReconcileAPIServer) managedClusterRegistration(ctx context.Context, mc *operatorv1.ManagementCluster, current, next *v1.Secret) (render.Component, error) {
+	secrets := &v1.SecretList{}
+	if err := r.client.List(ctx, secrets, client.InNamespace(common.OperatorNamespace()), client.HasLabels{render.ManagedClusterLabel}); err != nil {
+		return nil, err
+	}
+	existing := map[string]*v1.Secret{}
+	for i := range secrets.Items {
+		existing[secrets.Items[i].Labels[render.ManagedClusterLabel]] = &secrets.Items[i]
+	}
+
+	cfg := &render.ManagedClusterRegistrationConfiguration{
+		ManagementCluster:  mc,
+		TunnelCASecret:     current,
+		NextTunnelCASecret: next,
+	}
+	signingCA := render.TunnelSigningCA(mc, current, next)
+	for _, c := range mc.Spec.ManagedClusters {
+		creds, err := render.ManagedClusterCredentials(c.Name, signingCA, existing[c.Name])
+		if err != nil {
+			return nil, err
+		}
+		cfg.Credentials = append(cfg.Credentials, creds)
+		delete(existing, c.Name)
+	}
+	for cluster := range existing {
+		cfg.StaleClusters = append(cfg.StaleClusters, cluster)
+	}
+	sort.Strings(cfg.StaleClusters)
+	return render.ManagedClusterRegistration(cfg)
+}
@@ -17,17 +17,20 @@ package apiserver
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/stretchr/testify/mock"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
@@ -37,6 +40,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -71,11 +75,13 @@ var _ = Describe("apiserver controller tests", func() {
 		mockStatus.On("AddStatefulSets", mock.Anything).Return()
 		mockStatus.On("AddCronJobs", mock.Anything)
 		mockStatus.On("IsAvailable").Return(true)
-		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("OnCRFound", mock.Anything).Return()
 		mockStatus.On("ClearDegraded")
 		mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 		mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("SetObservedGeneration", mock.Anything)
+		mockStatus.On("SetRenderedVariant", mock.Anything)
 
 		variant = operatorv1.TigeraSecureEnterprise
 	})
@@ -298,6 +304,206 @@ var _ = Describe("apiserver controller tests", func() {
 			Expect(cli.Get(ctx, client.ObjectKey{Namespace: common.OperatorNamespace(), Name: render.PacketCaptureCertSecret}, secret)).ShouldNot(HaveOccurred())
 			Expect(secret.GetOwnerReferences()).To(HaveLen(1))
 		})
+
+		Context("management cluster", func() {
+			var r ReconcileAPIServer
+			var mc *operatorv1.ManagementCluster
+
+			BeforeEach(func() {
+				setUpApiServerInstallation(cli, ctx, variant, nil)
+				mc = &operatorv1.ManagementCluster{ObjectMeta: metav1.ObjectMeta{Name: utils.DefaultTSEEInstanceKey.Name}}
+				Expect(cli.Create(ctx, mc)).NotTo(HaveOccurred())
+
+				r = ReconcileAPIServer{
+					client:   cli,
+					scheme:   scheme,
+					provider: operatorv1.ProviderNone,
+					status:   mockStatus,
+				}
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+
+			setPhase := func(phase operatorv1.TunnelCARotationPhase) {
+				Expect(cli.Get(ctx, client.ObjectKey{Name: mc.Name}, mc)).NotTo(HaveOccurred())
+				mc.Spec.TunnelCARotation = &phase
+				Expect(cli.Update(ctx, mc)).NotTo(HaveOccurred())
+			}
+
+			getTunnelSecret := func(name string) (*v1.Secret, error) {
+				s := &v1.Secret{}
+				return s, cli.Get(ctx, client.ObjectKey{Name: name, Namespace: common.OperatorNamespace()}, s)
+			}
+
+			It("should publish a new CA and make it current once retired", func() {
+				current, err := getTunnelSecret(render.VoltronTunnelSecretName)
+				Expect(err).NotTo(HaveOccurred())
+
+				setPhase(operatorv1.TunnelCARotationPublish)
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				next, err := getTunnelSecret(render.VoltronTunnelNextSecretName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(next.Data[render.VoltronTunnelSecretCertName]).NotTo(Equal(current.Data[render.VoltronTunnelSecretCertName]))
+
+				bundle := &v1.ConfigMap{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: render.ManagementClusterCABundleConfigMapName, Namespace: common.OperatorNamespace()}, bundle)).NotTo(HaveOccurred())
+				Expect(bundle.Data[render.ManagementClusterCABundleKey]).To(ContainSubstring(string(current.Data[render.VoltronTunnelSecretCertName])))
+				Expect(bundle.Data[render.ManagementClusterCABundleKey]).To(ContainSubstring(string(next.Data[render.VoltronTunnelSecretCertName])))
+
+				setPhase(operatorv1.TunnelCARotationReissue)
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				next, err = getTunnelSecret(render.VoltronTunnelNextSecretName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(next.Annotations).To(HaveKeyWithValue(render.TunnelCAReissuedAnnotation, "true"))
+
+				setPhase(operatorv1.TunnelCARotationRetire)
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				retired, err := getTunnelSecret(render.VoltronTunnelSecretName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(retired.Data).To(Equal(next.Data))
+				_, err = getTunnelSecret(render.VoltronTunnelNextSecretName)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				Expect(cli.Get(ctx, client.ObjectKey{Name: render.ManagementClusterCABundleConfigMapName, Namespace: common.OperatorNamespace()}, bundle)).NotTo(HaveOccurred())
+				Expect(bundle.Data[render.ManagementClusterCABundleKey]).NotTo(ContainSubstring(string(current.Data[render.VoltronTunnelSecretCertName])))
+			})
+
+			It("should register the managed clusters and delete those that are no longer registered", func() {
+				Expect(cli.Get(ctx, client.ObjectKey{Name: mc.Name}, mc)).NotTo(HaveOccurred())
+				mc.Spec.Address = "example.com:1234"
+				mc.Spec.ManagedClusters = []operatorv1.ManagedClusterRegistration{{Name: "cluster-a"}, {Name: "cluster-b"}}
+				Expect(cli.Update(ctx, mc)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				for _, name := range []string{"cluster-a", "cluster-b"} {
+					managed := &v3.ManagedCluster{}
+					Expect(cli.Get(ctx, client.ObjectKey{Name: name}, managed)).NotTo(HaveOccurred())
+					Expect(managed.Annotations).To(HaveKey("certs.tigera.io/active-fingerprint"))
+					creds, err := getTunnelSecret(render.ManagedClusterSecretName(name))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(creds.Data).To(HaveKey(render.ManagedClusterManifestKey))
+				}
+				credsA, _ := getTunnelSecret(render.ManagedClusterSecretName("cluster-a"))
+
+				Expect(cli.Get(ctx, client.ObjectKey{Name: mc.Name}, mc)).NotTo(HaveOccurred())
+				mc.Spec.ManagedClusters = []operatorv1.ManagedClusterRegistration{{Name: "cluster-a"}}
+				Expect(cli.Update(ctx, mc)).NotTo(HaveOccurred())
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				By("keeping the credentials of the clusters that are still registered")
+				creds, err := getTunnelSecret(render.ManagedClusterSecretName("cluster-a"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(creds.Data[render.ManagedClusterCertKey]).To(Equal(credsA.Data[render.ManagedClusterCertKey]))
+
+				By("deleting the clusters that are no longer registered")
+				Expect(errors.IsNotFound(cli.Get(ctx, client.ObjectKey{Name: "cluster-b"}, &v3.ManagedCluster{}))).To(BeTrue())
+				_, err = getTunnelSecret(render.ManagedClusterSecretName("cluster-b"))
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should degrade when a managed cluster name is not a DNS label", func() {
+				mockStatus.On("SetDegraded", mock.Anything, mock.Anything).Return()
+
+				Expect(cli.Get(ctx, client.ObjectKey{Name: mc.Name}, mc)).NotTo(HaveOccurred())
+				mc.Spec.Address = "example.com:1234"
+				mc.Spec.ManagedClusters = []operatorv1.ManagedClusterRegistration{{Name: "Cluster.A"}}
+				Expect(cli.Update(ctx, mc)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).Should(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid managed cluster registrations", mock.MatchedBy(func(msg string) bool {
+					return strings.HasPrefix(msg, `managed cluster name "Cluster.A" is invalid`)
+				}))
+			})
+
+			It("should degrade when managed clusters are registered without an address", func() {
+				mockStatus.On("SetDegraded", mock.Anything, mock.Anything).Return()
+
+				Expect(cli.Get(ctx, client.ObjectKey{Name: mc.Name}, mc)).NotTo(HaveOccurred())
+				mc.Spec.ManagedClusters = []operatorv1.ManagedClusterRegistration{{Name: "cluster-a"}}
+				Expect(cli.Update(ctx, mc)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).Should(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid managed cluster registrations", "spec.address is required to register managed clusters")
+			})
+
+			It("should degrade when the new CA is retired before it is reissued", func() {
+				mockStatus.On("SetDegraded", mock.Anything, mock.Anything).Return()
+
+				setPhase(operatorv1.TunnelCARotationPublish)
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				setPhase(operatorv1.TunnelCARotationRetire)
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).Should(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid tunnel CA rotation",
+					"the tunnel CA rotation must be in the Reissue phase before the Retire phase")
+				_, err = getTunnelSecret(render.VoltronTunnelNextSecretName)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should degrade when the new CA is reissued before it is published", func() {
+				mockStatus.On("SetDegraded", mock.Anything, mock.Anything).Return()
+
+				setPhase(operatorv1.TunnelCARotationReissue)
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).Should(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid tunnel CA rotation", mock.Anything)
+			})
+		})
+
+		Context("audit policy", func() {
+			var r ReconcileAPIServer
+
+			BeforeEach(func() {
+				setUpApiServerInstallation(cli, ctx, variant, nil)
+				apiServer := &operatorv1.APIServer{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, apiServer)).NotTo(HaveOccurred())
+				apiServer.Spec.AuditPolicy = &operatorv1.APIServerAuditPolicy{ConfigMapName: "my-audit-policy"}
+				Expect(cli.Update(ctx, apiServer)).NotTo(HaveOccurred())
+
+				r = ReconcileAPIServer{
+					client:   cli,
+					scheme:   scheme,
+					provider: operatorv1.ProviderNone,
+					status:   mockStatus,
+				}
+			})
+
+			It("should merge the audit policy from the ConfigMap", func() {
+				Expect(cli.Create(ctx, &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-audit-policy", Namespace: common.OperatorNamespace()},
+					Data: map[string]string{"config": `apiVersion: audit.k8s.io/v1beta1
+kind: Policy
+rules:
+- level: Metadata
+`},
+				})).NotTo(HaveOccurred())
+
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cm := &v1.ConfigMap{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-audit-policy", Namespace: "tigera-system"}, cm)).NotTo(HaveOccurred())
+				Expect(cm.Data["config"]).To(ContainSubstring("level: Metadata"))
+				Expect(cm.Data["config"]).To(ContainSubstring("level: RequestResponse"))
+			})
+
+			It("should degrade when the ConfigMap does not exist", func() {
+				mockStatus.On("SetDegraded", mock.Anything, mock.Anything).Return()
+
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).Should(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Error reading audit policy ConfigMap", "audit policy ConfigMap my-audit-policy not found in namespace tigera-operator")
+			})
+		})
 	})
 })
 
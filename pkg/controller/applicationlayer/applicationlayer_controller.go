@@ -176,6 +176,7 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 		r.status.SetDegraded("Error querying for Application Layer", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.OnCRFound(applicationLayer)
 
 	preDefaultPatchFrom := client.MergeFrom(applicationLayer.DeepCopy())
 
@@ -208,6 +209,7 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	if variant != operatorv1.TigeraSecureEnterprise {
 		reqLogger.Error(err, fmt.Sprintf("Waiting for network to be %s", operatorv1.TigeraSecureEnterprise))
@@ -262,7 +264,7 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 
 	ch := utils.NewComponentHandler(log, r.client, r.scheme, applicationLayer)
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, installation, component); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
@@ -285,6 +287,9 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 		return reconcile.Result{}, err
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(applicationLayer.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
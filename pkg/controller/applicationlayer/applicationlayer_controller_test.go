@@ -68,11 +68,13 @@ var _ = Describe("Application layer controller tests", func() {
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
-			mockStatus.On("OnCRFound").Return()
+			mockStatus.On("OnCRFound", mock.Anything).Return()
 			mockStatus.On("OnCRNotFound").Return()
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("SetDegraded", "Waiting for LicenseKeyAPI to be ready", "").Return().Maybe()
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetObservedGeneration", mock.Anything)
+			mockStatus.On("SetRenderedVariant", mock.Anything)
 
 			r = ReconcileApplicationLayer{
 				client:          c,
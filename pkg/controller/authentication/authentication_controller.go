@@ -63,11 +63,12 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileAuthentication {
 	r := &ReconcileAuthentication{
-		client:        mgr.GetClient(),
-		scheme:        mgr.GetScheme(),
-		provider:      opts.DetectedProvider,
-		status:        status.New(mgr.GetClient(), "authentication", opts.KubernetesVersion),
-		clusterDomain: opts.ClusterDomain,
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		provider:       opts.DetectedProvider,
+		status:         status.New(mgr.GetClient(), "authentication", opts.KubernetesVersion),
+		clusterDomain:  opts.ClusterDomain,
+		usePolicyV1PDB: opts.UsePolicyV1PDB,
 	}
 	r.status.Run(opts.ShutdownContext)
 	return r
@@ -116,11 +117,12 @@ var _ reconcile.Reconciler = &ReconcileAuthentication{}
 
 // ReconcileAuthentication reconciles an Authentication object
 type ReconcileAuthentication struct {
-	client        client.Client
-	scheme        *runtime.Scheme
-	provider      oprv1.Provider
-	status        status.StatusManager
-	clusterDomain string
+	client         client.Client
+	scheme         *runtime.Scheme
+	provider       oprv1.Provider
+	status         status.StatusManager
+	clusterDomain  string
+	usePolicyV1PDB bool
 }
 
 // Reconciles the cluster state with the Authentication object that is found in the cluster.
@@ -140,7 +142,7 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 		}
 		return reconcile.Result{}, err
 	}
-	r.status.OnCRFound()
+	r.status.OnCRFound(authentication)
 	reqLogger.V(2).Info("Loaded config", "config", authentication)
 	preDefaultPatchFrom := client.MergeFrom(authentication.DeepCopy())
 
@@ -172,6 +174,7 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 	if variant != oprv1.TigeraSecureEnterprise {
 		log.Error(err, fmt.Sprintf("Waiting for network to be %s", oprv1.TigeraSecureEnterprise))
 		r.status.SetDegraded(fmt.Sprintf("Waiting for network to be %s", oprv1.TigeraSecureEnterprise), "")
@@ -257,33 +260,55 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 	hlr := utils.NewComponentHandler(log, r.client, r.scheme, authentication)
 
 	dexComponentCfg := &render.DexComponentConfiguration{
-		PullSecrets:   pullSecrets,
-		Openshift:     r.provider == oprv1.ProviderOpenShift,
-		Installation:  install,
-		DexConfig:     dexCfg,
-		ClusterDomain: r.clusterDomain,
-		DeleteDex:     disableDex,
+		PullSecrets:    pullSecrets,
+		Openshift:      r.provider == oprv1.ProviderOpenShift,
+		Installation:   install,
+		DexConfig:      dexCfg,
+		ClusterDomain:  r.clusterDomain,
+		DeleteDex:      disableDex,
+		UsePolicyV1PDB: r.usePolicyV1PDB,
 	}
 
 	// Render the desired objects from the CRD and create or update them.
 	reqLogger.V(3).Info("rendering components")
 	component := render.Dex(dexComponentCfg)
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, install, component); err != nil {
 		log.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
 	}
 
-	if err := hlr.CreateOrUpdateOrDelete(context.Background(), component, r.status); err != nil {
-		log.Error(err, "Error creating / updating resource")
-		r.status.SetDegraded("Error creating / updating resource", err.Error())
+	// The policy of Dex is removed along with Dex, which rendering the policies without an Installation does.
+	policyInstall := install
+	if disableDex {
+		policyInstall = nil
+	}
+
+	tier, err := utils.AllowTigeraTier(ctx, r.client, policyInstall, component)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
 		return reconcile.Result{}, err
 	}
 
+	for _, c := range []render.Component{component, render.NetworkPolicies(policyInstall, tier, component)} {
+		if err := hlr.CreateOrUpdateOrDelete(context.Background(), c, r.status); err != nil {
+			log.Error(err, "Error creating / updating resource")
+			r.status.SetDegraded("Error creating / updating resource", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(authentication.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
+	if result, requeue := utils.AllowTigeraTierRequeue(policyInstall, tier); requeue {
+		return result, nil
+	}
+
 	if !r.status.IsAvailable() {
 		// Schedule a kick to check again in the near future.
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
@@ -17,6 +17,9 @@ package clusterconnection
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"reflect"
+	"time"
 
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/options"
@@ -28,6 +31,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +45,10 @@ import (
 
 const controllerName = "clusterconnection-controller"
 
+// failoverTimeout is how long guardian may fail to reach a management cluster address before it is moved to the
+// next one.
+const failoverTimeout = 3 * time.Minute
+
 var log = logf.Log.WithName(controllerName)
 
 // Add creates a new ManagementClusterConnection Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -57,10 +65,11 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(cli client.Client, schema *runtime.Scheme, statusMgr status.StatusManager, p operatorv1.Provider, opts options.AddOptions) reconcile.Reconciler {
 	c := &ReconcileConnection{
-		Client:   cli,
-		Scheme:   schema,
-		Provider: p,
-		status:   statusMgr,
+		Client:        cli,
+		Scheme:        schema,
+		Provider:      p,
+		status:        statusMgr,
+		clusterDomain: opts.ClusterDomain,
 	}
 	c.status.Run(opts.ShutdownContext)
 	return c
@@ -100,6 +109,18 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("%s failed to watch Secret resource %s: %w", controllerName, render.PrometheusTLSSecretName, err)
 	}
 
+	// Watch for changes to the secrets guardian uses to reach the management cluster through a proxy.
+	for _, name := range []string{render.GuardianProxyCASecretName, render.GuardianProxyCredentialsSecretName} {
+		if err = utils.AddSecretsWatch(c, name, common.OperatorNamespace()); err != nil {
+			return fmt.Errorf("%s failed to watch Secret resource %s: %w", controllerName, name, err)
+		}
+	}
+
+	// Watch the guardian pods, whose readiness reflects the health of the tunnel.
+	if err = utils.AddNamespacedWatch(c, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: render.GuardianNamespace}}); err != nil {
+		return fmt.Errorf("%s failed to watch guardian pods: %w", controllerName, err)
+	}
+
 	if err = utils.AddNetworkWatch(c); err != nil {
 		return fmt.Errorf("%s failed to watch Network resource: %w", controllerName, err)
 	}
@@ -116,10 +137,11 @@ var _ reconcile.Reconciler = &ReconcileConnection{}
 
 // ReconcileConnection reconciles a ManagementClusterConnection object
 type ReconcileConnection struct {
-	Client   client.Client
-	Scheme   *runtime.Scheme
-	Provider operatorv1.Provider
-	status   status.StatusManager
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	Provider      operatorv1.Provider
+	status        status.StatusManager
+	clusterDomain string
 }
 
 // Reconcile reads that state of the cluster for a ManagementClusterConnection object and makes changes based on the
@@ -135,6 +157,7 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 	if err != nil {
 		return result, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	managementCluster, err := utils.GetManagementCluster(ctx, r.Client)
 	if err != nil {
@@ -161,7 +184,13 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	log.V(2).Info("Loaded ManagementClusterConnection config", "config", managementClusterConnection)
-	r.status.OnCRFound()
+	r.status.OnCRFound(managementClusterConnection)
+
+	if err := validateConnection(&managementClusterConnection.Spec); err != nil {
+		log.Error(err, "Invalid ManagementClusterConnection")
+		r.status.SetDegraded("Invalid ManagementClusterConnection", err.Error())
+		return reconcile.Result{}, nil
+	}
 
 	pullSecrets, err := utils.GetNetworkingPullSecrets(instl, r.Client)
 	if err != nil {
@@ -210,31 +239,200 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(render.GuardianNamespace), client.MatchingLabels{"k8s-app": render.GuardianName}); err != nil {
+		r.status.SetDegraded("Error querying guardian pods", err.Error())
+		return reconcile.Result{}, err
+	}
+	tunnel := tunnelStatus(pods.Items, managementClusterConnection.Status.Tunnel)
+	var failoverAfter time.Duration
+	tunnel.Addr, failoverAfter = activeAddr(&managementClusterConnection.Spec, tunnel, pods.Items, time.Now())
+	result.RequeueAfter = failoverAfter
+
 	ch := utils.NewComponentHandler(log, r.Client, r.Scheme, managementClusterConnection)
 	guardianCfg := &render.GuardianConfiguration{
-		URL:                  managementClusterConnection.Spec.ManagementClusterAddr,
+		URL:                  tunnel.Addr,
 		PullSecrets:          pullSecrets,
 		Openshift:            r.Provider == operatorv1.ProviderOpenShift,
 		Installation:         instl,
 		TunnelSecret:         tunnelSecret,
 		PacketCaptureSecret:  packetCaptureServerCertSecret,
 		PrometheusCertSecret: prometheusCertSecret,
+		ClusterDomain:        r.clusterDomain,
+	}
+	if proxy := managementClusterConnection.Spec.Proxy; proxy != nil {
+		// The proxy secrets are only read when a proxy is configured, so that leftover secrets cannot degrade the
+		// connection.
+		guardianCfg.ProxyURL = proxy.URL
+		guardianCfg.ProxyCASecret, err = r.getProxySecret(ctx, render.GuardianProxyCASecretName, corev1.TLSCertKey)
+		if err != nil {
+			r.status.SetDegraded(fmt.Sprintf("Failed to retrieve %s", render.GuardianProxyCASecretName), err.Error())
+			return reconcile.Result{}, err
+		}
+		guardianCfg.ProxyCredentialsSecret, err = r.getProxySecret(ctx, render.GuardianProxyCredentialsSecretName, render.GuardianProxyUsernameKey, render.GuardianProxyPasswordKey)
+		if err != nil {
+			r.status.SetDegraded(fmt.Sprintf("Failed to retrieve %s", render.GuardianProxyCredentialsSecretName), err.Error())
+			return reconcile.Result{}, err
+		}
 	}
 	component := render.Guardian(guardianCfg)
 
-	if err = imageset.ApplyImageSet(ctx, r.Client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.Client, variant, instl, component); err != nil {
 		log.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
 	}
 
-	if err := ch.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
-		r.status.SetDegraded("Error creating / updating resource", err.Error())
-		return result, err
+	tier, err := utils.AllowTigeraTier(ctx, r.Client, instl, component)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	for _, c := range []render.Component{component, render.NetworkPolicies(instl, tier, component)} {
+		if err := ch.CreateOrUpdateOrDelete(ctx, c, r.status); err != nil {
+			r.status.SetDegraded("Error creating / updating resource", err.Error())
+			return result, err
+		}
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(managementClusterConnection.Generation)
+
 	r.status.ClearDegraded()
 
+	if err := r.updateTunnelStatus(ctx, managementClusterConnection, tunnel); err != nil {
+		log.Error(err, "Error updating the tunnel status")
+		return reconcile.Result{}, err
+	}
+
+	if result, requeue := utils.AllowTigeraTierRequeue(instl, tier); requeue {
+		return result, nil
+	}
+
 	//We should create the Guardian deployment.
 	return result, nil
 }
+
+// validateConnection returns an error if the addresses or the proxy of the connection are not usable.
+func validateConnection(spec *operatorv1.ManagementClusterConnectionSpec) error {
+	for _, addr := range spec.FailoverAddrs {
+		if addr == "" {
+			return fmt.Errorf("spec.failoverAddrs must not contain empty addresses")
+		}
+	}
+	if spec.Proxy != nil {
+		u, err := url.Parse(spec.Proxy.URL)
+		if err != nil {
+			return fmt.Errorf("spec.proxy.url %q is invalid: %w", spec.Proxy.URL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("spec.proxy.url %q must be of the form http(s)://host[:port]", spec.Proxy.URL)
+		}
+	}
+	return nil
+}
+
+// getProxySecret returns the secret with the given name in the operator namespace, or nil if it doesn't exist.
+// An error is returned if the secret is missing any of the given keys.
+func (r *ReconcileConnection) getProxySecret(ctx context.Context, name string, keys ...string) (*corev1.Secret, error) {
+	secret, err := utils.GetSecret(ctx, r.Client, name, common.OperatorNamespace())
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if len(secret.Data[k]) == 0 {
+			return nil, fmt.Errorf("secret %s/%s does not contain the key %s", secret.Namespace, secret.Name, k)
+		}
+	}
+	return secret, nil
+}
+
+// updateTunnelStatus reports the health of the tunnel on the status of the ManagementClusterConnection.
+func (r *ReconcileConnection) updateTunnelStatus(ctx context.Context, mcc *operatorv1.ManagementClusterConnection, tunnel *operatorv1.ManagementClusterTunnelStatus) error {
+	if reflect.DeepEqual(tunnel, mcc.Status.Tunnel) {
+		return nil
+	}
+	mcc.Status.Tunnel = tunnel
+	return r.Client.Status().Update(ctx, mcc)
+}
+
+// activeAddr returns the management cluster address guardian should connect to and, while it is not connected and
+// there are other addresses to fail over to, how long to wait before checking again. Guardian is moved to the next
+// address once its pods for the current one have not been ready for failoverTimeout.
+func activeAddr(spec *operatorv1.ManagementClusterConnectionSpec, tunnel *operatorv1.ManagementClusterTunnelStatus, pods []corev1.Pod, now time.Time) (string, time.Duration) {
+	addrs := append([]string{spec.ManagementClusterAddr}, spec.FailoverAddrs...)
+	current := 0
+	for i, addr := range addrs {
+		if addr == tunnel.Addr {
+			current = i
+			break
+		}
+	}
+	if tunnel.Connected || len(addrs) == 1 {
+		return addrs[current], 0
+	}
+
+	// Only the pods connecting to the current address tell whether it is reachable. Pods that were never ready
+	// have been trying since they were created.
+	var failingSince time.Time
+	for _, pod := range pods {
+		if pod.Annotations[render.GuardianAddrAnnotation] != addrs[current] {
+			continue
+		}
+		since := pod.CreationTimestamp.Time
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+				since = cond.LastTransitionTime.Time
+			}
+		}
+		if failingSince.IsZero() || since.Before(failingSince) {
+			failingSince = since
+		}
+	}
+	if failingSince.IsZero() {
+		return addrs[current], failoverTimeout
+	}
+	if failing := now.Sub(failingSince); failing < failoverTimeout {
+		return addrs[current], failoverTimeout - failing
+	}
+	return addrs[(current+1)%len(addrs)], failoverTimeout
+}
+
+// tunnelStatus returns the tunnel status reported by the guardian pods. Guardian is only ready while its tunnel is
+// established, so the tunnel was connected when the pod last became ready. When it is not, the error is taken from
+// the last termination message of guardian, which falls back to its logs, or from the pod readiness.
+func tunnelStatus(pods []corev1.Pod, previous *operatorv1.ManagementClusterTunnelStatus) *operatorv1.ManagementClusterTunnelStatus {
+	tunnel := &operatorv1.ManagementClusterTunnelStatus{}
+	if previous != nil {
+		tunnel = previous.DeepCopy()
+	}
+	tunnel.Connected = false
+
+	var lastError string
+	for _, pod := range pods {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type != corev1.PodReady {
+				continue
+			}
+			if cond.Status == corev1.ConditionTrue {
+				tunnel.Connected = true
+				if tunnel.LastConnectTime == nil || tunnel.LastConnectTime.Before(&cond.LastTransitionTime) {
+					t := cond.LastTransitionTime
+					tunnel.LastConnectTime = &t
+				}
+			} else if cond.Message != "" {
+				lastError = cond.Message
+			}
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Message != "" {
+				lastError = cs.LastTerminationState.Terminated.Message
+			}
+		}
+	}
+	if !tunnel.Connected && lastError != "" {
+		tunnel.LastError = lastError
+	}
+	return tunnel
+}
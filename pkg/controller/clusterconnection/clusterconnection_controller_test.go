@@ -17,6 +17,7 @@ package clusterconnection_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
@@ -70,8 +71,10 @@ var _ = Describe("ManagementClusterConnection controller tests", func() {
 		mockStatus.On("AddCronJobs", mock.Anything)
 		mockStatus.On("ClearDegraded", mock.Anything)
 		mockStatus.On("SetDegraded", mock.Anything, mock.Anything)
-		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("OnCRFound", mock.Anything).Return()
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("SetObservedGeneration", mock.Anything)
+		mockStatus.On("SetRenderedVariant", mock.Anything)
 
 		r = clusterconnection.NewReconcilerWithShims(c, scheme, mockStatus, operatorv1.ProviderNone)
 		dpl = &appsv1.Deployment{
@@ -211,4 +214,159 @@ var _ = Describe("ManagementClusterConnection controller tests", func() {
 					"sha256:guardianhash")))
 		})
 	})
+
+	Context("proxy and failover", func() {
+		guardianEnv := func() []corev1.EnvVar {
+			d := appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: render.GuardianDeploymentName, Namespace: render.GuardianNamespace},
+			}
+			Expect(test.GetResource(c, &d)).To(BeNil())
+			return d.Spec.Template.Spec.Containers[0].Env
+		}
+
+		It("should not render an invalid proxy", func() {
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			cfg.Spec.Proxy = &operatorv1.ManagementClusterConnectionProxy{URL: "ftp://proxy.example.com"}
+			Expect(c.Update(ctx, cfg)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, env := range guardianEnv() {
+				Expect(env.Name).NotTo(Equal("HTTPS_PROXY"))
+			}
+		})
+
+		It("should ignore malformed proxy secrets when no proxy is configured", func() {
+			Expect(c.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.GuardianProxyCredentialsSecretName, Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{render.GuardianProxyUsernameKey: []byte("user")},
+			})).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			cfg.Spec.Proxy = nil
+			Expect(c.Update(ctx, cfg)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			By("failing once the proxy that needs them is configured")
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			cfg.Spec.Proxy = &operatorv1.ManagementClusterConnectionProxy{URL: "https://proxy.example.com:3128"}
+			Expect(c.Update(ctx, cfg)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).Should(HaveOccurred())
+
+			Expect(c.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.GuardianProxyCredentialsSecretName, Namespace: common.OperatorNamespace()},
+			})).NotTo(HaveOccurred())
+		})
+
+		It("should render the proxy", func() {
+			Expect(c.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.GuardianProxyCASecretName, Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("ca")},
+			})).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			cfg.Spec.Proxy = &operatorv1.ManagementClusterConnectionProxy{URL: "https://proxy.example.com:3128"}
+			Expect(c.Update(ctx, cfg)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(guardianEnv()).To(ContainElements(
+				corev1.EnvVar{Name: "HTTPS_PROXY", Value: "https://proxy.example.com:3128"},
+				corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/certs/proxy"},
+			))
+			Expect(test.GetResource(c, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.GuardianProxyCASecretName, Namespace: render.GuardianNamespace},
+			})).To(BeNil())
+		})
+
+		It("should fail over to the next address when guardian cannot connect", func() {
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			cfg.Spec.Proxy = nil
+			cfg.Spec.FailoverAddrs = []string{"127.0.0.2:12345"}
+			Expect(c.Update(ctx, cfg)).NotTo(HaveOccurred())
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "tigera-guardian-failover",
+					Namespace:   render.GuardianNamespace,
+					Labels:      map[string]string{"k8s-app": render.GuardianName},
+					Annotations: map[string]string{render.GuardianAddrAnnotation: "127.0.0.1:12345"},
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.Now()}},
+				},
+			}
+			Expect(c.Create(ctx, pod)).NotTo(HaveOccurred())
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(guardianEnv()).To(ContainElement(corev1.EnvVar{Name: "GUARDIAN_VOLTRON_URL", Value: "127.0.0.1:12345"}))
+
+			By("moving guardian to the failover address once it has failed for long enough")
+			pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-10 * time.Minute))
+			Expect(c.Update(ctx, pod)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(guardianEnv()).To(ContainElement(corev1.EnvVar{Name: "GUARDIAN_VOLTRON_URL", Value: "127.0.0.2:12345"}))
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			Expect(cfg.Status.Tunnel.Addr).To(Equal("127.0.0.2:12345"))
+
+			By("staying on the failover address while the pods for it are starting")
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(guardianEnv()).To(ContainElement(corev1.EnvVar{Name: "GUARDIAN_VOLTRON_URL", Value: "127.0.0.2:12345"}))
+
+			Expect(c.Delete(ctx, pod)).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			cfg.Spec.FailoverAddrs = nil
+			cfg.Status.Tunnel = nil
+			Expect(c.Update(ctx, cfg)).NotTo(HaveOccurred())
+			Expect(c.Status().Update(ctx, cfg)).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("tunnel status", func() {
+		It("should report the health of the tunnel from the guardian pods", func() {
+			connectTime := metav1.NewTime(metav1.Now().Rfc3339Copy().Add(-time.Minute))
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tigera-guardian-abc",
+					Namespace: render.GuardianNamespace,
+					Labels:    map[string]string{"k8s-app": render.GuardianName},
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: connectTime}},
+				},
+			}
+			Expect(c.Create(ctx, pod)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			Expect(cfg.Status.Tunnel).NotTo(BeNil())
+			Expect(cfg.Status.Tunnel.Connected).To(BeTrue())
+			Expect(cfg.Status.Tunnel.LastConnectTime.Equal(&connectTime)).To(BeTrue())
+
+			By("reporting the error guardian terminated with once the tunnel is down")
+			pod.Status.Conditions[0].Status = corev1.ConditionFalse
+			pod.Status.Conditions[0].LastTransitionTime = metav1.Now()
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name: render.GuardianDeploymentName,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "failed to dial 127.0.0.1:12345"},
+				},
+			}}
+			Expect(c.Update(ctx, pod)).NotTo(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).NotTo(HaveOccurred())
+			Expect(cfg.Status.Tunnel.Connected).To(BeFalse())
+			Expect(cfg.Status.Tunnel.LastError).To(Equal("failed to dial 127.0.0.1:12345"))
+			Expect(cfg.Status.Tunnel.LastConnectTime.Equal(&connectTime)).To(BeTrue())
+		})
+	})
 })
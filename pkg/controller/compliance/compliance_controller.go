@@ -82,6 +82,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		provider:        opts.DetectedProvider,
 		status:          status.New(mgr.GetClient(), "compliance", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
+		usePSP:          opts.UsePSP,
 		licenseAPIReady: licenseAPIReady,
 	}
 	r.status.Run(opts.ShutdownContext)
@@ -159,6 +160,7 @@ type ReconcileCompliance struct {
 	provider        operatorv1.Provider
 	status          status.StatusManager
 	clusterDomain   string
+	usePSP          bool
 	licenseAPIReady *utils.ReadyFlag
 }
 
@@ -194,7 +196,7 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		r.status.SetDegraded("Error querying compliance", err.Error())
 		return reconcile.Result{}, err
 	}
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
 	if !utils.IsAPIServerReady(r.client, reqLogger) {
@@ -227,6 +229,7 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	pullSecrets, err := utils.GetNetworkingPullSecrets(network, r.client)
 	if err != nil {
@@ -377,6 +380,7 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		KeyValidatorConfig:          keyValidatorConfig,
 		ClusterDomain:               r.clusterDomain,
 		HasNoLicense:                hasNoLicense,
+		UsePSP:                      r.usePSP,
 	}
 	// Render the desired objects from the CRD and create or update them.
 	component, err := render.Compliance(complianceCfg)
@@ -386,13 +390,19 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, network, component); err != nil {
 		log.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
 	}
 
-	components = append(components, component)
+	tier, err := utils.AllowTigeraTier(ctx, r.client, network, component)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	components = append(components, component, render.NetworkPolicies(network, tier, component))
 	for _, component := range components {
 		if err := handler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
 			r.status.SetDegraded("Error creating / updating / deleting resource", err.Error())
@@ -406,6 +416,9 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, nil
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(instance.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
@@ -420,5 +433,8 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 	if err = r.client.Status().Update(ctx, instance); err != nil {
 		return reconcile.Result{}, err
 	}
+	if result, requeue := utils.AllowTigeraTierRequeue(network, tier); requeue {
+		return result, nil
+	}
 	return reconcile.Result{}, nil
 }
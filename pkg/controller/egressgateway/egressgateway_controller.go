@@ -0,0 +1,451 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/egressgateway"
+)
+
+var log = logf.Log.WithName("controller_egressgateway")
+
+// Add creates a new EgressGateway Controller and adds it to the Manager.
+// The Manager will set fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, opts options.AddOptions) error {
+	if !opts.EnterpriseCRDExists {
+		// No need to start this controller.
+		return nil
+	}
+
+	reconciler := newReconciler(mgr, opts)
+
+	c, err := controller.New("egressgateway-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	if err != nil {
+		return err
+	}
+
+	return add(mgr, c)
+}
+
+// newReconciler returns a new *reconcile.Reconciler.
+func newReconciler(mgr manager.Manager, opts options.AddOptions) reconcile.Reconciler {
+	r := &ReconcileEgressGateway{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		status:         status.New(mgr.GetClient(), "egressgateway", opts.KubernetesVersion),
+		usePolicyV1PDB: opts.UsePolicyV1PDB,
+	}
+	r.status.Run(opts.ShutdownContext)
+	return r
+}
+
+// add adds watches for resources that are available at startup.
+func add(mgr manager.Manager, c controller.Controller) error {
+	var err error
+
+	// Watch for changes to primary resource EgressGateway.
+	err = c.Watch(&source.Kind{Type: &operatorv1.EgressGateway{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	if err = imageset.AddImageSetWatch(c); err != nil {
+		return fmt.Errorf("egressgateway-controller failed to watch ImageSet: %w", err)
+	}
+
+	if err = utils.AddNetworkWatch(c); err != nil {
+		log.V(5).Info("Failed to create network watch", "err", err)
+		return fmt.Errorf("egressgateway-controller failed to watch Tigera network resource: %v", err)
+	}
+
+	// Watch for changes to the IPPools, which the IP pools of the egress gateways must not overlap.
+	err = c.Watch(&source.Kind{Type: &crdv1.IPPool{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("egressgateway-controller failed to watch IPPool resource: %w", err)
+	}
+
+	// Watch for changes to FelixConfiguration.
+	err = c.Watch(&source.Kind{Type: &crdv1.FelixConfiguration{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("egressgateway-controller failed to watch FelixConfiguration resource: %w", err)
+	}
+
+	return nil
+}
+
+// Blank assignment to verify that ReconcileEgressGateway implements reconcile.Reconciler.
+var _ reconcile.Reconciler = &ReconcileEgressGateway{}
+
+// ReconcileEgressGateway reconciles the EgressGateway objects. All of them are reconciled together, since they
+// share a TigeraStatus and their IP pools must not overlap.
+type ReconcileEgressGateway struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver.
+	client client.Client
+	scheme *runtime.Scheme
+	status status.StatusManager
+
+	// usePolicyV1PDB is true if the cluster serves PodDisruptionBudgets from the policy/v1 API.
+	usePolicyV1PDB bool
+}
+
+// Reconcile reads that state of the cluster for the EgressGateway objects and makes changes
+// based on the state read and what is in their Spec.
+func (r *ReconcileEgressGateway) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling EgressGateways")
+
+	gateways := operatorv1.EgressGatewayList{}
+	if err := r.client.List(ctx, &gateways); err != nil {
+		reqLogger.Error(err, "Error querying for EgressGateways")
+		r.status.SetDegraded("Error querying for EgressGateways", err.Error())
+		return reconcile.Result{}, err
+	}
+	pools := crdv1.IPPoolList{}
+	if err := r.client.List(ctx, &pools); err != nil {
+		reqLogger.Error(err, "Error querying for IPPools")
+		r.status.SetDegraded("Error querying for IPPools", err.Error())
+		return reconcile.Result{}, err
+	}
+	stalePools := staleIPPools(gateways.Items, pools.Items)
+	for _, pool := range stalePools {
+		// The Deployment of a deleted egress gateway is garbage collected, so stop monitoring it.
+		if gw, ok := gatewayOfIPPool(pool); ok {
+			r.status.RemoveDeployments(gw)
+		}
+	}
+
+	if len(gateways.Items) == 0 {
+		reqLogger.Info("EgressGateway objects not found")
+		if err := r.deleteStaleIPPools(ctx, stalePools); err != nil {
+			reqLogger.Error(err, "Error deleting the IPPools of deleted EgressGateways")
+		}
+		r.status.OnCRNotFound()
+		return reconcile.Result{}, nil
+	}
+	r.status.OnCRFound(conditionsObjects(gateways.Items)...)
+
+	variant, installation, err := utils.GetInstallation(ctx, r.client)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			reqLogger.Error(err, "Installation not found")
+			r.setDegraded(ctx, gateways.Items, "Installation not found", err.Error())
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error querying installation")
+		r.setDegraded(ctx, gateways.Items, "Error querying installation", err.Error())
+		return reconcile.Result{}, err
+	}
+	r.status.SetRenderedVariant(variant)
+
+	if variant != operatorv1.TigeraSecureEnterprise {
+		reqLogger.Error(err, fmt.Sprintf("Waiting for network to be %s", operatorv1.TigeraSecureEnterprise))
+		r.setDegraded(ctx, gateways.Items, fmt.Sprintf("Waiting for network to be %s", operatorv1.TigeraSecureEnterprise), "")
+		return reconcile.Result{}, nil
+	}
+
+	if err = validateInstallation(installation); err != nil {
+		reqLogger.Error(err, err.Error())
+		r.setDegraded(ctx, gateways.Items, err.Error(), "")
+		return reconcile.Result{}, nil
+	}
+
+	// An invalid EgressGateway is reported as degraded on its own status, and the valid ones are still rolled out.
+	var valid []operatorv1.EgressGateway
+	for i := range gateways.Items {
+		gw := &gateways.Items[i]
+		preDefault := gw.DeepCopy()
+		fillDefaults(gw)
+
+		if err = validateEgressGateway(gw); err != nil {
+			reqLogger.Error(err, err.Error())
+			if err = r.setInvalid(ctx, preDefault, err.Error()); err != nil {
+				reqLogger.Error(err, "Failed to report the invalid EgressGateway", "namespace", gw.Namespace, "name", gw.Name)
+				r.setDegraded(ctx, gateways.Items, "Failed to report the invalid EgressGateway", err.Error())
+				return reconcile.Result{}, err
+			}
+			continue
+		}
+
+		// Write the defaults back to the datastore.
+		if !reflect.DeepEqual(preDefault.Spec, gw.Spec) {
+			if err = r.client.Patch(ctx, gw, client.MergeFrom(preDefault)); err != nil {
+				reqLogger.Error(err, "Failed to write defaults to EgressGateway", "namespace", gw.Namespace, "name", gw.Name)
+				r.setDegraded(ctx, gateways.Items, "Failed to write defaults to EgressGateway", err.Error())
+				return reconcile.Result{}, err
+			}
+		}
+		valid = append(valid, *gw)
+	}
+	r.status.OnCRFound(conditionsObjects(valid)...)
+
+	if err = validateIPPoolOverlaps(valid, pools.Items); err != nil {
+		reqLogger.Error(err, err.Error())
+		r.setDegraded(ctx, valid, err.Error(), "")
+		return reconcile.Result{}, nil
+	}
+
+	pullSecrets, err := utils.GetNetworkingPullSecrets(installation, r.client)
+	if err != nil {
+		reqLogger.Error(err, "Error retrieving pull secrets")
+		r.setDegraded(ctx, valid, "Error retrieving pull secrets", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if err = r.patchFelixConfiguration(ctx); err != nil {
+		reqLogger.Error(err, "Error patching felix configuration")
+		r.setDegraded(ctx, valid, "Error patching felix configuration", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// The IPPools are cluster scoped, so they can't be owned by the EgressGateways. They are created before the
+	// Deployments, so that the egress gateway pods can be assigned their addresses.
+	ch := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+	if err = ch.CreateOrUpdateOrDelete(ctx, egressgateway.IPPools(valid, stalePools), nil); err != nil {
+		reqLogger.Error(err, "Error creating / updating resource")
+		r.setDegraded(ctx, valid, "Error creating / updating resource", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	for i := range valid {
+		gw := &valid[i]
+		component := egressgateway.EgressGateway(&egressgateway.Config{
+			Installation:   installation,
+			PullSecrets:    pullSecrets,
+			EgressGateway:  gw,
+			UsePolicyV1PDB: r.usePolicyV1PDB,
+		})
+
+		if err = imageset.ApplyImageSet(ctx, r.client, variant, installation, component); err != nil {
+			reqLogger.Error(err, "Error with images from ImageSet")
+			r.setDegraded(ctx, valid, "Error with images from ImageSet", err.Error())
+			return reconcile.Result{}, err
+		}
+
+		ch := utils.NewComponentHandler(log, r.client, r.scheme, gw)
+		if err = ch.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
+			reqLogger.Error(err, "Error creating / updating resource")
+			r.setDegraded(ctx, valid, "Error creating / updating resource", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Clear the degraded bit if we've reached this far.
+	r.status.ClearDegraded()
+
+	if !r.status.IsAvailable() {
+		// Schedule a kick to check again in the near future, hopefully by then things will be available.
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// Everything is available - update the CRD status.
+	for i := range valid {
+		gw := &valid[i]
+		if gw.Status.State == operatorv1.TigeraStatusReady {
+			continue
+		}
+		gw.Status.State = operatorv1.TigeraStatusReady
+		if err = r.client.Status().Update(ctx, gw); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// setDegraded reports the egress gateways as degraded, and clears the state of those that were reported ready.
+func (r *ReconcileEgressGateway) setDegraded(ctx context.Context, gateways []operatorv1.EgressGateway, reason, msg string) {
+	r.status.SetDegraded(reason, msg)
+	for i := range gateways {
+		gw := &gateways[i]
+		if gw.Status.State == "" {
+			continue
+		}
+		gw.Status.State = ""
+		if err := r.client.Status().Update(ctx, gw); err != nil {
+			log.Error(err, "Failed to clear the state of EgressGateway", "namespace", gw.Namespace, "name", gw.Name)
+		}
+	}
+}
+
+// setInvalid reports an EgressGateway with an invalid spec as not ready and degraded on its own status. The
+// conditions of the valid egress gateways are reported by the status manager.
+func (r *ReconcileEgressGateway) setInvalid(ctx context.Context, gw *operatorv1.EgressGateway, msg string) error {
+	old := gw.Status.DeepCopy()
+	gw.Status.State = ""
+	meta.SetStatusCondition(&gw.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1.ComponentReady),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: gw.Generation,
+		Reason:             "InvalidSpec",
+		Message:            msg,
+	})
+	meta.SetStatusCondition(&gw.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1.ComponentDegraded),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: gw.Generation,
+		Reason:             "InvalidSpec",
+		Message:            msg,
+	})
+	if reflect.DeepEqual(*old, gw.Status) {
+		return nil
+	}
+	return r.client.Status().Update(ctx, gw)
+}
+
+// conditionsObjects returns the egress gateways as the CRs that the status manager reports the conditions on.
+func conditionsObjects(gateways []operatorv1.EgressGateway) []status.ConditionsObject {
+	objs := make([]status.ConditionsObject, len(gateways))
+	for i := range gateways {
+		objs[i] = &gateways[i]
+	}
+	return objs
+}
+
+// patchFelixConfiguration enables the egress IP support of Felix, and the policy sync API through which Felix
+// reports the health of the egress gateways, unless they are already enabled.
+func (r *ReconcileEgressGateway) patchFelixConfiguration(ctx context.Context) error {
+	// Fetch any existing default FelixConfiguration object.
+	fc := &crdv1.FelixConfiguration{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: "default"}, fc)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	patchFrom := client.MergeFrom(fc.DeepCopy())
+
+	updated := false
+	if fc.Spec.EgressIPSupport == "" || fc.Spec.EgressIPSupport == "Disabled" {
+		fc.Spec.EgressIPSupport = "EnabledPerNamespaceOrPerPod"
+		updated = true
+	}
+	if fc.Spec.PolicySyncPathPrefix == "" {
+		fc.Spec.PolicySyncPathPrefix = egressgateway.PolicySyncPathPrefix
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+
+	log.Info("Enabling egress IP support in FelixConfiguration", "egressIPSupport", fc.Spec.EgressIPSupport)
+	if fc.ResourceVersion == "" {
+		fc.ObjectMeta.Name = "default"
+		return r.client.Create(ctx, fc)
+	}
+	return r.client.Patch(ctx, fc, patchFrom)
+}
+
+func (r *ReconcileEgressGateway) deleteStaleIPPools(ctx context.Context, stalePools []string) error {
+	if len(stalePools) == 0 {
+		return nil
+	}
+	ch := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+	return ch.CreateOrUpdateOrDelete(ctx, egressgateway.IPPools(nil, stalePools), nil)
+}
+
+// staleIPPools returns the sorted names of the dedicated IPPools of egress gateways that no longer exist.
+func staleIPPools(gateways []operatorv1.EgressGateway, pools []crdv1.IPPool) []string {
+	desired := map[string]bool{}
+	for i := range gateways {
+		desired[egressgateway.IPPoolName(&gateways[i])] = true
+	}
+	var stale []string
+	for _, p := range pools {
+		if p.Labels[render.IPPoolManagedByLabel] == egressgateway.IPPoolManagedByLabelValue && !desired[p.Name] {
+			stale = append(stale, p.Name)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// gatewayOfIPPool returns the egress gateway that the dedicated IPPool was created for. Namespaces cannot contain
+// dots, so the namespace ends at the first dot after the prefix.
+func gatewayOfIPPool(pool string) (types.NamespacedName, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(pool, egressgateway.IPPoolNamePrefix), ".", 2)
+	if len(parts) != 2 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, true
+}
+
+// fillDefaults populates the default values onto an EgressGateway.
+func fillDefaults(gw *operatorv1.EgressGateway) {
+	if gw.Spec.Replicas == nil {
+		var one int32 = 1
+		gw.Spec.Replicas = &one
+	}
+	if gw.Spec.IPPool.BlockSize == nil {
+		var blockSize int32 = egressgateway.DefaultBlockSize
+		gw.Spec.IPPool.BlockSize = &blockSize
+	}
+	if gw.Spec.LogSeverity == nil {
+		severity := egressgateway.DefaultLogSeverity
+		gw.Spec.LogSeverity = &severity
+	}
+	if gw.Spec.FailureDetection == nil {
+		gw.Spec.FailureDetection = &operatorv1.EgressGatewayFailureDetection{}
+	}
+	fd := gw.Spec.FailureDetection
+	if fd.HealthTimeoutDataStoreSeconds == nil {
+		var timeout int32 = egressgateway.DefaultHealthTimeoutDataStoreSeconds
+		fd.HealthTimeoutDataStoreSeconds = &timeout
+	}
+	if p := fd.ICMPProbe; p != nil {
+		if p.IntervalSeconds == nil {
+			var interval int32 = egressgateway.DefaultICMPProbeIntervalSeconds
+			p.IntervalSeconds = &interval
+		}
+		if p.TimeoutSeconds == nil {
+			var timeout int32 = egressgateway.DefaultICMPProbeTimeoutSeconds
+			p.TimeoutSeconds = &timeout
+		}
+	}
+	if p := fd.HTTPProbe; p != nil {
+		if p.IntervalSeconds == nil {
+			var interval int32 = egressgateway.DefaultHTTPProbeIntervalSeconds
+			p.IntervalSeconds = &interval
+		}
+		if p.TimeoutSeconds == nil {
+			var timeout int32 = egressgateway.DefaultHTTPProbeTimeoutSeconds
+			p.TimeoutSeconds = &timeout
+		}
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/egressgateway"
+	"github.com/tigera/operator/test"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Egress gateway controller tests", func() {
+	var c client.Client
+	var ctx context.Context
+	var r ReconcileEgressGateway
+	var scheme *runtime.Scheme
+	var mockStatus *status.MockStatus
+
+	BeforeEach(func() {
+		// The schema contains all objects that should be known to the fake client when the test runs.
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(policyv1beta1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(operatorv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		// Create a client that will have a crud interface of k8s objects.
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+
+		mockStatus = &status.MockStatus{}
+		mockStatus.On("AddDaemonsets", mock.Anything).Return()
+		mockStatus.On("AddDeployments", mock.Anything).Return()
+		mockStatus.On("IsAvailable").Return(true)
+		mockStatus.On("AddStatefulSets", mock.Anything).Return()
+		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("RemoveDeployments", mock.Anything)
+		mockStatus.On("OnCRFound", mock.Anything).Return()
+		mockStatus.On("OnCRNotFound").Return()
+		mockStatus.On("ClearDegraded")
+		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("SetObservedGeneration", mock.Anything)
+		mockStatus.On("SetRenderedVariant", mock.Anything)
+
+		r = ReconcileEgressGateway{
+			client: c,
+			scheme: scheme,
+			status: mockStatus,
+		}
+
+		Expect(c.Create(ctx, &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: operatorv1.InstallationSpec{
+				Variant:  operatorv1.TigeraSecureEnterprise,
+				Registry: "some.registry.org/",
+				CNI: &operatorv1.CNISpec{
+					Type: operatorv1.PluginCalico,
+					IPAM: &operatorv1.IPAMSpec{Type: operatorv1.IPAMPluginCalico},
+				},
+			},
+			Status: operatorv1.InstallationStatus{
+				Variant: operatorv1.TigeraSecureEnterprise,
+			},
+		})).NotTo(HaveOccurred())
+
+		Expect(c.Create(ctx, &crdv1.IPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-ipv4-ippool"},
+			Spec:       crdv1.IPPoolSpec{CIDR: "192.168.0.0/16"},
+		})).NotTo(HaveOccurred())
+	})
+
+	createGateway := func(cidr string) {
+		Expect(c.Create(ctx, &operatorv1.EgressGateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"},
+			Spec: operatorv1.EgressGatewaySpec{
+				IPPool: operatorv1.EgressGatewayIPPool{CIDR: cidr},
+				Labels: map[string]string{"egress-code": "red"},
+			},
+		})).NotTo(HaveOccurred())
+	}
+
+	It("should render the egress gateway, its IPPool and the felix configuration", func() {
+		createGateway("10.10.10.0/30")
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "red", Namespace: "app"}})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("writing the defaults to the EgressGateway")
+		gw := operatorv1.EgressGateway{ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"}}
+		Expect(test.GetResource(c, &gw)).To(BeNil())
+		Expect(*gw.Spec.Replicas).To(BeEquivalentTo(1))
+		Expect(*gw.Spec.IPPool.BlockSize).To(BeEquivalentTo(egressgateway.DefaultBlockSize))
+		Expect(*gw.Spec.LogSeverity).To(Equal(egressgateway.DefaultLogSeverity))
+		Expect(*gw.Spec.FailureDetection.HealthTimeoutDataStoreSeconds).To(BeEquivalentTo(egressgateway.DefaultHealthTimeoutDataStoreSeconds))
+		Expect(gw.Status.State).To(Equal(operatorv1.TigeraStatusReady))
+
+		By("rendering the Deployment of the egress gateway")
+		d := appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"},
+		}
+		Expect(test.GetResource(c, &d)).To(BeNil())
+		Expect(d.OwnerReferences).To(HaveLen(1))
+		Expect(d.OwnerReferences[0].Kind).To(Equal("EgressGateway"))
+		Expect(d.Spec.Template.Labels).To(HaveKeyWithValue("egress-code", "red"))
+		Expect(d.Spec.Template.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("some.registry.org/%s:%s",
+			components.ComponentEgressGateway.Image, components.ComponentEgressGateway.Version)))
+
+		By("rendering the dedicated IPPool of the egress gateway")
+		pool := crdv1.IPPool{ObjectMeta: metav1.ObjectMeta{Name: "egress-gateway.app.red"}}
+		Expect(test.GetResource(c, &pool)).To(BeNil())
+		Expect(pool.Spec.CIDR).To(Equal("10.10.10.0/30"))
+		Expect(pool.Spec.NodeSelector).To(Equal("!all()"))
+		Expect(pool.Labels).To(HaveKeyWithValue(render.IPPoolManagedByLabel, egressgateway.IPPoolManagedByLabelValue))
+
+		By("enabling egress IP support in the felix configuration")
+		fc := crdv1.FelixConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		Expect(test.GetResource(c, &fc)).To(BeNil())
+		Expect(fc.Spec.EgressIPSupport).To(Equal("EnabledPerNamespaceOrPerPod"))
+		Expect(fc.Spec.PolicySyncPathPrefix).To(Equal(egressgateway.PolicySyncPathPrefix))
+	})
+
+	It("should not override the egress IP support of the felix configuration", func() {
+		Expect(c.Create(ctx, &crdv1.FelixConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: crdv1.FelixConfigurationSpec{
+				EgressIPSupport:      "EnabledPerNamespace",
+				PolicySyncPathPrefix: "/var/run/other",
+			},
+		})).NotTo(HaveOccurred())
+		createGateway("10.10.10.0/30")
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		fc := crdv1.FelixConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		Expect(test.GetResource(c, &fc)).To(BeNil())
+		Expect(fc.Spec.EgressIPSupport).To(Equal("EnabledPerNamespace"))
+		Expect(fc.Spec.PolicySyncPathPrefix).To(Equal("/var/run/other"))
+	})
+
+	It("should degrade when the IPPool of the egress gateway overlaps with another IPPool", func() {
+		mockStatus.On("SetDegraded", "spec.ipPool.cidr 192.168.10.0/30 of EgressGateway app/red overlaps with IPPool default-ipv4-ippool", "").Return()
+		createGateway("192.168.10.0/30")
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "spec.ipPool.cidr 192.168.10.0/30 of EgressGateway app/red overlaps with IPPool default-ipv4-ippool", "")
+
+		err = c.Get(ctx, types.NamespacedName{Name: "red", Namespace: "app"}, &appsv1.Deployment{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should degrade when the Installation does not use Calico IPAM", func() {
+		mockStatus.On("SetDegraded", "EgressGateways require the Calico CNI plugin and IPAM", "").Return()
+		installation := operatorv1.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		Expect(test.GetResource(c, &installation)).To(BeNil())
+		installation.Spec.CNI.IPAM.Type = operatorv1.IPAMPluginHostLocal
+		Expect(c.Update(ctx, &installation)).NotTo(HaveOccurred())
+		createGateway("10.10.10.0/30")
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "EgressGateways require the Calico CNI plugin and IPAM", "")
+	})
+
+	It("should clear the state of the egress gateways when degraded", func() {
+		mockStatus.On("SetDegraded", "EgressGateways require the Calico CNI plugin and IPAM", "").Return()
+		createGateway("10.10.10.0/30")
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+		gw := operatorv1.EgressGateway{ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"}}
+		Expect(test.GetResource(c, &gw)).To(BeNil())
+		Expect(gw.Status.State).To(Equal(operatorv1.TigeraStatusReady))
+
+		installation := operatorv1.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		Expect(test.GetResource(c, &installation)).To(BeNil())
+		installation.Spec.CNI.IPAM.Type = operatorv1.IPAMPluginHostLocal
+		Expect(c.Update(ctx, &installation)).NotTo(HaveOccurred())
+
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+		gw = operatorv1.EgressGateway{ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"}}
+		Expect(test.GetResource(c, &gw)).To(BeNil())
+		Expect(gw.Status.State).To(BeEmpty())
+	})
+
+	It("should degrade an invalid egress gateway and still render the valid ones", func() {
+		createGateway("10.10.10.0/30")
+		Expect(c.Create(ctx, &operatorv1.EgressGateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "blue", Namespace: "app", Generation: 3},
+			Spec: operatorv1.EgressGatewaySpec{
+				IPPool: operatorv1.EgressGatewayIPPool{CIDR: "fd00::/120"},
+				Labels: map[string]string{"egress-code": "blue"},
+			},
+		})).NotTo(HaveOccurred())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("rendering the valid egress gateway")
+		red := operatorv1.EgressGateway{ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"}}
+		Expect(test.GetResource(c, &red)).To(BeNil())
+		Expect(red.Status.State).To(Equal(operatorv1.TigeraStatusReady))
+		Expect(test.GetResource(c, &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"},
+		})).To(BeNil())
+		mockStatus.AssertCalled(GinkgoT(), "OnCRFound", mock.MatchedBy(func(crs []status.ConditionsObject) bool {
+			return len(crs) == 1 && crs[0].GetName() == "red"
+		}))
+
+		By("reporting the invalid egress gateway as degraded")
+		blue := operatorv1.EgressGateway{ObjectMeta: metav1.ObjectMeta{Name: "blue", Namespace: "app"}}
+		Expect(test.GetResource(c, &blue)).To(BeNil())
+		Expect(blue.Status.State).To(BeEmpty())
+		Expect(blue.Spec.Replicas).To(BeNil())
+		msg := `spec.ipPool.cidr "fd00::/120" of EgressGateway app/blue must be an IPv4 CIDR`
+		for i := range blue.Status.Conditions {
+			blue.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+		}
+		Expect(blue.Status.Conditions).To(ConsistOf(
+			metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, ObservedGeneration: blue.Generation, Reason: "InvalidSpec", Message: msg},
+			metav1.Condition{Type: "Degraded", Status: metav1.ConditionTrue, ObservedGeneration: blue.Generation, Reason: "InvalidSpec", Message: msg},
+		))
+		err = c.Get(ctx, types.NamespacedName{Name: "blue", Namespace: "app"}, &appsv1.Deployment{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		err = c.Get(ctx, types.NamespacedName{Name: "egress-gateway.app.blue"}, &crdv1.IPPool{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should delete the IPPool of a deleted egress gateway", func() {
+		createGateway("10.10.10.0/30")
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(test.GetResource(c, &crdv1.IPPool{ObjectMeta: metav1.ObjectMeta{Name: "egress-gateway.app.red"}})).To(BeNil())
+
+		Expect(c.Delete(ctx, &operatorv1.EgressGateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"},
+		})).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = c.Get(ctx, types.NamespacedName{Name: "egress-gateway.app.red"}, &crdv1.IPPool{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(test.GetResource(c, &crdv1.IPPool{ObjectMeta: metav1.ObjectMeta{Name: "default-ipv4-ippool"}})).To(BeNil())
+		mockStatus.AssertCalled(GinkgoT(), "RemoveDeployments", []types.NamespacedName{{Name: "red", Namespace: "app"}})
+		mockStatus.AssertCalled(GinkgoT(), "OnCRNotFound")
+	})
+})
+
+var _ = Describe("Egress gateway validation tests", func() {
+	var gw *operatorv1.EgressGateway
+
+	BeforeEach(func() {
+		gw = &operatorv1.EgressGateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"},
+			Spec: operatorv1.EgressGatewaySpec{
+				IPPool: operatorv1.EgressGatewayIPPool{CIDR: "10.10.10.0/30"},
+			},
+		}
+		fillDefaults(gw)
+	})
+
+	It("should accept a valid EgressGateway", func() {
+		Expect(validateEgressGateway(gw)).NotTo(HaveOccurred())
+	})
+
+	It("should reject an IPv6 CIDR", func() {
+		gw.Spec.IPPool.CIDR = "fd00::/120"
+		Expect(validateEgressGateway(gw)).To(HaveOccurred())
+	})
+
+	It("should reject a block size smaller than the prefix length of the CIDR", func() {
+		var blockSize int32 = 28
+		gw.Spec.IPPool.BlockSize = &blockSize
+		Expect(validateEgressGateway(gw)).To(HaveOccurred())
+	})
+
+	It("should reject invalid probes", func() {
+		gw.Spec.FailureDetection.ICMPProbe = &operatorv1.EgressGatewayICMPProbe{IPs: []string{"10.0.0.1"}}
+		gw.Spec.FailureDetection.HTTPProbe = &operatorv1.EgressGatewayHTTPProbe{URLs: []string{"http://example.com/health"}}
+		fillDefaults(gw)
+		Expect(validateEgressGateway(gw)).NotTo(HaveOccurred())
+
+		gw.Spec.FailureDetection.ICMPProbe.IPs = []string{"not-an-ip"}
+		Expect(validateEgressGateway(gw)).To(HaveOccurred())
+
+		gw.Spec.FailureDetection.ICMPProbe.IPs = []string{"10.0.0.1"}
+		gw.Spec.FailureDetection.HTTPProbe.URLs = []string{"ftp://example.com"}
+		Expect(validateEgressGateway(gw)).To(HaveOccurred())
+	})
+
+	It("should reject egress gateways with overlapping IPPools", func() {
+		other := gw.DeepCopy()
+		other.Name = "blue"
+		other.Spec.IPPool.CIDR = "10.10.10.2/31"
+		Expect(validateIPPoolOverlaps([]operatorv1.EgressGateway{*gw, *other}, nil)).To(HaveOccurred())
+
+		other.Spec.IPPool.CIDR = "10.10.10.4/31"
+		Expect(validateIPPoolOverlaps([]operatorv1.EgressGateway{*gw, *other}, nil)).NotTo(HaveOccurred())
+	})
+
+	It("should ignore the dedicated IPPools of the egress gateways", func() {
+		pool := crdv1.IPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   egressgateway.IPPoolName(gw),
+				Labels: map[string]string{render.IPPoolManagedByLabel: egressgateway.IPPoolManagedByLabelValue},
+			},
+			Spec: crdv1.IPPoolSpec{CIDR: "10.10.10.0/30"},
+		}
+		Expect(validateIPPoolOverlaps([]operatorv1.EgressGateway{*gw}, []crdv1.IPPool{pool})).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/egressgateway"
+)
+
+// validateInstallation checks that the Installation supports egress gateways, which are assigned their addresses
+// by Calico IPAM.
+func validateInstallation(install *operatorv1.InstallationSpec) error {
+	if install.CNI == nil || install.CNI.Type != operatorv1.PluginCalico ||
+		install.CNI.IPAM == nil || install.CNI.IPAM.Type != operatorv1.IPAMPluginCalico {
+		return fmt.Errorf("EgressGateways require the %s CNI plugin and IPAM", operatorv1.PluginCalico)
+	}
+	return nil
+}
+
+// validateEgressGateway validates an EgressGateway, with its defaults set.
+func validateEgressGateway(gw *operatorv1.EgressGateway) error {
+	ip, cidr, err := net.ParseCIDR(gw.Spec.IPPool.CIDR)
+	if err != nil || ip.To4() == nil {
+		return fmt.Errorf("spec.ipPool.cidr %q of EgressGateway %s/%s must be an IPv4 CIDR", gw.Spec.IPPool.CIDR, gw.Namespace, gw.Name)
+	}
+	if ones, _ := cidr.Mask.Size(); int(*gw.Spec.IPPool.BlockSize) < ones {
+		return fmt.Errorf("spec.ipPool.blockSize %d of EgressGateway %s/%s must not be smaller than the prefix length of spec.ipPool.cidr %s",
+			*gw.Spec.IPPool.BlockSize, gw.Namespace, gw.Name, gw.Spec.IPPool.CIDR)
+	}
+	if name := egressgateway.IPPoolName(gw); len(name) > validation.DNS1123SubdomainMaxLength {
+		return fmt.Errorf("the IP pool name %s of EgressGateway %s/%s must be no more than %d characters",
+			name, gw.Namespace, gw.Name, validation.DNS1123SubdomainMaxLength)
+	}
+
+	fd := gw.Spec.FailureDetection
+	if p := fd.ICMPProbe; p != nil {
+		if len(p.IPs) == 0 {
+			return fmt.Errorf("spec.failureDetection.icmpProbe.ips of EgressGateway %s/%s must not be empty", gw.Namespace, gw.Name)
+		}
+		for _, ip := range p.IPs {
+			if net.ParseIP(ip) == nil {
+				return fmt.Errorf("spec.failureDetection.icmpProbe.ips of EgressGateway %s/%s has an invalid IP address %q", gw.Namespace, gw.Name, ip)
+			}
+		}
+	}
+	if p := fd.HTTPProbe; p != nil {
+		if len(p.URLs) == 0 {
+			return fmt.Errorf("spec.failureDetection.httpProbe.urls of EgressGateway %s/%s must not be empty", gw.Namespace, gw.Name)
+		}
+		for _, u := range p.URLs {
+			if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("spec.failureDetection.httpProbe.urls of EgressGateway %s/%s has an invalid URL %q", gw.Namespace, gw.Name, u)
+			}
+		}
+	}
+	return nil
+}
+
+// validateIPPoolOverlaps checks that the IP pools of the egress gateways overlap neither with each other, nor with
+// the other IPPools of the cluster.
+func validateIPPoolOverlaps(gateways []operatorv1.EgressGateway, pools []crdv1.IPPool) error {
+	for i := range gateways {
+		gw := &gateways[i]
+		_, cidr, _ := net.ParseCIDR(gw.Spec.IPPool.CIDR)
+		for j := range gateways[:i] {
+			other := &gateways[j]
+			_, otherCIDR, _ := net.ParseCIDR(other.Spec.IPPool.CIDR)
+			if cidr.Contains(otherCIDR.IP) || otherCIDR.Contains(cidr.IP) {
+				return fmt.Errorf("spec.ipPool.cidr %s of EgressGateway %s/%s overlaps with spec.ipPool.cidr %s of EgressGateway %s/%s",
+					gw.Spec.IPPool.CIDR, gw.Namespace, gw.Name, other.Spec.IPPool.CIDR, other.Namespace, other.Name)
+			}
+		}
+		for _, pool := range pools {
+			if pool.Labels[render.IPPoolManagedByLabel] == egressgateway.IPPoolManagedByLabelValue {
+				continue
+			}
+			_, poolCIDR, err := net.ParseCIDR(pool.Spec.CIDR)
+			if err != nil {
+				continue
+			}
+			if cidr.Contains(poolCIDR.IP) || poolCIDR.Contains(cidr.IP) {
+				return fmt.Errorf("spec.ipPool.cidr %s of EgressGateway %s/%s overlaps with IPPool %s",
+					gw.Spec.IPPool.CIDR, gw.Namespace, gw.Name, pool.Name)
+			}
+		}
+	}
+	return nil
+}
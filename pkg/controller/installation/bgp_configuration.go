@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+)
+
+// bgpManagedFieldsAnnotation records, on the default BGPConfiguration, the values of the fields that are managed
+// through the service advertisement of the Installation as they were last applied. The fields are keyed by their
+// JSON name.
+const bgpManagedFieldsAnnotation = "operator.tigera.io/bgp-managed-fields"
+
+// setBGPConfiguration applies the service advertisement of the Installation to the passed in bc. The Service CIDRs
+// that the Installation does not specify are left as they are, except the ones it specified before, which are
+// cleared unless they were edited directly since they were last applied. If the BGPConfiguration ResourceVersion is
+// empty, then the default BGPConfiguration will be created, otherwise a patch will be performed.
+func (r *ReconcileInstallation) setBGPConfiguration(ctx context.Context, install *operator.Installation, bc *crdv1.BGPConfiguration, log logr.Logger) error {
+	var sa *operator.ServiceAdvertisementSpec
+	if install.Spec.CalicoNetwork != nil {
+		sa = install.Spec.CalicoNetwork.ServiceAdvertisement
+	}
+	if sa == nil && bc.Annotations[bgpManagedFieldsAnnotation] == "" {
+		return nil
+	}
+	original := bc.DeepCopy()
+	patchFrom := client.MergeFrom(original)
+	bc.ObjectMeta.Name = "default"
+
+	recorded := map[string]json.RawMessage{}
+	if a := bc.Annotations[bgpManagedFieldsAnnotation]; a != "" {
+		if err := json.Unmarshal([]byte(a), &recorded); err != nil {
+			// The annotation is rewritten below, so don't block on a bad one.
+			log.Info("Ignoring invalid BGPConfiguration annotation", "annotation", bgpManagedFieldsAnnotation, "reason", err.Error())
+		}
+	}
+	current, err := specFields(&bc.Spec)
+	if err != nil {
+		r.SetDegraded("Unable to read the BGPConfiguration fields managed by the Installation", err, log)
+		return err
+	}
+
+	managed := applyServiceAdvertisementSpec(sa, &bc.Spec)
+	isManaged := map[string]bool{}
+	for _, f := range managed {
+		isManaged[f] = true
+	}
+	for f, v := range recorded {
+		if !isManaged[f] && bytes.Equal(current[f], v) {
+			clearBGPField(&bc.Spec, f)
+		}
+	}
+
+	if len(managed) == 0 {
+		delete(bc.Annotations, bgpManagedFieldsAnnotation)
+	} else {
+		all, err := specFields(&bc.Spec)
+		if err != nil {
+			r.SetDegraded("Unable to record the BGPConfiguration fields managed by the Installation", err, log)
+			return err
+		}
+		values := map[string]json.RawMessage{}
+		for _, f := range managed {
+			values[f] = all[f]
+		}
+		b, err := json.Marshal(values)
+		if err != nil {
+			r.SetDegraded("Unable to record the BGPConfiguration fields managed by the Installation", err, log)
+			return err
+		}
+		if bc.Annotations == nil {
+			bc.Annotations = map[string]string{}
+		}
+		bc.Annotations[bgpManagedFieldsAnnotation] = string(b)
+	}
+
+	if bc.ResourceVersion != "" &&
+		reflect.DeepEqual(original.Spec, bc.Spec) &&
+		reflect.DeepEqual(original.Annotations, bc.Annotations) {
+		return nil
+	}
+
+	if bc.ResourceVersion == "" {
+		if err := r.client.Create(ctx, bc); err != nil {
+			r.SetDegraded("Unable to Create default BGPConfiguration", err, log)
+			return err
+		}
+	} else {
+		if err := r.client.Patch(ctx, bc, patchFrom); err != nil {
+			r.SetDegraded("Unable to Patch default BGPConfiguration", err, log)
+			return err
+		}
+	}
+	return nil
+}
+
+// applyServiceAdvertisementSpec sets the Service CIDRs that the service advertisement specifies on spec, including
+// the CIDR of the LoadBalancer IP pool, and returns the JSON names of the fields it set.
+func applyServiceAdvertisementSpec(sa *operator.ServiceAdvertisementSpec, spec *crdv1.BGPConfigurationSpec) []string {
+	if sa == nil {
+		return nil
+	}
+	var managed []string
+	if len(sa.ClusterIPCIDRs) > 0 {
+		spec.ServiceClusterIPs = nil
+		for _, cidr := range sa.ClusterIPCIDRs {
+			spec.ServiceClusterIPs = append(spec.ServiceClusterIPs, crdv1.ServiceClusterIPBlock{CIDR: cidr})
+		}
+		managed = append(managed, "serviceClusterIPs")
+	}
+	if len(sa.ExternalIPCIDRs) > 0 {
+		spec.ServiceExternalIPs = nil
+		for _, cidr := range sa.ExternalIPCIDRs {
+			spec.ServiceExternalIPs = append(spec.ServiceExternalIPs, crdv1.ServiceExternalIPBlock{CIDR: cidr})
+		}
+		managed = append(managed, "serviceExternalIPs")
+	}
+	if sa.LoadBalancerIPPool != nil {
+		spec.ServiceLoadBalancerIPs = []crdv1.ServiceLoadBalancerIPBlock{{CIDR: sa.LoadBalancerIPPool.CIDR}}
+		managed = append(managed, "serviceLoadBalancerIPs")
+	}
+	return managed
+}
+
+// clearBGPField clears the field of spec with the given JSON name, which is one of the fields set by
+// applyServiceAdvertisementSpec.
+func clearBGPField(spec *crdv1.BGPConfigurationSpec, field string) {
+	switch field {
+	case "serviceClusterIPs":
+		spec.ServiceClusterIPs = nil
+	case "serviceExternalIPs":
+		spec.ServiceExternalIPs = nil
+	case "serviceLoadBalancerIPs":
+		spec.ServiceLoadBalancerIPs = nil
+	}
+}
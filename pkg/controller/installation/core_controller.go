@@ -86,6 +86,17 @@ const (
 	// This is separate from the calico/node prometheus metrics port, which is user configurable.
 	defaultNodeReporterPort = 9081
 	CalicoFinalizer         = "tigera.io/operator-cleanup"
+
+	// The names of the IPPools that calico/node creates by default, which are used for the IP pools of the
+	// Installation when there is a single pool of the IP version.
+	defaultIPv4PoolName = "default-ipv4-ippool"
+	defaultIPv6PoolName = "default-ipv6-ippool"
+
+	// The Service cluster IP range of kubeadm clusters, which VPP load balances when no other is specified.
+	defaultServiceCIDR = "10.96.0.0/12"
+
+	// The name of the IPPool of the LoadBalancer Service IPs, when not specified on the Installation.
+	defaultLoadBalancerIPPoolName = "loadbalancer-ippool"
 )
 
 //// Node and Installation finalizer
@@ -168,6 +179,8 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) (*ReconcileInst
 		amazonCRDExists:       opts.AmazonCRDExists,
 		enterpriseCRDsExist:   opts.EnterpriseCRDExists,
 		clusterDomain:         opts.ClusterDomain,
+		usePSP:                opts.UsePSP,
+		usePolicyV1PDB:        opts.UsePolicyV1PDB,
 		manageCRDs:            opts.ManageCRDs,
 	}
 	r.status.Run(opts.ShutdownContext)
@@ -271,6 +284,26 @@ func add(mgr manager.Manager, r *ReconcileInstallation) error {
 		return fmt.Errorf("tigera-installation-controller failed to watch FelixConfiguration resource: %w", err)
 	}
 
+	// Watch for changes to BGPConfiguration.
+	err = c.Watch(&source.Kind{Type: &crdv1.BGPConfiguration{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch BGPConfiguration resource: %w", err)
+	}
+
+	if err = addWireGuardNodeWatch(c); err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch Nodes: %w", err)
+	}
+
+	if err = addCalicoNodePoolNodeWatch(c); err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch Nodes: %w", err)
+	}
+
+	// Watch for changes to IPPools.
+	err = c.Watch(&source.Kind{Type: &crdv1.IPPool{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch IPPool resource: %w", err)
+	}
+
 	if r.enterpriseCRDsExist {
 		// Watch for changes to primary resource ManagementCluster
 		err = c.Watch(&source.Kind{Type: &operator.ManagementCluster{}}, &handler.EnqueueRequestForObject{})
@@ -350,6 +383,8 @@ type ReconcileInstallation struct {
 	amazonCRDExists       bool
 	migrationChecked      bool
 	clusterDomain         string
+	usePSP                bool
+	usePolicyV1PDB        bool
 	manageCRDs            bool
 }
 
@@ -559,17 +594,20 @@ func fillDefaults(instance *operator.Installation) error {
 		}
 	}
 
+	v4pools := render.GetIPv4Pools(instance.Spec.CalicoNetwork.IPPools)
+	v6pools := render.GetIPv6Pools(instance.Spec.CalicoNetwork.IPPools)
+
 	needIPv4Autodetection := false
-	if *instance.Spec.CalicoNetwork.LinuxDataplane == operator.LinuxDataplaneBPF {
-		// BPF dataplane requires IP autodetection even if we're not using Calico IPAM.
+	if *instance.Spec.CalicoNetwork.LinuxDataplane == operator.LinuxDataplaneBPF && (len(v4pools) > 0 || len(v6pools) == 0) {
+		// BPF dataplane requires IP autodetection even if we're not using Calico IPAM. IPv6-only clusters
+		// only need IPv6 autodetection, which is defaulted with the IPv6 pools below.
 		needIPv4Autodetection = true
 	}
 
-	var v4pool, v6pool *operator.IPPool
-	v4pool = render.GetIPv4Pool(instance.Spec.CalicoNetwork.IPPools)
-	v6pool = render.GetIPv6Pool(instance.Spec.CalicoNetwork.IPPools)
-
-	if v4pool != nil {
+	for _, v4pool := range v4pools {
+		if v4pool.Name == "" && len(v4pools) == 1 {
+			v4pool.Name = defaultIPv4PoolName
+		}
 		if v4pool.Encapsulation == "" {
 			if instance.Spec.CNI.Type == operator.PluginCalico {
 				v4pool.Encapsulation = operator.EncapsulationIPIP
@@ -587,6 +625,10 @@ func fillDefaults(instance *operator.Installation) error {
 			var twentySix int32 = 26
 			v4pool.BlockSize = &twentySix
 		}
+		if v4pool.AssignmentMode == nil {
+			automatic := operator.IPPoolAssignmentAutomatic
+			v4pool.AssignmentMode = &automatic
+		}
 		needIPv4Autodetection = true
 	}
 
@@ -613,7 +655,10 @@ func fillDefaults(instance *operator.Installation) error {
 		}
 	}
 
-	if v6pool != nil {
+	for _, v6pool := range v6pools {
+		if v6pool.Name == "" && len(v6pools) == 1 {
+			v6pool.Name = defaultIPv6PoolName
+		}
 		if v6pool.Encapsulation == "" {
 			v6pool.Encapsulation = operator.EncapsulationNone
 		}
@@ -623,17 +668,22 @@ func fillDefaults(instance *operator.Installation) error {
 		if v6pool.NodeSelector == "" {
 			v6pool.NodeSelector = operator.NodeSelectorDefault
 		}
-		if instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 == nil {
-			// Default IPv6 address detection to "first found" if not specified.
-			t := true
-			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 = &operator.NodeAddressAutodetection{
-				FirstFound: &t,
-			}
-		}
 		if v6pool.BlockSize == nil {
 			var oneTwentyTwo int32 = 122
 			v6pool.BlockSize = &oneTwentyTwo
 		}
+		if v6pool.AssignmentMode == nil {
+			automatic := operator.IPPoolAssignmentAutomatic
+			v6pool.AssignmentMode = &automatic
+		}
+	}
+
+	if len(v6pools) > 0 && instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 == nil {
+		// Default IPv6 address detection to "first found" if not specified.
+		t := true
+		instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 = &operator.NodeAddressAutodetection{
+			FirstFound: &t,
+		}
 	}
 
 	// While a number of the fields in this section are relevant to all CNI plugins,
@@ -657,6 +707,22 @@ func fillDefaults(instance *operator.Installation) error {
 		}
 	}
 
+	if vpp := instance.Spec.CalicoNetwork.VPP; vpp != nil {
+		if vpp.Hugepages == nil {
+			var hugepages int32 = 256
+			vpp.Hugepages = &hugepages
+		}
+		if len(vpp.ServiceCIDRs) == 0 {
+			vpp.ServiceCIDRs = []string{defaultServiceCIDR}
+		}
+	}
+
+	if lb := render.LoadBalancerIPPool(&instance.Spec); lb != nil {
+		if lb.Name == "" {
+			lb.Name = defaultLoadBalancerIPPoolName
+		}
+	}
+
 	// If not specified by the user, set the default control plane replicas to 2.
 	if instance.Spec.ControlPlaneReplicas == nil {
 		var replicas int32 = 2
@@ -741,7 +807,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 
 	// Mark CR found so we can report converter problems via tigerastatus
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 
 	if !r.migrationChecked {
 		// update Installation resource with existing install if it exists.
@@ -1044,6 +1110,48 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// With Multus, calico-node installs the Calico CNI configuration for Multus to delegate to, so pods can't be
+	// networked until Multus is installed. The rest of the Installation is still reconciled, without the
+	// NetworkAttachmentDefinitions, and is reported as degraded once it is.
+	multusEnabled := render.MultusEnabled(&instance.Spec) && !terminating
+	var multusErr error
+	if multusEnabled {
+		if multusErr = r.checkMultusInstalled(ctx); multusErr != nil {
+			reqLogger.Error(multusErr, "Multus is not installed, not reconciling the NetworkAttachmentDefinitions")
+		}
+	}
+
+	// Switch to the Linux dataplane of the Installation. Until the switch is complete, calico-node may keep running
+	// the eBPF dataplane while the spec says otherwise, or the other way around.
+	var dataplane *operator.TigeraStatusDataplane
+	var kubeProxyErr error
+	nodeBPF := false
+	if terminating {
+		// Enable kube-proxy again, so that services keep working once Calico has been removed.
+		err = r.setKubeProxyEnabled(ctx, instance, true)
+	} else {
+		dataplane, nodeBPF, err = r.switchDataplane(ctx, instance)
+		// The switch is refused until kube-proxy is disabled through the provider, which is reported as degraded
+		// once the rest of the Installation has been reconciled.
+		if errors.As(err, &kubeProxyManagedError{}) {
+			kubeProxyErr, err = err, nil
+			reqLogger.Error(kubeProxyErr, "Not switching to the eBPF dataplane")
+		}
+	}
+	if err != nil {
+		r.SetDegraded("Error switching the Linux dataplane", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	nodeInstallation := &instance.Spec
+	if specBPF := *instance.Spec.CalicoNetwork.LinuxDataplane == operator.LinuxDataplaneBPF; !terminating && nodeBPF != specBPF {
+		nodeInstallation = instance.Spec.DeepCopy()
+		linuxDataplane := operator.LinuxDataplaneIptables
+		if nodeBPF {
+			linuxDataplane = operator.LinuxDataplaneBPF
+		}
+		nodeInstallation.CalicoNetwork.LinuxDataplane = &linuxDataplane
+	}
+
 	openShiftOnAws := false
 	if instance.Spec.KubernetesProvider == operator.ProviderOpenShift {
 		openShiftOnAws, err = isOpenshiftOnAws(instance, ctx, r.client)
@@ -1089,6 +1197,37 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// Disable the automatic host endpoints when they would cut the nodes off from the cluster. The rest of the
+	// Installation is still reconciled, and is reported as degraded once it is.
+	apiServerPorts, err := apiServerHostPorts(ctx, r.client, k8sapi.Endpoint())
+	if err != nil {
+		r.SetDegraded("Unable to determine the API server ports", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	hostProtectionErr := validateHostProtectionFailsafePorts(instance, felixConfiguration, apiServerPorts)
+	if hostProtectionErr != nil {
+		reqLogger.Error(hostProtectionErr, "Disabling the automatic host endpoints")
+	}
+
+	modifiedFelixFields, err := r.setFelixConfiguration(ctx, instance, felixConfiguration, reqLogger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if err = r.reportFelixConfigurationModified(ctx, instance, modifiedFelixFields, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Advertise the Service addresses of the Installation over BGP, or stop advertising the ones it no longer has.
+	bgpConfiguration := &crdv1.BGPConfiguration{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: "default"}, bgpConfiguration)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.SetDegraded("Unable to read BGPConfiguration", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	if err = r.setBGPConfiguration(ctx, instance, bgpConfiguration, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// nodeReporterMetricsPort is a port used in Enterprise to host internal metrics.
 	// Operator is responsible for creating a service which maps to that port.
 	// Here, we'll check the default felixconfiguration to see if the user is specifying
@@ -1159,6 +1298,9 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		r.SetDegraded("Unable to read KubeControllersConfiguration", err, reqLogger)
 		return reconcile.Result{}, err
 	}
+	if err = r.setKubeControllersConfiguration(ctx, instance, kubeControllersConfig, hostProtectionErr == nil, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	// Determine the port to use for kube-controllers metrics.
 	kubeControllersMetricsPort := 0
@@ -1224,14 +1366,36 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 
 	}
 
+	// Reconcile the IP pools of the Installation, before calico/node creates default IP pools from them.
+	ipPools, err := r.ipPools(ctx, instance)
+	if err != nil {
+		r.SetDegraded("Error reconciling IP pools", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	if err = utils.NewComponentHandler(log, r.client, r.scheme, nil).CreateOrUpdateOrDelete(ctx, ipPools, nil); err != nil {
+		r.SetDegraded("Error reconciling IP pools", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
+	if multusEnabled && multusErr == nil {
+		multus, err := r.multus(ctx, instance)
+		if err != nil {
+			r.SetDegraded("Error reconciling the NetworkAttachmentDefinitions of the IP pools", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		components = append(components, multus)
+	}
+
 	// Build a configuration for rendering calico/typha.
 	typhaCfg := render.TyphaConfiguration{
-		K8sServiceEp:           k8sapi.Endpoint,
+		K8sServiceEp:           k8sapi.Endpoint(),
 		Installation:           &instance.Spec,
 		TLS:                    typhaNodeTLS,
 		AmazonCloudIntegration: aci,
 		MigrateNamespaces:      needNsMigration,
 		ClusterDomain:          r.clusterDomain,
+		UsePSP:                 r.usePSP,
+		UsePolicyV1PDB:         r.usePolicyV1PDB,
 	}
 	components = append(components, render.Typha(&typhaCfg))
 
@@ -1255,10 +1419,22 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 			nodeTerminating = true
 		}
 	}
+	// Label the nodes of the CalicoNodePools before rendering, since the calico-node DaemonSets select their nodes
+	// by the label.
+	if err = r.labelCalicoNodePoolNodes(ctx, instance); err != nil {
+		r.SetDegraded("Error labeling the nodes of the CalicoNodePools", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	staleNodePools, err := r.staleCalicoNodePools(ctx, instance)
+	if err != nil {
+		r.SetDegraded("Error querying calico-node DaemonSets of the CalicoNodePools", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	// Build a configuration for rendering calico/node.
 	nodeCfg := render.NodeConfiguration{
-		K8sServiceEp:              k8sapi.Endpoint,
-		Installation:              &instance.Spec,
+		K8sServiceEp:              k8sapi.Endpoint(),
+		Installation:              nodeInstallation,
 		AmazonCloudIntegration:    aci,
 		LogCollector:              logCollector,
 		BirdTemplates:             birdTemplates,
@@ -1271,12 +1447,14 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		Terminating:               nodeTerminating,
 		PrometheusServerTLS:       nodePrometheusTLS,
 		PrometheusMetricsCABundle: metricsBundle,
+		UsePSP:                    r.usePSP,
+		StaleNodePools:            staleNodePools,
 	}
 	components = append(components, render.Node(&nodeCfg))
 
 	// Build a configuration for rendering calico/kube-controllers.
 	kubeControllersCfg := kubecontrollers.KubeControllersConfiguration{
-		K8sServiceEp:                k8sapi.Endpoint,
+		K8sServiceEp:                k8sapi.Endpoint(),
 		Installation:                &instance.Spec,
 		ManagementCluster:           managementCluster,
 		ManagementClusterConnection: managementClusterConnection,
@@ -1284,6 +1462,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		MetricsPort:                 kubeControllersMetricsPort,
 		ManagerInternalSecret:       managerInternalTLSSecret,
 		Terminating:                 terminating,
+		UsePSP:                      r.usePSP,
 	}
 	components = append(components, kubecontrollers.NewCalicoKubeControllers(&kubeControllersCfg))
 
@@ -1293,6 +1472,19 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	}
 	components = append(components, render.Windows(&windowsCfg))
 
+	vppCfg := render.VPPConfiguration{
+		Installation: &instance.Spec,
+		Terminating:  terminating,
+	}
+	components = append(components, render.VPP(&vppCfg))
+
+	tier, err := utils.AllowTigeraTier(ctx, r.client, &instance.Spec, components...)
+	if err != nil {
+		r.SetDegraded("Error querying allow-tigera tier", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	components = append(components, render.NetworkPolicies(&instance.Spec, tier, components...))
+
 	if nodePrometheusTLS != nil {
 		oprIssued, err := utils.IsCertOperatorIssued(nodePrometheusTLS.Data[corev1.TLSCertKey])
 		if err != nil {
@@ -1320,6 +1512,11 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	if err = imageset.VerifyImages(ctx, r.client, &instance.Spec, imageSet, components...); err != nil {
+		r.SetDegraded("Error verifying image signatures", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	// Create a component handler to create or update the rendered components.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
 	for _, component := range components {
@@ -1331,7 +1528,21 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 
 	// TODO: We handle too many components in this controller at the moment. Once we are done consolidating,
 	// we can have the CreateOrUpdate logic handle this for us.
-	r.status.AddDaemonsets([]types.NamespacedName{{Name: "calico-node", Namespace: "calico-system"}})
+	var nodePools []string
+	for _, pool := range instance.Spec.CalicoNodePools {
+		nodePools = append(nodePools, pool.Name)
+	}
+	r.status.AddDaemonsets(append([]types.NamespacedName{{Name: "calico-node", Namespace: "calico-system"}}, calicoNodePoolDaemonSets(nodePools)...))
+	if len(staleNodePools) > 0 {
+		r.status.RemoveDaemonsets(calicoNodePoolDaemonSets(staleNodePools)...)
+	}
+	// The calico TigeraStatus is not available until VPP is ready on the nodes.
+	vppNode := types.NamespacedName{Name: render.VPPNodeName, Namespace: common.CalicoNamespace}
+	if render.VPPEnabled(&instance.Spec) {
+		r.status.AddDaemonsets([]types.NamespacedName{vppNode})
+	} else {
+		r.status.RemoveDaemonsets(vppNode)
+	}
 	r.status.AddDeployments([]types.NamespacedName{{Name: "calico-kube-controllers", Namespace: "calico-system"}})
 	if instance.Spec.CertificateManagement != nil {
 		r.status.AddCertificateSigningRequests(render.CSRLabelCalicoSystem, map[string]string{
@@ -1359,6 +1570,15 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	// Report the readiness of WireGuard encryption on the nodes.
+	wireGuard, err := r.wireGuardStatus(ctx, instance)
+	if err != nil {
+		r.SetDegraded("Unable to determine the WireGuard encryption status of the nodes", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	r.status.SetWireGuardStatus(wireGuard)
+	r.status.SetDataplaneStatus(dataplane)
+
 	// Determine which MTU to use in the status fields.
 	statusMTU := 0
 	if instance.Spec.CalicoNetwork != nil && instance.Spec.CalicoNetwork.MTU != nil {
@@ -1411,10 +1631,21 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	}
 
 	// Tell the status manager that we're ready to monitor the resources we've told it about and receive statuses.
+	r.status.SetObservedGeneration(instance.Generation)
+	r.status.SetRenderedVariant(instance.Spec.Variant)
 	r.status.ReadyToMonitor()
 
-	// We can clear the degraded state now since as far as we know everything is in order.
-	r.status.ClearDegraded()
+	switch {
+	case multusErr != nil:
+		r.SetDegraded("Multus is not installed", multusErr, reqLogger)
+	case hostProtectionErr != nil:
+		r.SetDegraded("Host protection would block the API server or BGP traffic of the nodes", hostProtectionErr, reqLogger)
+	case kubeProxyErr != nil:
+		r.SetDegraded("kube-proxy must be disabled through the Kubernetes provider to switch to the eBPF dataplane", kubeProxyErr, reqLogger)
+	default:
+		// We can clear the degraded state now since as far as we know everything is in order.
+		r.status.ClearDegraded()
+	}
 
 	if !r.status.IsAvailable() {
 		// Schedule a kick to check again in the near future. Hopefully by then
@@ -1442,6 +1673,17 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	if terminating {
 		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 	}
+	if result, requeue := utils.AllowTigeraTierRequeue(&instance.Spec, tier); requeue {
+		return result, nil
+	}
+	if dataplane != nil && dataplane.Phase != operator.DataplanePhaseComplete {
+		// kube-proxy is not watched, so check again whether the switch of the Linux dataplane can move on.
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	if multusErr != nil {
+		// Multus is not watched, so check again whether it has been installed.
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
 	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
@@ -1623,6 +1865,70 @@ func (r *ReconcileInstallation) setDefaultsOnFelixConfiguration(ctx context.Cont
 	return nil
 }
 
+// setKubeControllersConfiguration applies the kube-controllers settings of the Installation to the passed in
+// kcc. Settings that are not specified on the Installation are left as they are. If the KubeControllersConfiguration
+// ResourceVersion is empty, then the default KubeControllersConfiguration will be created, otherwise a patch will be
+// performed. A created KubeControllersConfiguration enables the node controller, as the one kube-controllers creates
+// does: kube-controllers disables the controllers that are not set. The automatic host endpoints are disabled when
+// the Installation enables them but allowAutoHostEndpoints is false, so that host endpoints that were enabled before
+// do not keep cutting the nodes off.
+func (r *ReconcileInstallation) setKubeControllersConfiguration(ctx context.Context, install *operator.Installation, kcc *crdv1.KubeControllersConfiguration, allowAutoHostEndpoints bool, log logr.Logger) error {
+	kc := install.Spec.KubeControllers
+	autoHEPs := autoHostEndpoints(install)
+	if autoHEPs != nil && *autoHEPs == operator.KubeControllerEnabled && !allowAutoHostEndpoints {
+		disabled := operator.KubeControllerDisabled
+		autoHEPs = &disabled
+	}
+	if kc == nil && autoHEPs == nil {
+		return nil
+	}
+	original := kcc.DeepCopy()
+	patchFrom := client.MergeFrom(original)
+	kcc.ObjectMeta.Name = "default"
+	if kcc.ResourceVersion == "" && kcc.Spec.Controllers.Node == nil {
+		kcc.Spec.Controllers.Node = &crdv1.NodeControllerConfig{}
+	}
+
+	if kc != nil && kc.LogSeverityScreen != nil {
+		kcc.Spec.LogSeverityScreen = *kc.LogSeverityScreen
+	}
+	if kc != nil && kc.Controllers != nil && kc.Controllers.Node != nil {
+		node := kc.Controllers.Node
+		if kcc.Spec.Controllers.Node == nil {
+			kcc.Spec.Controllers.Node = &crdv1.NodeControllerConfig{}
+		}
+		if node.ReconcilerPeriod != nil {
+			kcc.Spec.Controllers.Node.ReconcilerPeriod = node.ReconcilerPeriod.DeepCopy()
+		}
+		if node.LeakGracePeriod != nil {
+			kcc.Spec.Controllers.Node.LeakGracePeriod = node.LeakGracePeriod.DeepCopy()
+		}
+	}
+	if autoHEPs != nil {
+		if kcc.Spec.Controllers.Node == nil {
+			kcc.Spec.Controllers.Node = &crdv1.NodeControllerConfig{}
+		}
+		kcc.Spec.Controllers.Node.HostEndpoint = &crdv1.AutoHostEndpointConfig{AutoCreate: string(*autoHEPs)}
+	}
+
+	if kcc.ResourceVersion != "" && reflect.DeepEqual(original.Spec, kcc.Spec) {
+		return nil
+	}
+
+	if kcc.ResourceVersion == "" {
+		if err := r.client.Create(ctx, kcc); err != nil {
+			r.SetDegraded("Unable to Create default KubeControllersConfiguration", err, log)
+			return err
+		}
+	} else {
+		if err := r.client.Patch(ctx, kcc, patchFrom); err != nil {
+			r.SetDegraded("Unable to Patch default KubeControllersConfiguration", err, log)
+			return err
+		}
+	}
+	return nil
+}
+
 var osExitOverride = os.Exit
 
 // checkActive verifies the operator that calls this function is designated as the active operator.
@@ -25,6 +25,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	schedv1 "k8s.io/api/scheduling/v1"
+	apiextenv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -45,9 +47,11 @@ import (
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	nadv1 "github.com/tigera/operator/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/installation/windows"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/dns"
@@ -116,6 +120,7 @@ var _ = Describe("Testing core-controller installation", func() {
 	)
 	var defaultMTU int32 = 1440
 	var twentySix int32 = 26
+	var automatic operator.IPPoolAssignmentMode = operator.IPPoolAssignmentAutomatic
 	var hpEnabled operator.HostPortsType = operator.HostPortsEnabled
 	var hpDisabled operator.HostPortsType = operator.HostPortsDisabled
 	table.DescribeTable("Installation and Openshift should be merged and defaulted by mergeAndFillDefaults",
@@ -157,11 +162,13 @@ var _ = Describe("Testing core-controller installation", func() {
 			&operator.CalicoNetworkSpec{
 				IPPools: []operator.IPPool{
 					{
-						CIDR:          "192.168.0.0/16",
-						Encapsulation: "IPIP",
-						NATOutgoing:   "Enabled",
-						NodeSelector:  "all()",
-						BlockSize:     &twentySix,
+						Name:           "default-ipv4-ippool",
+						CIDR:           "192.168.0.0/16",
+						Encapsulation:  "IPIP",
+						NATOutgoing:    "Enabled",
+						NodeSelector:   "all()",
+						BlockSize:      &twentySix,
+						AssignmentMode: &automatic,
 					},
 				},
 				MTU:       &defaultMTU,
@@ -182,11 +189,13 @@ var _ = Describe("Testing core-controller installation", func() {
 			&operator.CalicoNetworkSpec{
 				IPPools: []operator.IPPool{
 					{
-						CIDR:          "10.0.0.0/8",
-						Encapsulation: "IPIP",
-						NATOutgoing:   "Enabled",
-						NodeSelector:  "all()",
-						BlockSize:     &twentySix,
+						Name:           "default-ipv4-ippool",
+						CIDR:           "10.0.0.0/8",
+						Encapsulation:  "IPIP",
+						NATOutgoing:    "Enabled",
+						NodeSelector:   "all()",
+						BlockSize:      &twentySix,
+						AssignmentMode: &automatic,
 					},
 				},
 				MTU:       &defaultMTU,
@@ -215,11 +224,13 @@ var _ = Describe("Testing core-controller installation", func() {
 			&operator.CalicoNetworkSpec{
 				IPPools: []operator.IPPool{
 					{
-						CIDR:          "10.0.0.0/24",
-						Encapsulation: "VXLAN",
-						NATOutgoing:   "Disabled",
-						NodeSelector:  "all()",
-						BlockSize:     &twentySix,
+						Name:           "default-ipv4-ippool",
+						CIDR:           "10.0.0.0/24",
+						Encapsulation:  "VXLAN",
+						NATOutgoing:    "Disabled",
+						NodeSelector:   "all()",
+						BlockSize:      &twentySix,
+						AssignmentMode: &automatic,
 					},
 				},
 				MTU:       &defaultMTU,
@@ -273,11 +284,13 @@ var _ = Describe("Testing core-controller installation", func() {
 			&operator.CalicoNetworkSpec{
 				IPPools: []operator.IPPool{
 					{
-						CIDR:          "192.168.0.0/16",
-						Encapsulation: "IPIP",
-						NATOutgoing:   "Enabled",
-						NodeSelector:  "all()",
-						BlockSize:     &twentySix,
+						Name:           "default-ipv4-ippool",
+						CIDR:           "192.168.0.0/16",
+						Encapsulation:  "IPIP",
+						NATOutgoing:    "Enabled",
+						NodeSelector:   "all()",
+						BlockSize:      &twentySix,
+						AssignmentMode: &automatic,
 					},
 				},
 				MTU:       &defaultMTU,
@@ -294,11 +307,13 @@ var _ = Describe("Testing core-controller installation", func() {
 			&operator.CalicoNetworkSpec{
 				IPPools: []operator.IPPool{
 					{
-						CIDR:          "192.168.0.0/16",
-						Encapsulation: "IPIP",
-						NATOutgoing:   "Enabled",
-						NodeSelector:  "all()",
-						BlockSize:     &twentySix,
+						Name:           "default-ipv4-ippool",
+						CIDR:           "192.168.0.0/16",
+						Encapsulation:  "IPIP",
+						NATOutgoing:    "Enabled",
+						NodeSelector:   "all()",
+						BlockSize:      &twentySix,
+						AssignmentMode: &automatic,
 					},
 				},
 				MTU:       &defaultMTU,
@@ -349,15 +364,20 @@ var _ = Describe("Testing core-controller installation", func() {
 			// Create an object we can use throughout the test to do the compliance reconcile loops.
 			mockStatus = &status.MockStatus{}
 			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("RemoveDaemonsets", mock.Anything).Return()
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
 			mockStatus.On("IsAvailable").Return(true)
-			mockStatus.On("OnCRFound").Return()
+			mockStatus.On("OnCRFound", mock.Anything).Return()
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetObservedGeneration", mock.Anything)
+			mockStatus.On("SetRenderedVariant", mock.Anything)
+			mockStatus.On("SetWireGuardStatus", mock.Anything)
+			mockStatus.On("SetDataplaneStatus", mock.Anything)
 
 			// Create the indexer and informer shared by the typhaAutoscaler and
 			// calicoWindowsUpgrader.
@@ -708,15 +728,20 @@ var _ = Describe("Testing core-controller installation", func() {
 			// Create an object we can use throughout the test to do the compliance reconcile loops.
 			mockStatus = &status.MockStatus{}
 			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("RemoveDaemonsets", mock.Anything).Return()
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
 			mockStatus.On("IsAvailable").Return(true)
-			mockStatus.On("OnCRFound").Return()
+			mockStatus.On("OnCRFound", mock.Anything).Return()
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetObservedGeneration", mock.Anything)
+			mockStatus.On("SetRenderedVariant", mock.Anything)
+			mockStatus.On("SetWireGuardStatus", mock.Anything)
+			mockStatus.On("SetDataplaneStatus", mock.Anything)
 			mockStatus.On("SetWindowsUpgradeStatus", mock.Anything, mock.Anything, mock.Anything, nil)
 
 			// Create the indexer and informer shared by the typhaAutoscaler and
@@ -942,12 +967,17 @@ var _ = Describe("Testing core-controller installation", func() {
 			// Create an object we can use throughout the test to do the core reconcile loops.
 			mockStatus = &status.MockStatus{}
 			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("RemoveDaemonsets", mock.Anything).Return()
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("IsAvailable").Return(true)
-			mockStatus.On("OnCRFound").Return()
+			mockStatus.On("OnCRFound", mock.Anything).Return()
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetObservedGeneration", mock.Anything)
+			mockStatus.On("SetRenderedVariant", mock.Anything)
+			mockStatus.On("SetWireGuardStatus", mock.Anything)
+			mockStatus.On("SetDataplaneStatus", mock.Anything)
 
 			// Create the indexer and informer shared by the typhaAutoscaler and
 			// calicoWindowsUpgrader.
@@ -1081,6 +1111,709 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(*fc.Spec.RouteTableRange).To(Equal(crdv1.RouteTableRange{Min: 65, Max: 99}))
 			Expect(fc.Spec.LogSeverityScreen).To(Equal("Error"))
 		})
+		It("should apply the kube-controllers settings to the KubeControllersConfiguration", func() {
+			port := 9094
+			Expect(c.Create(ctx, &crdv1.KubeControllersConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: crdv1.KubeControllersConfigurationSpec{
+					PrometheusMetricsPort: &port,
+					Controllers: crdv1.ControllersConfig{
+						Node: &crdv1.NodeControllerConfig{ReconcilerPeriod: &metav1.Duration{Duration: 5 * time.Minute}},
+					},
+				},
+			})).NotTo(HaveOccurred())
+
+			logLevel := "Debug"
+			autoHEPs := operator.KubeControllerEnabled
+			cr.Spec.KubeControllers = &operator.KubeControllersSpec{
+				LogSeverityScreen: &logLevel,
+				Controllers: &operator.KubeControllersControllers{
+					Node: &operator.KubeControllersNodeController{
+						LeakGracePeriod:   &metav1.Duration{Duration: 0},
+						AutoHostEndpoints: &autoHEPs,
+					},
+				},
+			}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			kcc := &crdv1.KubeControllersConfiguration{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, kcc)).NotTo(HaveOccurred())
+			Expect(kcc.Spec.LogSeverityScreen).To(Equal("Debug"))
+			Expect(kcc.Spec.Controllers.Node.LeakGracePeriod).To(Equal(&metav1.Duration{Duration: 0}))
+			Expect(kcc.Spec.Controllers.Node.HostEndpoint).To(Equal(&crdv1.AutoHostEndpointConfig{AutoCreate: "Enabled"}))
+
+			By("leaving the settings the Installation does not specify as they are")
+			Expect(kcc.Spec.Controllers.Node.ReconcilerPeriod).To(Equal(&metav1.Duration{Duration: 5 * time.Minute}))
+			Expect(kcc.Spec.PrometheusMetricsPort).To(Equal(&port))
+		})
+
+		Context("with host protection", func() {
+			var autoHEPs operator.KubeControllerState
+			BeforeEach(func() {
+				autoHEPs = operator.KubeControllerEnabled
+				cr.Spec.KubeControllers = &operator.KubeControllersSpec{
+					Controllers: &operator.KubeControllersControllers{
+						Node: &operator.KubeControllersNodeController{AutoHostEndpoints: &autoHEPs},
+					},
+				}
+				cr.Spec.Felix = &operator.FelixSpec{
+					FailsafeInboundHostPorts: &[]operator.FelixProtoPort{
+						{Protocol: "TCP", Port: 22},
+						{Protocol: "TCP", Port: 179},
+						{Protocol: "TCP", Port: 6443},
+					},
+					FailsafeOutboundHostPorts: &[]operator.FelixProtoPort{
+						{Protocol: "UDP", Port: 53},
+						{Protocol: "TCP", Port: 179},
+						{Protocol: "TCP", Port: 6443},
+					},
+				}
+			})
+
+			It("should enable the automatic host endpoints and set the failsafe ports", func() {
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				kcc := &crdv1.KubeControllersConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, kcc)).NotTo(HaveOccurred())
+				Expect(kcc.Spec.Controllers.Node.HostEndpoint).To(Equal(&crdv1.AutoHostEndpointConfig{AutoCreate: "Enabled"}))
+
+				fc := &crdv1.FelixConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+				Expect(*fc.Spec.FailsafeInboundHostPorts).To(Equal([]crdv1.ProtoPort{
+					{Protocol: "TCP", Port: 22}, {Protocol: "TCP", Port: 179}, {Protocol: "TCP", Port: 6443},
+				}))
+				Expect(*fc.Spec.FailsafeOutboundHostPorts).To(Equal([]crdv1.ProtoPort{
+					{Protocol: "UDP", Port: 53}, {Protocol: "TCP", Port: 179}, {Protocol: "TCP", Port: 6443},
+				}))
+			})
+
+			It("should disable the automatic host endpoints when the failsafe ports block the API server", func() {
+				mockStatus.On("SetDegraded", "Host protection would block the API server or BGP traffic of the nodes", mock.Anything).Return()
+				defer k8sapi.ResetEndpoint()
+				Expect(c.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()},
+					Data:       map[string]string{"KUBERNETES_SERVICE_HOST": "api.example.com", "KUBERNETES_SERVICE_PORT": "8443"},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &crdv1.KubeControllersConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "default"},
+					Spec: crdv1.KubeControllersConfigurationSpec{Controllers: crdv1.ControllersConfig{
+						Node: &crdv1.NodeControllerConfig{HostEndpoint: &crdv1.AutoHostEndpointConfig{AutoCreate: "Enabled"}},
+					}},
+				})).NotTo(HaveOccurred())
+
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Host protection would block the API server or BGP traffic of the nodes",
+					"the failsafe inbound host ports must include TCP port 8443 when the automatic host endpoints are enabled")
+				mockStatus.AssertNotCalled(GinkgoT(), "ClearDegraded")
+
+				By("still reconciling the rest of the Installation")
+				Expect(c.Get(ctx, types.NamespacedName{Name: "calico-node", Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})).NotTo(HaveOccurred())
+				fc := &crdv1.FelixConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+				Expect(fc.Spec.FailsafeInboundHostPorts).NotTo(BeNil())
+
+				By("disabling the automatic host endpoints that were enabled before")
+				kcc := &crdv1.KubeControllersConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, kcc)).NotTo(HaveOccurred())
+				Expect(kcc.Spec.Controllers.Node.HostEndpoint).To(Equal(&crdv1.AutoHostEndpointConfig{AutoCreate: "Disabled"}))
+			})
+
+			It("should check the ports of the kubernetes service endpoints", func() {
+				mockStatus.On("SetDegraded", "Host protection would block the API server or BGP traffic of the nodes", mock.Anything).Return()
+				Expect(c.Create(ctx, &corev1.Endpoints{
+					ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"},
+					Subsets: []corev1.EndpointSubset{{
+						Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+						Ports:     []corev1.EndpointPort{{Name: "https", Port: 8443, Protocol: corev1.ProtocolTCP}},
+					}},
+				})).NotTo(HaveOccurred())
+
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Host protection would block the API server or BGP traffic of the nodes",
+					"the failsafe inbound host ports must include TCP port 8443 when the automatic host endpoints are enabled")
+
+				kcc := &crdv1.KubeControllersConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, kcc)).NotTo(HaveOccurred())
+				Expect(kcc.Spec.Controllers.Node.HostEndpoint).To(Equal(&crdv1.AutoHostEndpointConfig{AutoCreate: "Disabled"}))
+			})
+
+			It("should check the BGP port against the Felix default failsafe ports", func() {
+				mockStatus.On("SetDegraded", "Host protection would block the API server or BGP traffic of the nodes", mock.Anything).Return()
+				Expect(c.Create(ctx, &crdv1.FelixConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "default"},
+					Spec: crdv1.FelixConfigurationSpec{
+						FailsafeOutboundHostPorts: &[]crdv1.ProtoPort{{Protocol: "tcp", Port: 6443}},
+					},
+				})).NotTo(HaveOccurred())
+				cr.Spec.Felix = nil
+				bgp := operator.BGPEnabled
+				cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{BGP: &bgp}
+
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Host protection would block the API server or BGP traffic of the nodes",
+					"the failsafe outbound host ports must include TCP port 179 when the automatic host endpoints are enabled")
+			})
+
+			It("should enable the automatic host endpoints without the reachability check when it is disabled", func() {
+				defer k8sapi.ResetEndpoint()
+				Expect(c.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()},
+					Data:       map[string]string{"KUBERNETES_SERVICE_HOST": "api.example.com", "KUBERNETES_SERVICE_PORT": "8443"},
+				})).NotTo(HaveOccurred())
+				check := operator.HostProtectionCheckDisabled
+				cr.Spec.HostProtection = &operator.HostProtectionSpec{ReachabilityCheck: &check}
+
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				mockStatus.AssertNotCalled(GinkgoT(), "SetDegraded", "Host protection would block the API server or BGP traffic of the nodes", mock.Anything)
+
+				kcc := &crdv1.KubeControllersConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, kcc)).NotTo(HaveOccurred())
+				Expect(kcc.Spec.Controllers.Node.HostEndpoint).To(Equal(&crdv1.AutoHostEndpointConfig{AutoCreate: "Enabled"}))
+			})
+		})
+
+		Context("with Felix settings", func() {
+			BeforeEach(func() {
+				logLevel := "Debug"
+				backend := "NFT"
+				cr.Spec.Felix = &operator.FelixSpec{
+					LogSeverityScreen:       &logLevel,
+					IptablesBackend:         &backend,
+					IptablesRefreshInterval: &metav1.Duration{Duration: time.Minute},
+					RouteTableRange:         &operator.FelixRouteTableRange{Min: 100, Max: 200},
+					FailsafeInboundHostPorts: &[]operator.FelixProtoPort{
+						{Protocol: "TCP", Port: 22},
+					},
+				}
+			})
+
+			It("should apply the Felix settings to the FelixConfiguration", func() {
+				port := 9095
+				Expect(c.Create(ctx, &crdv1.FelixConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "default"},
+					Spec: crdv1.FelixConfigurationSpec{
+						LogSeverityScreen:      "Error",
+						PrometheusReporterPort: &port,
+					},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				fc := &crdv1.FelixConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+				Expect(fc.Spec.LogSeverityScreen).To(Equal("Debug"))
+				Expect(*fc.Spec.IptablesBackend).To(Equal(crdv1.IptablesBackend(crdv1.IptablesBackendNFTables)))
+				Expect(fc.Spec.IptablesRefreshInterval).To(Equal(&metav1.Duration{Duration: time.Minute}))
+				Expect(fc.Spec.RouteTableRange).To(Equal(&crdv1.RouteTableRange{Min: 100, Max: 200}))
+				Expect(*fc.Spec.FailsafeInboundHostPorts).To(Equal([]crdv1.ProtoPort{{Protocol: "TCP", Port: 22}}))
+				Expect(fc.Annotations).To(HaveKey("operator.tigera.io/felix-managed-fields"))
+
+				By("leaving the settings the Installation does not specify as they are")
+				Expect(fc.Spec.PrometheusReporterPort).To(Equal(&port))
+				Expect(fc.Spec.FailsafeOutboundHostPorts).To(BeNil())
+				Expect(fc.Spec.BPFExternalServiceMode).To(BeEmpty())
+			})
+
+			It("should revert and report direct edits of the managed settings", func() {
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				fc := &crdv1.FelixConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+				legacy := crdv1.IptablesBackend(crdv1.IptablesBackendLegacy)
+				fc.Spec.IptablesBackend = &legacy
+				fc.Spec.LogSeverityScreen = "Info"
+				fc.Spec.BPFExternalServiceMode = "DSR"
+				Expect(c.Update(ctx, fc)).NotTo(HaveOccurred())
+
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+				Expect(*fc.Spec.IptablesBackend).To(Equal(crdv1.IptablesBackend(crdv1.IptablesBackendNFTables)))
+				Expect(fc.Spec.LogSeverityScreen).To(Equal("Debug"))
+				Expect(fc.Spec.BPFExternalServiceMode).To(Equal("DSR"))
+
+				inst := &operator.Installation{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, inst)).NotTo(HaveOccurred())
+				Expect(inst.Status.Conditions).To(HaveLen(1))
+				Expect(inst.Status.Conditions[0].Type).To(Equal(operator.FelixConfigurationModified))
+				Expect(inst.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+				Expect(inst.Status.Conditions[0].Message).To(ContainSubstring("iptablesBackend, logSeverityScreen"))
+
+				By("keeping the condition until the Installation is updated")
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, inst)).NotTo(HaveOccurred())
+				Expect(inst.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			})
+		})
+
+		It("should enable WireGuard and report its readiness on the nodes", func() {
+			enabled := operator.WireGuardEnabled
+			cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+				WireGuard: &operator.WireGuardSpec{IPv4: &enabled},
+			}
+			Expect(c.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:        "node1",
+				Annotations: map[string]string{"projectcalico.org/WireguardPublicKey": "key"},
+			}})).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}})).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   "windows",
+				Labels: map[string]string{corev1.LabelOSStable: "windows"},
+			}})).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+
+			fc := &crdv1.FelixConfiguration{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+			Expect(*fc.Spec.WireguardEnabled).To(BeTrue())
+
+			mockStatus.AssertCalled(GinkgoT(), "SetWireGuardStatus", &operator.TigeraStatusWireGuard{
+				Desired:       2,
+				Ready:         1,
+				NotReadyNodes: []string{"node2"},
+			})
+		})
+
+		It("should bound the nodes that WireGuard is reported not ready on", func() {
+			enabled := operator.WireGuardEnabled
+			cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+				WireGuard: &operator.WireGuardSpec{IPv4: &enabled},
+			}
+			for i := 0; i < 25; i++ {
+				Expect(c.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node%02d", i)}})).NotTo(HaveOccurred())
+			}
+
+			status, err := r.wireGuardStatus(ctx, cr)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.Desired).To(Equal(int32(25)))
+			Expect(status.Ready).To(BeZero())
+			Expect(status.NotReadyNodes).To(HaveLen(20))
+			Expect(status.NotReadyNodes[0]).To(Equal("node00"))
+		})
+
+		It("should re-render the components when the services endpoint ConfigMap changes", func() {
+			defer k8sapi.ResetEndpoint()
+			endpointEnv := func(c corev1.Container) []corev1.EnvVar {
+				var env []corev1.EnvVar
+				for _, e := range c.Env {
+					if e.Name == "KUBERNETES_SERVICE_HOST" || e.Name == "KUBERNETES_SERVICE_PORT" {
+						env = append(env, e)
+					}
+				}
+				return env
+			}
+			expectEndpoint := func(env ...corev1.EnvVar) {
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				ds := &appsv1.DaemonSet{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace}, ds)).NotTo(HaveOccurred())
+				Expect(endpointEnv(ds.Spec.Template.Spec.Containers[0])).To(Equal(env))
+				typha := &appsv1.Deployment{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: common.TyphaDeploymentName, Namespace: common.CalicoNamespace}, typha)).NotTo(HaveOccurred())
+				Expect(endpointEnv(typha.Spec.Template.Spec.Containers[0])).To(Equal(env))
+			}
+
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()},
+				Data:       map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBERNETES_SERVICE_PORT": "6443"},
+			}
+			Expect(c.Create(ctx, cm)).NotTo(HaveOccurred())
+			expectEndpoint(
+				corev1.EnvVar{Name: "KUBERNETES_SERVICE_HOST", Value: "10.0.0.1"},
+				corev1.EnvVar{Name: "KUBERNETES_SERVICE_PORT", Value: "6443"},
+			)
+
+			By("updating the ConfigMap")
+			cm.Data["KUBERNETES_SERVICE_HOST"] = "api.example.com"
+			Expect(c.Update(ctx, cm)).NotTo(HaveOccurred())
+			expectEndpoint(
+				corev1.EnvVar{Name: "KUBERNETES_SERVICE_HOST", Value: "api.example.com"},
+				corev1.EnvVar{Name: "KUBERNETES_SERVICE_PORT", Value: "6443"},
+			)
+
+			By("removing the ConfigMap")
+			Expect(c.Delete(ctx, cm)).NotTo(HaveOccurred())
+			expectEndpoint(k8sapi.DefaultEndpoint().EnvVars(true, cr.Spec.KubernetesProvider)...)
+		})
+
+		It("should render the calico-node DaemonSets of the CalicoNodePools and delete the removed ones", func() {
+			cr.Spec.CalicoNodePools = []operator.CalicoNodePool{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "edge", NodeSelector: map[string]string{"pool": "edge"}},
+			}
+			for _, node := range []*corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "gpu-1", Labels: map[string]string{"pool": "gpu"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "edge-1", Labels: map[string]string{"pool": "edge"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "other-1", Labels: map[string]string{render.CalicoNodePoolLabel: "gpu"}}},
+			} {
+				Expect(c.Create(ctx, node)).NotTo(HaveOccurred())
+			}
+			expectNodePool := func(name, pool string) {
+				node := &corev1.Node{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: name}, node)).NotTo(HaveOccurred())
+				if pool == "" {
+					Expect(node.Labels).NotTo(HaveKey(render.CalicoNodePoolLabel))
+				} else {
+					Expect(node.Labels).To(HaveKeyWithValue(render.CalicoNodePoolLabel, pool))
+				}
+			}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			By("labeling the nodes with the pool that selects them")
+			expectNodePool("gpu-1", "gpu")
+			expectNodePool("edge-1", "edge")
+			expectNodePool("other-1", "")
+
+			for _, name := range []string{"calico-node", "calico-node-gpu", "calico-node-edge"} {
+				Expect(c.Get(ctx, types.NamespacedName{Name: name, Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})).NotTo(HaveOccurred())
+			}
+			mockStatus.AssertCalled(GinkgoT(), "AddDaemonsets", []types.NamespacedName{
+				{Name: "calico-node", Namespace: common.CalicoNamespace},
+				{Name: "calico-node-gpu", Namespace: common.CalicoNamespace},
+				{Name: "calico-node-edge", Namespace: common.CalicoNamespace},
+			})
+
+			Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, cr)).NotTo(HaveOccurred())
+			cr.Spec.CalicoNodePools = cr.Spec.CalicoNodePools[:1]
+			Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = c.Get(ctx, types.NamespacedName{Name: "calico-node-edge", Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(c.Get(ctx, types.NamespacedName{Name: "calico-node-gpu", Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})).NotTo(HaveOccurred())
+			mockStatus.AssertCalled(GinkgoT(), "RemoveDaemonsets", []types.NamespacedName{
+				{Name: "calico-node-edge", Namespace: common.CalicoNamespace},
+			})
+			expectNodePool("gpu-1", "gpu")
+			expectNodePool("edge-1", "")
+		})
+
+		Context("with IP pools", func() {
+			BeforeEach(func() {
+				cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+					IPPools: []operator.IPPool{
+						{Name: "zone-a", CIDR: "192.168.0.0/24", NodeSelector: "zone == 'a'"},
+						{Name: "zone-b", CIDR: "192.168.1.0/24", NodeSelector: "zone == 'b'"},
+					},
+				}
+			})
+
+			It("should reconcile the IPPools and delete the ones it created that were removed once they are drained", func() {
+				Expect(c.Create(ctx, &crdv1.IPPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "zone-c",
+						Labels: map[string]string{render.IPPoolManagedByLabel: render.IPPoolManagedByLabelValue},
+					},
+					Spec: crdv1.IPPoolSpec{CIDR: "192.168.2.0/24"},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &crdv1.IPPool{
+					ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"},
+					Spec:       crdv1.IPPoolSpec{CIDR: "192.168.3.0/24"},
+				})).NotTo(HaveOccurred())
+				block := &crdv1.IPAMBlock{
+					ObjectMeta: metav1.ObjectMeta{Name: "192-168-2-0-26"},
+					Spec:       crdv1.IPAMBlockSpec{CIDR: "192.168.2.0/26"},
+				}
+				Expect(c.Create(ctx, block)).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				poolNames := func() []string {
+					pools := &crdv1.IPPoolList{}
+					Expect(c.List(ctx, pools)).NotTo(HaveOccurred())
+					var names []string
+					for _, p := range pools.Items {
+						names = append(names, p.Name)
+					}
+					return names
+				}
+				Expect(poolNames()).To(ConsistOf("zone-a", "zone-b", "zone-c", "unmanaged"))
+				stale := &crdv1.IPPool{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "zone-c"}, stale)).NotTo(HaveOccurred())
+				Expect(stale.Spec.Disabled).To(BeTrue())
+
+				By("keeping the disabled IPPool while it has IPAM blocks")
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(poolNames()).To(ConsistOf("zone-a", "zone-b", "zone-c", "unmanaged"))
+
+				By("deleting the disabled IPPool once its IPAM blocks are released")
+				Expect(c.Delete(ctx, block)).NotTo(HaveOccurred())
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(poolNames()).To(ConsistOf("zone-a", "zone-b", "unmanaged"))
+
+				pool := &crdv1.IPPool{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "zone-b"}, pool)).NotTo(HaveOccurred())
+				Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+					CIDR:         "192.168.1.0/24",
+					IPIPMode:     crdv1.IPIPModeAlways,
+					VXLANMode:    crdv1.VXLANModeNever,
+					NATOutgoing:  true,
+					BlockSize:    26,
+					NodeSelector: "zone == 'b'",
+				}))
+				Expect(pool.OwnerReferences).To(BeEmpty())
+			})
+
+			It("should only update the fields of an existing IPPool that are set from the Installation", func() {
+				Expect(c.Create(ctx, &crdv1.IPPool{
+					ObjectMeta: metav1.ObjectMeta{Name: "zone-a"},
+					Spec: crdv1.IPPoolSpec{
+						CIDR:      "192.168.0.0/24",
+						BlockSize: 26,
+						VXLANMode: crdv1.VXLANModeAlways,
+						Disabled:  true,
+					},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				pool := &crdv1.IPPool{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "zone-a"}, pool)).NotTo(HaveOccurred())
+				Expect(pool.Labels).To(HaveKeyWithValue(render.IPPoolManagedByLabel, render.IPPoolManagedByLabelValue))
+				Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+					CIDR:         "192.168.0.0/24",
+					BlockSize:    26,
+					IPIPMode:     crdv1.IPIPModeAlways,
+					VXLANMode:    crdv1.VXLANModeNever,
+					NATOutgoing:  true,
+					NodeSelector: "zone == 'a'",
+					Disabled:     true,
+				}))
+			})
+
+			It("should not allow the IP pools to overlap with an IPPool that is not reconciled from the Installation", func() {
+				Expect(c.Create(ctx, &crdv1.IPPool{
+					ObjectMeta: metav1.ObjectMeta{Name: "default-ipv4-ippool"},
+					Spec:       crdv1.IPPoolSpec{CIDR: "192.168.0.0/16"},
+				})).NotTo(HaveOccurred())
+				mockStatus.On("SetDegraded", "Error reconciling IP pools", mock.Anything).Return()
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).To(MatchError("ipPool.CIDR(192.168.0.0/24) of zone-a overlaps with IPPool default-ipv4-ippool"))
+
+				err = c.Get(ctx, types.NamespacedName{Name: "zone-a"}, &crdv1.IPPool{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should not change the CIDR of an existing IPPool", func() {
+				Expect(c.Create(ctx, &crdv1.IPPool{
+					ObjectMeta: metav1.ObjectMeta{Name: "zone-a"},
+					Spec:       crdv1.IPPoolSpec{CIDR: "10.0.0.0/24"},
+				})).NotTo(HaveOccurred())
+				mockStatus.On("SetDegraded", "Error reconciling IP pools", mock.Anything).Return()
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).To(MatchError("the CIDR of IPPool zone-a cannot be changed from 10.0.0.0/24 to 192.168.0.0/24, add a new IP pool instead"))
+
+				pool := &crdv1.IPPool{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "zone-a"}, pool)).NotTo(HaveOccurred())
+				Expect(pool.Spec.CIDR).To(Equal("10.0.0.0/24"))
+			})
+		})
+
+		Context("with Multus", func() {
+			BeforeEach(func() {
+				multus := operator.MultiInterfaceModeMultus
+				manual := operator.IPPoolAssignmentManual
+				nads := operator.NetworkAttachmentDefinitionsEnabled
+				cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+					MultiInterfaceMode: &multus,
+					Multus:             &operator.MultusSpec{NetworkAttachmentDefinitions: &nads},
+					IPPools: []operator.IPPool{
+						{Name: "default-ipv4-ippool", CIDR: "192.168.0.0/24"},
+						{Name: "secondary", CIDR: "172.16.0.0/24", AssignmentMode: &manual},
+					},
+				}
+			})
+
+			It("should degrade until Multus is installed", func() {
+				mockStatus.On("SetDegraded", "Multus is not installed", mock.Anything).Return()
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				result, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Multus is not installed",
+					"the network-attachment-definitions.k8s.cni.cncf.io CRD does not exist")
+				mockStatus.AssertNotCalled(GinkgoT(), "ClearDegraded")
+
+				By("still reconciling the rest of the Installation")
+				Expect(c.Get(ctx, types.NamespacedName{Name: "calico-node", Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})).NotTo(HaveOccurred())
+
+				Expect(c.Create(ctx, &apiextenv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: nadv1.NetworkAttachmentDefinitionCRDName},
+				})).NotTo(HaveOccurred())
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Multus is not installed", "no DaemonSet labeled app=multus exists")
+			})
+
+			It("should reconcile the NetworkAttachmentDefinitions of the secondary IP pools", func() {
+				Expect(c.Create(ctx, &apiextenv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: nadv1.NetworkAttachmentDefinitionCRDName},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "kube-multus-ds", Namespace: "kube-system", Labels: map[string]string{"app": "multus"}},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &nadv1.NetworkAttachmentDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "removed",
+						Namespace: render.MultusNetworkAttachmentDefinitionsNamespace,
+						Labels:    map[string]string{render.IPPoolManagedByLabel: render.IPPoolManagedByLabelValue},
+					},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &nadv1.NetworkAttachmentDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: render.MultusNetworkAttachmentDefinitionsNamespace},
+				})).NotTo(HaveOccurred())
+
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				nads := &nadv1.NetworkAttachmentDefinitionList{}
+				Expect(c.List(ctx, nads)).NotTo(HaveOccurred())
+				var names []string
+				for _, nad := range nads.Items {
+					names = append(names, nad.Name)
+				}
+				Expect(names).To(ConsistOf("secondary", "unmanaged"))
+
+				nad := &nadv1.NetworkAttachmentDefinition{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "secondary", Namespace: "default"}, nad)).NotTo(HaveOccurred())
+				Expect(nad.Spec.Config).To(ContainSubstring(`"ipv4_pools": ["secondary"]`))
+			})
+		})
+
+		It("should render and monitor the calico-vpp-node DaemonSet of the VPP dataplane", func() {
+			vpp := operator.LinuxDataplaneVPP
+			cr.Spec.Variant = operator.Calico
+			cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+				LinuxDataplane: &vpp,
+				VPP:            &operator.VPPSpec{UplinkInterface: "eth1"},
+			}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ds := &appsv1.DaemonSet{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "calico-vpp-node", Namespace: common.CalicoNamespace}, ds)).NotTo(HaveOccurred())
+			mockStatus.AssertCalled(GinkgoT(), "AddDaemonsets", []types.NamespacedName{{Name: "calico-vpp-node", Namespace: common.CalicoNamespace}})
+
+			By("removing the VPP settings")
+			Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, cr)).NotTo(HaveOccurred())
+			cr.Spec.CalicoNetwork.VPP = nil
+			Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = c.Get(ctx, types.NamespacedName{Name: "calico-vpp-node", Namespace: common.CalicoNamespace}, ds)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			mockStatus.AssertCalled(GinkgoT(), "RemoveDaemonsets", []types.NamespacedName{{Name: "calico-vpp-node", Namespace: common.CalicoNamespace}})
+		})
+
+		Context("with service advertisement", func() {
+			BeforeEach(func() {
+				cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+					IPPools: []operator.IPPool{{CIDR: "192.168.0.0/16"}},
+					ServiceAdvertisement: &operator.ServiceAdvertisementSpec{
+						ClusterIPCIDRs:     []string{"10.96.0.0/12"},
+						LoadBalancerIPPool: &operator.LoadBalancerIPPool{CIDR: "172.17.0.0/24"},
+					},
+				}
+			})
+
+			It("should advertise the Service CIDRs and assign LoadBalancer IPs", func() {
+				Expect(c.Create(ctx, &crdv1.BGPConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "default"},
+					Spec: crdv1.BGPConfigurationSpec{
+						ServiceClusterIPs:  []crdv1.ServiceClusterIPBlock{{CIDR: "10.0.0.0/16"}},
+						ServiceExternalIPs: []crdv1.ServiceExternalIPBlock{{CIDR: "172.16.0.0/24"}},
+					},
+				})).NotTo(HaveOccurred())
+
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				bc := &crdv1.BGPConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, bc)).NotTo(HaveOccurred())
+				Expect(bc.Spec.ServiceClusterIPs).To(Equal([]crdv1.ServiceClusterIPBlock{{CIDR: "10.96.0.0/12"}}))
+				Expect(bc.Spec.ServiceLoadBalancerIPs).To(Equal([]crdv1.ServiceLoadBalancerIPBlock{{CIDR: "172.17.0.0/24"}}))
+				By("leaving the Service CIDRs that the Installation does not specify as they are")
+				Expect(bc.Spec.ServiceExternalIPs).To(Equal([]crdv1.ServiceExternalIPBlock{{CIDR: "172.16.0.0/24"}}))
+
+				pool := &crdv1.IPPool{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "loadbalancer-ippool"}, pool)).NotTo(HaveOccurred())
+				Expect(pool.Spec.CIDR).To(Equal("172.17.0.0/24"))
+				Expect(pool.Spec.AllowedUses).To(Equal([]crdv1.IPPoolAllowedUse{crdv1.IPPoolAllowedUseLoadBalancer}))
+				Expect(pool.OwnerReferences).To(BeEmpty())
+
+				By("not writing the LoadBalancer controller to the KubeControllersConfiguration, which the CRD does not define")
+				err = c.Get(ctx, types.NamespacedName{Name: "default"}, &crdv1.KubeControllersConfiguration{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should clear the Service CIDRs it advertised once they are removed, unless they were edited", func() {
+				Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				bc := &crdv1.BGPConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, bc)).NotTo(HaveOccurred())
+				bc.Spec.ServiceClusterIPs = []crdv1.ServiceClusterIPBlock{{CIDR: "10.0.0.0/16"}}
+				Expect(c.Update(ctx, bc)).NotTo(HaveOccurred())
+
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, cr)).NotTo(HaveOccurred())
+				cr.Spec.CalicoNetwork.ServiceAdvertisement = nil
+				Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				bc = &crdv1.BGPConfiguration{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, bc)).NotTo(HaveOccurred())
+				Expect(bc.Spec.ServiceClusterIPs).To(Equal([]crdv1.ServiceClusterIPBlock{{CIDR: "10.0.0.0/16"}}))
+				Expect(bc.Spec.ServiceLoadBalancerIPs).To(BeEmpty())
+				Expect(bc.Annotations).NotTo(HaveKey("operator.tigera.io/bgp-managed-fields"))
+
+				By("disabling the LoadBalancer IPPool before deleting it")
+				pool := &crdv1.IPPool{}
+				Expect(c.Get(ctx, types.NamespacedName{Name: "loadbalancer-ippool"}, pool)).NotTo(HaveOccurred())
+				Expect(pool.Spec.Disabled).To(BeTrue())
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				err = c.Get(ctx, types.NamespacedName{Name: "loadbalancer-ippool"}, &crdv1.IPPool{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
 		It("should Reconcile with GKE and create a resource quota", func() {
 			cr.Spec.KubernetesProvider = operator.ProviderGKE
 			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
@@ -0,0 +1,386 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	configv1 "github.com/openshift/api/config/v1"
+	ocsoperatorv1 "github.com/openshift/api/operator/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	// kubeProxyNodeSelectorKey is added to the nodeSelector of the kube-proxy DaemonSet to disable kube-proxy for the
+	// eBPF dataplane. kube-proxy keeps running on the nodes that are labelled with it, which is useful for nodes
+	// that don't run Calico.
+	kubeProxyNodeSelectorKey = "operator.tigera.io/run-kube-proxy"
+
+	// openShiftKubeProxyDisabledAnnotation is set on the OpenShift Network operator configuration when the operator
+	// has disabled kube-proxy through it, so that only kube-proxy that was disabled by the operator is enabled again.
+	openShiftKubeProxyDisabledAnnotation = "operator.tigera.io/kube-proxy-disabled"
+
+	// The kubeconfig of kube-proxy holds the address of the API server on kubeadm based clusters.
+	kubeProxyConfigMapName      = "kube-proxy"
+	kubeProxyKubeconfigKey      = "kubeconfig.conf"
+	openShiftInfrastructureName = "cluster"
+)
+
+var (
+	kubeProxyDaemonSet          = types.NamespacedName{Namespace: "kube-system", Name: "kube-proxy"}
+	openShiftKubeProxyDaemonSet = types.NamespacedName{Namespace: "openshift-kube-proxy", Name: "openshift-kube-proxy"}
+)
+
+// kubeProxyManagedError is returned when the switch to the eBPF dataplane is refused because kube-proxy is an add-on
+// of the Kubernetes provider, which deploys it again when the operator disables it.
+type kubeProxyManagedError struct {
+	provider operator.Provider
+}
+
+func (e kubeProxyManagedError) Error() string {
+	return fmt.Sprintf("kube-proxy is managed by %s and is deployed again when the operator disables it, disable kube-proxy through %s to switch to the eBPF dataplane",
+		e.provider, e.provider)
+}
+
+// kubeProxyManagedByProvider returns whether kube-proxy is an add-on that the Kubernetes provider reconciles.
+func kubeProxyManagedByProvider(install *operator.Installation) bool {
+	switch install.Spec.KubernetesProvider {
+	case operator.ProviderAKS, operator.ProviderEKS, operator.ProviderGKE:
+		return true
+	}
+	return false
+}
+
+// switchDataplane moves the cluster to the Linux dataplane of the Installation, one phase at a time, and returns the
+// progress along with whether calico-node should be rendered with the eBPF dataplane.
+//
+// Switching to the eBPF dataplane first persists the address of the API server, since the eBPF dataplane cannot
+// reach it through the kubernetes service without kube-proxy. Then calico-node is rolled out with the eBPF dataplane,
+// and once it is ready, kube-proxy is disabled. Switching back enables kube-proxy again, and calico-node only leaves
+// the eBPF dataplane once kube-proxy is ready.
+//
+// On AKS, EKS and GKE, kube-proxy is managed by the provider, so the operator does not disable it. The switch to the
+// eBPF dataplane is refused with a kubeProxyManagedError, along with the progress, until kube-proxy has been disabled
+// through the provider.
+func (r *ReconcileInstallation) switchDataplane(ctx context.Context, install *operator.Installation) (*operator.TigeraStatusDataplane, bool, error) {
+	target := operator.LinuxDataplaneIptables
+	if install.Spec.CalicoNetwork != nil && install.Spec.CalicoNetwork.LinuxDataplane != nil {
+		target = *install.Spec.CalicoNetwork.LinuxDataplane
+	}
+	status := &operator.TigeraStatusDataplane{LinuxDataplane: target}
+
+	nodeBPF, nodeRolledOut, err := r.calicoNodeDataplane(ctx, install)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if target == operator.LinuxDataplaneBPF {
+		configured, err := r.ensureAPIServerEndpoint(ctx, install)
+		if err != nil {
+			return nil, false, err
+		}
+		if !configured {
+			status.Phase = operator.DataplanePhaseConfiguringAPIServerEndpoint
+			status.Message = fmt.Sprintf("The address of the API server could not be discovered, add it to the %s ConfigMap in the %s namespace",
+				render.K8sSvcEndpointConfigMapName, common.OperatorNamespace())
+			return status, nodeBPF, nil
+		}
+		managed := kubeProxyManagedByProvider(install)
+		if managed {
+			if running, err := r.kubeProxyRunning(ctx); err != nil {
+				return nil, false, err
+			} else if running {
+				status.Phase = operator.DataplanePhaseDisablingKubeProxy
+				status.Message = fmt.Sprintf("Waiting for kube-proxy to be disabled through %s", install.Spec.KubernetesProvider)
+				return status, nodeBPF, kubeProxyManagedError{provider: install.Spec.KubernetesProvider}
+			}
+		}
+		if !nodeBPF || !nodeRolledOut {
+			status.Phase = operator.DataplanePhaseRollingOutCalicoNode
+			status.Message = "Waiting for calico-node to be ready with the eBPF dataplane"
+			return status, true, nil
+		}
+		if !managed {
+			if err := r.setKubeProxyEnabled(ctx, install, false); err != nil {
+				return nil, false, err
+			}
+		}
+		if rolledOut, err := r.kubeProxyRolledOut(ctx, install, false); err != nil {
+			return nil, false, err
+		} else if !rolledOut {
+			status.Phase = operator.DataplanePhaseDisablingKubeProxy
+			status.Message = "Waiting for kube-proxy to be removed from the nodes"
+			return status, true, nil
+		}
+		status.Phase = operator.DataplanePhaseComplete
+		return status, true, nil
+	}
+
+	if err := r.setKubeProxyEnabled(ctx, install, true); err != nil {
+		return nil, false, err
+	}
+	if nodeBPF {
+		// The cluster network operator of OpenShift deploys kube-proxy again once it is enabled, so it is expected.
+		// The providers that manage kube-proxy deploy it again once it is enabled through them.
+		managed := kubeProxyManagedByProvider(install)
+		required := install.Spec.KubernetesProvider == operator.ProviderOpenShift || managed
+		if rolledOut, err := r.kubeProxyRolledOut(ctx, install, required); err != nil {
+			return nil, false, err
+		} else if !rolledOut {
+			status.Phase = operator.DataplanePhaseEnablingKubeProxy
+			status.Message = "Waiting for kube-proxy to be ready before calico-node leaves the eBPF dataplane"
+			if managed {
+				status.Message = fmt.Sprintf("Waiting for kube-proxy to be enabled through %s and ready before calico-node leaves the eBPF dataplane",
+					install.Spec.KubernetesProvider)
+			}
+			return status, true, nil
+		}
+	}
+	if nodeBPF || !nodeRolledOut {
+		status.Phase = operator.DataplanePhaseRollingOutCalicoNode
+		status.Message = fmt.Sprintf("Waiting for calico-node to be ready with the %s dataplane", target)
+		return status, false, nil
+	}
+	status.Phase = operator.DataplanePhaseComplete
+	return status, false, nil
+}
+
+// calicoNodeDataplane returns whether the calico-node DaemonSet runs the eBPF dataplane, and whether it and the
+// calico-node DaemonSets of the CalicoNodePools have been rolled out.
+func (r *ReconcileInstallation) calicoNodeDataplane(ctx context.Context, install *operator.Installation) (bool, bool, error) {
+	ds := &appsv1.DaemonSet{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: common.CalicoNamespace, Name: common.NodeDaemonSetName}, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	bpf := false
+	for _, c := range ds.Spec.Template.Spec.Containers {
+		if c.Name != "calico-node" {
+			continue
+		}
+		for _, env := range c.Env {
+			if env.Name == "FELIX_BPFENABLED" && env.Value == "true" {
+				bpf = true
+			}
+		}
+	}
+	if !daemonSetRolledOut(ds) {
+		return bpf, false, nil
+	}
+
+	// The DaemonSets of the pools are rendered with the same dataplane as the calico-node DaemonSet.
+	for _, pool := range install.Spec.CalicoNodePools {
+		poolDS := &appsv1.DaemonSet{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: common.CalicoNamespace, Name: render.NodePoolDaemonSetName(pool.Name)}, poolDS); err != nil {
+			if apierrors.IsNotFound(err) {
+				return bpf, false, nil
+			}
+			return false, false, err
+		}
+		if !daemonSetRolledOut(poolDS) {
+			return bpf, false, nil
+		}
+	}
+	return bpf, true, nil
+}
+
+// kubeProxyRolledOut returns true if the kube-proxy DaemonSet has been rolled out, or if there is none and it is not
+// required.
+func (r *ReconcileInstallation) kubeProxyRolledOut(ctx context.Context, install *operator.Installation, required bool) (bool, error) {
+	key := kubeProxyDaemonSet
+	if install.Spec.KubernetesProvider == operator.ProviderOpenShift {
+		key = openShiftKubeProxyDaemonSet
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := r.client.Get(ctx, key, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			return !required, nil
+		}
+		return false, err
+	}
+	return daemonSetRolledOut(ds), nil
+}
+
+// kubeProxyRunning returns true if kube-proxy is scheduled on, or still running on, any of the nodes.
+func (r *ReconcileInstallation) kubeProxyRunning(ctx context.Context) (bool, error) {
+	ds := &appsv1.DaemonSet{}
+	if err := r.client.Get(ctx, kubeProxyDaemonSet, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return ds.Status.DesiredNumberScheduled > 0 || ds.Status.NumberAvailable > 0, nil
+}
+
+func daemonSetRolledOut(ds *appsv1.DaemonSet) bool {
+	return ds.Status.ObservedGeneration >= ds.Generation &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberAvailable == ds.Status.DesiredNumberScheduled
+}
+
+// setKubeProxyEnabled disables kube-proxy, or enables it again if it was disabled by the operator. On OpenShift,
+// kube-proxy is deployed by the cluster network operator, so it is disabled through its configuration. Otherwise,
+// kube-proxy is disabled by adding a nodeSelector to its DaemonSet that the nodes don't match.
+func (r *ReconcileInstallation) setKubeProxyEnabled(ctx context.Context, install *operator.Installation, enabled bool) error {
+	if install.Spec.KubernetesProvider == operator.ProviderOpenShift {
+		network := &ocsoperatorv1.Network{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: openshiftNetworkConfig}, network); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		patchFrom := client.MergeFrom(network.DeepCopy())
+		disabled := network.Annotations[openShiftKubeProxyDisabledAnnotation] == "true"
+		switch {
+		case enabled && disabled:
+			network.Spec.DeployKubeProxy = nil
+			delete(network.Annotations, openShiftKubeProxyDisabledAnnotation)
+		case !enabled && !disabled:
+			deploy := false
+			network.Spec.DeployKubeProxy = &deploy
+			if network.Annotations == nil {
+				network.Annotations = map[string]string{}
+			}
+			network.Annotations[openShiftKubeProxyDisabledAnnotation] = "true"
+		default:
+			return nil
+		}
+		return r.client.Patch(ctx, network, patchFrom)
+	}
+
+	ds := &appsv1.DaemonSet{}
+	if err := r.client.Get(ctx, kubeProxyDaemonSet, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	patchFrom := client.MergeFrom(ds.DeepCopy())
+	_, disabled := ds.Spec.Template.Spec.NodeSelector[kubeProxyNodeSelectorKey]
+	switch {
+	case enabled && disabled:
+		delete(ds.Spec.Template.Spec.NodeSelector, kubeProxyNodeSelectorKey)
+	case !enabled && !disabled:
+		if ds.Spec.Template.Spec.NodeSelector == nil {
+			ds.Spec.Template.Spec.NodeSelector = map[string]string{}
+		}
+		ds.Spec.Template.Spec.NodeSelector[kubeProxyNodeSelectorKey] = "true"
+	default:
+		return nil
+	}
+	return r.client.Patch(ctx, ds, patchFrom)
+}
+
+// ensureAPIServerEndpoint returns true if the address of the API server is configured in the services endpoint
+// ConfigMap. When it is not, the address is discovered and persisted in the ConfigMap, so that calico-node reaches
+// the API server directly.
+func (r *ReconcileInstallation) ensureAPIServerEndpoint(ctx context.Context, install *operator.Installation) (bool, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()}, cm)
+	if err == nil {
+		return cm.Data["KUBERNETES_SERVICE_HOST"] != "" && cm.Data["KUBERNETES_SERVICE_PORT"] != "", nil
+	} else if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	host, port, err := r.discoverAPIServerEndpoint(ctx, install)
+	if err != nil || host == "" {
+		return false, err
+	}
+	cm = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()},
+		Data: map[string]string{
+			"KUBERNETES_SERVICE_HOST": host,
+			"KUBERNETES_SERVICE_PORT": port,
+		},
+	}
+	if err := r.client.Create(ctx, cm); err != nil {
+		return false, err
+	}
+	k8sapi.SetEndpoint(k8sapi.ServiceEndpoint{Host: host, Port: port})
+	return true, nil
+}
+
+// discoverAPIServerEndpoint returns the host and port of the API server, as configured for the internal clients of
+// the cluster, or an empty host if it can't be found.
+func (r *ReconcileInstallation) discoverAPIServerEndpoint(ctx context.Context, install *operator.Installation) (string, string, error) {
+	var server string
+	if install.Spec.KubernetesProvider == operator.ProviderOpenShift {
+		infra := &configv1.Infrastructure{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: openShiftInfrastructureName}, infra); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", "", nil
+			}
+			return "", "", err
+		}
+		server = infra.Status.APIServerInternalURL
+	} else {
+		cm := &corev1.ConfigMap{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: kubeProxyConfigMapName, Namespace: kubeProxyDaemonSet.Namespace}, cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", "", nil
+			}
+			return "", "", err
+		}
+		config, err := clientcmd.Load([]byte(cm.Data[kubeProxyKubeconfigKey]))
+		if err != nil {
+			log.Info("Unable to read the API server address from the kube-proxy kubeconfig", "reason", err.Error())
+			return "", "", nil
+		}
+		if kctx, ok := config.Contexts[config.CurrentContext]; ok && config.Clusters[kctx.Cluster] != nil {
+			server = config.Clusters[kctx.Cluster].Server
+		} else if len(config.Clusters) == 1 {
+			for _, c := range config.Clusters {
+				server = c.Server
+			}
+		}
+	}
+	if server == "" {
+		return "", "", nil
+	}
+
+	u, err := url.Parse(server)
+	if err != nil || u.Hostname() == "" {
+		log.Info("Ignoring invalid API server address", "address", server)
+		return "", "", nil
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && ip.IsLoopback() {
+		// The address is only reachable from the node that kube-proxy runs on, for example through a local proxy.
+		return "", "", nil
+	}
+	return u.Hostname(), port, nil
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ocsoperatorv1 "github.com/openshift/api/operator/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const kubeProxyKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://10.0.0.1:6443
+  name: default
+contexts:
+- context:
+    cluster: default
+  name: default
+current-context: default
+`
+
+var _ = Describe("Linux dataplane switch", func() {
+	var c client.Client
+	var ctx context.Context
+	var r *ReconcileInstallation
+	var install *operator.Installation
+
+	calicoNode := func(bpf bool, rolledOut bool) *appsv1.DaemonSet {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "calico-node"}},
+			}}},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+		}
+		if bpf {
+			ds.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "FELIX_BPFENABLED", Value: "true"}}
+		}
+		if !rolledOut {
+			ds.Status.NumberAvailable = 2
+		}
+		return ds
+	}
+
+	kubeProxy := func(nodeSelector map[string]string, desired int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-proxy", Namespace: "kube-system"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				NodeSelector: nodeSelector,
+			}}},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired, UpdatedNumberScheduled: desired, NumberAvailable: desired},
+		}
+	}
+
+	setKubeProxyStatus := func(desired, available int32) {
+		ds := &appsv1.DaemonSet{}
+		Expect(c.Get(ctx, kubeProxyDaemonSet, ds)).NotTo(HaveOccurred())
+		ds.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: desired, UpdatedNumberScheduled: desired, NumberAvailable: available}
+		Expect(c.Update(ctx, ds)).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(operator.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+		r = &ReconcileInstallation{client: c, scheme: scheme}
+
+		bpf := operator.LinuxDataplaneBPF
+		install = &operator.Installation{Spec: operator.InstallationSpec{
+			CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &bpf},
+		}}
+	})
+
+	AfterEach(func() {
+		k8sapi.ResetEndpoint()
+	})
+
+	Context("to the eBPF dataplane", func() {
+		It("should persist the API server address from the kube-proxy kubeconfig", func() {
+			Expect(c.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "kube-proxy", Namespace: "kube-system"},
+				Data:       map[string]string{"kubeconfig.conf": kubeProxyKubeconfig},
+			})).NotTo(HaveOccurred())
+
+			status, bpf, err := r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeTrue())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseRollingOutCalicoNode))
+
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()}, cm)).NotTo(HaveOccurred())
+			Expect(cm.Data).To(Equal(map[string]string{
+				"KUBERNETES_SERVICE_HOST": "10.0.0.1",
+				"KUBERNETES_SERVICE_PORT": "6443",
+			}))
+			Expect(k8sapi.Endpoint()).To(Equal(k8sapi.ServiceEndpoint{Host: "10.0.0.1", Port: "6443"}))
+		})
+
+		It("should wait for the API server address when it can't be discovered", func() {
+			Expect(c.Create(ctx, calicoNode(false, true))).NotTo(HaveOccurred())
+
+			status, bpf, err := r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeFalse())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseConfiguringAPIServerEndpoint))
+		})
+
+		It("should disable kube-proxy once calico-node is ready", func() {
+			Expect(c.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()},
+				Data:       map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBERNETES_SERVICE_PORT": "6443"},
+			})).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, calicoNode(true, false))).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, kubeProxy(nil, 3))).NotTo(HaveOccurred())
+
+			By("waiting for calico-node to roll out")
+			status, bpf, err := r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeTrue())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseRollingOutCalicoNode))
+			ds := &appsv1.DaemonSet{}
+			Expect(c.Get(ctx, kubeProxyDaemonSet, ds)).NotTo(HaveOccurred())
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(BeEmpty())
+
+			By("disabling kube-proxy")
+			Expect(c.Update(ctx, calicoNode(true, true))).NotTo(HaveOccurred())
+			setKubeProxyStatus(0, 3)
+			status, _, err = r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseDisablingKubeProxy))
+			Expect(c.Get(ctx, kubeProxyDaemonSet, ds)).NotTo(HaveOccurred())
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue(kubeProxyNodeSelectorKey, "true"))
+
+			By("completing once kube-proxy is removed")
+			setKubeProxyStatus(0, 0)
+			status, bpf, err = r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeTrue())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseComplete))
+		})
+	})
+
+	Context("back to the iptables dataplane", func() {
+		BeforeEach(func() {
+			iptables := operator.LinuxDataplaneIptables
+			install.Spec.CalicoNetwork.LinuxDataplane = &iptables
+		})
+
+		It("should enable kube-proxy before calico-node leaves the eBPF dataplane", func() {
+			Expect(c.Create(ctx, calicoNode(true, true))).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, kubeProxy(map[string]string{"kubernetes.io/os": "linux", kubeProxyNodeSelectorKey: "true"}, 0))).NotTo(HaveOccurred())
+			setKubeProxyStatus(3, 0)
+
+			status, bpf, err := r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeTrue())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseEnablingKubeProxy))
+			ds := &appsv1.DaemonSet{}
+			Expect(c.Get(ctx, kubeProxyDaemonSet, ds)).NotTo(HaveOccurred())
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
+
+			setKubeProxyStatus(3, 3)
+			status, bpf, err = r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeFalse())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseRollingOutCalicoNode))
+
+			Expect(c.Update(ctx, calicoNode(false, true))).NotTo(HaveOccurred())
+			status, bpf, err = r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeFalse())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseComplete))
+		})
+
+		It("should not wait for kube-proxy when calico-node never ran the eBPF dataplane", func() {
+			Expect(c.Create(ctx, calicoNode(false, true))).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, kubeProxy(nil, 3))).NotTo(HaveOccurred())
+			setKubeProxyStatus(3, 1)
+
+			status, bpf, err := r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeFalse())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseComplete))
+		})
+	})
+
+	Context("on a provider that manages kube-proxy", func() {
+		BeforeEach(func() {
+			install.Spec.KubernetesProvider = operator.ProviderEKS
+			Expect(c.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: render.K8sSvcEndpointConfigMapName, Namespace: common.OperatorNamespace()},
+				Data:       map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "KUBERNETES_SERVICE_PORT": "6443"},
+			})).NotTo(HaveOccurred())
+		})
+
+		It("should refuse to switch to the eBPF dataplane until kube-proxy is disabled through the provider", func() {
+			Expect(c.Create(ctx, calicoNode(false, true))).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, kubeProxy(nil, 3))).NotTo(HaveOccurred())
+
+			status, bpf, err := r.switchDataplane(ctx, install)
+			Expect(err).To(MatchError(kubeProxyManagedError{provider: operator.ProviderEKS}))
+			Expect(bpf).To(BeFalse())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseDisablingKubeProxy))
+			Expect(status.Message).To(Equal("Waiting for kube-proxy to be disabled through EKS"))
+			ds := &appsv1.DaemonSet{}
+			Expect(c.Get(ctx, kubeProxyDaemonSet, ds)).NotTo(HaveOccurred())
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(BeEmpty())
+
+			By("switching once kube-proxy is no longer running")
+			Expect(c.Delete(ctx, ds)).NotTo(HaveOccurred())
+			status, bpf, err = r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeTrue())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseRollingOutCalicoNode))
+
+			Expect(c.Update(ctx, calicoNode(true, true))).NotTo(HaveOccurred())
+			status, bpf, err = r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeTrue())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseComplete))
+		})
+
+		It("should wait for kube-proxy to be enabled through the provider before calico-node leaves the eBPF dataplane", func() {
+			iptables := operator.LinuxDataplaneIptables
+			install.Spec.CalicoNetwork.LinuxDataplane = &iptables
+			Expect(c.Create(ctx, calicoNode(true, true))).NotTo(HaveOccurred())
+
+			status, bpf, err := r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeTrue())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseEnablingKubeProxy))
+
+			Expect(c.Create(ctx, kubeProxy(nil, 3))).NotTo(HaveOccurred())
+			status, bpf, err = r.switchDataplane(ctx, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bpf).To(BeFalse())
+			Expect(status.Phase).To(Equal(operator.DataplanePhaseRollingOutCalicoNode))
+		})
+	})
+
+	Context("on OpenShift", func() {
+		BeforeEach(func() {
+			install.Spec.KubernetesProvider = operator.ProviderOpenShift
+			Expect(c.Create(ctx, &ocsoperatorv1.Network{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})).NotTo(HaveOccurred())
+		})
+
+		It("should disable kube-proxy through the cluster network operator and enable it again", func() {
+			Expect(r.setKubeProxyEnabled(ctx, install, false)).NotTo(HaveOccurred())
+			network := &ocsoperatorv1.Network{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "cluster"}, network)).NotTo(HaveOccurred())
+			Expect(*network.Spec.DeployKubeProxy).To(BeFalse())
+			Expect(network.Annotations).To(HaveKeyWithValue(openShiftKubeProxyDisabledAnnotation, "true"))
+
+			Expect(r.setKubeProxyEnabled(ctx, install, true)).NotTo(HaveOccurred())
+			network = &ocsoperatorv1.Network{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "cluster"}, network)).NotTo(HaveOccurred())
+			Expect(network.Spec.DeployKubeProxy).To(BeNil())
+			Expect(network.Annotations).NotTo(HaveKey(openShiftKubeProxyDisabledAnnotation))
+		})
+
+		It("should not enable kube-proxy that it did not disable", func() {
+			deploy := false
+			network := &ocsoperatorv1.Network{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "cluster"}, network)).NotTo(HaveOccurred())
+			network.Spec.DeployKubeProxy = &deploy
+			Expect(c.Update(ctx, network)).NotTo(HaveOccurred())
+
+			Expect(r.setKubeProxyEnabled(ctx, install, true)).NotTo(HaveOccurred())
+			network = &ocsoperatorv1.Network{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "cluster"}, network)).NotTo(HaveOccurred())
+			Expect(*network.Spec.DeployKubeProxy).To(BeFalse())
+		})
+	})
+})
@@ -86,6 +86,8 @@ var _ = Describe("Defaulting logic tests", func() {
 		var oneTwoThree int32 = 123
 		var one intstr.IntOrString = intstr.FromInt(1)
 		var replicas int32 = 3
+		automatic := operator.IPPoolAssignmentAutomatic
+		manual := operator.IPPoolAssignmentManual
 
 		hpEnabled := operator.HostPortsEnabled
 		disabled := operator.BGPDisabled
@@ -113,18 +115,31 @@ var _ = Describe("Defaulting logic tests", func() {
 					LinuxDataplane: &dpIptables, // Actually the default but BPF would make other values invalid.
 					IPPools: []operator.IPPool{
 						{
-							CIDR:          "1.2.3.0/24",
-							Encapsulation: "VXLANCrossSubnet",
-							NATOutgoing:   "Enabled",
-							NodeSelector:  "has(thiskey)",
-							BlockSize:     &twentySeven,
+							Name:           "zone-a",
+							CIDR:           "1.2.3.0/24",
+							Encapsulation:  "VXLANCrossSubnet",
+							NATOutgoing:    "Enabled",
+							NodeSelector:   "has(thiskey)",
+							BlockSize:      &twentySeven,
+							AssignmentMode: &automatic,
 						},
 						{
-							CIDR:          "fd00::0/64",
-							Encapsulation: "None",
-							NATOutgoing:   "Enabled",
-							NodeSelector:  "has(thiskey)",
-							BlockSize:     &oneTwoThree,
+							Name:           "reserved",
+							CIDR:           "1.2.4.0/24",
+							Encapsulation:  "VXLANCrossSubnet",
+							NATOutgoing:    "Disabled",
+							NodeSelector:   "has(thiskey)",
+							BlockSize:      &twentySeven,
+							AssignmentMode: &manual,
+						},
+						{
+							Name:           "zone-a-v6",
+							CIDR:           "fd00::0/64",
+							Encapsulation:  "None",
+							NATOutgoing:    "Enabled",
+							NodeSelector:   "has(thiskey)",
+							BlockSize:      &oneTwoThree,
+							AssignmentMode: &automatic,
 						},
 					},
 					MTU: &mtu,
@@ -163,6 +178,7 @@ var _ = Describe("Defaulting logic tests", func() {
 		var twentySeven int32 = 27
 		var one intstr.IntOrString = intstr.FromInt(1)
 		var replicas int32 = 3
+		automatic := operator.IPPoolAssignmentAutomatic
 
 		disabled := operator.BGPDisabled
 		miMode := operator.MultiInterfaceModeNone
@@ -190,11 +206,13 @@ var _ = Describe("Defaulting logic tests", func() {
 					LinuxDataplane: &dpBPF, // Actually the default but BPF would make other values invalid.
 					IPPools: []operator.IPPool{
 						{
-							CIDR:          "1.2.3.0/24",
-							Encapsulation: "VXLANCrossSubnet",
-							NATOutgoing:   "Enabled",
-							NodeSelector:  "has(thiskey)",
-							BlockSize:     &twentySeven,
+							Name:           "zone-a",
+							CIDR:           "1.2.3.0/24",
+							Encapsulation:  "VXLANCrossSubnet",
+							NATOutgoing:    "Enabled",
+							NodeSelector:   "has(thiskey)",
+							BlockSize:      &twentySeven,
+							AssignmentMode: &automatic,
 						},
 					},
 					MTU: &mtu,
@@ -563,6 +581,23 @@ var _ = Describe("Defaulting logic tests", func() {
 			}))
 			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
 		})
+
+		It("should only default IPv6 autodetection with BPF enabled and only IPv6 pools", func() {
+			dpBPF := operator.LinuxDataplaneBPF
+			instance := &operator.Installation{
+				Spec: operator.InstallationSpec{
+					CalicoNetwork: &operator.CalicoNetworkSpec{
+						LinuxDataplane: &dpBPF,
+						IPPools:        []operator.IPPool{{CIDR: "fd00::/64"}},
+					},
+				},
+			}
+			err := fillDefaults(instance)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4).To(BeNil())
+			Expect(instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6).NotTo(BeNil())
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
 	})
 
 	table.DescribeTable("should default IPAM type based on CNI type",
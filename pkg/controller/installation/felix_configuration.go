@@ -0,0 +1,268 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+)
+
+// felixManagedFieldsAnnotation records, on the default FelixConfiguration, the values of the fields that are
+// managed through the Felix settings of the Installation as they were last applied. The fields are keyed by their
+// JSON name.
+const felixManagedFieldsAnnotation = "operator.tigera.io/felix-managed-fields"
+
+// setFelixConfiguration applies the Felix settings of the Installation to the passed in fc. Settings that are not
+// specified on the Installation are left as they are. The applied values are recorded on fc, so that the managed
+// fields that were edited directly since they were last applied are detected. Their JSON names are returned, after
+// the edits have been reverted. If the FelixConfiguration ResourceVersion is empty, then the default
+// FelixConfiguration will be created, otherwise a patch will be performed.
+func (r *ReconcileInstallation) setFelixConfiguration(ctx context.Context, install *operator.Installation, fc *crdv1.FelixConfiguration, log logr.Logger) ([]string, error) {
+	felix := install.Spec.Felix
+	var wireGuard *operator.WireGuardSpec
+	if install.Spec.CalicoNetwork != nil {
+		wireGuard = install.Spec.CalicoNetwork.WireGuard
+	}
+	if felix == nil && wireGuard == nil && fc.Annotations[felixManagedFieldsAnnotation] == "" {
+		return nil, nil
+	}
+	original := fc.DeepCopy()
+	patchFrom := client.MergeFrom(original)
+	fc.ObjectMeta.Name = "default"
+
+	modified, err := modifiedFelixFields(fc)
+	if err != nil {
+		// The annotation is rewritten below, so don't block on a bad one.
+		log.Info("Ignoring invalid FelixConfiguration annotation", "annotation", felixManagedFieldsAnnotation, "reason", err.Error())
+	}
+
+	managed := append(applyFelixSpec(felix, &fc.Spec), applyWireGuardSpec(wireGuard, &fc.Spec)...)
+	if len(managed) == 0 {
+		delete(fc.Annotations, felixManagedFieldsAnnotation)
+	} else {
+		values, err := felixFieldValues(&fc.Spec, managed)
+		if err != nil {
+			r.SetDegraded("Unable to record the FelixConfiguration fields managed by the Installation", err, log)
+			return nil, err
+		}
+		if fc.Annotations == nil {
+			fc.Annotations = map[string]string{}
+		}
+		fc.Annotations[felixManagedFieldsAnnotation] = string(values)
+	}
+
+	if fc.ResourceVersion != "" &&
+		reflect.DeepEqual(original.Spec, fc.Spec) &&
+		reflect.DeepEqual(original.Annotations, fc.Annotations) {
+		return modified, nil
+	}
+
+	if fc.ResourceVersion == "" {
+		if err := r.client.Create(ctx, fc); err != nil {
+			r.SetDegraded("Unable to Create default FelixConfiguration", err, log)
+			return nil, err
+		}
+	} else {
+		if err := r.client.Patch(ctx, fc, patchFrom); err != nil {
+			r.SetDegraded("Unable to Patch default FelixConfiguration", err, log)
+			return nil, err
+		}
+	}
+	return modified, nil
+}
+
+// reportFelixConfigurationModified sets the FelixConfigurationModified condition on the status of the Installation
+// when managed FelixConfiguration fields were edited directly. The condition is reset once the Installation has been
+// updated since the edits were reported.
+func (r *ReconcileInstallation) reportFelixConfigurationModified(ctx context.Context, install *operator.Installation, modified []string, log logr.Logger) error {
+	var condition metav1.Condition
+	current := meta.FindStatusCondition(install.Status.Conditions, operator.FelixConfigurationModified)
+	switch {
+	case len(modified) > 0:
+		log.Info("Reverted direct edits of FelixConfiguration fields managed by the Installation", "fields", modified)
+		condition = metav1.Condition{
+			Type:               operator.FelixConfigurationModified,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ManagedFieldsEdited",
+			Message:            fmt.Sprintf("Edits of the FelixConfiguration fields managed by spec.felix were reverted: %s", strings.Join(modified, ", ")),
+			ObservedGeneration: install.Generation,
+		}
+	case current != nil && current.Status == metav1.ConditionTrue && current.ObservedGeneration != install.Generation:
+		condition = metav1.Condition{
+			Type:               operator.FelixConfigurationModified,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InstallationUpdated",
+			Message:            "The Installation was updated since the FelixConfiguration was last edited",
+			ObservedGeneration: install.Generation,
+		}
+	default:
+		return nil
+	}
+
+	// Only the status is patched since the spec of install may hold the computed configuration, and the patch has
+	// no resource version so that it doesn't conflict with the status update at the end of the reconcile.
+	original := &operator.Installation{
+		ObjectMeta: metav1.ObjectMeta{Name: install.Name},
+		Status:     *install.Status.DeepCopy(),
+	}
+	updated := original.DeepCopy()
+	meta.SetStatusCondition(&updated.Status.Conditions, condition)
+	if err := r.client.Status().Patch(ctx, updated, client.MergeFrom(original)); err != nil {
+		r.SetDegraded("Failed to report FelixConfiguration edits", err, log)
+		return err
+	}
+	meta.SetStatusCondition(&install.Status.Conditions, condition)
+	install.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// applyFelixSpec sets the Felix settings that are specified in felix on spec, and returns the JSON names of the
+// FelixConfiguration fields that were set.
+func applyFelixSpec(felix *operator.FelixSpec, spec *crdv1.FelixConfigurationSpec) []string {
+	if felix == nil {
+		return nil
+	}
+	var managed []string
+	if felix.LogSeverityScreen != nil {
+		spec.LogSeverityScreen = *felix.LogSeverityScreen
+		managed = append(managed, "logSeverityScreen")
+	}
+	if felix.IptablesBackend != nil {
+		backend := crdv1.IptablesBackend(*felix.IptablesBackend)
+		spec.IptablesBackend = &backend
+		managed = append(managed, "iptablesBackend")
+	}
+	if felix.IptablesRefreshInterval != nil {
+		spec.IptablesRefreshInterval = felix.IptablesRefreshInterval.DeepCopy()
+		managed = append(managed, "iptablesRefreshInterval")
+	}
+	if felix.RouteTableRange != nil {
+		spec.RouteTableRange = &crdv1.RouteTableRange{Min: felix.RouteTableRange.Min, Max: felix.RouteTableRange.Max}
+		managed = append(managed, "routeTableRange")
+	}
+	if felix.BPFKubeProxyIptablesCleanup != nil {
+		enabled := *felix.BPFKubeProxyIptablesCleanup == operator.FelixFeatureEnabled
+		spec.BPFKubeProxyIptablesCleanupEnabled = &enabled
+		managed = append(managed, "bpfKubeProxyIptablesCleanupEnabled")
+	}
+	if felix.BPFExternalServiceMode != nil {
+		spec.BPFExternalServiceMode = *felix.BPFExternalServiceMode
+		managed = append(managed, "bpfExternalServiceMode")
+	}
+	if felix.FailsafeInboundHostPorts != nil {
+		spec.FailsafeInboundHostPorts = protoPorts(*felix.FailsafeInboundHostPorts)
+		managed = append(managed, "failsafeInboundHostPorts")
+	}
+	if felix.FailsafeOutboundHostPorts != nil {
+		spec.FailsafeOutboundHostPorts = protoPorts(*felix.FailsafeOutboundHostPorts)
+		managed = append(managed, "failsafeOutboundHostPorts")
+	}
+	return managed
+}
+
+// applyWireGuardSpec enables or disables WireGuard for IPv4 on spec when it is specified in wireGuard, and returns the
+// JSON names of the FelixConfiguration fields that were set. The FelixConfiguration CRD has no IPv6 field in every
+// release, so IPv6 is passed to calico-node through its environment instead.
+func applyWireGuardSpec(wireGuard *operator.WireGuardSpec, spec *crdv1.FelixConfigurationSpec) []string {
+	if wireGuard == nil {
+		return nil
+	}
+	var managed []string
+	if wireGuard.IPv4 != nil {
+		enabled := *wireGuard.IPv4 == operator.WireGuardEnabled
+		spec.WireguardEnabled = &enabled
+		managed = append(managed, "wireguardEnabled")
+	}
+	return managed
+}
+
+func protoPorts(ports []operator.FelixProtoPort) *[]crdv1.ProtoPort {
+	pps := []crdv1.ProtoPort{}
+	for _, p := range ports {
+		pps = append(pps, crdv1.ProtoPort{Protocol: p.Protocol, Port: p.Port})
+	}
+	return &pps
+}
+
+// felixFieldValues returns the JSON encoded values of the given fields of spec, keyed by the JSON name of the field.
+func felixFieldValues(spec *crdv1.FelixConfigurationSpec, fields []string) ([]byte, error) {
+	all, err := specFields(spec)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]json.RawMessage{}
+	for _, f := range fields {
+		values[f] = all[f]
+	}
+	return json.Marshal(values)
+}
+
+// modifiedFelixFields returns the sorted JSON names of the fields recorded on fc as managed by the Installation
+// whose value differs from the recorded one.
+func modifiedFelixFields(fc *crdv1.FelixConfiguration) ([]string, error) {
+	recorded := map[string]json.RawMessage{}
+	if a := fc.Annotations[felixManagedFieldsAnnotation]; a != "" {
+		if err := json.Unmarshal([]byte(a), &recorded); err != nil {
+			return nil, err
+		}
+	}
+	if len(recorded) == 0 || fc.ResourceVersion == "" {
+		return nil, nil
+	}
+
+	current, err := specFields(&fc.Spec)
+	if err != nil {
+		return nil, err
+	}
+	var modified []string
+	for f, v := range recorded {
+		c := current[f]
+		if c == nil {
+			// The field was omitted, which is how a field recorded as null is encoded.
+			c = json.RawMessage("null")
+		}
+		if !bytes.Equal(c, v) {
+			modified = append(modified, f)
+		}
+	}
+	sort.Strings(modified)
+	return modified, nil
+}
+
+// specFields returns the JSON encoded values of the fields of spec that are set, keyed by the JSON name of the field.
+func specFields(spec interface{}) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
+)
+
+const (
+	// defaultAPIServerPort is the port the API server listens on by default, used when the kubernetes service has
+	// no endpoints to read it from.
+	defaultAPIServerPort = 6443
+	bgpPort              = 179
+)
+
+// The failsafe ports that Felix uses when none are configured.
+var (
+	felixDefaultFailsafeInboundHostPorts = []crdv1.ProtoPort{
+		{Protocol: "TCP", Port: 22},
+		{Protocol: "UDP", Port: 68},
+		{Protocol: "TCP", Port: 179},
+		{Protocol: "TCP", Port: 2379},
+		{Protocol: "TCP", Port: 2380},
+		{Protocol: "TCP", Port: 5473},
+		{Protocol: "TCP", Port: 6443},
+		{Protocol: "TCP", Port: 6666},
+		{Protocol: "TCP", Port: 6667},
+	}
+	felixDefaultFailsafeOutboundHostPorts = []crdv1.ProtoPort{
+		{Protocol: "UDP", Port: 53},
+		{Protocol: "UDP", Port: 67},
+		{Protocol: "TCP", Port: 179},
+		{Protocol: "TCP", Port: 2379},
+		{Protocol: "TCP", Port: 2380},
+		{Protocol: "TCP", Port: 5473},
+		{Protocol: "TCP", Port: 6443},
+		{Protocol: "TCP", Port: 6666},
+		{Protocol: "TCP", Port: 6667},
+	}
+)
+
+// autoHostEndpoints returns the state of the automatic host endpoints set by
+// spec.kubeControllers.controllers.node, or nil if the Installation sets none.
+func autoHostEndpoints(install *operator.Installation) *operator.KubeControllerState {
+	kc := install.Spec.KubeControllers
+	if kc != nil && kc.Controllers != nil && kc.Controllers.Node != nil {
+		return kc.Controllers.Node.AutoHostEndpoints
+	}
+	return nil
+}
+
+// validateHostProtectionFailsafePorts checks, when the Installation enables the automatic host endpoints, that the
+// failsafe ports Felix will use once the Installation is applied to fc keep the API server reachable on each of
+// apiServerPorts, and the BGP peers too when BGP is enabled. Otherwise enforcing the host endpoints could cut the
+// nodes off from the cluster. The check is skipped when spec.hostProtection.reachabilityCheck is Disabled.
+func validateHostProtectionFailsafePorts(install *operator.Installation, fc *crdv1.FelixConfiguration, apiServerPorts []uint16) error {
+	if state := autoHostEndpoints(install); state == nil || *state != operator.KubeControllerEnabled {
+		return nil
+	}
+	if hp := install.Spec.HostProtection; hp != nil && hp.ReachabilityCheck != nil &&
+		*hp.ReachabilityCheck == operator.HostProtectionCheckDisabled {
+		return nil
+	}
+
+	required := append([]uint16{}, apiServerPorts...)
+	if install.Spec.CalicoNetwork != nil && install.Spec.CalicoNetwork.BGP != nil &&
+		*install.Spec.CalicoNetwork.BGP == operator.BGPEnabled {
+		required = append(required, bgpPort)
+	}
+
+	spec := fc.Spec.DeepCopy()
+	applyFelixSpec(install.Spec.Felix, spec)
+	inbound, outbound := felixDefaultFailsafeInboundHostPorts, felixDefaultFailsafeOutboundHostPorts
+	if spec.FailsafeInboundHostPorts != nil {
+		inbound = *spec.FailsafeInboundHostPorts
+	}
+	if spec.FailsafeOutboundHostPorts != nil {
+		outbound = *spec.FailsafeOutboundHostPorts
+	}
+
+	for _, port := range required {
+		if !hasTCPPort(inbound, port) {
+			return fmt.Errorf("the failsafe inbound host ports must include TCP port %d when the automatic host endpoints are enabled", port)
+		}
+		if !hasTCPPort(outbound, port) {
+			return fmt.Errorf("the failsafe outbound host ports must include TCP port %d when the automatic host endpoints are enabled", port)
+		}
+	}
+	return nil
+}
+
+// apiServerHostPorts returns the ports of the API server that the host endpoint policy applies to. The nodes connect
+// directly to the services endpoint when one is configured, while their connections to the kubernetes service are
+// translated to the ports of its endpoints before the policy applies.
+func apiServerHostPorts(ctx context.Context, cli client.Client, apiServer k8sapi.ServiceEndpoint) ([]uint16, error) {
+	if apiServer != k8sapi.DefaultEndpoint() && apiServer.Port != "" {
+		port, err := strconv.ParseUint(apiServer.Port, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid services endpoint port %q: %v", apiServer.Port, err)
+		}
+		return []uint16{uint16(port)}, nil
+	}
+
+	endpoints := &corev1.Endpoints{}
+	err := cli.Get(ctx, types.NamespacedName{Name: "kubernetes", Namespace: "default"}, endpoints)
+	if apierrors.IsNotFound(err) {
+		return []uint16{defaultAPIServerPort}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var ports []uint16
+	seen := map[int32]bool{}
+	for _, subset := range endpoints.Subsets {
+		for _, p := range subset.Ports {
+			if seen[p.Port] || (p.Protocol != "" && p.Protocol != corev1.ProtocolTCP) {
+				continue
+			}
+			seen[p.Port] = true
+			ports = append(ports, uint16(p.Port))
+		}
+	}
+	if len(ports) == 0 {
+		return []uint16{defaultAPIServerPort}, nil
+	}
+	return ports, nil
+}
+
+func hasTCPPort(ports []crdv1.ProtoPort, port uint16) bool {
+	for _, p := range ports {
+		// Felix treats a port without a protocol as TCP.
+		if p.Port == port && (p.Protocol == "" || strings.EqualFold(p.Protocol, "TCP")) {
+			return true
+		}
+	}
+	return false
+}
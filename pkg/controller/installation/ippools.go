@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// ipPools returns the component reconciling the IP pools of the Installation. The IPPools that were created for IP
+// pools that are no longer in the Installation are disabled first, and deleted once no IPAM blocks are left in them,
+// since the addresses of running workloads may still be assigned from them. The CIDR and block size of an existing
+// IPPool cannot be changed since addresses may have been assigned from it, so an error is returned when the
+// Installation changes them. The component must be handled without an owner, so that the IPPools are not garbage
+// collected with the Installation.
+func (r *ReconcileInstallation) ipPools(ctx context.Context, install *operator.Installation) (render.Component, error) {
+	pools := crdv1.IPPoolList{}
+	if err := r.client.List(ctx, &pools); err != nil {
+		return nil, err
+	}
+	if err := validateIPPoolOverlaps(install, pools.Items); err != nil {
+		return nil, err
+	}
+	existing := map[string]*crdv1.IPPool{}
+	for i := range pools.Items {
+		existing[pools.Items[i].Name] = &pools.Items[i]
+	}
+
+	cfg := &render.IPPoolsConfiguration{Installation: &install.Spec, ExistingPools: existing}
+	desired := map[string]bool{}
+	if install.Spec.CalicoNetwork != nil {
+		for _, p := range install.Spec.CalicoNetwork.IPPools {
+			desired[p.Name] = true
+			current, ok := existing[p.Name]
+			if !ok {
+				continue
+			}
+			if current.Spec.CIDR != p.CIDR {
+				return nil, fmt.Errorf("the CIDR of IPPool %s cannot be changed from %s to %s, add a new IP pool instead", p.Name, current.Spec.CIDR, p.CIDR)
+			}
+			if p.BlockSize != nil && current.Spec.BlockSize != 0 && current.Spec.BlockSize != int(*p.BlockSize) {
+				return nil, fmt.Errorf("the block size of IPPool %s cannot be changed from %d to %d, add a new IP pool instead", p.Name, current.Spec.BlockSize, *p.BlockSize)
+			}
+		}
+	}
+	if lb := render.LoadBalancerIPPool(&install.Spec); lb != nil {
+		desired[lb.Name] = true
+		if current, ok := existing[lb.Name]; ok && current.Spec.CIDR != lb.CIDR {
+			return nil, fmt.Errorf("the CIDR of IPPool %s cannot be changed from %s to %s, add a new IP pool instead", lb.Name, current.Spec.CIDR, lb.CIDR)
+		}
+	}
+
+	var blocks *crdv1.IPAMBlockList
+	for name, p := range existing {
+		if desired[name] || p.Labels[render.IPPoolManagedByLabel] != render.IPPoolManagedByLabelValue {
+			continue
+		}
+		if !p.Spec.Disabled {
+			cfg.DisabledPools = append(cfg.DisabledPools, name)
+			continue
+		}
+		// The blocks are released as the workloads with addresses from the pool are deleted. The Installation is
+		// reconciled periodically, which deletes the pool once the last of them is gone.
+		if blocks == nil {
+			blocks = &crdv1.IPAMBlockList{}
+			if err := r.client.List(ctx, blocks); err != nil {
+				return nil, err
+			}
+		}
+		if hasIPAMBlocks(p, blocks.Items) {
+			cfg.DisabledPools = append(cfg.DisabledPools, name)
+		} else {
+			cfg.StalePools = append(cfg.StalePools, name)
+		}
+	}
+	sort.Strings(cfg.DisabledPools)
+	sort.Strings(cfg.StalePools)
+	return render.IPPools(cfg), nil
+}
+
+// hasIPAMBlocks returns whether any of the blocks is in the CIDR of the pool.
+func hasIPAMBlocks(pool *crdv1.IPPool, blocks []crdv1.IPAMBlock) bool {
+	_, cidr, err := net.ParseCIDR(pool.Spec.CIDR)
+	if err != nil {
+		return false
+	}
+	for _, b := range blocks {
+		ip, _, err := net.ParseCIDR(b.Spec.CIDR)
+		if err == nil && cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apps "k8s.io/api/apps/v1"
+	apiextenv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	nadv1 "github.com/tigera/operator/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// The Multus manifests, as well as OpenShift, label the Multus DaemonSet with app=multus.
+const (
+	multusDaemonSetLabel      = "app"
+	multusDaemonSetLabelValue = "multus"
+)
+
+// checkMultusInstalled returns an error describing what is missing if Multus is not installed, that is if either the
+// NetworkAttachmentDefinition CRD or the Multus DaemonSet does not exist.
+func (r *ReconcileInstallation) checkMultusInstalled(ctx context.Context) error {
+	crd := &apiextenv1.CustomResourceDefinition{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: nadv1.NetworkAttachmentDefinitionCRDName}, crd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("the %s CRD does not exist", nadv1.NetworkAttachmentDefinitionCRDName)
+		}
+		return err
+	}
+
+	daemonSets := apps.DaemonSetList{}
+	if err := r.client.List(ctx, &daemonSets, client.MatchingLabels{multusDaemonSetLabel: multusDaemonSetLabelValue}); err != nil {
+		return err
+	}
+	if len(daemonSets.Items) == 0 {
+		return fmt.Errorf("no DaemonSet labeled %s=%s exists", multusDaemonSetLabel, multusDaemonSetLabelValue)
+	}
+	return nil
+}
+
+// multus returns the component reconciling the NetworkAttachmentDefinitions of the secondary IP pools. The
+// NetworkAttachmentDefinitions that were created for IP pools that are no longer secondary, or once they are
+// disabled, are deleted. Multus must be installed, since the NetworkAttachmentDefinitions are listed.
+func (r *ReconcileInstallation) multus(ctx context.Context, install *operator.Installation) (render.Component, error) {
+	nads := nadv1.NetworkAttachmentDefinitionList{}
+	if err := r.client.List(ctx, &nads,
+		client.InNamespace(render.MultusNetworkAttachmentDefinitionsNamespace),
+		client.MatchingLabels{render.IPPoolManagedByLabel: render.IPPoolManagedByLabelValue},
+	); err != nil {
+		return nil, err
+	}
+
+	desired := map[string]bool{}
+	if render.MultusNetworkAttachmentDefinitionsEnabled(&install.Spec) {
+		for _, p := range render.SecondaryIPPools(install.Spec.CalicoNetwork.IPPools) {
+			desired[p.Name] = true
+		}
+	}
+	cfg := &render.MultusConfiguration{Installation: &install.Spec}
+	for _, nad := range nads.Items {
+		if !desired[nad.Name] {
+			cfg.StaleNetworkAttachmentDefinitions = append(cfg.StaleNetworkAttachmentDefinitions, nad.Name)
+		}
+	}
+	sort.Strings(cfg.StaleNetworkAttachmentDefinitions)
+	return render.Multus(cfg), nil
+}
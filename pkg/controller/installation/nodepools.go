@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// addCalicoNodePoolNodeWatch watches the labels of the nodes, so that the nodes are labeled with the
+// CalicoNodePool that selects them.
+func addCalicoNodePoolNodeWatch(c controller.Controller) error {
+	return c.Watch(&source.Kind{Type: &corev1.Node{}},
+		handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "default"}}}
+		}),
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return !labels.Equals(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+			},
+			DeleteFunc: func(event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(event.GenericEvent) bool {
+				return false
+			},
+		},
+	)
+}
+
+// labelCalicoNodePoolNodes sets the CalicoNodePoolLabel of each node to the CalicoNodePool that selects it, and
+// removes it from the nodes that no pool selects. The calico-node DaemonSets select their nodes by this label.
+func (r *ReconcileInstallation) labelCalicoNodePoolNodes(ctx context.Context, install *operator.Installation) error {
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(ctx, nodes); err != nil {
+		return err
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		pool := ""
+		for _, p := range install.Spec.CalicoNodePools {
+			if labels.SelectorFromSet(p.NodeSelector).Matches(labels.Set(node.Labels)) {
+				pool = p.Name
+				break
+			}
+		}
+		current, labeled := node.Labels[render.CalicoNodePoolLabel]
+		if (pool == "" && !labeled) || (pool != "" && current == pool) {
+			continue
+		}
+
+		patchFrom := client.MergeFrom(node.DeepCopy())
+		if pool == "" {
+			delete(node.Labels, render.CalicoNodePoolLabel)
+		} else {
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[render.CalicoNodePoolLabel] = pool
+		}
+		if err := r.client.Patch(ctx, node, patchFrom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// staleCalicoNodePools returns the names of the CalicoNodePools whose calico-node DaemonSets exist but that are no
+// longer in the Installation.
+func (r *ReconcileInstallation) staleCalicoNodePools(ctx context.Context, install *operator.Installation) ([]string, error) {
+	dss := appsv1.DaemonSetList{}
+	if err := r.client.List(ctx, &dss, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.CalicoNodePoolLabel}); err != nil {
+		return nil, err
+	}
+
+	desired := map[string]bool{}
+	for _, pool := range install.Spec.CalicoNodePools {
+		desired[pool.Name] = true
+	}
+	var stale []string
+	for _, ds := range dss.Items {
+		if pool := ds.Labels[render.CalicoNodePoolLabel]; !desired[pool] {
+			stale = append(stale, pool)
+		}
+	}
+	sort.Strings(stale)
+	return stale, nil
+}
+
+// calicoNodePoolDaemonSets returns the calico-node DaemonSets of the given CalicoNodePools.
+func calicoNodePoolDaemonSets(pools []string) []types.NamespacedName {
+	var dss []types.NamespacedName
+	for _, pool := range pools {
+		dss = append(dss, types.NamespacedName{Name: render.NodePoolDaemonSetName(pool), Namespace: common.CalicoNamespace})
+	}
+	return dss
+}
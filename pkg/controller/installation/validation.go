@@ -18,13 +18,23 @@ import (
 	"fmt"
 	"net"
 	"path"
+	"regexp"
 	"strings"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/render"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// cpuListRegexp matches the Linux CPU list format, for example 2-3,6.
+var cpuListRegexp = regexp.MustCompile(`^[0-9]+(-[0-9]+)?(,[0-9]+(-[0-9]+)?)*$`)
+
+// maxCalicoNodePoolNameLength keeps the names of the calico-node DaemonSets of the CalicoNodePools valid DNS labels.
+const maxCalicoNodePoolNameLength = validation.DNS1123LabelMaxLength - len(common.NodeDaemonSetName+"-")
+
 // validateCustomResource validates that the given custom resource is correct. This
 // should be called after populating defaults and before rendering objects.
 func validateCustomResource(instance *operatorv1.Installation) error {
@@ -107,24 +117,33 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 	if instance.Spec.CalicoNetwork != nil {
 		bpfDataplane := instance.Spec.CalicoNetwork.LinuxDataplane != nil && *instance.Spec.CalicoNetwork.LinuxDataplane == operatorv1.LinuxDataplaneBPF
 
-		nPools := len(instance.Spec.CalicoNetwork.IPPools)
-		if nPools > 2 {
-			return fmt.Errorf("only one IPPool per version is allowed")
-		}
-
-		v4pool := render.GetIPv4Pool(instance.Spec.CalicoNetwork.IPPools)
-		v6pool := render.GetIPv6Pool(instance.Spec.CalicoNetwork.IPPools)
-
-		if nPools == 2 {
-			if v4pool == nil {
-				return fmt.Errorf("multiple IPv6 pools detected: only one IPPool per version is allowed")
+		poolNames := map[string]bool{}
+		for _, pool := range instance.Spec.CalicoNetwork.IPPools {
+			if _, _, err := net.ParseCIDR(pool.CIDR); err != nil {
+				return fmt.Errorf("ipPool.CIDR(%s) is invalid: %s", pool.CIDR, err)
 			}
-			if v6pool == nil {
-				return fmt.Errorf("multiple IPv4 IPPools detected: only one IPPool per version is allowed")
+			if pool.Name == "" {
+				continue
 			}
+			if errs := validation.IsDNS1123Subdomain(pool.Name); len(errs) > 0 {
+				return fmt.Errorf("ipPool.name %s is invalid: %s", pool.Name, strings.Join(errs, ", "))
+			}
+			if poolNames[pool.Name] {
+				return fmt.Errorf("ipPool.name %s is used by more than one IPPool", pool.Name)
+			}
+			poolNames[pool.Name] = true
+		}
+		v4pools := render.GetIPv4Pools(instance.Spec.CalicoNetwork.IPPools)
+		v6pools := render.GetIPv6Pools(instance.Spec.CalicoNetwork.IPPools)
+
+		if err := validateIPPools(instance, v4pools, "IPv4"); err != nil {
+			return err
+		}
+		if err := validateIPPools(instance, v6pools, "IPv6"); err != nil {
+			return err
 		}
 
-		if v4pool != nil {
+		for _, v4pool := range v4pools {
 			_, cidr, err := net.ParseCIDR(v4pool.CIDR)
 			if err != nil {
 				return fmt.Errorf("ipPool.CIDR(%s) is invalid: %s", v4pool.CIDR, err)
@@ -202,7 +221,7 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 			}
 		}
 
-		if v6pool != nil {
+		for _, v6pool := range v6pools {
 			_, cidr, err := net.ParseCIDR(v6pool.CIDR)
 			if err != nil {
 				return fmt.Errorf("ipPool.CIDR(%s) is invalid: %s", v6pool.CIDR, err)
@@ -212,8 +231,8 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 				return fmt.Errorf("Encapsulation is not supported by IPv6 pools, but it is set for %s", v6pool.CIDR)
 			}
 
-			if bpfDataplane {
-				return fmt.Errorf("IPv6 IP pool is specified but eBPF mode does not support IPv6")
+			if bpfDataplane && !bpfIPv6Supported(instance.Spec.Variant) {
+				return fmt.Errorf("IPv6 IP pool is specified but eBPF mode does not support IPv6 in this %s release", instance.Spec.Variant)
 			}
 
 			// Verify NAT outgoing values.
@@ -262,10 +281,23 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 				return fmt.Errorf("VPP doesn't support disabling HostPorts")
 			}
 		}
+		if instance.Spec.CalicoNetwork.VPP != nil {
+			if err := validateVPP(instance.Spec.CalicoNetwork); err != nil {
+				return err
+			}
+		}
 
-		if bpfDataplane && instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 == nil {
+		// The BPF dataplane needs the node addresses of the IP versions it runs. It runs IPv4 unless only IPv6 pools
+		// are configured.
+		if bpfDataplane && instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 == nil && (len(v4pools) > 0 || len(v6pools) == 0) {
 			return fmt.Errorf("spec.calicoNetwork.nodeAddressAutodetectionV4 is required for the BPF dataplane")
 		}
+		if bpfDataplane && instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 == nil && len(v6pools) > 0 {
+			return fmt.Errorf("spec.calicoNetwork.nodeAddressAutodetectionV6 is required for the BPF dataplane with IPv6 IP pools")
+		}
+		if bpfDataplane && len(v4pools) > 0 && len(v6pools) > 0 && !bpfDualStackSupported(instance.Spec.Variant) {
+			return fmt.Errorf("IPv4 and IPv6 IP pools are specified but eBPF mode does not support dual-stack in this %s release", instance.Spec.Variant)
+		}
 
 		if instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 != nil {
 			err := validateNodeAddressDetection(instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4)
@@ -302,6 +334,18 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 			}
 		}
 
+		if instance.Spec.CalicoNetwork.Multus != nil {
+			if err := validateMultus(instance.Spec.CalicoNetwork); err != nil {
+				return err
+			}
+		}
+
+		if instance.Spec.CalicoNetwork.ServiceAdvertisement != nil {
+			if err := validateServiceAdvertisement(instance); err != nil {
+				return err
+			}
+		}
+
 		if instance.Spec.CalicoNetwork.ContainerIPForwarding != nil {
 			if instance.Spec.CNI.Type != operatorv1.PluginCalico {
 				return fmt.Errorf("spec.calicoNetwork.containerIPForwarding is supported only for Calico CNI")
@@ -346,6 +390,77 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 		}
 	}
 
+	if pdbs := instance.Spec.PodDisruptionBudgets; pdbs != nil {
+		seen := map[string]bool{}
+		for _, c := range pdbs.Components {
+			if seen[c.Name] {
+				return fmt.Errorf("Installation spec.PodDisruptionBudgets.Components has more than one entry for %s", c.Name)
+			}
+			seen[c.Name] = true
+			if c.MinAvailable != nil && c.MaxUnavailable != nil {
+				return fmt.Errorf("Installation spec.PodDisruptionBudgets.Components %s cannot set both minAvailable and maxUnavailable", c.Name)
+			}
+		}
+	}
+
+	if instance.Spec.ComponentNetworkPolicies != nil &&
+		*instance.Spec.ComponentNetworkPolicies == operatorv1.ComponentNetworkPoliciesEnabled &&
+		instance.Spec.Variant != operatorv1.TigeraSecureEnterprise {
+		return fmt.Errorf("spec.componentNetworkPolicies can only be enabled for the %s variant", operatorv1.TigeraSecureEnterprise)
+	}
+
+	if kc := instance.Spec.KubeControllers; kc != nil && kc.Controllers != nil && instance.Spec.Variant != operatorv1.TigeraSecureEnterprise {
+		if kc.Controllers.Service != nil {
+			return fmt.Errorf("spec.kubeControllers.controllers.service can only be set for the %s variant", operatorv1.TigeraSecureEnterprise)
+		}
+		if kc.Controllers.FederatedServices != nil {
+			return fmt.Errorf("spec.kubeControllers.controllers.federatedServices can only be set for the %s variant", operatorv1.TigeraSecureEnterprise)
+		}
+	}
+
+	// kube-controllers cannot run without any controller enabled.
+	if kc := instance.Spec.KubeControllers; kc != nil && kc.Controllers != nil && kc.Controllers.Node != nil &&
+		kubeControllerDisabled(kc.Controllers.Node.State) {
+		if instance.Spec.Variant != operatorv1.TigeraSecureEnterprise {
+			return fmt.Errorf("spec.kubeControllers.controllers.node.state cannot be Disabled for the %s variant, which runs no other controller", operatorv1.Calico)
+		}
+		if kc.Controllers.Service != nil && kubeControllerDisabled(kc.Controllers.Service.State) &&
+			kc.Controllers.FederatedServices != nil && kubeControllerDisabled(kc.Controllers.FederatedServices.State) {
+			return fmt.Errorf("spec.kubeControllers.controllers cannot disable every controller")
+		}
+		if node := kc.Controllers.Node; node.AutoHostEndpoints != nil && *node.AutoHostEndpoints == operatorv1.KubeControllerEnabled {
+			return fmt.Errorf("spec.kubeControllers.controllers.node.autoHostEndpoints requires the node controller, which is disabled by spec.kubeControllers.controllers.node.state")
+		}
+	}
+
+	if felix := instance.Spec.Felix; felix != nil {
+		if r := felix.RouteTableRange; r != nil && r.Min > r.Max {
+			return fmt.Errorf("spec.felix.routeTableRange.min %d must not be greater than spec.felix.routeTableRange.max %d", r.Min, r.Max)
+		}
+		if len(felix.HealthTimeoutOverrides) > 0 && !healthTimeoutOverridesSupported(instance.Spec.Variant) {
+			return fmt.Errorf("spec.felix.healthTimeoutOverrides is not supported in this %s release", instance.Spec.Variant)
+		}
+		seen := map[string]bool{}
+		for _, o := range felix.HealthTimeoutOverrides {
+			if o.Name == "" || strings.ContainsAny(o.Name, ",=") {
+				return fmt.Errorf("spec.felix.healthTimeoutOverrides has an invalid component name %q", o.Name)
+			}
+			if seen[o.Name] {
+				return fmt.Errorf("spec.felix.healthTimeoutOverrides has more than one override for %s", o.Name)
+			}
+			seen[o.Name] = true
+		}
+	}
+
+	if cn := instance.Spec.CalicoNetwork; cn != nil && cn.WireGuard != nil && cn.WireGuard.IPv6 != nil &&
+		*cn.WireGuard.IPv6 == operatorv1.WireGuardEnabled && !wireGuardIPv6Supported(instance.Spec.Variant) {
+		return fmt.Errorf("spec.calicoNetwork.wireGuard.ipv6 is not supported in this %s release", instance.Spec.Variant)
+	}
+
+	if err := validateCalicoNodePools(instance); err != nil {
+		return err
+	}
+
 	// Verify that we are running in non-privileged mode only with the appropriate feature set
 	if instance.Spec.NonPrivileged != nil && *instance.Spec.NonPrivileged == operatorv1.NonPrivilegedEnabled {
 		// BPF must be disabled
@@ -364,6 +479,251 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 	return nil
 }
 
+// validateMultus validates the Multus integration. Pods attach their additional interfaces with addresses from the
+// Manual IP pools, which are not used for their primary interface, so at least one is needed for the
+// NetworkAttachmentDefinitions.
+func validateMultus(cn *operatorv1.CalicoNetworkSpec) error {
+	if cn.MultiInterfaceMode == nil || *cn.MultiInterfaceMode != operatorv1.MultiInterfaceModeMultus {
+		return fmt.Errorf("spec.calicoNetwork.multus is supported only when spec.calicoNetwork.multiInterfaceMode is %s", operatorv1.MultiInterfaceModeMultus)
+	}
+	nads := cn.Multus.NetworkAttachmentDefinitions
+	if nads == nil {
+		return nil
+	}
+	switch *nads {
+	case operatorv1.NetworkAttachmentDefinitionsEnabled:
+		if len(render.SecondaryIPPools(cn.IPPools)) == 0 {
+			return fmt.Errorf("spec.calicoNetwork.multus.networkAttachmentDefinitions requires at least one IP pool with ipPool.assignmentMode %s", operatorv1.IPPoolAssignmentManual)
+		}
+	case operatorv1.NetworkAttachmentDefinitionsDisabled:
+	default:
+		return fmt.Errorf("%s is invalid for spec.calicoNetwork.multus.networkAttachmentDefinitions, should be one of %s,%s",
+			*nads, operatorv1.NetworkAttachmentDefinitionsEnabled, operatorv1.NetworkAttachmentDefinitionsDisabled)
+	}
+	return nil
+}
+
+// validateServiceAdvertisement validates the advertisement of the Service addresses, which are advertised to the
+// BGP peers of the nodes. The LoadBalancer IP pool is an IPPool of its own, so it must not overlap or share its
+// name with the IP pools of the workloads.
+func validateServiceAdvertisement(instance *operatorv1.Installation) error {
+	cn := instance.Spec.CalicoNetwork
+	if cn.BGP == nil || *cn.BGP != operatorv1.BGPEnabled {
+		return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement is supported only when spec.calicoNetwork.bgp is %s", operatorv1.BGPEnabled)
+	}
+	sa := cn.ServiceAdvertisement
+	for _, c := range sa.ClusterIPCIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.clusterIPCIDRs entry %s is invalid: %s", c, err)
+		}
+	}
+	for _, c := range sa.ExternalIPCIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.externalIPCIDRs entry %s is invalid: %s", c, err)
+		}
+	}
+
+	lb := sa.LoadBalancerIPPool
+	if lb == nil {
+		return nil
+	}
+	if instance.Spec.CNI.Type != operatorv1.PluginCalico || instance.Spec.CNI.IPAM.Type != operatorv1.IPAMPluginCalico {
+		return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool is supported only with %s CNI and %s IPAM",
+			operatorv1.PluginCalico, operatorv1.IPAMPluginCalico)
+	}
+	if !loadBalancerIPAMSupported(instance.Spec.Variant) {
+		return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool is not supported in this %s release, which does not assign LoadBalancer IPs", instance.Spec.Variant)
+	}
+	if errs := validation.IsDNS1123Subdomain(lb.Name); len(errs) > 0 {
+		return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool.name %s is invalid: %s", lb.Name, strings.Join(errs, ", "))
+	}
+	_, cidr, err := net.ParseCIDR(lb.CIDR)
+	if err != nil {
+		return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool.cidr %s is invalid: %s", lb.CIDR, err)
+	}
+	for _, pool := range cn.IPPools {
+		if pool.Name == lb.Name {
+			return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool.name %s is used by an IPPool of spec.calicoNetwork.ipPools", lb.Name)
+		}
+		_, poolCIDR, _ := net.ParseCIDR(pool.CIDR)
+		if cidr.Contains(poolCIDR.IP) || poolCIDR.Contains(cidr.IP) {
+			return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool.cidr %s overlaps with ipPool.CIDR(%s) of %s", lb.CIDR, pool.CIDR, pool.Name)
+		}
+	}
+	return nil
+}
+
+// validateVPP validates the settings of the VPP dataplane.
+func validateVPP(cn *operatorv1.CalicoNetworkSpec) error {
+	if cn.LinuxDataplane == nil || *cn.LinuxDataplane != operatorv1.LinuxDataplaneVPP {
+		return fmt.Errorf("spec.calicoNetwork.vpp is supported only when spec.calicoNetwork.linuxDataplane is %s", operatorv1.LinuxDataplaneVPP)
+	}
+	vpp := cn.VPP
+	if vpp.UplinkInterface == "" {
+		return fmt.Errorf("spec.calicoNetwork.vpp.uplinkInterface is required")
+	}
+	if vpp.Driver != nil {
+		if _, ok := render.VPPDrivers[*vpp.Driver]; !ok {
+			return fmt.Errorf("%s is invalid for spec.calicoNetwork.vpp.driver, should be one of %s,%s,%s,%s,%s,%s,%s", *vpp.Driver,
+				operatorv1.VPPDriverAFPacket, operatorv1.VPPDriverAFXDP, operatorv1.VPPDriverAVF, operatorv1.VPPDriverDPDK,
+				operatorv1.VPPDriverRDMA, operatorv1.VPPDriverVirtio, operatorv1.VPPDriverVMXNET3)
+		}
+	}
+	if vpp.Hugepages != nil && *vpp.Hugepages < 0 {
+		return fmt.Errorf("spec.calicoNetwork.vpp.hugepages %d is invalid, it must not be negative", *vpp.Hugepages)
+	}
+	if vpp.CPUPinning != nil {
+		if vpp.CPUPinning.MainCore < 0 {
+			return fmt.Errorf("spec.calicoNetwork.vpp.cpuPinning.mainCore %d is invalid", vpp.CPUPinning.MainCore)
+		}
+		if vpp.CPUPinning.WorkerCores != "" && !cpuListRegexp.MatchString(vpp.CPUPinning.WorkerCores) {
+			return fmt.Errorf("spec.calicoNetwork.vpp.cpuPinning.workerCores %s is not a CPU list", vpp.CPUPinning.WorkerCores)
+		}
+	}
+	for _, c := range vpp.ServiceCIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return fmt.Errorf("spec.calicoNetwork.vpp.serviceCIDRs entry %s is invalid: %s", c, err)
+		}
+	}
+	return nil
+}
+
+// validateIPPools validates the IP pools of an IP version against each other.
+func validateIPPools(instance *operatorv1.Installation, pools []*operatorv1.IPPool, version string) error {
+	if len(pools) == 0 {
+		return nil
+	}
+	if len(pools) > 1 {
+		if instance.Spec.CNI.Type != operatorv1.PluginCalico || instance.Spec.CNI.IPAM.Type != operatorv1.IPAMPluginCalico {
+			return fmt.Errorf("only one %s IPPool is allowed with %s CNI and %s IPAM", version, instance.Spec.CNI.Type, instance.Spec.CNI.IPAM.Type)
+		}
+	}
+
+	automatic := false
+	for i, pool := range pools {
+		if pool.Name == "" && len(pools) > 1 {
+			return fmt.Errorf("ipPool.name must be set for %s when there is more than one %s IPPool", pool.CIDR, version)
+		}
+
+		if pool.AssignmentMode != nil {
+			switch *pool.AssignmentMode {
+			case operatorv1.IPPoolAssignmentAutomatic:
+				automatic = true
+			case operatorv1.IPPoolAssignmentManual:
+			default:
+				return fmt.Errorf("%s is invalid for ipPool.assignmentMode, should be one of %s,%s",
+					*pool.AssignmentMode, operatorv1.IPPoolAssignmentAutomatic, operatorv1.IPPoolAssignmentManual)
+			}
+		} else {
+			automatic = true
+		}
+
+		_, cidr, _ := net.ParseCIDR(pool.CIDR)
+		for _, other := range pools[:i] {
+			_, otherCIDR, _ := net.ParseCIDR(other.CIDR)
+			if cidr.Contains(otherCIDR.IP) || otherCIDR.Contains(cidr.IP) {
+				return fmt.Errorf("ipPool.CIDR(%s) of %s overlaps with ipPool.CIDR(%s) of %s", pool.CIDR, pool.Name, other.CIDR, other.Name)
+			}
+		}
+	}
+	if !automatic {
+		return fmt.Errorf("at least one %s IPPool must have ipPool.assignmentMode Automatic", version)
+	}
+	return nil
+}
+
+// validateIPPoolOverlaps validates that the IP pools of the Installation do not overlap with the IPPools in the
+// cluster that the operator does not reconcile from the Installation, such as the IPPools of the egress gateways.
+// An IPPool with the name of an IP pool of the Installation is the IPPool of that IP pool.
+func validateIPPoolOverlaps(instance *operatorv1.Installation, pools []crdv1.IPPool) error {
+	if instance.Spec.CalicoNetwork == nil {
+		return nil
+	}
+	lb := render.LoadBalancerIPPool(&instance.Spec)
+	names := map[string]bool{}
+	for _, p := range instance.Spec.CalicoNetwork.IPPools {
+		names[p.Name] = true
+	}
+	if lb != nil {
+		names[lb.Name] = true
+	}
+	for _, pool := range pools {
+		if names[pool.Name] || pool.Labels[render.IPPoolManagedByLabel] == render.IPPoolManagedByLabelValue {
+			continue
+		}
+		_, poolCIDR, err := net.ParseCIDR(pool.Spec.CIDR)
+		if err != nil {
+			continue
+		}
+		for _, p := range instance.Spec.CalicoNetwork.IPPools {
+			_, cidr, err := net.ParseCIDR(p.CIDR)
+			if err != nil {
+				continue
+			}
+			if cidr.Contains(poolCIDR.IP) || poolCIDR.Contains(cidr.IP) {
+				return fmt.Errorf("ipPool.CIDR(%s) of %s overlaps with IPPool %s", p.CIDR, p.Name, pool.Name)
+			}
+		}
+		if lb != nil {
+			_, cidr, err := net.ParseCIDR(lb.CIDR)
+			if err == nil && (cidr.Contains(poolCIDR.IP) || poolCIDR.Contains(cidr.IP)) {
+				return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool.cidr %s overlaps with IPPool %s", lb.CIDR, pool.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCalicoNodePools validates the CalicoNodePools. Since a node must run a single calico-node pod, the node
+// selectors of every pair of pools must select different values for at least one label. The MTU and node address
+// autodetection of a pool override those of spec.calicoNetwork, so they require it.
+func validateCalicoNodePools(instance *operatorv1.Installation) error {
+	pools := instance.Spec.CalicoNodePools
+	for i, pool := range pools {
+		if errs := validation.IsDNS1123Label(pool.Name); len(errs) > 0 {
+			return fmt.Errorf("spec.calicoNodePools name %q is invalid: %s", pool.Name, strings.Join(errs, ", "))
+		}
+		if len(pool.Name) > maxCalicoNodePoolNameLength {
+			return fmt.Errorf("spec.calicoNodePools name %s must be no more than %d characters", pool.Name, maxCalicoNodePoolNameLength)
+		}
+		if len(pool.NodeSelector) == 0 {
+			return fmt.Errorf("spec.calicoNodePools %s must have a nodeSelector", pool.Name)
+		}
+		if instance.Spec.CalicoNetwork == nil && (pool.MTU != nil || pool.NodeAddressAutodetectionV4 != nil || pool.NodeAddressAutodetectionV6 != nil) {
+			return fmt.Errorf("spec.calicoNodePools %s cannot override the mtu or the node address autodetection when spec.calicoNetwork is not set", pool.Name)
+		}
+		for _, ad := range []*operatorv1.NodeAddressAutodetection{pool.NodeAddressAutodetectionV4, pool.NodeAddressAutodetectionV6} {
+			if ad == nil {
+				continue
+			}
+			if err := validateNodeAddressDetection(ad); err != nil {
+				return err
+			}
+		}
+
+		for _, other := range pools[:i] {
+			if other.Name == pool.Name {
+				return fmt.Errorf("spec.calicoNodePools name %s is used by more than one pool", pool.Name)
+			}
+			exclusive := false
+			for k, v := range pool.NodeSelector {
+				if ov, ok := other.NodeSelector[k]; ok && ov != v {
+					exclusive = true
+					break
+				}
+			}
+			if !exclusive {
+				return fmt.Errorf("spec.calicoNodePools %s and %s may select the same nodes, their nodeSelectors must select different values for at least one label", other.Name, pool.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func kubeControllerDisabled(state *operatorv1.KubeControllerState) bool {
+	return state != nil && *state == operatorv1.KubeControllerDisabled
+}
+
 // validateNodeAddressDetection checks that at most one form of IP auto-detection is configured per-family.
 func validateNodeAddressDetection(ad *operatorv1.NodeAddressAutodetection) error {
 	numEnabled := 0
@@ -15,13 +15,20 @@
 package installation
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
 )
 
 var _ = Describe("Installation validation tests", func() {
@@ -68,19 +75,132 @@ var _ = Describe("Installation validation tests", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
-	It("should prevent IPv6 if BPF is enabled", func() {
-		bpf := operator.LinuxDataplaneBPF
-		instance.Spec.CalicoNetwork.LinuxDataplane = &bpf
-		instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
-			{
-				CIDR:          "1eef::/64",
+	Describe("IPv6 with BPF enabled", func() {
+		var calicoNodeVersion, tigeraNodeVersion string
+		var firstFound = true
+
+		BeforeEach(func() {
+			calicoNodeVersion = components.ComponentCalicoNode.Version
+			tigeraNodeVersion = components.ComponentTigeraNode.Version
+
+			bpf := operator.LinuxDataplaneBPF
+			instance.Spec.CalicoNetwork.LinuxDataplane = &bpf
+			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 = &operator.NodeAddressAutodetection{FirstFound: &firstFound}
+			instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+				{
+					CIDR:          "1eef::/64",
+					NATOutgoing:   operator.NATOutgoingEnabled,
+					Encapsulation: operator.EncapsulationNone,
+					NodeSelector:  "all()",
+				},
+			}
+		})
+
+		AfterEach(func() {
+			components.ComponentCalicoNode.Version = calicoNodeVersion
+			components.ComponentTigeraNode.Version = tigeraNodeVersion
+		})
+
+		It("should prevent IPv6 before the releases that support it", func() {
+			components.ComponentCalicoNode.Version = "v3.26.4"
+			err := validateCustomResource(instance)
+			Expect(err).To(MatchError("IPv6 IP pool is specified but eBPF mode does not support IPv6 in this Calico release"))
+
+			components.ComponentCalicoNode.Version = "v3.27.0"
+			instance.Spec.Variant = operator.TigeraSecureEnterprise
+			components.ComponentTigeraNode.Version = "v3.18.1"
+			err = validateCustomResource(instance)
+			Expect(err).To(MatchError("IPv6 IP pool is specified but eBPF mode does not support IPv6 in this TigeraSecureEnterprise release"))
+
+			components.ComponentTigeraNode.Version = "v3.19.0"
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should allow IPv6-only without IPv4 autodetection", func() {
+			components.ComponentCalicoNode.Version = "v3.27.0"
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 = nil
+			err := validateCustomResource(instance)
+			Expect(err).To(MatchError("spec.calicoNetwork.nodeAddressAutodetectionV6 is required for the BPF dataplane with IPv6 IP pools"))
+		})
+
+		It("should require IPv4 autodetection for dual-stack", func() {
+			instance.Spec.CalicoNetwork.IPPools = append(instance.Spec.CalicoNetwork.IPPools, operator.IPPool{
+				CIDR:          "192.168.0.0/16",
 				NATOutgoing:   operator.NATOutgoingEnabled,
-				Encapsulation: operator.EncapsulationNone,
+				Encapsulation: operator.EncapsulationVXLAN,
 				NodeSelector:  "all()",
-			},
-		}
-		err := validateCustomResource(instance)
-		Expect(err).To(MatchError("IPv6 IP pool is specified but eBPF mode does not support IPv6"))
+			})
+			err := validateCustomResource(instance)
+			Expect(err).To(MatchError("spec.calicoNetwork.nodeAddressAutodetectionV4 is required for the BPF dataplane"))
+
+			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 = &operator.NodeAddressAutodetection{FirstFound: &firstFound}
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+			components.ComponentCalicoNode.Version = "v3.27.2"
+			err = validateCustomResource(instance)
+			Expect(err).To(MatchError("IPv4 and IPv6 IP pools are specified but eBPF mode does not support dual-stack in this Calico release"))
+		})
+	})
+
+	Describe("with multiple IP pools per IP version", func() {
+		BeforeEach(func() {
+			enabled := operator.BGPEnabled
+			instance.Spec.CalicoNetwork.BGP = &enabled
+			instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+				{Name: "zone-a", CIDR: "192.168.0.0/24", NodeSelector: "zone == 'a'"},
+				{Name: "zone-b", CIDR: "192.168.1.0/24", NodeSelector: "zone == 'b'"},
+				{Name: "zone-a-v6", CIDR: "fd00:0:0:1::/64", NodeSelector: "zone == 'a'"},
+				{Name: "zone-b-v6", CIDR: "fd00:0:0:2::/64", NodeSelector: "zone == 'b'"},
+			}
+			Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should allow them", func() {
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should require names", func() {
+			instance.Spec.CalicoNetwork.IPPools[1].Name = ""
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"ipPool.name must be set for 192.168.1.0/24 when there is more than one IPv4 IPPool"))
+		})
+
+		It("should require unique names across IP versions", func() {
+			instance.Spec.CalicoNetwork.IPPools[3].Name = "zone-b"
+			Expect(validateCustomResource(instance)).To(MatchError("ipPool.name zone-b is used by more than one IPPool"))
+		})
+
+		It("should reject invalid names", func() {
+			instance.Spec.CalicoNetwork.IPPools[0].Name = "Zone_A"
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should reject overlapping CIDRs", func() {
+			instance.Spec.CalicoNetwork.IPPools[1].CIDR = "192.168.0.128/25"
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"ipPool.CIDR(192.168.0.128/25) of zone-b overlaps with ipPool.CIDR(192.168.0.0/24) of zone-a"))
+		})
+
+		It("should require an Automatic pool per IP version", func() {
+			manual := operator.IPPoolAssignmentManual
+			instance.Spec.CalicoNetwork.IPPools[0].AssignmentMode = &manual
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+			instance.Spec.CalicoNetwork.IPPools[1].AssignmentMode = &manual
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"at least one IPv4 IPPool must have ipPool.assignmentMode Automatic"))
+		})
+
+		It("should reject them with HostLocal IPAM", func() {
+			instance.Spec.CNI.IPAM.Type = operator.IPAMPluginHostLocal
+			Expect(validateCustomResource(instance)).To(MatchError("only one IPv4 IPPool is allowed with Calico CNI and HostLocal IPAM"))
+		})
+	})
+
+	It("should reject an invalid IP pool CIDR", func() {
+		instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{{CIDR: "192.168.0.0"}}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
 	})
 
 	It("should prevent multiple node address autodetection methods", func() {
@@ -102,6 +222,110 @@ var _ = Describe("Installation validation tests", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should only allow component network policies for Calico Enterprise", func() {
+		enabled := operator.ComponentNetworkPoliciesEnabled
+		instance.Spec.ComponentNetworkPolicies = &enabled
+		err := validateCustomResource(instance)
+		Expect(err).To(MatchError("spec.componentNetworkPolicies can only be enabled for the TigeraSecureEnterprise variant"))
+
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should only allow the Enterprise kube-controllers to be configured for Calico Enterprise", func() {
+		instance.Spec.KubeControllers = &operator.KubeControllersSpec{
+			Controllers: &operator.KubeControllersControllers{Service: &operator.KubeControllersController{}},
+		}
+		err := validateCustomResource(instance)
+		Expect(err).To(MatchError("spec.kubeControllers.controllers.service can only be set for the TigeraSecureEnterprise variant"))
+
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should not allow every kube-controllers controller to be disabled", func() {
+		disabled := operator.KubeControllerDisabled
+		instance.Spec.KubeControllers = &operator.KubeControllersSpec{
+			Controllers: &operator.KubeControllersControllers{Node: &operator.KubeControllersNodeController{State: &disabled}},
+		}
+		err := validateCustomResource(instance)
+		Expect(err).To(MatchError("spec.kubeControllers.controllers.node.state cannot be Disabled for the Calico variant, which runs no other controller"))
+
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.KubeControllers.Controllers.Service = &operator.KubeControllersController{State: &disabled}
+		instance.Spec.KubeControllers.Controllers.FederatedServices = &operator.KubeControllersController{State: &disabled}
+		err = validateCustomResource(instance)
+		Expect(err).To(MatchError("spec.kubeControllers.controllers cannot disable every controller"))
+	})
+
+	It("should validate the Felix settings", func() {
+		instance.Spec.Felix = &operator.FelixSpec{
+			RouteTableRange: &operator.FelixRouteTableRange{Min: 100, Max: 50},
+		}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.felix.routeTableRange.min 100 must not be greater than spec.felix.routeTableRange.max 50"))
+
+		instance.Spec.Felix.RouteTableRange.Max = 150
+		instance.Spec.Felix.HealthTimeoutOverrides = []operator.FelixHealthTimeoutOverride{
+			{Name: "InternalDataplaneMainLoop", Timeout: metav1.Duration{Duration: time.Minute}},
+			{Name: "InternalDataplaneMainLoop", Timeout: metav1.Duration{Duration: 2 * time.Minute}},
+		}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.felix.healthTimeoutOverrides has more than one override for InternalDataplaneMainLoop"))
+
+		instance.Spec.Felix.HealthTimeoutOverrides[1].Name = "CalculationGraph=1s"
+		Expect(validateCustomResource(instance)).To(MatchError(`spec.felix.healthTimeoutOverrides has an invalid component name "CalculationGraph=1s"`))
+
+		instance.Spec.Felix.HealthTimeoutOverrides = instance.Spec.Felix.HealthTimeoutOverrides[:1]
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should require a release whose Felix reads the health timeout overrides", func() {
+		calicoNodeVersion, tigeraNodeVersion := components.ComponentCalicoNode.Version, components.ComponentTigeraNode.Version
+		defer func() {
+			components.ComponentCalicoNode.Version, components.ComponentTigeraNode.Version = calicoNodeVersion, tigeraNodeVersion
+		}()
+		instance.Spec.Felix = &operator.FelixSpec{
+			HealthTimeoutOverrides: []operator.FelixHealthTimeoutOverride{
+				{Name: "InternalDataplaneMainLoop", Timeout: metav1.Duration{Duration: time.Minute}},
+			},
+		}
+
+		components.ComponentCalicoNode.Version = "v3.24.5"
+		Expect(validateCustomResource(instance)).To(MatchError("spec.felix.healthTimeoutOverrides is not supported in this Calico release"))
+		components.ComponentCalicoNode.Version = "v3.25.0"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		components.ComponentTigeraNode.Version = "v3.15.1"
+		Expect(validateCustomResource(instance)).To(MatchError("spec.felix.healthTimeoutOverrides is not supported in this TigeraSecureEnterprise release"))
+		components.ComponentTigeraNode.Version = "v3.16.0"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should require a release whose Felix encrypts IPv6 traffic with WireGuard", func() {
+		calicoNodeVersion, tigeraNodeVersion := components.ComponentCalicoNode.Version, components.ComponentTigeraNode.Version
+		defer func() {
+			components.ComponentCalicoNode.Version, components.ComponentTigeraNode.Version = calicoNodeVersion, tigeraNodeVersion
+		}()
+		disabled := operator.WireGuardDisabled
+		instance.Spec.CalicoNetwork.WireGuard = &operator.WireGuardSpec{IPv6: &disabled}
+
+		components.ComponentCalicoNode.Version = "v3.22.4"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		enabled := operator.WireGuardEnabled
+		instance.Spec.CalicoNetwork.WireGuard.IPv6 = &enabled
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.wireGuard.ipv6 is not supported in this Calico release"))
+		components.ComponentCalicoNode.Version = "v3.23.0"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		components.ComponentTigeraNode.Version = "v3.13.2"
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.wireGuard.ipv6 is not supported in this TigeraSecureEnterprise release"))
+		components.ComponentTigeraNode.Version = "v3.14.0"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
 	It("should prevent host ports if BPF is enabled", func() {
 		bpf := operator.LinuxDataplaneBPF
 		instance.Spec.CalicoNetwork.LinuxDataplane = &bpf
@@ -287,6 +511,23 @@ var _ = Describe("Installation validation tests", func() {
 		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
 	})
 
+	It("should validate PodDisruptionBudgets", func() {
+		one := intstr.FromInt(1)
+		instance.Spec.PodDisruptionBudgets = &operator.PodDisruptionBudgets{
+			Components: []operator.ComponentPodDisruptionBudget{
+				{Name: "calico-typha", MaxUnavailable: &one},
+				{Name: "calico-apiserver", MinAvailable: &one},
+			},
+		}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.PodDisruptionBudgets.Components[1].MaxUnavailable = &one
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.PodDisruptionBudgets.Components[1] = operator.ComponentPodDisruptionBudget{Name: "calico-typha", Disabled: true}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
 	It("should validate HostPorts", func() {
 		instance.Spec.CalicoNetwork.HostPorts = nil
 		err := validateCustomResource(instance)
@@ -589,6 +830,225 @@ var _ = Describe("Installation validation tests", func() {
 		})
 	})
 
+	DescribeTable("CalicoNodePools validation", func(pools []operator.CalicoNodePool, expectedErr string) {
+		instance.Spec.CalicoNodePools = pools
+		err := validateCustomResource(instance)
+		if expectedErr == "" {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(expectedErr))
+		}
+	},
+		Entry("mutually exclusive pools", []operator.CalicoNodePool{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			{Name: "edge", NodeSelector: map[string]string{"pool": "edge", "zone": "edge-1"}},
+		}, ""),
+		Entry("invalid name", []operator.CalicoNodePool{
+			{Name: "GPU", NodeSelector: map[string]string{"pool": "gpu"}},
+		}, `spec.calicoNodePools name "GPU" is invalid: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`),
+		Entry("too long name", []operator.CalicoNodePool{
+			{Name: strings.Repeat("a", 52), NodeSelector: map[string]string{"pool": "gpu"}},
+		}, fmt.Sprintf("spec.calicoNodePools name %s must be no more than 51 characters", strings.Repeat("a", 52))),
+		Entry("duplicate names", []operator.CalicoNodePool{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "edge"}},
+		}, "spec.calicoNodePools name gpu is used by more than one pool"),
+		Entry("missing node selector", []operator.CalicoNodePool{
+			{Name: "gpu"},
+		}, "spec.calicoNodePools gpu must have a nodeSelector"),
+		Entry("overlapping node selectors", []operator.CalicoNodePool{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			{Name: "fast", NodeSelector: map[string]string{"disk": "ssd"}},
+		}, "spec.calicoNodePools gpu and fast may select the same nodes, their nodeSelectors must select different values for at least one label"),
+		Entry("invalid autodetection", []operator.CalicoNodePool{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}, NodeAddressAutodetectionV4: &operator.NodeAddressAutodetection{
+				Interface: "ib.*", CanReach: "8.8.8.8",
+			}},
+		}, "no more than one node address autodetection method can be specified per-family"),
+	)
+
+	It("should reject the MTU of a CalicoNodePool without spec.calicoNetwork", func() {
+		mtu := int32(8950)
+		instance.Spec.CalicoNetwork = nil
+		instance.Spec.CNI.Type = operator.PluginAmazonVPC
+		instance.Spec.CNI.IPAM = &operator.IPAMSpec{Type: operator.IPAMPluginAmazonVPC}
+		instance.Spec.CalicoNodePools = []operator.CalicoNodePool{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}, MTU: &mtu},
+		}
+		Expect(validateCustomResource(instance)).To(MatchError(
+			"spec.calicoNodePools gpu cannot override the mtu or the node address autodetection when spec.calicoNetwork is not set"))
+
+		instance.Spec.CalicoNodePools[0].MTU = nil
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should require the node controller for the automatic host endpoints", func() {
+		enabled := operator.KubeControllerEnabled
+		disabled := operator.KubeControllerDisabled
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		instance.Spec.KubeControllers = &operator.KubeControllersSpec{
+			Controllers: &operator.KubeControllersControllers{
+				Node: &operator.KubeControllersNodeController{State: &disabled, AutoHostEndpoints: &enabled},
+			},
+		}
+		Expect(validateCustomResource(instance)).To(MatchError(
+			"spec.kubeControllers.controllers.node.autoHostEndpoints requires the node controller, which is disabled by spec.kubeControllers.controllers.node.state"))
+
+		instance.Spec.KubeControllers.Controllers.Node.AutoHostEndpoints = &disabled
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	Describe("Multus", func() {
+		var nadsEnabled operator.NetworkAttachmentDefinitionsState
+		BeforeEach(func() {
+			multus := operator.MultiInterfaceModeMultus
+			manual := operator.IPPoolAssignmentManual
+			nadsEnabled = operator.NetworkAttachmentDefinitionsEnabled
+			instance.Spec.CalicoNetwork.MultiInterfaceMode = &multus
+			instance.Spec.CalicoNetwork.Multus = &operator.MultusSpec{NetworkAttachmentDefinitions: &nadsEnabled}
+			instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+				{Name: "default-ipv4-ippool", CIDR: "192.168.0.0/24"},
+				{Name: "secondary", CIDR: "172.16.0.0/24", AssignmentMode: &manual},
+			}
+			Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should allow the NetworkAttachmentDefinitions of the secondary IP pools", func() {
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should require a secondary IP pool for the NetworkAttachmentDefinitions", func() {
+			instance.Spec.CalicoNetwork.IPPools = instance.Spec.CalicoNetwork.IPPools[:1]
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.multus.networkAttachmentDefinitions requires at least one IP pool with ipPool.assignmentMode Manual"))
+		})
+
+		It("should require the Multus multi-interface mode", func() {
+			none := operator.MultiInterfaceModeNone
+			instance.Spec.CalicoNetwork.MultiInterfaceMode = &none
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.multus is supported only when spec.calicoNetwork.multiInterfaceMode is Multus"))
+		})
+	})
+
+	Describe("VPP", func() {
+		BeforeEach(func() {
+			vpp := operator.LinuxDataplaneVPP
+			instance.Spec.CalicoNetwork.LinuxDataplane = &vpp
+			instance.Spec.CalicoNetwork.VPP = &operator.VPPSpec{UplinkInterface: "eth1"}
+			Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should default the VPP settings", func() {
+			Expect(*instance.Spec.CalicoNetwork.VPP.Hugepages).To(Equal(int32(256)))
+			Expect(instance.Spec.CalicoNetwork.VPP.ServiceCIDRs).To(Equal([]string{"10.96.0.0/12"}))
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should require the VPP dataplane", func() {
+			iptables := operator.LinuxDataplaneIptables
+			instance.Spec.CalicoNetwork.LinuxDataplane = &iptables
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.vpp is supported only when spec.calicoNetwork.linuxDataplane is VPP"))
+		})
+
+		It("should require the uplink interface", func() {
+			instance.Spec.CalicoNetwork.VPP.UplinkInterface = ""
+			Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vpp.uplinkInterface is required"))
+		})
+
+		It("should not allow an invalid driver", func() {
+			driver := operator.VPPDriver("e1000")
+			instance.Spec.CalicoNetwork.VPP.Driver = &driver
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"e1000 is invalid for spec.calicoNetwork.vpp.driver, should be one of AFPacket,AFXDP,AVF,DPDK,RDMA,Virtio,VMXNET3"))
+		})
+
+		It("should not allow a negative number of hugepages", func() {
+			var hugepages int32 = -1
+			instance.Spec.CalicoNetwork.VPP.Hugepages = &hugepages
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.vpp.hugepages -1 is invalid, it must not be negative"))
+		})
+
+		It("should validate the CPU list of the workers", func() {
+			instance.Spec.CalicoNetwork.VPP.CPUPinning = &operator.VPPCPUPinning{MainCore: 1, WorkerCores: "2-3,6"}
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+			instance.Spec.CalicoNetwork.VPP.CPUPinning.WorkerCores = "2 3"
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.vpp.cpuPinning.workerCores 2 3 is not a CPU list"))
+		})
+	})
+
+	Describe("service advertisement", func() {
+		BeforeEach(func() {
+			instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{{CIDR: "192.168.0.0/16"}}
+			instance.Spec.CalicoNetwork.ServiceAdvertisement = &operator.ServiceAdvertisementSpec{
+				ClusterIPCIDRs:     []string{"10.96.0.0/12"},
+				ExternalIPCIDRs:    []string{"172.16.0.0/24"},
+				LoadBalancerIPPool: &operator.LoadBalancerIPPool{CIDR: "172.17.0.0/24"},
+			}
+			Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should default the LoadBalancer IP pool", func() {
+			lb := instance.Spec.CalicoNetwork.ServiceAdvertisement.LoadBalancerIPPool
+			Expect(lb.Name).To(Equal("loadbalancer-ippool"))
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should require BGP", func() {
+			disabled := operator.BGPDisabled
+			instance.Spec.CalicoNetwork.BGP = &disabled
+			instance.Spec.CalicoNetwork.IPPools[0].Encapsulation = operator.EncapsulationVXLAN
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.serviceAdvertisement is supported only when spec.calicoNetwork.bgp is Enabled"))
+		})
+
+		It("should not allow an invalid Service CIDR", func() {
+			instance.Spec.CalicoNetwork.ServiceAdvertisement.ExternalIPCIDRs = []string{"172.16.0.0"}
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.serviceAdvertisement.externalIPCIDRs entry 172.16.0.0 is invalid: invalid CIDR address: 172.16.0.0"))
+		})
+
+		It("should not allow the LoadBalancer IP pool to overlap an IP pool", func() {
+			instance.Spec.CalicoNetwork.ServiceAdvertisement.LoadBalancerIPPool.CIDR = "192.168.100.0/24"
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool.cidr 192.168.100.0/24 overlaps with ipPool.CIDR(192.168.0.0/16) of default-ipv4-ippool"))
+		})
+
+		It("should not allow the LoadBalancer IP pool to use the name of an IP pool", func() {
+			instance.Spec.CalicoNetwork.ServiceAdvertisement.LoadBalancerIPPool.Name = "default-ipv4-ippool"
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool.name default-ipv4-ippool is used by an IPPool of spec.calicoNetwork.ipPools"))
+		})
+
+		It("should require a release that assigns LoadBalancer IPs", func() {
+			calicoNodeVersion, tigeraNodeVersion := components.ComponentCalicoNode.Version, components.ComponentTigeraNode.Version
+			defer func() {
+				components.ComponentCalicoNode.Version, components.ComponentTigeraNode.Version = calicoNodeVersion, tigeraNodeVersion
+			}()
+
+			components.ComponentCalicoNode.Version = "v3.27.3"
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool is not supported in this Calico release, which does not assign LoadBalancer IPs"))
+			components.ComponentCalicoNode.Version = "v3.28.0"
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+			instance.Spec.Variant = operator.TigeraSecureEnterprise
+			components.ComponentTigeraNode.Version = "v3.19.2"
+			Expect(validateCustomResource(instance)).To(MatchError(
+				"spec.calicoNetwork.serviceAdvertisement.loadBalancerIPPool is not supported in this TigeraSecureEnterprise release, which does not assign LoadBalancer IPs"))
+			components.ComponentTigeraNode.Version = "v3.20.0"
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+			By("still advertising the Service CIDRs without the LoadBalancer IP pool")
+			components.ComponentTigeraNode.Version = "v3.19.2"
+			instance.Spec.CalicoNetwork.ServiceAdvertisement.LoadBalancerIPPool = nil
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+	})
+
 	It("validate custom installation", func() {
 		disabled := operator.BGPDisabled
 		ipfw := operator.ContainerIPForwardingEnabled
@@ -19,9 +19,35 @@ import (
 	"regexp"
 
 	gv "github.com/hashicorp/go-version"
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/version"
 )
 
+const (
+	// The first Calico and Calico Enterprise releases with IPv6 support in the eBPF dataplane, for IPv6-only
+	// clusters. Calico Enterprise 3.19 is built on Calico 3.27, 3.18 is built on Calico 3.26.
+	bpfIPv6MinCalicoVersion     = "v3.27.0"
+	bpfIPv6MinEnterpriseVersion = "v3.19.0"
+
+	// The first Calico and Calico Enterprise releases with dual-stack support in the eBPF dataplane.
+	bpfDualStackMinCalicoVersion     = "v3.28.0"
+	bpfDualStackMinEnterpriseVersion = "v3.20.0"
+
+	// The first Calico and Calico Enterprise releases whose kube-controllers assigns the IPs of the LoadBalancer
+	// Services from the IP pools with the LoadBalancer allowed use.
+	loadBalancerIPAMMinCalicoVersion     = "v3.28.0"
+	loadBalancerIPAMMinEnterpriseVersion = "v3.20.0"
+
+	// The first Calico and Calico Enterprise releases whose Felix reads the health timeout overrides.
+	healthTimeoutOverridesMinCalicoVersion     = "v3.25.0"
+	healthTimeoutOverridesMinEnterpriseVersion = "v3.16.0"
+
+	// The first Calico and Calico Enterprise releases whose Felix encrypts IPv6 traffic with WireGuard.
+	wireGuardIPv6MinCalicoVersion     = "v3.23.0"
+	wireGuardIPv6MinEnterpriseVersion = "v3.14.0"
+)
+
 var buildVersion *gv.Version
 var gitDescribeSuffixRegexp = regexp.MustCompile(`-\d+-\w+$`)
 var versionRegexp = regexp.MustCompile("^" + gv.VersionRegexpRaw + "$")
@@ -74,3 +100,48 @@ func versionFromBuildVersion(buildVersion string) (*gv.Version, error) {
 	s := gitDescribeSuffixRegexp.ReplaceAllString(buildVersion, "")
 	return gv.NewVersion(s)
 }
+
+// nodeVersionAtLeast returns whether the node release deployed for the variant is at least calicoMin for Calico or
+// enterpriseMin for Calico Enterprise. Versions that are not releases, such as master, are assumed to be newer than
+// any release.
+func nodeVersionAtLeast(variant operator.ProductVariant, calicoMin, enterpriseMin string) bool {
+	nodeVersion, minVersion := components.ComponentCalicoNode.Version, calicoMin
+	if variant == operator.TigeraSecureEnterprise {
+		nodeVersion, minVersion = components.ComponentTigeraNode.Version, enterpriseMin
+	}
+
+	v, err := gv.NewVersion(nodeVersion)
+	if err != nil {
+		return true
+	}
+	return v.GreaterThanOrEqual(gv.Must(gv.NewVersion(minVersion)))
+}
+
+// bpfIPv6Supported returns whether the eBPF dataplane of the node release deployed for the variant supports IPv6.
+func bpfIPv6Supported(variant operator.ProductVariant) bool {
+	return nodeVersionAtLeast(variant, bpfIPv6MinCalicoVersion, bpfIPv6MinEnterpriseVersion)
+}
+
+// bpfDualStackSupported returns whether the eBPF dataplane of the node release deployed for the variant supports
+// running IPv4 and IPv6 at the same time.
+func bpfDualStackSupported(variant operator.ProductVariant) bool {
+	return nodeVersionAtLeast(variant, bpfDualStackMinCalicoVersion, bpfDualStackMinEnterpriseVersion)
+}
+
+// loadBalancerIPAMSupported returns whether the release deployed for the variant assigns the IPs of the
+// LoadBalancer Services. kube-controllers is released with the node.
+func loadBalancerIPAMSupported(variant operator.ProductVariant) bool {
+	return nodeVersionAtLeast(variant, loadBalancerIPAMMinCalicoVersion, loadBalancerIPAMMinEnterpriseVersion)
+}
+
+// healthTimeoutOverridesSupported returns whether Felix in the node release deployed for the variant reads the health
+// timeout overrides.
+func healthTimeoutOverridesSupported(variant operator.ProductVariant) bool {
+	return nodeVersionAtLeast(variant, healthTimeoutOverridesMinCalicoVersion, healthTimeoutOverridesMinEnterpriseVersion)
+}
+
+// wireGuardIPv6Supported returns whether Felix in the node release deployed for the variant encrypts IPv6 traffic
+// with WireGuard.
+func wireGuardIPv6Supported(variant operator.ProductVariant) bool {
+	return nodeVersionAtLeast(variant, wireGuardIPv6MinCalicoVersion, wireGuardIPv6MinEnterpriseVersion)
+}
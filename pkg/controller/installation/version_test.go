@@ -22,6 +22,8 @@ import (
 
 	gv "github.com/hashicorp/go-version"
 	"github.com/onsi/ginkgo/extensions/table"
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/version"
 )
 
@@ -71,4 +73,23 @@ var _ = Describe("Version validation logic tests", func() {
 		table.Entry("empty build version", "", "",
 			fmt.Errorf(`Invalid build version: ""`)),
 	)
+
+	table.DescribeTable("should compare the node version of the variant",
+		func(variant operator.ProductVariant, calicoNodeVersion, tigeraNodeVersion string, expected bool) {
+			defer func(calico, tigera string) {
+				components.ComponentCalicoNode.Version = calico
+				components.ComponentTigeraNode.Version = tigera
+			}(components.ComponentCalicoNode.Version, components.ComponentTigeraNode.Version)
+			components.ComponentCalicoNode.Version = calicoNodeVersion
+			components.ComponentTigeraNode.Version = tigeraNodeVersion
+
+			Expect(nodeVersionAtLeast(variant, "v3.27.0", "v3.19.0")).To(Equal(expected))
+		},
+		table.Entry("older Calico", operator.Calico, "v3.26.1", "v3.19.0", false),
+		table.Entry("same Calico", operator.Calico, "v3.27.0", "v3.17.0", true),
+		table.Entry("newer Calico", operator.Calico, "v3.28.0-1.0", "v3.17.0", true),
+		table.Entry("older Enterprise", operator.TigeraSecureEnterprise, "v3.27.0", "v3.18.2", false),
+		table.Entry("newer Enterprise", operator.TigeraSecureEnterprise, "v3.26.0", "v3.19.0", true),
+		table.Entry("unreleased Calico", operator.Calico, "master", "v3.17.0", true),
+	)
 })
@@ -29,8 +29,10 @@ import (
 	"github.com/tigera/operator/pkg/controller/status"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -44,6 +46,8 @@ var (
 	windowsLog = logf.Log.WithName("windows_upgrader")
 
 	defaultMaxUnavailable = 1
+
+	defaultNodeUpgradeTimeout = 30 * time.Minute
 )
 
 type CalicoWindowsUpgrader interface {
@@ -101,20 +105,22 @@ func (w *calicoWindowsUpgrader) UpdateConfig(install *operatorv1.InstallationSpe
 // getNodeUpgradeStatus checks the nodes from its indexer and determines whether
 // the nodes are:
 // - pending: Node does not have the expected variant and/or version. No upgrade label.
-// - inProgress: Node does not have the expected variant and/or version. It has the upgrade in-progress label.
-// - inSync: Node has the expected variant and version. It does not have the upgrade in-progress label.
+// - inProgress: Node has the upgrade draining or in-progress label.
+// - inSync: Node has the expected variant and version. It does not have the upgrade label.
+// - failed: Node has the upgrade failed label.
 // This returns an error (if any) and maps of the nodes that are pending,
-// inProgress, or inSync.
-func (w *calicoWindowsUpgrader) getNodeUpgradeStatus() (map[string]*corev1.Node, map[string]*corev1.Node, map[string]*corev1.Node, error) {
+// inProgress, inSync, or failed.
+func (w *calicoWindowsUpgrader) getNodeUpgradeStatus() (map[string]*corev1.Node, map[string]*corev1.Node, map[string]*corev1.Node, map[string]*corev1.Node, error) {
 	pending := make(map[string]*corev1.Node)
 	inSync := make(map[string]*corev1.Node)
 	inProgress := make(map[string]*corev1.Node)
+	failed := make(map[string]*corev1.Node)
 	expectedVersion := w.getExpectedVersion()
 
 	for _, obj := range w.nodeIndexInformer.GetIndexer().List() {
 		node, ok := obj.(*corev1.Node)
 		if !ok {
-			return nil, nil, nil, fmt.Errorf("Never expected index to have anything other than a Node object: %v", obj)
+			return nil, nil, nil, nil, fmt.Errorf("Never expected index to have anything other than a Node object: %v", obj)
 		}
 
 		if node.Labels[corev1.LabelOSStable] != "windows" {
@@ -128,7 +134,7 @@ func (w *calicoWindowsUpgrader) getNodeUpgradeStatus() (map[string]*corev1.Node,
 		// Calico Windows node or it needs to be upgraded manually to
 		// a version supported by the calicoWindowsUpgrader.
 		if !exists {
-			return nil, nil, nil, fmt.Errorf("Node %v does not have the version annotation, it might be unhealthy or it might be running an unsupported Calico version.", node.Name)
+			return nil, nil, nil, nil, fmt.Errorf("Node %v does not have the version annotation, it might be unhealthy or it might be running an unsupported Calico version.", node.Name)
 		}
 
 		// Don't upgrade from Calico to Calico
@@ -137,20 +143,26 @@ func (w *calicoWindowsUpgrader) getNodeUpgradeStatus() (map[string]*corev1.Node,
 			continue
 		}
 
-		if node.Labels[common.CalicoWindowsUpgradeLabel] == common.CalicoWindowsUpgradeLabelInProgress {
-			windowsLog.V(1).Info(fmt.Sprintf("Node %v has the upgrade in-progress label", node.Name))
+		switch node.Labels[common.CalicoWindowsUpgradeLabel] {
+		case common.CalicoWindowsUpgradeLabelInProgress, common.CalicoWindowsUpgradeLabelDraining:
+			windowsLog.V(1).Info(fmt.Sprintf("Node %v has the upgrade %v label", node.Name, node.Labels[common.CalicoWindowsUpgradeLabel]))
 			inProgress[node.Name] = node
-		} else if variant != w.install.Variant || version != expectedVersion {
-			windowsLog.V(1).Info(fmt.Sprintf("Node %v doesn't have the latest variant and/or version. variant=%v, expectedVariant=%v, version=%v, expectedVersion=%v", node.Name, variant, w.install.Variant, version, expectedVersion))
-			pending[node.Name] = node
-		} else {
-			windowsLog.V(1).Info(fmt.Sprintf("Node %v has the latest variant and version", node.Name))
-			inSync[node.Name] = node
+		case common.CalicoWindowsUpgradeLabelFailed:
+			windowsLog.V(1).Info(fmt.Sprintf("Node %v has the upgrade failed label", node.Name))
+			failed[node.Name] = node
+		default:
+			if variant != w.install.Variant || version != expectedVersion {
+				windowsLog.V(1).Info(fmt.Sprintf("Node %v doesn't have the latest variant and/or version. variant=%v, expectedVariant=%v, version=%v, expectedVersion=%v", node.Name, variant, w.install.Variant, version, expectedVersion))
+				pending[node.Name] = node
+			} else {
+				windowsLog.V(1).Info(fmt.Sprintf("Node %v has the latest variant and version", node.Name))
+				inSync[node.Name] = node
+			}
 		}
 	}
 
-	windowsLog.V(1).Info(fmt.Sprintf("pending=%v, in-progress=%v, in-sync=%v", len(pending), len(inProgress), len(inSync)))
-	return pending, inProgress, inSync, nil
+	windowsLog.V(1).Info(fmt.Sprintf("pending=%v, in-progress=%v, in-sync=%v, failed=%v", len(pending), len(inProgress), len(inSync), len(failed)))
+	return pending, inProgress, inSync, failed, nil
 }
 
 func (w *calicoWindowsUpgrader) upgradeCompleted(node *corev1.Node) bool {
@@ -161,10 +173,45 @@ func (w *calicoWindowsUpgrader) upgradeCompleted(node *corev1.Node) bool {
 	return false
 }
 
-func (w *calicoWindowsUpgrader) isUpgradeFromCalicoToEnterprise(node *corev1.Node) bool {
-	_, variant, _ := common.GetNodeVariantAndVersion(node)
-	if variant == operatorv1.Calico && w.install.Variant == operatorv1.TigeraSecureEnterprise {
-		return true
+// upgradeTimedOut returns whether the node has been upgrading for longer than
+// the configured node upgrade timeout. Paused nodes never time out.
+func (w *calicoWindowsUpgrader) upgradeTimedOut(node *corev1.Node) bool {
+	if isUpgradePaused(node) {
+		return false
+	}
+	started, err := time.Parse(time.RFC3339, node.Annotations[common.CalicoWindowsUpgradeStartedAnnotation])
+	if err != nil {
+		// The start time of a resumed node, or of an upgrade started by an
+		// earlier version of the operator, is only set on the next sync.
+		return false
+	}
+	return time.Since(started) > w.getNodeUpgradeTimeout()
+}
+
+func (w *calicoWindowsUpgrader) getNodeUpgradeTimeout() time.Duration {
+	if w.install.CalicoWindowsUpgrade != nil && w.install.CalicoWindowsUpgrade.NodeUpgradeTimeout != nil {
+		return w.install.CalicoWindowsUpgrade.NodeUpgradeTimeout.Duration
+	}
+	return defaultNodeUpgradeTimeout
+}
+
+func (w *calicoWindowsUpgrader) cordonAndDrainEnabled() bool {
+	return w.install.CalicoWindowsUpgrade != nil &&
+		w.install.CalicoWindowsUpgrade.CordonAndDrain != nil &&
+		*w.install.CalicoWindowsUpgrade.CordonAndDrain == operatorv1.CordonAndDrainEnabled
+}
+
+// isUpgradePaused returns whether the node has been annotated to pause its upgrade.
+func isUpgradePaused(node *corev1.Node) bool {
+	return node.Annotations[common.CalicoWindowsUpgradePausedAnnotation] == "true"
+}
+
+// isNodeReady returns whether the node's Ready condition is true.
+func isNodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
 	}
 	return false
 }
@@ -196,7 +243,7 @@ func (w *calicoWindowsUpgrader) updateWindowsNodes() {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 
-	pending, inProgress, inSync, err := w.getNodeUpgradeStatus()
+	pending, inProgress, inSync, failed, err := w.getNodeUpgradeStatus()
 	if err != nil {
 		windowsLog.Error(err, "Failed to get Windows nodes upgrade status")
 		w.isDegraded = true
@@ -204,25 +251,70 @@ func (w *calicoWindowsUpgrader) updateWindowsNodes() {
 		return
 	}
 
-	for _, nodeName := range sortedSliceFromMap(inProgress) {
-		node := inProgress[nodeName]
+	// Failed nodes are left alone unless they have since caught up, e.g. the
+	// upgrade finished after the timeout expired.
+	for _, nodeName := range sortedSliceFromMap(failed) {
+		node := failed[nodeName]
 
-		if w.upgradeCompleted(node) {
+		if w.upgradeCompleted(node) && isNodeReady(node) {
 			if err := w.finishUpgrade(context.Background(), node); err != nil {
-				// Log the error and continue. We will retry when we update nodes again.
 				windowsLog.Info(fmt.Sprintf("Could not complete upgrade on node %v: %v", node.Name, err))
 				continue
 			}
-			// Successfully finished completing the upgrade. Moving the node
-			// from in-progress to in-sync.
 			inSync[node.Name] = node
+			delete(failed, node.Name)
+		}
+	}
+
+	for _, nodeName := range sortedSliceFromMap(inProgress) {
+		node := inProgress[nodeName]
+
+		if w.upgradeCompleted(node) {
+			// Wait for the node to report ready before the node's upgrade is
+			// considered complete.
+			if !isNodeReady(node) {
+				windowsLog.V(1).Info(fmt.Sprintf("Node %v has been upgraded but is not ready yet", node.Name))
+			} else {
+				if err := w.finishUpgrade(context.Background(), node); err != nil {
+					// Log the error and continue. We will retry when we update nodes again.
+					windowsLog.Info(fmt.Sprintf("Could not complete upgrade on node %v: %v", node.Name, err))
+					continue
+				}
+				// Successfully finished completing the upgrade. Moving the node
+				// from in-progress to in-sync.
+				inSync[node.Name] = node
+				delete(inProgress, node.Name)
+				continue
+			}
+		}
+
+		if err := w.resetUpgradeStart(context.Background(), node); err != nil {
+			windowsLog.Info(fmt.Sprintf("Could not reset the upgrade start time of node %v: %v", node.Name, err))
+			continue
+		}
+
+		if w.upgradeTimedOut(node) {
+			if err := w.failUpgrade(context.Background(), node); err != nil {
+				windowsLog.Info(fmt.Sprintf("Could not mark upgrade as failed on node %v: %v", node.Name, err))
+				continue
+			}
+			failed[node.Name] = node
 			delete(inProgress, node.Name)
+			continue
+		}
+
+		// Nodes that are still being drained start their upgrade once no pods
+		// remain to be evicted.
+		if node.Labels[common.CalicoWindowsUpgradeLabel] == common.CalicoWindowsUpgradeLabelDraining && !isUpgradePaused(node) {
+			if err := w.drainAndStartUpgrade(context.Background(), node); err != nil {
+				windowsLog.Info(fmt.Sprintf("Could not drain node %v: %v", node.Name, err))
+			}
 		}
 	}
 
 	// Get the total # of windows nodes we can have upgrading using the
 	// maxUnavailable value, if the node upgrade strategy was respected.
-	numWindowsNodes := len(pending) + len(inProgress) + len(inSync)
+	numWindowsNodes := len(pending) + len(inProgress) + len(inSync) + len(failed)
 	maxUnavailable, err := intstr.GetValueFromIntOrPercent(w.install.NodeUpdateStrategy.RollingUpdate.MaxUnavailable, numWindowsNodes, false)
 	if err != nil {
 		windowsLog.Error(err, "Invalid maxUnavailable value, falling back to default of 1")
@@ -232,28 +324,52 @@ func (w *calicoWindowsUpgrader) updateWindowsNodes() {
 	for _, nodeName := range sortedSliceFromMap(pending) {
 		node := pending[nodeName]
 
-		// For upgrades from Calico -> Enterprise, we always upgrade regardless
-		// of maxUnavailable. For other upgrades, check that we have room
-		// available.
-		if w.isUpgradeFromCalicoToEnterprise(node) || len(inProgress) < maxUnavailable {
-			if err := w.startUpgrade(context.Background(), node); err != nil {
-				// Log the error and continue. We will retry when we update nodes again.
-				windowsLog.Info(fmt.Sprintf("Could not start upgrade on node %v: %v", node.Name, err))
-				continue
-			}
-			// Successfully started the upgrade. Moving the node
-			// from pending to in-progress.
-			inProgress[node.Name] = node
-			delete(pending, node.Name)
+		// Failed nodes remain tainted so they count against maxUnavailable
+		// along with the nodes that are upgrading.
+		if len(inProgress)+len(failed) >= maxUnavailable {
+			break
+		}
+
+		if isUpgradePaused(node) {
+			windowsLog.V(1).Info(fmt.Sprintf("Upgrade of node %v is paused", node.Name))
+			continue
+		}
+
+		if err := w.startUpgrade(context.Background(), node); err != nil {
+			// Log the error and continue. We will retry when we update nodes again.
+			windowsLog.Info(fmt.Sprintf("Could not start upgrade on node %v: %v", node.Name, err))
+			continue
 		}
+		// Successfully started the upgrade. Moving the node
+		// from pending to in-progress.
+		inProgress[node.Name] = node
+		delete(pending, node.Name)
 	}
 
-	// Notify status manager of upgrades status.
-	w.isDegraded = false
-	w.statusManager.SetWindowsUpgradeStatus(sortedSliceFromMap(pending), sortedSliceFromMap(inProgress), sortedSliceFromMap(inSync), nil)
+	// Notify status manager of upgrades status. Nodes that failed to upgrade
+	// degrade the status until they are upgraded or the failed label is
+	// removed from them.
+	if len(failed) > 0 {
+		w.isDegraded = true
+		err = fmt.Errorf("Calico for Windows upgrade failed on nodes: %v", strings.Join(sortedSliceFromMap(failed), ", "))
+	} else {
+		w.isDegraded = false
+		err = nil
+	}
+	w.statusManager.SetWindowsUpgradeStatus(sortedSliceFromMap(pending), sortedSliceFromMap(inProgress), sortedSliceFromMap(inSync), err)
 }
 
+// startUpgrade starts the upgrade of the node. If cordon and drain is enabled,
+// the node is cordoned and drained before it is patched to start the upgrade.
 func (w *calicoWindowsUpgrader) startUpgrade(ctx context.Context, node *corev1.Node) error {
+	if w.cordonAndDrainEnabled() {
+		windowsLog.Info(fmt.Sprintf("Cordoning node %v before upgrading Calico Windows", node.Name))
+		if err := patchNodeToStartDrain(ctx, w.clientset, node.Name); err != nil {
+			return fmt.Errorf("Unable to patch node %v to start drain: %w", node.Name, err)
+		}
+		return w.drainAndStartUpgrade(ctx, node)
+	}
+
 	windowsLog.Info(fmt.Sprintf("Starting Calico Windows upgrade on node %v", node.Name))
 	if err := patchNodeToStartUpgrade(ctx, w.clientset, node.Name); err != nil {
 		return fmt.Errorf("Unable to patch node %v to start upgrade: %w", node.Name, err)
@@ -262,6 +378,44 @@ func (w *calicoWindowsUpgrader) startUpgrade(ctx context.Context, node *corev1.N
 	return nil
 }
 
+// drainAndStartUpgrade evicts the pods on a cordoned node and patches the node
+// to start the upgrade once the node has been drained.
+func (w *calicoWindowsUpgrader) drainAndStartUpgrade(ctx context.Context, node *corev1.Node) error {
+	drained, err := drainNode(ctx, w.clientset, node.Name)
+	if err != nil {
+		return err
+	}
+	if !drained {
+		windowsLog.V(1).Info(fmt.Sprintf("Waiting for node %v to be drained", node.Name))
+		return nil
+	}
+
+	windowsLog.Info(fmt.Sprintf("Starting Calico Windows upgrade on drained node %v", node.Name))
+	if err := patchNodeToStartUpgrade(ctx, w.clientset, node.Name); err != nil {
+		return fmt.Errorf("Unable to patch node %v to start upgrade: %w", node.Name, err)
+	}
+	return nil
+}
+
+// resetUpgradeStart removes the upgrade start time of a paused node and sets it
+// again once the node is resumed, so that the time the node was paused does not
+// count against the node upgrade timeout.
+func (w *calicoWindowsUpgrader) resetUpgradeStart(ctx context.Context, node *corev1.Node) error {
+	_, started := node.Annotations[common.CalicoWindowsUpgradeStartedAnnotation]
+	if isUpgradePaused(node) && started {
+		windowsLog.V(1).Info(fmt.Sprintf("Upgrade of node %v is paused, removing its start time", node.Name))
+		return patchNode(ctx, w.clientset, node.Name, objPatch{
+			Op:   "remove",
+			Path: fmt.Sprintf("/metadata/annotations/%s", escapeJSONPointer(common.CalicoWindowsUpgradeStartedAnnotation)),
+		})
+	}
+	if !isUpgradePaused(node) && !started {
+		windowsLog.V(1).Info(fmt.Sprintf("Upgrade of node %v is resumed, setting its start time", node.Name))
+		return patchNode(ctx, w.clientset, node.Name, startedAnnotationPatch())
+	}
+	return nil
+}
+
 func (w *calicoWindowsUpgrader) finishUpgrade(ctx context.Context, node *corev1.Node) error {
 	windowsLog.Info(fmt.Sprintf("Completing upgrade on upgraded node %v", node.Name))
 	if err := patchNodeToCompleteUpgrade(ctx, w.clientset, node.Name); err != nil {
@@ -271,6 +425,15 @@ func (w *calicoWindowsUpgrader) finishUpgrade(ctx context.Context, node *corev1.
 	return nil
 }
 
+func (w *calicoWindowsUpgrader) failUpgrade(ctx context.Context, node *corev1.Node) error {
+	windowsLog.Info(fmt.Sprintf("Upgrade of node %v did not complete within %v, marking it as failed", node.Name, w.getNodeUpgradeTimeout()))
+	if err := patchNodeToFailUpgrade(ctx, w.clientset, node); err != nil {
+		return fmt.Errorf("Unable to patch node %v to fail upgrade: %w", node.Name, err)
+	}
+
+	return nil
+}
+
 // Start begins running the calicoWindowsUpgrader.
 func (w *calicoWindowsUpgrader) Start(ctx context.Context) {
 	go func() {
@@ -308,7 +471,8 @@ func (w *calicoWindowsUpgrader) IsDegraded() bool {
 }
 
 // patchNodeToStartUpgrade patches a Windows node to prepare it for the calico
-// windows upgrade. It applies a NoSchedule taint and adds the upgrade label.
+// windows upgrade. It applies a NoSchedule taint, adds the upgrade label and
+// records the time the upgrade started.
 func patchNodeToStartUpgrade(ctx context.Context, client kubernetes.Interface, nodeName string) error {
 	return wait.PollImmediate(1*time.Second, 1*time.Minute, func() (bool, error) {
 		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
@@ -360,17 +524,19 @@ func patchNodeToStartUpgrade(ctx context.Context, client kubernetes.Interface, n
 
 		if !upgradeLabelExists {
 			windowsLog.V(1).Info(fmt.Sprintf("Upgrade label missing for node %v", nodeName))
-			// With JSONPatch '/' must be escaped as '~1' http://jsonpatch.com/
-			labelKey := strings.Replace(common.CalicoWindowsUpgradeLabel, "/", "~1", -1)
-
 			p := objPatch{
 				Op:    "add",
-				Path:  fmt.Sprintf("/metadata/labels/%s", labelKey),
+				Path:  fmt.Sprintf("/metadata/labels/%s", escapeJSONPointer(common.CalicoWindowsUpgradeLabel)),
 				Value: common.CalicoWindowsUpgradeLabelInProgress,
 			}
 			patches = append(patches, p)
 		}
 
+		// Keep the start time of nodes that were drained before upgrading.
+		if _, ok := node.Annotations[common.CalicoWindowsUpgradeStartedAnnotation]; !ok {
+			patches = append(patches, startedAnnotationPatch())
+		}
+
 		// If either the taint or label do not exist, patch the node to add them.
 		if len(patches) > 0 {
 			windowsLog.V(1).Info(fmt.Sprintf("Patching node %v to add upgrade taint and/or label", nodeName))
@@ -393,9 +559,61 @@ func patchNodeToStartUpgrade(ctx context.Context, client kubernetes.Interface, n
 	})
 }
 
-// patchNodeToCompleteUpgrade patches a Windows node to remove the taint and
-// upgrade label added before upgrading the node. The taint and label should be
-// removed when the node has finished upgrading.
+// patchNodeToStartDrain patches a Windows node to cordon it and adds the
+// upgrade draining label. The node is only marked as cordoned by the upgrade
+// if it was schedulable, so that nodes cordoned by the user are not
+// uncordoned when the upgrade completes.
+func patchNodeToStartDrain(ctx context.Context, client kubernetes.Interface, nodeName string) error {
+	return wait.PollImmediate(1*time.Second, 1*time.Minute, func() (bool, error) {
+		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		patches := []objPatch{
+			{
+				Op:    "add",
+				Path:  fmt.Sprintf("/metadata/labels/%s", escapeJSONPointer(common.CalicoWindowsUpgradeLabel)),
+				Value: common.CalicoWindowsUpgradeLabelDraining,
+			},
+			startedAnnotationPatch(),
+		}
+
+		if !node.Spec.Unschedulable {
+			p := []objPatch{
+				{
+					Op:    "add",
+					Path:  "/spec/unschedulable",
+					Value: true,
+				},
+				{
+					Op:    "add",
+					Path:  fmt.Sprintf("/metadata/annotations/%s", escapeJSONPointer(common.CalicoWindowsUpgradeCordonedAnnotation)),
+					Value: "true",
+				},
+			}
+			patches = append(patches, p...)
+		}
+
+		windowsLog.V(1).Info(fmt.Sprintf("Patching node %v to cordon it and add the upgrade draining label", nodeName))
+		err = patchNode(ctx, client, nodeName, patches...)
+
+		if err == nil {
+			return true, nil
+		}
+		if !apierrors.IsConflict(err) {
+			return false, err
+		}
+
+		// Retry on update conflicts.
+		return false, nil
+	})
+}
+
+// patchNodeToCompleteUpgrade patches a Windows node to remove the taint, the
+// upgrade label and the upgrade start time added before upgrading the node. If
+// the node was cordoned by the upgrade, it is uncordoned. The taint and label
+// should be removed when the node has finished upgrading.
 func patchNodeToCompleteUpgrade(ctx context.Context, client kubernetes.Interface, nodeName string) error {
 	return wait.PollImmediate(1*time.Second, 1*time.Minute, func() (bool, error) {
 		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
@@ -416,8 +634,9 @@ func patchNodeToCompleteUpgrade(ctx context.Context, client kubernetes.Interface
 			}
 		}
 
-		// Check if the label exists.
-		if curr, ok := node.Labels[common.CalicoWindowsUpgradeLabel]; ok && curr == common.CalicoWindowsUpgradeLabelInProgress {
+		// Check if the label exists. The label may have any of the upgrade
+		// label values.
+		if _, ok := node.Labels[common.CalicoWindowsUpgradeLabel]; ok {
 			upgradeLabelExists = true
 		}
 
@@ -444,17 +663,39 @@ func patchNodeToCompleteUpgrade(ctx context.Context, client kubernetes.Interface
 
 		if upgradeLabelExists {
 			windowsLog.V(1).Info(fmt.Sprintf("Upgrade label exists for node %v", nodeName))
-			// With JSONPatch '/' must be escaped as '~1' http://jsonpatch.com/
-			labelKey := strings.Replace(common.CalicoWindowsUpgradeLabel, "/", "~1", -1)
-
 			p := objPatch{
 				// Remove the upgrade label.
 				Op:   "remove",
-				Path: fmt.Sprintf("/metadata/labels/%s", labelKey),
+				Path: fmt.Sprintf("/metadata/labels/%s", escapeJSONPointer(common.CalicoWindowsUpgradeLabel)),
+			}
+			patches = append(patches, p)
+		}
+
+		if _, ok := node.Annotations[common.CalicoWindowsUpgradeStartedAnnotation]; ok {
+			p := objPatch{
+				Op:   "remove",
+				Path: fmt.Sprintf("/metadata/annotations/%s", escapeJSONPointer(common.CalicoWindowsUpgradeStartedAnnotation)),
 			}
 			patches = append(patches, p)
 		}
 
+		if _, ok := node.Annotations[common.CalicoWindowsUpgradeCordonedAnnotation]; ok {
+			windowsLog.V(1).Info(fmt.Sprintf("Node %v was cordoned for the upgrade", nodeName))
+			p := []objPatch{
+				// Uncordon the node.
+				{
+					Op:    "add",
+					Path:  "/spec/unschedulable",
+					Value: false,
+				},
+				{
+					Op:   "remove",
+					Path: fmt.Sprintf("/metadata/annotations/%s", escapeJSONPointer(common.CalicoWindowsUpgradeCordonedAnnotation)),
+				},
+			}
+			patches = append(patches, p...)
+		}
+
 		// If either the taint or label exist, patch the node to remove them.
 		if len(patches) > 0 {
 			windowsLog.V(1).Info(fmt.Sprintf("Patching node %v to remove upgrade taint and/or label", nodeName))
@@ -477,6 +718,98 @@ func patchNodeToCompleteUpgrade(ctx context.Context, client kubernetes.Interface
 	})
 }
 
+// patchNodeToFailUpgrade patches a Windows node to set the upgrade label to
+// failed and remove the upgrade start time so that the node is not timed out
+// again.
+func patchNodeToFailUpgrade(ctx context.Context, client kubernetes.Interface, node *corev1.Node) error {
+	patches := []objPatch{
+		{
+			Op:    "add",
+			Path:  fmt.Sprintf("/metadata/labels/%s", escapeJSONPointer(common.CalicoWindowsUpgradeLabel)),
+			Value: common.CalicoWindowsUpgradeLabelFailed,
+		},
+	}
+	if _, ok := node.Annotations[common.CalicoWindowsUpgradeStartedAnnotation]; ok {
+		patches = append(patches, objPatch{
+			Op:   "remove",
+			Path: fmt.Sprintf("/metadata/annotations/%s", escapeJSONPointer(common.CalicoWindowsUpgradeStartedAnnotation)),
+		})
+	}
+	return patchNode(ctx, client, node.Name, patches...)
+}
+
+// drainNode evicts the pods running on a node, excluding DaemonSet and mirror
+// pods. It returns whether the node has been drained of all such pods.
+// Evictions that are refused due to a PodDisruptionBudget are retried the next
+// time the node is drained.
+func drainNode(ctx context.Context, client kubernetes.Interface, nodeName string) (bool, error) {
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	drained := true
+	for _, pod := range pods.Items {
+		if !podNeedsEviction(&pod) {
+			continue
+		}
+		drained = false
+
+		// The pod is already terminating.
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		windowsLog.V(1).Info(fmt.Sprintf("Evicting pod %s/%s from node %v", pod.Namespace, pod.Name, nodeName))
+		eviction := &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		}
+		if err := client.CoreV1().Pods(pod.Namespace).Evict(ctx, eviction); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if apierrors.IsTooManyRequests(err) {
+				windowsLog.Info(fmt.Sprintf("Eviction of pod %s/%s is blocked by a PodDisruptionBudget", pod.Namespace, pod.Name))
+				continue
+			}
+			return false, fmt.Errorf("Unable to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	return drained, nil
+}
+
+// podNeedsEviction returns whether the pod must be evicted to drain its node.
+// Completed pods, mirror pods and pods owned by a DaemonSet are left alone.
+func podNeedsEviction(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+	if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
+
+func startedAnnotationPatch() objPatch {
+	return objPatch{
+		Op:    "add",
+		Path:  fmt.Sprintf("/metadata/annotations/%s", escapeJSONPointer(common.CalicoWindowsUpgradeStartedAnnotation)),
+		Value: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// escapeJSONPointer escapes a key for use in a JSONPatch path. With JSONPatch
+// '/' must be escaped as '~1' http://jsonpatch.com/
+func escapeJSONPointer(key string) string {
+	return strings.Replace(key, "/", "~1", -1)
+}
+
 type objPatch struct {
 	Op    string      `json:"op"`
 	Path  string      `json:"path"`
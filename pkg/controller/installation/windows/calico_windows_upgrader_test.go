@@ -31,13 +31,16 @@ import (
 	test "github.com/tigera/operator/test"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	schedv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	kfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -261,6 +264,9 @@ var _ = Describe("Calico windows upgrader", func() {
 					*common.CalicoWindowsUpgradingTaint,
 				},
 			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
 		}
 		n1, err := cs.CoreV1().Nodes().Create(context.Background(), n1, metav1.CreateOptions{})
 		Expect(err).To(BeNil())
@@ -471,9 +477,7 @@ var _ = Describe("Calico windows upgrader", func() {
 			Consistently(count, 5*time.Second).Should(Equal(2))
 		})
 
-		It("should upgrade nodes going from Calico to Enterprise right away, even if max available slots is 0", func() {
-			Skip("This test does not yet work since upgrades to Enterprise are not supported yet")
-
+		It("should count already upgrading nodes against maxUnavailable, for Calico to Enterprise upgrades", func() {
 			_ = test.CreateWindowsNode(cs, "node1", operator.Calico, "v3.21.999")
 			_ = test.CreateWindowsNode(cs, "node2", operator.Calico, "v3.21.999")
 			_ = test.CreateWindowsNode(cs, "node3", operator.Calico, "v3.21.999")
@@ -487,12 +491,222 @@ var _ = Describe("Calico windows upgrader", func() {
 			count := func() int {
 				return countNodesUpgrading(nodeIndexInformer)
 			}
-			// All of the pending nodes should still be upgraded despite max unavailability
-			// value.
-			Eventually(count, 5*time.Second).Should(Equal(3))
-			Consistently(count, 5*time.Second).Should(Equal(3))
+			Eventually(count, 5*time.Second).Should(Equal(1))
+			Consistently(count, 5*time.Second).Should(Equal(1))
+
+			mu := intstr.FromInt(2)
+			cr.NodeUpdateStrategy.RollingUpdate.MaxUnavailable = &mu
+			c.UpdateConfig(cr)
+
+			Eventually(count, 5*time.Second).Should(Equal(2))
+			Consistently(count, 5*time.Second).Should(Equal(2))
+		})
+	})
+
+	It("should not upgrade nodes with the paused annotation", func() {
+		n1 := test.CreateNode(cs, "node1", map[string]string{"kubernetes.io/os": "windows"},
+			map[string]string{
+				common.CalicoVersionAnnotation:              "v3.21.999",
+				common.CalicoVariantAnnotation:              string(operator.Calico),
+				common.CalicoWindowsUpgradePausedAnnotation: "true",
+			})
+		n2 := test.CreateWindowsNode(cs, "node2", operator.Calico, "v3.21.999")
+		mockStatus.On("SetWindowsUpgradeStatus", []string{"node1"}, []string{"node2"}, []string{}, nil)
+
+		c.Start(ctx)
+		cr.Variant = operator.TigeraSecureEnterprise
+		c.UpdateConfig(cr)
+
+		// node1 is first in line to be upgraded but node2 is upgraded instead.
+		Eventually(func() error {
+			return test.AssertNodesHadUpgradeTriggered(cs, n2)
+		}, 5*time.Second).Should(BeNil())
+		Consistently(func() error {
+			return test.AssertNodesUnchanged(cs, n1)
+		}, 5*time.Second, 100*time.Millisecond).Should(BeNil())
+
+		waitForSetWindowsUpgradeStatusCalled(mockStatus, []string{"node1"}, []string{"node2"}, []string{}, nil)
+		mockStatus.AssertExpectations(GinkgoT())
+	})
+
+	It("should wait for upgraded nodes to be ready before completing the upgrade", func() {
+		n1 := test.CreateWindowsNode(cs, "node1", operator.Calico, "v3.21.999")
+		mockStatus.On("SetWindowsUpgradeStatus", []string{}, []string{"node1"}, []string{}, nil)
+
+		c.Start(ctx)
+		cr.Variant = operator.TigeraSecureEnterprise
+		c.UpdateConfig(cr)
+
+		Eventually(func() error {
+			return test.AssertNodesHadUpgradeTriggered(cs, n1)
+		}, 5*time.Second).Should(BeNil())
+
+		// The node service restarts Calico and the node is not ready yet.
+		setNodeReady(cs, n1, corev1.ConditionFalse)
+		setNodeVariantAndVersion(cs, nodeIndexInformer, n1, operator.TigeraSecureEnterprise, components.ComponentTigeraWindows.Version)
+
+		Consistently(func() error {
+			return test.AssertNodesHadUpgradeTriggered(cs, n1)
+		}, 5*time.Second, 100*time.Millisecond).Should(BeNil())
+
+		mockStatus.On("SetWindowsUpgradeStatus", []string{}, []string{}, []string{"node1"}, nil)
+		setNodeReady(cs, n1, corev1.ConditionTrue)
+
+		Eventually(func() error {
+			return assertNodesFinishedUpgrade(cs, n1)
+		}, 5*time.Second).Should(BeNil())
+
+		waitForSetWindowsUpgradeStatusCalled(mockStatus, []string{}, []string{}, []string{"node1"}, nil)
+		mockStatus.AssertExpectations(GinkgoT())
+	})
 
+	It("should mark nodes that do not complete their upgrade within the timeout as failed", func() {
+		_ = test.CreateWindowsNode(cs, "node1", operator.Calico, "v3.21.999")
+		_ = test.CreateWindowsNode(cs, "node2", operator.Calico, "v3.21.999")
+		mockStatus.On("SetWindowsUpgradeStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		c.Start(ctx)
+		cr.Variant = operator.TigeraSecureEnterprise
+		cr.CalicoWindowsUpgrade = &operator.CalicoWindowsUpgradeSpec{
+			NodeUpgradeTimeout: &metav1.Duration{Duration: 2 * time.Second},
+		}
+		c.UpdateConfig(cr)
+
+		Eventually(func() string {
+			n, err := cs.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			return n.Labels[common.CalicoWindowsUpgradeLabel]
+		}, 10*time.Second).Should(Equal(common.CalicoWindowsUpgradeLabelFailed))
+		n1, err := cs.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(n1.Annotations).NotTo(HaveKey(common.CalicoWindowsUpgradeStartedAnnotation))
+
+		Eventually(c.IsDegraded, 5*time.Second).Should(BeTrue())
+		waitForSetWindowsUpgradeStatusCalled(mockStatus, []string{"node2"}, []string{}, []string{},
+			fmt.Errorf("Calico for Windows upgrade failed on nodes: node1"))
+
+		// The failed node still counts against maxUnavailable so node2 is not
+		// upgraded.
+		Consistently(func() int {
+			return countNodesUpgrading(nodeIndexInformer)
+		}, 5*time.Second).Should(Equal(1))
+		n2, err := cs.CoreV1().Nodes().Get(ctx, "node2", metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(n2.Labels).NotTo(HaveKey(common.CalicoWindowsUpgradeLabel))
+	})
+
+	It("should reset the upgrade start time of nodes that are paused and resumed", func() {
+		_ = test.CreateNode(cs, "node1",
+			map[string]string{
+				"kubernetes.io/os":               "windows",
+				common.CalicoWindowsUpgradeLabel: common.CalicoWindowsUpgradeLabelInProgress,
+			},
+			map[string]string{
+				common.CalicoVersionAnnotation:               "v3.21.999",
+				common.CalicoVariantAnnotation:               string(operator.Calico),
+				common.CalicoWindowsUpgradeStartedAnnotation: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+				common.CalicoWindowsUpgradePausedAnnotation:  "true",
+			})
+		mockStatus.On("SetWindowsUpgradeStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		c.Start(ctx)
+		cr.Variant = operator.TigeraSecureEnterprise
+		cr.CalicoWindowsUpgrade = &operator.CalicoWindowsUpgradeSpec{
+			NodeUpgradeTimeout: &metav1.Duration{Duration: 30 * time.Second},
+		}
+		c.UpdateConfig(cr)
+
+		getNode := func() *corev1.Node {
+			n, err := cs.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			return n
+		}
+
+		By("removing the start time of the paused node")
+		Eventually(func() map[string]string {
+			return getNode().Annotations
+		}, 5*time.Second).ShouldNot(HaveKey(common.CalicoWindowsUpgradeStartedAnnotation))
+		Expect(getNode().Labels).To(HaveKeyWithValue(common.CalicoWindowsUpgradeLabel, common.CalicoWindowsUpgradeLabelInProgress))
+
+		By("setting the start time again once the node is resumed")
+		n1 := getNode()
+		delete(n1.Annotations, common.CalicoWindowsUpgradePausedAnnotation)
+		_, err := cs.CoreV1().Nodes().Update(ctx, n1, metav1.UpdateOptions{})
+		Expect(err).To(BeNil())
+
+		Eventually(func() map[string]string {
+			return getNode().Annotations
+		}, 5*time.Second).Should(HaveKey(common.CalicoWindowsUpgradeStartedAnnotation))
+		started, err := time.Parse(time.RFC3339, getNode().Annotations[common.CalicoWindowsUpgradeStartedAnnotation])
+		Expect(err).To(BeNil())
+		Expect(started).To(BeTemporally("~", time.Now(), 10*time.Second))
+		Consistently(func() map[string]string {
+			return getNode().Labels
+		}, 3*time.Second).Should(HaveKeyWithValue(common.CalicoWindowsUpgradeLabel, common.CalicoWindowsUpgradeLabelInProgress))
+	})
+
+	It("should cordon and drain nodes before upgrading them", func() {
+		n1 := test.CreateWindowsNode(cs, "node1", operator.Calico, "v3.21.999")
+		_, err := cs.CoreV1().Pods("default").Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "node1"},
+		}, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		// Evictions are refused until the test allows them.
+		allowEvictions := make(chan struct{})
+		cs.PrependReactor("create", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			select {
+			case <-allowEvictions:
+			default:
+				return true, nil, apierrors.NewTooManyRequests("disruption budget", 1)
+			}
+			eviction := action.(ktesting.CreateAction).GetObject().(*policyv1beta1.Eviction)
+			return true, nil, cs.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
 		})
+		mockStatus.On("SetWindowsUpgradeStatus", mock.Anything, mock.Anything, mock.Anything, nil)
+
+		c.Start(ctx)
+		cr.Variant = operator.TigeraSecureEnterprise
+		cordonAndDrain := operator.CordonAndDrainEnabled
+		cr.CalicoWindowsUpgrade = &operator.CalicoWindowsUpgradeSpec{CordonAndDrain: &cordonAndDrain}
+		c.UpdateConfig(cr)
+
+		getNode := func() *corev1.Node {
+			n, err := cs.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			return n
+		}
+
+		// The node is cordoned but its upgrade does not start while the pod
+		// cannot be evicted.
+		Eventually(func() bool {
+			return getNode().Spec.Unschedulable
+		}, 5*time.Second).Should(BeTrue())
+		Consistently(func() string {
+			return getNode().Labels[common.CalicoWindowsUpgradeLabel]
+		}, 5*time.Second).Should(Equal(common.CalicoWindowsUpgradeLabelDraining))
+
+		close(allowEvictions)
+
+		Eventually(func() string {
+			return getNode().Labels[common.CalicoWindowsUpgradeLabel]
+		}, 5*time.Second).Should(Equal(common.CalicoWindowsUpgradeLabelInProgress))
+		_, err = cs.CoreV1().Pods("default").Get(ctx, "pod1", metav1.GetOptions{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		setNodeVariantAndVersion(cs, nodeIndexInformer, n1, operator.TigeraSecureEnterprise, components.ComponentTigeraWindows.Version)
+
+		Eventually(func() error {
+			return assertNodesFinishedUpgrade(cs, n1)
+		}, 5*time.Second).Should(BeNil())
+		n := getNode()
+		Expect(n.Spec.Unschedulable).To(BeFalse())
+		Expect(n.Annotations).NotTo(HaveKey(common.CalicoWindowsUpgradeCordonedAnnotation))
+		Expect(n.Annotations).NotTo(HaveKey(common.CalicoWindowsUpgradeStartedAnnotation))
 	})
 })
 
@@ -548,6 +762,15 @@ func waitForSetWindowsUpgradeStatusCalled(mockStatus *status.MockStatus, argumen
 	}, 5*time.Second).Should(BeTrue())
 }
 
+func setNodeReady(c kubernetes.Interface, node *corev1.Node, status corev1.ConditionStatus) {
+	n, err := c.CoreV1().Nodes().Get(context.Background(), node.Name, metav1.GetOptions{})
+	Expect(err).To(BeNil())
+
+	n.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}
+	_, err = c.CoreV1().Nodes().Update(context.Background(), n, metav1.UpdateOptions{})
+	Expect(err).To(BeNil())
+}
+
 func setNodeVariantAndVersion(c kubernetes.Interface, indexInformer cache.SharedIndexInformer, node *corev1.Node, variant operator.ProductVariant, version string) {
 	// Get the existing node.
 	n, err := c.CoreV1().Nodes().Get(context.Background(), node.Name, metav1.GetOptions{})
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operator "github.com/tigera/operator/api/v1"
+)
+
+// Felix publishes the WireGuard public key of a node in these annotations once it has set up WireGuard on the node,
+// which requires the kernel of the node to support WireGuard.
+const (
+	wireGuardPublicKeyAnnotation   = "projectcalico.org/WireguardPublicKey"
+	wireGuardPublicKeyV6Annotation = "projectcalico.org/WireguardPublicKeyV6"
+)
+
+// maxWireGuardNotReadyNodes bounds the nodes listed in the WireGuard status, which is reported on the TigeraStatus.
+const maxWireGuardNotReadyNodes = 20
+
+// addWireGuardNodeWatch watches the nodes being added and removed and Felix publishing their WireGuard public keys,
+// which change the WireGuard status. All the node events are mapped to the same request, so that they are coalesced.
+func addWireGuardNodeWatch(c controller.Controller) error {
+	return c.Watch(&source.Kind{Type: &corev1.Node{}},
+		handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "default"}}}
+		}),
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldAnnotations, newAnnotations := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+				return oldAnnotations[wireGuardPublicKeyAnnotation] != newAnnotations[wireGuardPublicKeyAnnotation] ||
+					oldAnnotations[wireGuardPublicKeyV6Annotation] != newAnnotations[wireGuardPublicKeyV6Annotation]
+			},
+			GenericFunc: func(event.GenericEvent) bool {
+				return false
+			},
+		},
+	)
+}
+
+// wireGuardStatus returns the readiness of WireGuard encryption on the Linux nodes, or nil if WireGuard encryption
+// is not enabled. WireGuard encryption is ready on a node once Felix has published its public key for each enabled
+// address family. At most maxWireGuardNotReadyNodes of the nodes it is not ready on are listed.
+func (r *ReconcileInstallation) wireGuardStatus(ctx context.Context, install *operator.Installation) (*operator.TigeraStatusWireGuard, error) {
+	var annotations []string
+	if wireGuardEnabled(install, 4) {
+		annotations = append(annotations, wireGuardPublicKeyAnnotation)
+	}
+	if wireGuardEnabled(install, 6) {
+		annotations = append(annotations, wireGuardPublicKeyV6Annotation)
+	}
+	if len(annotations) == 0 {
+		return nil, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	status := &operator.TigeraStatusWireGuard{}
+	for _, node := range nodes.Items {
+		if node.Labels[corev1.LabelOSStable] == "windows" {
+			// Calico for Windows does not support WireGuard.
+			continue
+		}
+		status.Desired++
+		ready := true
+		for _, a := range annotations {
+			if node.Annotations[a] == "" {
+				ready = false
+			}
+		}
+		if ready {
+			status.Ready++
+		} else {
+			status.NotReadyNodes = append(status.NotReadyNodes, node.Name)
+		}
+	}
+	sort.Strings(status.NotReadyNodes)
+	if len(status.NotReadyNodes) > maxWireGuardNotReadyNodes {
+		status.NotReadyNodes = status.NotReadyNodes[:maxWireGuardNotReadyNodes]
+	}
+	return status, nil
+}
+
+// wireGuardEnabled returns true if spec.calicoNetwork.wireGuard enables WireGuard encryption for the given IP version.
+func wireGuardEnabled(install *operator.Installation, version int) bool {
+	cn := install.Spec.CalicoNetwork
+	if cn == nil || cn.WireGuard == nil {
+		return false
+	}
+	state := cn.WireGuard.IPv4
+	if version == 6 {
+		state = cn.WireGuard.IPv6
+	}
+	return state != nil && *state == operator.WireGuardEnabled
+}
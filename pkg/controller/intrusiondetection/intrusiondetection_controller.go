@@ -93,6 +93,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		provider:        opts.DetectedProvider,
 		status:          status.New(mgr.GetClient(), "intrusion-detection", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
+		usePSP:          opts.UsePSP,
 		licenseAPIReady: licenseAPIReady,
 		dpiAPIReady:     dpiAPIReady,
 	}
@@ -188,6 +189,7 @@ type ReconcileIntrusionDetection struct {
 	provider        operatorv1.Provider
 	status          status.StatusManager
 	clusterDomain   string
+	usePSP          bool
 	licenseAPIReady *utils.ReadyFlag
 	dpiAPIReady     *utils.ReadyFlag
 }
@@ -218,7 +220,7 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
 	if err := r.setDefaultsOnIntrusionDetection(ctx, instance); err != nil {
@@ -257,6 +259,7 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	// Query for pull secrets in operator namespace
 	pullSecrets, err := utils.GetNetworkingPullSecrets(network, r.client)
@@ -373,10 +376,11 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		ManagedCluster:           managementClusterConnection != nil,
 		HasNoLicense:             hasNoLicense,
 		ManagerInternalTLSSecret: managerInternalTLSSecret,
+		UsePSP:                   r.usePSP,
 	}
 	component := render.IntrusionDetection(intrusionDetectionCfg)
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, network, component); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
@@ -387,6 +391,17 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
+	tier, err := utils.AllowTigeraTier(ctx, r.client, network, component)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if err := handler.CreateOrUpdateOrDelete(ctx, render.NetworkPolicies(network, tier, component), r.status); err != nil {
+		r.status.SetDegraded("Error creating / updating resource", err.Error())
+		return reconcile.Result{}, err
+	}
+
 	var typhaTLSSecret, nodeTLSSecret *corev1.Secret
 	typhaCAConfigMap := &corev1.ConfigMap{}
 	dpiList := &v3.DeepPacketInspectionList{}
@@ -443,7 +458,7 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		ClusterDomain:      r.clusterDomain,
 	})
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, dpiComponent); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, network, dpiComponent); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
@@ -460,6 +475,9 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, nil
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(instance.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
@@ -474,6 +492,9 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 	if err = r.client.Status().Update(ctx, instance); err != nil {
 		return reconcile.Result{}, err
 	}
+	if result, requeue := utils.AllowTigeraTierRequeue(network, tier); requeue {
+		return result, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	operator "github.com/tigera/operator/api/v1"
 	v1 "k8s.io/api/core/v1"
@@ -25,18 +26,48 @@ import (
 
 const dockerEEProxyLocal = "proxy.local"
 
-// Endpoint is the default ServiceEndpoint learned from environment variables.
-var Endpoint ServiceEndpoint
+var (
+	endpointLock sync.RWMutex
+	// endpoint overrides the DefaultEndpoint, when set.
+	endpoint *ServiceEndpoint
+)
 
-func init() {
+// DefaultEndpoint returns the ServiceEndpoint learned from environment variables. They are read on each call, since
+// the operator sets them from its kubeconfig once it has started.
+func DefaultEndpoint() ServiceEndpoint {
 	// We read whatever is in the variable. We would read "" if they were not set.
 	// We decide at the point of usage what to do with the values.
-	Endpoint = ServiceEndpoint{
+	return ServiceEndpoint{
 		Host: os.Getenv("KUBERNETES_SERVICE_HOST"),
 		Port: os.Getenv("KUBERNETES_SERVICE_PORT"),
 	}
 }
 
+// Endpoint returns the ServiceEndpoint that components are rendered with. It is the DefaultEndpoint unless it
+// is overridden by the kubernetes-services-endpoint ConfigMap.
+func Endpoint() ServiceEndpoint {
+	endpointLock.RLock()
+	defer endpointLock.RUnlock()
+	if endpoint != nil {
+		return *endpoint
+	}
+	return DefaultEndpoint()
+}
+
+// SetEndpoint overrides the DefaultEndpoint that components are rendered with.
+func SetEndpoint(ep ServiceEndpoint) {
+	endpointLock.Lock()
+	defer endpointLock.Unlock()
+	endpoint = &ep
+}
+
+// ResetEndpoint renders the components with the DefaultEndpoint again.
+func ResetEndpoint() {
+	endpointLock.Lock()
+	defer endpointLock.Unlock()
+	endpoint = nil
+}
+
 // ServiceEndpoint is the Host/Port of the K8s endpoint.
 type ServiceEndpoint struct {
 	Host string
@@ -91,6 +91,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		provider:        opts.DetectedProvider,
 		status:          status.New(mgr.GetClient(), "log-collector", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
+		usePSP:          opts.UsePSP,
 		licenseAPIReady: licenseAPIReady,
 	}
 	c.status.Run(opts.ShutdownContext)
@@ -157,6 +158,7 @@ type ReconcileLogCollector struct {
 	provider        operatorv1.Provider
 	status          status.StatusManager
 	clusterDomain   string
+	usePSP          bool
 	licenseAPIReady *utils.ReadyFlag
 }
 
@@ -238,7 +240,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 	reqLogger.V(2).Info("Loaded config", "config", instance)
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 
 	if !utils.IsAPIServerReady(r.client, reqLogger) {
@@ -285,6 +287,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	esClusterConfig, err := utils.GetElasticsearchClusterConfig(ctx, r.client)
 	if err != nil {
@@ -494,6 +497,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		OSType:          rmeta.OSTypeLinux,
 		TLS:             fluentdPrometheusTLS,
 		TrustedBundle:   trustedBundle,
+		UsePSP:          r.usePSP,
 	}
 	// Render the fluentd component for Linux
 	component := render.Fluentd(fluentdCfg)
@@ -509,12 +513,19 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, installation, component); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
 	}
 
+	tier, err := utils.AllowTigeraTier(ctx, r.client, installation, component)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, err
+	}
+	components = append(components, render.NetworkPolicies(installation, tier, component))
+
 	for _, comp := range components {
 		if err := handler.CreateOrUpdateOrDelete(ctx, comp, r.status); err != nil {
 			r.status.SetDegraded("Error creating / updating resource", err.Error())
@@ -541,10 +552,11 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 			Installation:    installation,
 			ClusterDomain:   r.clusterDomain,
 			OSType:          rmeta.OSTypeWindows,
+			UsePSP:          r.usePSP,
 		}
 		component = render.Fluentd(fluentdCfg)
 
-		if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+		if err = imageset.ApplyImageSet(ctx, r.client, variant, installation, component); err != nil {
 			reqLogger.Error(err, "Error with images from ImageSet")
 			r.status.SetDegraded("Error with images from ImageSet", err.Error())
 			return reconcile.Result{}, err
@@ -553,15 +565,24 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		// Create a component handler to manage the rendered component.
 		handler = utils.NewComponentHandler(log, r.client, r.scheme, instance)
 
-		if err := handler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
-			r.status.SetDegraded("Error creating / updating resource", err.Error())
-			return reconcile.Result{}, err
+		for _, comp := range []render.Component{component, render.NetworkPolicies(installation, tier, component)} {
+			if err := handler.CreateOrUpdateOrDelete(ctx, comp, r.status); err != nil {
+				r.status.SetDegraded("Error creating / updating resource", err.Error())
+				return reconcile.Result{}, err
+			}
 		}
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(instance.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
+	if result, requeue := utils.AllowTigeraTierRequeue(installation, tier); requeue {
+		return result, nil
+	}
+
 	if !r.status.IsAvailable() {
 		// Schedule a kick to check again in the near future. Hopefully by then
 		// things will be available.
@@ -76,10 +76,12 @@ var _ = Describe("LogCollector controller tests", func() {
 			mockStatus.On("AddCronJobs", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything).Return()
 			mockStatus.On("IsAvailable").Return(true)
-			mockStatus.On("OnCRFound").Return()
+			mockStatus.On("OnCRFound", mock.Anything).Return()
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("SetDegraded", "Waiting for LicenseKeyAPI to be ready", "").Return().Maybe()
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetObservedGeneration", mock.Anything)
+			mockStatus.On("SetRenderedVariant", mock.Anything)
 
 			// Create an object we can use throughout the test to do the compliance reconcile loops.
 			// As the parameters in the client changes, we expect the outcomes of the reconcile loops to change.
@@ -83,11 +83,12 @@ func (r *ReconcileLogStorage) createEsGateway(
 		EsInternalCertSecret:       esInternalCertSecret,
 		ClusterDomain:              r.clusterDomain,
 		EsAdminUserName:            esAdminUserName,
+		UsePolicyV1PDB:             r.usePolicyV1PDB,
 	}
 
 	esGatewayComponent := esgateway.EsGateway(cfg)
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, esGatewayComponent); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, install, esGatewayComponent); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, false, err
@@ -107,5 +108,17 @@ func (r *ReconcileLogStorage) createEsGateway(
 		return reconcile.Result{}, false, err
 	}
 
+	tier, err := utils.AllowTigeraTier(ctx, r.client, install, esGatewayComponent)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, false, err
+	}
+
+	if err := hdler.CreateOrUpdateOrDelete(ctx, render.NetworkPolicies(install, tier, esGatewayComponent), r.status); err != nil {
+		reqLogger.Error(err, err.Error())
+		r.status.SetDegraded("Error creating / updating resource", err.Error())
+		return reconcile.Result{}, false, err
+	}
+
 	return reconcile.Result{}, true, nil
 }
@@ -62,6 +62,12 @@ func (r *ReconcileLogStorage) createEsKubeControllers(
 		return reconcile.Result{}, false, err
 	}
 
+	if err = utils.GetK8sServiceEndPoint(r.client); err != nil {
+		log.Error(err, "Error reading services endpoint configmap")
+		r.status.SetDegraded("Error reading services endpoint configmap", err.Error())
+		return reconcile.Result{}, false, err
+	}
+
 	enableESOIDCWorkaround := false
 	if (authentication != nil && authentication.Spec.OIDC != nil && authentication.Spec.OIDC.Type == operatorv1.OIDCTypeTigera) ||
 		esLicenseType == render.ElasticsearchLicenseTypeBasic {
@@ -91,7 +97,7 @@ func (r *ReconcileLogStorage) createEsKubeControllers(
 		}
 	}
 	kubeControllersCfg := kubecontrollers.KubeControllersConfiguration{
-		K8sServiceEp:                 k8sapi.Endpoint,
+		K8sServiceEp:                 k8sapi.Endpoint(),
 		Installation:                 install,
 		ManagementCluster:            managementCluster,
 		ClusterDomain:                r.clusterDomain,
@@ -102,6 +108,7 @@ func (r *ReconcileLogStorage) createEsKubeControllers(
 		KubeControllersGatewaySecret: kubeControllersUserSecret,
 		KibanaSecret:                 kubeControllerKibanaPublicCertSecret,
 		LogStorageExists:             true,
+		UsePSP:                       r.usePSP,
 	}
 	esKubeControllerComponents := kubecontrollers.NewElasticsearchKubeControllers(&kubeControllersCfg)
 
@@ -124,6 +131,12 @@ func (r *ReconcileLogStorage) createEsKubeControllers(
 		return reconcile.Result{}, false, err
 	}
 
+	if err = imageset.VerifyImages(ctx, r.client, install, imageSet, esKubeControllerComponents); err != nil {
+		reqLogger.Error(err, "Error verifying image signatures for elasticsearch kube-controllers components")
+		r.status.SetDegraded("Error verifying image signatures for elasticsearch kube-controllers components", err.Error())
+		return reconcile.Result{}, false, err
+	}
+
 	if err := hdler.CreateOrUpdateOrDelete(ctx, esKubeControllerComponents, nil); err != nil {
 		reqLogger.Error(err, "Error creating / updating  elasticsearch kube-controllers resource")
 		r.status.SetDegraded("Error creating / updating  elasticsearch kube-controllers resource", err.Error())
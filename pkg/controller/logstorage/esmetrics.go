@@ -119,7 +119,7 @@ func (r *ReconcileLogStorage) createEsMetrics(
 		}
 	}
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, esMetricsComponent); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, install, esMetricsComponent); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, false, err
@@ -148,17 +148,24 @@ func (r *ReconcileLogStorage) createLogStorage(
 		ClusterDomain:               r.clusterDomain,
 		DexCfg:                      dexCfg,
 		ElasticLicenseType:          esLicenseType,
+		UsePSP:                      r.usePSP,
 	}
 
 	component := render.LogStorage(logStorageCfg)
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, install, component); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, false, finalizerCleanup, err
 	}
 
-	components = append(components, component)
+	tier, err := utils.AllowTigeraTier(ctx, r.client, install, component)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, false, finalizerCleanup, err
+	}
+
+	components = append(components, component, render.NetworkPolicies(install, tier, component))
 
 	var passThroughSecrets []client.Object
 	if kbCertSecret != nil && kbOperatorManagedCertSecret {
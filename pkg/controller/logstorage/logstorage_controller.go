@@ -79,12 +79,14 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(cli client.Client, schema *runtime.Scheme, statusMgr status.StatusManager, opts options.AddOptions, esCliCreator utils.ElasticsearchClientCreator) (*ReconcileLogStorage, error) {
 	c := &ReconcileLogStorage{
-		client:        cli,
-		scheme:        schema,
-		status:        statusMgr,
-		provider:      opts.DetectedProvider,
-		esCliCreator:  esCliCreator,
-		clusterDomain: opts.ClusterDomain,
+		client:         cli,
+		scheme:         schema,
+		status:         statusMgr,
+		provider:       opts.DetectedProvider,
+		esCliCreator:   esCliCreator,
+		clusterDomain:  opts.ClusterDomain,
+		usePSP:         opts.UsePSP,
+		usePolicyV1PDB: opts.UsePolicyV1PDB,
 	}
 
 	c.status.Run(opts.ShutdownContext)
@@ -166,6 +168,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("log-storage-controller failed to watch the ConfigMap resource: %w", err)
 	}
 
+	if err = utils.AddConfigMapWatch(c, render.K8sSvcEndpointConfigMapName, common.OperatorNamespace()); err != nil {
+		return fmt.Errorf("log-storage-controller failed to watch the ConfigMap resource: %w", err)
+	}
+
 	if err := utils.AddServiceWatch(c, render.ElasticsearchServiceName, render.ElasticsearchNamespace); err != nil {
 		return fmt.Errorf("log-storage-controller failed to watch the Service resource: %w", err)
 	}
@@ -196,12 +202,14 @@ var _ reconcile.Reconciler = &ReconcileLogStorage{}
 type ReconcileLogStorage struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client        client.Client
-	scheme        *runtime.Scheme
-	status        status.StatusManager
-	provider      operatorv1.Provider
-	esCliCreator  utils.ElasticsearchClientCreator
-	clusterDomain string
+	client         client.Client
+	scheme         *runtime.Scheme
+	status         status.StatusManager
+	provider       operatorv1.Provider
+	esCliCreator   utils.ElasticsearchClientCreator
+	clusterDomain  string
+	usePSP         bool
+	usePolicyV1PDB bool
 }
 
 // fillDefaults populates the default values onto an LogStorage object.
@@ -308,7 +316,7 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 		ls = nil
 		r.status.OnCRNotFound()
 	} else {
-		r.status.OnCRFound()
+		r.status.OnCRFound(ls)
 
 		//create predefaultpatch
 		preDefaultPatchFrom = client.MergeFrom(ls.DeepCopy())
@@ -339,6 +347,7 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 		r.status.SetDegraded("An error occurred while querying Installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	managementCluster, err := utils.GetManagementCluster(ctx, r.client)
 	if err != nil {
@@ -450,6 +459,12 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
+	tier, err := utils.AllowTigeraTier(ctx, r.client, install)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, err
+	}
+
 	result, proceed, finalizerCleanup, err := r.createLogStorage(
 		ls,
 		install,
@@ -536,6 +551,11 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	if ls != nil {
+		r.status.SetObservedGeneration(ls.Generation)
+	}
+
 	r.status.ClearDegraded()
 
 	// Since we don't re poll for the object we need to make sure the object wouldn't have been deleted on the patch
@@ -550,6 +570,9 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	if result, requeue := utils.AllowTigeraTierRequeue(install, tier); requeue {
+		return result, nil
+	}
 	return reconcile.Result{}, nil
 }
 
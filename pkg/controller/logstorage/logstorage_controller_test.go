@@ -237,6 +237,8 @@ var _ = Describe("LogStorage controller", func() {
 						mockStatus.On("OnCRNotFound").Return()
 						mockStatus.On("ClearDegraded")
 						mockStatus.On("ReadyToMonitor")
+						mockStatus.On("SetObservedGeneration", mock.Anything)
+						mockStatus.On("SetRenderedVariant", mock.Anything)
 					})
 					DescribeTable("tests that the ExternalService is setup with the default service name", func(clusterDomain, expectedSvcName string) {
 						r, err := NewReconcilerWithShims(cli, scheme, mockStatus, operatorv1.ProviderNone, mockEsCliCreator, clusterDomain)
@@ -259,7 +261,8 @@ var _ = Describe("LogStorage controller", func() {
 				Context("LogStorage exists", func() {
 					BeforeEach(func() {
 						setUpLogStorageComponents(cli, ctx, storageClassName, nil)
-						mockStatus.On("OnCRFound").Return()
+						mockStatus.On("OnCRFound", mock.Anything).Return()
+						mockStatus.On("SetRenderedVariant", mock.Anything)
 					})
 
 					It("returns an error if the LogStorage resource exists and is not marked for deletion", func() {
@@ -280,6 +283,8 @@ var _ = Describe("LogStorage controller", func() {
 						mockStatus.On("AddCronJobs", mock.Anything)
 						mockStatus.On("ClearDegraded", mock.Anything).Return()
 						mockStatus.On("ReadyToMonitor")
+						mockStatus.On("SetObservedGeneration", mock.Anything)
+						mockStatus.On("SetRenderedVariant", mock.Anything)
 
 						r, err := NewReconcilerWithShims(cli, scheme, mockStatus, operatorv1.ProviderNone, mockEsCliCreator, dns.DefaultClusterDomain)
 						Expect(err).ShouldNot(HaveOccurred())
@@ -355,8 +360,9 @@ var _ = Describe("LogStorage controller", func() {
 					mockStatus.On("AddDeployments", mock.Anything)
 					mockStatus.On("AddStatefulSets", mock.Anything)
 					mockStatus.On("AddCronJobs", mock.Anything)
-					mockStatus.On("OnCRFound").Return()
+					mockStatus.On("OnCRFound", mock.Anything).Return()
 					mockStatus.On("ReadyToMonitor")
+					mockStatus.On("SetRenderedVariant", mock.Anything)
 				})
 				It("test LogStorage reconciles successfully", func() {
 					Expect(cli.Create(ctx, &storagev1.StorageClass{
@@ -455,6 +461,7 @@ var _ = Describe("LogStorage controller", func() {
 					Expect(cli.Create(ctx, &corev1.Secret{ObjectMeta: esMetricsUsrSecretObjMeta})).ShouldNot(HaveOccurred())
 
 					mockStatus.On("ClearDegraded")
+					mockStatus.On("SetObservedGeneration", mock.Anything)
 					result, err = r.Reconcile(ctx, reconcile.Request{})
 					Expect(err).ShouldNot(HaveOccurred())
 					Expect(result).Should(Equal(reconcile.Result{}))
@@ -573,6 +580,7 @@ var _ = Describe("LogStorage controller", func() {
 					Expect(cli.Create(ctx, &corev1.Secret{ObjectMeta: esMetricsUsrSecretObjMeta})).ShouldNot(HaveOccurred())
 
 					mockStatus.On("ClearDegraded")
+					mockStatus.On("SetObservedGeneration", mock.Anything)
 					result, err = r.Reconcile(ctx, reconcile.Request{})
 					Expect(err).ShouldNot(HaveOccurred())
 					Expect(result).Should(Equal(reconcile.Result{}))
@@ -1054,6 +1062,7 @@ var _ = Describe("LogStorage controller", func() {
 				Context("checking rendered images", func() {
 					BeforeEach(func() {
 						mockStatus.On("ClearDegraded", mock.Anything)
+						mockStatus.On("SetObservedGeneration", mock.Anything)
 						Expect(cli.Create(ctx, &operatorv1.LogStorage{
 							ObjectMeta: metav1.ObjectMeta{
 								Name: "tigera-secure",
@@ -1393,8 +1402,10 @@ var _ = Describe("LogStorage controller", func() {
 					mockStatus.On("AddStatefulSets", mock.Anything)
 					mockStatus.On("AddCronJobs", mock.Anything)
 					mockStatus.On("ClearDegraded", mock.Anything)
-					mockStatus.On("OnCRFound").Return()
+					mockStatus.On("OnCRFound", mock.Anything).Return()
 					mockStatus.On("ReadyToMonitor")
+					mockStatus.On("SetObservedGeneration", mock.Anything)
+					mockStatus.On("SetRenderedVariant", mock.Anything)
 				})
 
 				It("deletes Elasticsearch and Kibana then removes the finalizers on the LogStorage CR", func() {
@@ -1600,6 +1611,7 @@ func setUpLogStorageComponents(cli client.Client, ctx context.Context, storageCl
 		},
 		ClusterDomain:      "cluster.local",
 		ElasticLicenseType: render.ElasticsearchLicenseTypeBasic,
+		UsePSP:             true,
 	}
 
 	component := render.LogStorage(cfg)
@@ -85,6 +85,8 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		provider:        opts.DetectedProvider,
 		status:          status.New(mgr.GetClient(), "manager", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
+		usePSP:          opts.UsePSP,
+		usePolicyV1PDB:  opts.UsePolicyV1PDB,
 		licenseAPIReady: licenseAPIReady,
 	}
 	c.status.Run(opts.ShutdownContext)
@@ -117,7 +119,7 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		for _, secretName := range []string{
 			render.ManagerTLSSecretName, relasticsearch.PublicCertSecret,
 			render.ElasticsearchManagerUserSecret, render.KibanaPublicCertSecret,
-			render.VoltronTunnelSecretName, render.ComplianceServerCertSecret, render.PacketCaptureCertSecret,
+			render.VoltronTunnelSecretName, render.VoltronTunnelNextSecretName, render.ComplianceServerCertSecret, render.PacketCaptureCertSecret,
 			render.ManagerInternalTLSSecretName, render.DexCertSecretName, render.PrometheusTLSSecretName,
 		} {
 			if err = utils.AddSecretsWatch(c, secretName, namespace); err != nil {
@@ -182,6 +184,8 @@ type ReconcileManager struct {
 	provider        operatorv1.Provider
 	status          status.StatusManager
 	clusterDomain   string
+	usePSP          bool
+	usePolicyV1PDB  bool
 	licenseAPIReady *utils.ReadyFlag
 }
 
@@ -220,7 +224,7 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		return reconcile.Result{}, err
 	}
 	reqLogger.V(2).Info("Loaded config", "config", instance)
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 
 	if !utils.IsAPIServerReady(r.client, reqLogger) {
 		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
@@ -254,6 +258,7 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	// Check that if the manager certpair secret exists that it is valid (has key and cert fields)
 	// If it does not exist then this function returns a nil secret but no error and a self-signed
@@ -412,6 +417,7 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 	}
 
 	var tunnelSecret *corev1.Secret
+	var nextTunnelSecret *corev1.Secret
 	var internalTrafficSecret *corev1.Secret
 	if managementCluster != nil {
 		// We expect that the secret that holds the certificates for tunnel certificate generation
@@ -423,6 +429,19 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 			return reconcile.Result{}, nil
 		}
 
+		// The CA a rotation of the tunnel CA moves to is also created by the Api Server, while the rotation is in
+		// progress.
+		nextTunnelSecret, err = utils.GetSecret(ctx, r.client, render.VoltronTunnelNextSecretName, common.OperatorNamespace())
+		if err != nil {
+			r.status.SetDegraded(fmt.Sprintf("Error fetching secret %s", render.VoltronTunnelNextSecretName), err.Error())
+			return reconcile.Result{}, err
+		}
+
+		if ts := managementCluster.Spec.TunnelService; ts != nil && ts.Type == operatorv1.TunnelServiceIngress && ts.Host == "" {
+			r.status.SetDegraded("spec.tunnelService.host is required when the tunnel is exposed with an Ingress", "")
+			return reconcile.Result{}, nil
+		}
+
 		// We expect that the secret that holds the certificates for internal communication within the management
 		// K8S cluster is already created by the KubeControllers
 		internalTrafficSecret = &corev1.Secret{}
@@ -525,10 +544,13 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		Installation:                  installation,
 		ManagementCluster:             managementCluster,
 		TunnelSecret:                  tunnelSecret,
+		NextTunnelSecret:              nextTunnelSecret,
 		InternalTrafficSecret:         internalTrafficSecret,
 		ClusterDomain:                 r.clusterDomain,
 		ESLicenseType:                 elasticLicenseType,
 		Replicas:                      replicas,
+		UsePSP:                        r.usePSP,
+		UsePolicyV1PDB:                r.usePolicyV1PDB,
 	}
 
 	// Render the desired objects from the CRD and create or update them.
@@ -539,13 +561,19 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		return reconcile.Result{}, err
 	}
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, installation, component); err != nil {
 		log.Error(err, "Error with images from ImageSet")
 		r.status.SetDegraded("Error with images from ImageSet", err.Error())
 		return reconcile.Result{}, err
 	}
 
-	components = append(components, component)
+	tier, err := utils.AllowTigeraTier(ctx, r.client, installation, component)
+	if err != nil {
+		r.status.SetDegraded("Error querying allow-tigera tier", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	components = append(components, component, render.NetworkPolicies(installation, tier, component))
 	for _, component := range components {
 		if err := handler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
 			r.status.SetDegraded("Error creating / updating resource", err.Error())
@@ -553,6 +581,9 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		}
 	}
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(instance.Generation)
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 	instance.Status.State = operatorv1.TigeraStatusReady
@@ -562,5 +593,8 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		}
 	}
 
+	if result, requeue := utils.AllowTigeraTierRequeue(installation, tier); requeue {
+		return result, nil
+	}
 	return reconcile.Result{}, nil
 }
@@ -23,6 +23,7 @@ var handlers = []handler{
 	handleTyphaMetrics,
 	handleCalicoCNI,
 	handleNonCalicoCNI,
+	handleWireGuard,
 	handleMTU,
 	handleIPPools,
 }
@@ -15,8 +15,8 @@ import (
 // pools in the datastore.
 // We read the pools from the datastore and select the appropriate ones.
 // See selectInitialPool for details on which pool will be selected.
-// Since the operator only supports one v4 and one v6 only one of each will be picked
-// if they exist.
+// Only one v4 and one v6 pool will be picked if they exist, keeping their names so that
+// the operator takes over those pools. Any other pools are left unmanaged.
 func handleIPPools(c *components, install *operatorv1.Installation) error {
 	pools := crdv1.IPPoolList{}
 	if err := c.client.List(ctx, &pools); err != nil && !kerrors.IsNotFound(err) {
@@ -199,7 +199,7 @@ func selectInitialPool(pools []crdv1.IPPool, isver func(ip net.IP) bool) (*crdv1
 
 // convertPool converts the src (CRD) pool into an Installation/Operator IPPool
 func convertPool(src crdv1.IPPool) (operatorv1.IPPool, error) {
-	p := operatorv1.IPPool{CIDR: src.Spec.CIDR}
+	p := operatorv1.IPPool{Name: src.Name, CIDR: src.Spec.CIDR}
 
 	ip := src.Spec.IPIPMode
 	if ip == "" {
@@ -94,6 +94,7 @@ var _ = Describe("Convert network tests", func() {
 			cfg, err := Convert(ctx, c)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cfg.Spec.CalicoNetwork.IPPools).To(Equal([]operatorv1.IPPool{{
+				Name:          "not-default",
 				CIDR:          "1.168.4.0/24",
 				Encapsulation: operatorv1.EncapsulationIPIP,
 				NATOutgoing:   operatorv1.NATOutgoingEnabled,
@@ -194,10 +195,12 @@ var _ = Describe("Convert network tests", func() {
 			cfg, err := Convert(ctx, c)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cfg.Spec.CalicoNetwork.IPPools).To(ConsistOf([]operatorv1.IPPool{{
+				Name:          "not-default",
 				CIDR:          "1.168.4.0/24",
 				Encapsulation: operatorv1.EncapsulationIPIP,
 				NATOutgoing:   operatorv1.NATOutgoingEnabled,
 			}, {
+				Name:          "not-default1-v6",
 				CIDR:          "ff00:0001::/24",
 				Encapsulation: operatorv1.EncapsulationNone,
 				NATOutgoing:   operatorv1.NATOutgoingEnabled,
@@ -7,6 +7,14 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 )
 
+// The bytes added to each packet by the IPIP and VXLAN encapsulations and by WireGuard. They are used to compute the
+// pod MTU from the tunnel MTUs when WireGuard is enabled, since the pod traffic is then sent over WireGuard.
+var mtuOverhead = map[string]int32{
+	"FELIX_IPINIPMTU":    20,
+	"FELIX_VXLANMTU":     50,
+	"FELIX_WIREGUARDMTU": 60,
+}
+
 // handleMTU is a migration handler which ensures MTU configuration is carried forward.
 func handleMTU(c *components, install *operatorv1.Installation) error {
 	var (
@@ -14,7 +22,22 @@ func handleMTU(c *components, install *operatorv1.Installation) error {
 		curMTUSrc string
 	)
 
-	for _, src := range []string{"FELIX_IPINIPMTU", "FELIX_VXLANMTU", "FELIX_WIREGUARDMTU"} {
+	sources := []string{"FELIX_IPINIPMTU", "FELIX_VXLANMTU", "FELIX_WIREGUARDMTU"}
+	wireGuard := wireGuardIPv4Enabled(install)
+	if wireGuard {
+		// The pods use the MTU of the WireGuard device. The tunnel MTUs are only used when it isn't set.
+		wireGuardMTU, err := c.node.getEnv(ctx, c.client, containerCalicoNode, "FELIX_WIREGUARDMTU")
+		if err != nil {
+			return err
+		}
+		if wireGuardMTU != nil {
+			c.node.ignoreEnv(containerCalicoNode, "FELIX_IPINIPMTU")
+			c.node.ignoreEnv(containerCalicoNode, "FELIX_VXLANMTU")
+			sources = []string{"FELIX_WIREGUARDMTU"}
+		}
+	}
+
+	for _, src := range sources {
 		mtu, err := getMTU(c, containerCalicoNode, src)
 		if err != nil {
 			return ErrIncompatibleCluster{
@@ -29,6 +52,11 @@ func handleMTU(c *components, install *operatorv1.Installation) error {
 			continue
 		}
 
+		if wireGuard {
+			// Leave room for the WireGuard header instead of the tunnel header.
+			*mtu -= mtuOverhead["FELIX_WIREGUARDMTU"] - mtuOverhead[src]
+		}
+
 		// compare against current mtu.
 		if curMTU != nil && *curMTU != *mtu {
 			return ErrIncompatibleCluster{
@@ -115,3 +143,9 @@ func getMTU(c *components, container, key string) (*int32, error) {
 	mtu := int32(i)
 	return &mtu, nil
 }
+
+// wireGuardIPv4Enabled returns true if the installation enables WireGuard encryption of the IPv4 traffic.
+func wireGuardIPv4Enabled(install *operatorv1.Installation) bool {
+	cn := install.Spec.CalicoNetwork
+	return cn != nil && cn.WireGuard != nil && cn.WireGuard.IPv4 != nil && *cn.WireGuard.IPv4 == operatorv1.WireGuardEnabled
+}
@@ -66,6 +66,39 @@ var _ = Describe("mtu handler", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	Context("with WireGuard enabled", func() {
+		BeforeEach(func() {
+			enabled := operatorv1.WireGuardEnabled
+			i.Spec.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+				WireGuard: &operatorv1.WireGuardSpec{IPv4: &enabled},
+			}
+		})
+
+		It("should use the wireguard mtu", func() {
+			comps.node.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{
+				{Name: "FELIX_IPINIPMTU", Value: "1440"},
+				{Name: "FELIX_WIREGUARDMTU", Value: "1420"},
+			}
+			comps.cni.CalicoConfig = &cni.CalicoConf{MTU: 1420}
+			err := handleMTU(&comps, i)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*i.Spec.CalicoNetwork.MTU).To(BeEquivalentTo(1420))
+		})
+
+		table.DescribeTable("should leave room for the wireguard header in the tunnel mtu", func(env string, expected int) {
+			comps.node.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{{
+				Name:  env,
+				Value: "1440",
+			}}
+			err := handleMTU(&comps, i)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*i.Spec.CalicoNetwork.MTU).To(BeEquivalentTo(expected))
+		},
+			table.Entry("ipip", "FELIX_IPINIPMTU", 1400),
+			table.Entry("vxlan", "FELIX_VXLANMTU", 1430),
+		)
+	})
+
 	It("should error if given conflicting mtu values between cni and env var", func() {
 		comps.node.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{{
 			Name:  "FELIX_IPINIPMTU",
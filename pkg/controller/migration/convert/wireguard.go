@@ -0,0 +1,56 @@
+package convert
+
+import (
+	"fmt"
+	"strconv"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+// handleWireGuard is a migration handler which carries WireGuard encryption enabled through the
+// FELIX_WIREGUARDENABLED and FELIX_WIREGUARDENABLEDV6 env vars forward.
+func handleWireGuard(c *components, install *operatorv1.Installation) error {
+	ipv4, err := getWireGuardState(c, "FELIX_WIREGUARDENABLED")
+	if err != nil {
+		return err
+	}
+	ipv6, err := getWireGuardState(c, "FELIX_WIREGUARDENABLEDV6")
+	if err != nil {
+		return err
+	}
+	if ipv4 == nil && ipv6 == nil {
+		return nil
+	}
+
+	if install.Spec.CalicoNetwork == nil {
+		install.Spec.CalicoNetwork = &operatorv1.CalicoNetworkSpec{}
+	}
+	install.Spec.CalicoNetwork.WireGuard = &operatorv1.WireGuardSpec{IPv4: ipv4, IPv6: ipv6}
+	return nil
+}
+
+// getWireGuardState retrieves the WireGuard state from a boolean env var on the calico-node container.
+// if the specified env var does not exist, it will return nil.
+func getWireGuardState(c *components, key string) (*operatorv1.WireGuardState, error) {
+	v, err := c.node.getEnv(ctx, c.client, containerCalicoNode, key)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	enabled, err := strconv.ParseBool(*v)
+	if err != nil {
+		return nil, ErrIncompatibleCluster{
+			err:       fmt.Sprintf("failed to parse %s=%s: %v", key, *v, err),
+			component: ComponentCalicoNode,
+			fix:       fmt.Sprintf("set %s to true or false", key),
+		}
+	}
+	state := operatorv1.WireGuardDisabled
+	if enabled {
+		state = operatorv1.WireGuardEnabled
+	}
+	return &state, nil
+}
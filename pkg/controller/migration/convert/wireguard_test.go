@@ -0,0 +1,43 @@
+package convert
+
+import (
+	operatorv1 "github.com/tigera/operator/api/v1"
+	v1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("wireguard handler", func() {
+	var (
+		comps = emptyComponents()
+		i     = &operatorv1.Installation{}
+	)
+
+	BeforeEach(func() {
+		comps = emptyComponents()
+		i = &operatorv1.Installation{}
+	})
+
+	It("should not set wireguard if not enabled through env vars", func() {
+		Expect(handleWireGuard(&comps, i)).ToNot(HaveOccurred())
+		Expect(i.Spec.CalicoNetwork).To(BeNil())
+	})
+
+	It("should carry the wireguard env vars forward", func() {
+		comps.node.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{
+			{Name: "FELIX_WIREGUARDENABLED", Value: "true"},
+			{Name: "FELIX_WIREGUARDENABLEDV6", Value: "false"},
+		}
+		Expect(handleWireGuard(&comps, i)).ToNot(HaveOccurred())
+		Expect(*i.Spec.CalicoNetwork.WireGuard.IPv4).To(Equal(operatorv1.WireGuardEnabled))
+		Expect(*i.Spec.CalicoNetwork.WireGuard.IPv6).To(Equal(operatorv1.WireGuardDisabled))
+	})
+
+	It("should error on an invalid value", func() {
+		comps.node.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{
+			{Name: "FELIX_WIREGUARDENABLED", Value: "yes please"},
+		}
+		Expect(handleWireGuard(&comps, i)).To(HaveOccurred())
+	})
+})
@@ -183,7 +183,7 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 		return reconcile.Result{}, err
 	}
 	reqLogger.V(2).Info("Loaded config", "config", instance)
-	r.status.OnCRFound()
+	r.status.OnCRFound(instance)
 
 	variant, install, err := utils.GetInstallation(context.Background(), r.client)
 	if err != nil {
@@ -194,6 +194,7 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 		r.setDegraded(reqLogger, err, "Failed to query Installation")
 		return reconcile.Result{}, err
 	}
+	r.status.SetRenderedVariant(variant)
 
 	pullSecrets, err := utils.GetNetworkingPullSecrets(install, r.client)
 	if err != nil {
@@ -345,11 +346,18 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 		components = append(components, render.NewPassthrough(alertmanagerConfigSecret))
 	}
 
-	if err = imageset.ApplyImageSet(ctx, r.client, variant, components...); err != nil {
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, install, components...); err != nil {
 		r.setDegraded(reqLogger, err, "Error with images from ImageSet")
 		return reconcile.Result{}, err
 	}
 
+	tier, err := utils.AllowTigeraTier(ctx, r.client, install, components...)
+	if err != nil {
+		r.setDegraded(reqLogger, err, "Error querying allow-tigera tier")
+		return reconcile.Result{}, err
+	}
+	components = append(components, render.NetworkPolicies(install, tier, components...))
+
 	for _, component := range components {
 		if err := hdler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
 			r.setDegraded(reqLogger, err, "Error creating / updating resource")
@@ -360,8 +368,15 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 	// Tell the status manager that we're ready to monitor the resources we've told it about and receive statuses.
 	r.status.ReadyToMonitor()
 
+	// Every component has been applied, so the generation of the CR has been rendered.
+	r.status.SetObservedGeneration(instance.Generation)
+
 	r.status.ClearDegraded()
 
+	if result, requeue := utils.AllowTigeraTierRequeue(install, tier); requeue {
+		return result, nil
+	}
+
 	if !r.status.IsAvailable() {
 		// Schedule a kick to check again in the near future. Hopefully by then things will be available.
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
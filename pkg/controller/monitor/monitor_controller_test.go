@@ -68,8 +68,10 @@ var _ = Describe("Monitor controller tests", func() {
 		mockStatus.On("AddStatefulSets", mock.Anything)
 		mockStatus.On("ClearDegraded")
 		mockStatus.On("IsAvailable").Return(true)
-		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("OnCRFound", mock.Anything).Return()
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("SetObservedGeneration", mock.Anything)
+		mockStatus.On("SetRenderedVariant", mock.Anything)
 		mockStatus.On("RemoveCertificateSigningRequests", common.TigeraPrometheusNamespace)
 
 		// Create an object we can use throughout the test to do the monitor reconcile loops.
@@ -17,6 +17,8 @@ type AddOptions struct {
 	AmazonCRDExists     bool
 	ClusterDomain       string
 	KubernetesVersion   *common.VersionInfo
+	UsePSP              bool
+	UsePolicyV1PDB      bool
 	ManageCRDs          bool
 	ShutdownContext     context.Context
 }
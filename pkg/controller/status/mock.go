@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"k8s.io/apimachinery/pkg/types"
+
+	operator "github.com/tigera/operator/api/v1"
 )
 
 // TODO use mockery to generate mock
@@ -20,8 +22,8 @@ func (m *MockStatus) ReadyToMonitor() {
 	m.Called()
 }
 
-func (m *MockStatus) OnCRFound() {
-	m.Called()
+func (m *MockStatus) OnCRFound(crs ...ConditionsObject) {
+	m.Called(crs)
 }
 
 func (m *MockStatus) OnCRNotFound() {
@@ -72,6 +74,22 @@ func (m *MockStatus) SetWindowsUpgradeStatus(pending, inProgress, completed []st
 	m.Called(pending, inProgress, completed, err)
 }
 
+func (m *MockStatus) SetWireGuardStatus(status *operator.TigeraStatusWireGuard) {
+	m.Called(status)
+}
+
+func (m *MockStatus) SetDataplaneStatus(status *operator.TigeraStatusDataplane) {
+	m.Called(status)
+}
+
+func (m *MockStatus) SetObservedGeneration(generation int64) {
+	m.Called(generation)
+}
+
+func (m *MockStatus) SetRenderedVariant(variant operator.ProductVariant) {
+	m.Called(variant)
+}
+
 func (m *MockStatus) SetDegraded(reason, msg string) {
 	m.Called(reason, msg)
 }
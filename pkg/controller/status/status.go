@@ -18,12 +18,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	batch "k8s.io/api/batch/v1beta1"
@@ -32,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -41,6 +44,13 @@ import (
 
 var log = logf.Log.WithName("status_manager")
 
+// ConditionsObject is a CR that reports the status conditions of the component it configures.
+type ConditionsObject interface {
+	client.Object
+	GetConditions() []metav1.Condition
+	SetConditions(conditions []metav1.Condition)
+}
+
 // StatusManager manages the status for a single controller and component, and reports the status via
 // a TigeraStatus API object. The status manager uses the following conditions/states to represent the
 // component's current status:
@@ -55,9 +65,12 @@ var log = logf.Log.WithName("status_manager")
 // Each of these states can be set independently of each other. For example, a component can be both available and
 // degraded if it is running successfully but a configuration change has resulted in a configuration that cannot
 // be actioned.
+//
+// The conditions are also reported on the status of the CR that configures the component, with Available
+// reported as Ready, so that clients can wait on the CR itself.
 type StatusManager interface {
 	Run(ctx context.Context)
-	OnCRFound()
+	OnCRFound(crs ...ConditionsObject)
 	OnCRNotFound()
 	AddDaemonsets(dss []types.NamespacedName)
 	AddDeployments(deps []types.NamespacedName)
@@ -70,6 +83,10 @@ type StatusManager interface {
 	RemoveCronJobs(cjs ...types.NamespacedName)
 	RemoveCertificateSigningRequests(name string)
 	SetWindowsUpgradeStatus(pending, inProgress, completed []string, err error)
+	SetWireGuardStatus(status *operator.TigeraStatusWireGuard)
+	SetDataplaneStatus(status *operator.TigeraStatusDataplane)
+	SetObservedGeneration(generation int64)
+	SetRenderedVariant(variant operator.ProductVariant)
 	SetDegraded(reason, msg string)
 	ClearDegraded()
 	IsAvailable() bool
@@ -98,9 +115,27 @@ type statusManager struct {
 	// Track degraded state set by calicoWindowsUpgrader.
 	windowsUpgradeDegradedMsg string
 
+	// wireGuard is the readiness of WireGuard encryption on the nodes, if it is enabled.
+	wireGuard *operator.TigeraStatusWireGuard
+
+	// dataplane is the progress of switching the Linux dataplane.
+	dataplane *operator.TigeraStatusDataplane
+
+	// observedGeneration is the generation of the owning CR that was most recently rendered.
+	observedGeneration int64
+
+	// variant is the product variant that the reconciler has rendered, whose release is reported as the version.
+	variant operator.ProductVariant
+
+	// crs are the CRs that configure the component, which the conditions are also reported on.
+	crs []ConditionsObject
+
 	// Keep track of currently calculated status.
-	progressing []string
-	failing     []string
+	progressing        []string
+	failing            []string
+	workloads          []operator.TigeraStatusWorkload
+	version            string
+	reportedGeneration int64
 
 	// readyToMonitor tells the status manager that it's ready to monitor the resources that it's been told to monitor,
 	// if there are any, and report statuses based on the state of those resources.
@@ -190,6 +225,7 @@ func (m *statusManager) updateStatus() {
 		}
 	}
 
+	m.updateCRConditions()
 }
 
 func (m *statusManager) isExplicitlyDegraded() bool {
@@ -232,12 +268,19 @@ func (m *statusManager) ReadyToMonitor() {
 
 // OnCRFound indicates to the status manager that it should start reporting status. Until called,
 // the status manager will be be in a "dormant" state, and will not write status to the API.
-// Call this function from a controller once it has first received an instance of its CRD.
-func (m *statusManager) OnCRFound() {
+// Call this function from a controller once it has first received an instance of its CRD, passing
+// the instances that configure the component so that the status conditions are also reported on them.
+func (m *statusManager) OnCRFound(crs ...ConditionsObject) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	t := true
 	m.enabled = &t
+	m.crs = nil
+	for _, cr := range crs {
+		if cr != nil {
+			m.crs = append(m.crs, cr.DeepCopyObject().(ConditionsObject))
+		}
+	}
 }
 
 // OnCRNotFound indicates that the CR managed by the parent controller has not been found. The
@@ -252,6 +295,12 @@ func (m *statusManager) OnCRNotFound() {
 	m.enabled = &f
 	m.progressing = []string{}
 	m.failing = []string{}
+	m.workloads = nil
+	m.wireGuard = nil
+	m.dataplane = nil
+	m.observedGeneration = 0
+	m.variant = ""
+	m.crs = nil
 	m.daemonsets = make(map[string]types.NamespacedName)
 	m.deployments = make(map[string]types.NamespacedName)
 	m.statefulsets = make(map[string]types.NamespacedName)
@@ -329,20 +378,59 @@ func (w *windowsNodeUpgrades) progressingReason() string {
 }
 
 // SetWindowsUpgradeStatus tells the status manager to monitor the upgrade
-// status of the given Windows node upgrades.
+// status of the given Windows node upgrades. If err is set the status is
+// degraded. The node lists are left unchanged if they are all nil, which
+// happens when the upgrade status could not be determined.
 func (m *statusManager) SetWindowsUpgradeStatus(pending, inProgress, completed []string, err error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	if err != nil {
 		m.windowsUpgradeDegradedMsg = err.Error()
+	} else {
+		m.windowsUpgradeDegradedMsg = ""
+	}
+
+	if pending == nil && inProgress == nil && completed == nil {
 		return
 	}
 
 	m.windowsNodeUpgrades.nodesPending = pending
 	m.windowsNodeUpgrades.nodesInProgress = inProgress
 	m.windowsNodeUpgrades.nodesCompleted = completed
-	m.windowsUpgradeDegradedMsg = ""
+}
+
+// SetWireGuardStatus tells the status manager the readiness of WireGuard encryption on the nodes, which is
+// reported on the TigeraStatus. A nil status means that WireGuard encryption is not enabled.
+func (m *statusManager) SetWireGuardStatus(status *operator.TigeraStatusWireGuard) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.wireGuard = status.DeepCopy()
+}
+
+// SetDataplaneStatus tells the status manager the progress of switching the Linux dataplane, which is reported on
+// the TigeraStatus.
+func (m *statusManager) SetDataplaneStatus(status *operator.TigeraStatusDataplane) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dataplane = status.DeepCopy()
+}
+
+// SetObservedGeneration tells the status manager the generation of the owning CR that has been rendered. It is
+// reported along with the state of the monitored resources, so that it is only reported once those resources
+// reflect the rendered generation.
+func (m *statusManager) SetObservedGeneration(generation int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.observedGeneration = generation
+}
+
+// SetRenderedVariant tells the status manager the product variant that the reconciler has rendered. The release of
+// the variant is reported as the version on the TigeraStatus.
+func (m *statusManager) SetRenderedVariant(variant operator.ProductVariant) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.variant = variant
 }
 
 // RemoveDaemonsets tells the status manager to stop monitoring the health of the given daemonsets
@@ -470,6 +558,11 @@ func (m *statusManager) syncState() {
 	defer m.lock.Unlock()
 	progressing := []string{}
 	failing := []string{}
+	workloads := []operator.TigeraStatusWorkload{}
+
+	// The generation is captured before querying the resources so that it is never reported alongside
+	// resource states that predate it.
+	generation := m.observedGeneration
 
 	// For each daemonset, check its rollout status.
 	for _, dsnn := range m.daemonsets {
@@ -490,9 +583,19 @@ func (m *statusManager) syncState() {
 		}
 
 		// Check if any pods within the daemonset are failing.
-		if f := m.podsFailing(ds.Spec.Selector, ds.Namespace); f != "" {
+		f, failingPods := m.podsFailing(ds.Spec.Selector, ds.Namespace)
+		if f != "" {
 			failing = append(failing, f)
 		}
+		workloads = append(workloads, operator.TigeraStatusWorkload{
+			Kind:        "DaemonSet",
+			Namespace:   ds.Namespace,
+			Name:        ds.Name,
+			Desired:     ds.Status.DesiredNumberScheduled,
+			Ready:       ds.Status.NumberReady,
+			Updated:     ds.Status.UpdatedNumberScheduled,
+			FailingPods: failingPods,
+		})
 	}
 
 	for _, depnn := range m.deployments {
@@ -511,9 +614,23 @@ func (m *statusManager) syncState() {
 		}
 
 		// Check if any pods within the deployment are failing.
-		if f := m.podsFailing(dep.Spec.Selector, dep.Namespace); f != "" {
+		f, failingPods := m.podsFailing(dep.Spec.Selector, dep.Namespace)
+		if f != "" {
 			failing = append(failing, f)
 		}
+		desired := int32(1)
+		if dep.Spec.Replicas != nil {
+			desired = *dep.Spec.Replicas
+		}
+		workloads = append(workloads, operator.TigeraStatusWorkload{
+			Kind:        "Deployment",
+			Namespace:   dep.Namespace,
+			Name:        dep.Name,
+			Desired:     desired,
+			Ready:       dep.Status.ReadyReplicas,
+			Updated:     dep.Status.UpdatedReplicas,
+			FailingPods: failingPods,
+		})
 	}
 
 	for _, depnn := range m.statefulsets {
@@ -523,16 +640,30 @@ func (m *statusManager) syncState() {
 			log.WithValues("reason", err).Info("Failed to query statefulset")
 			continue
 		}
-		if *ss.Spec.Replicas != ss.Status.CurrentReplicas {
-			progressing = append(progressing, fmt.Sprintf("Statefulset %q is not available (awaiting %d replicas)", depnn.String(), ss.Status.CurrentReplicas-*ss.Spec.Replicas))
+		desired := int32(1)
+		if ss.Spec.Replicas != nil {
+			desired = *ss.Spec.Replicas
+		}
+		if desired != ss.Status.CurrentReplicas {
+			progressing = append(progressing, fmt.Sprintf("Statefulset %q is not available (awaiting %d replicas)", depnn.String(), ss.Status.CurrentReplicas-desired))
 		} else if ss.Status.ObservedGeneration < ss.Generation {
 			progressing = append(progressing, fmt.Sprintf("Statefulset %q update is being processed (generation %d, observed generation %d)", ss.String(), ss.Generation, ss.Status.ObservedGeneration))
 		}
 
 		// Check if any pods within the deployment are failing.
-		if f := m.podsFailing(ss.Spec.Selector, ss.Namespace); f != "" {
+		f, failingPods := m.podsFailing(ss.Spec.Selector, ss.Namespace)
+		if f != "" {
 			failing = append(failing, f)
 		}
+		workloads = append(workloads, operator.TigeraStatusWorkload{
+			Kind:        "StatefulSet",
+			Namespace:   ss.Namespace,
+			Name:        ss.Name,
+			Desired:     desired,
+			Ready:       ss.Status.ReadyReplicas,
+			Updated:     ss.Status.UpdatedReplicas,
+			FailingPods: failingPods,
+		})
 	}
 
 	for _, depnn := range m.cronjobs {
@@ -558,6 +689,13 @@ func (m *statusManager) syncState() {
 		if numFailed > 0 {
 			failing = append(failing, "cronjob/"+cj.Name+" failed in ns '"+cj.Namespace+"'")
 		}
+		workloads = append(workloads, operator.TigeraStatusWorkload{
+			Kind:               "CronJob",
+			Namespace:          cj.Namespace,
+			Name:               cj.Name,
+			LastScheduleTime:   cj.Status.LastScheduleTime,
+			LastSuccessfulTime: cj.Status.LastSuccessfulTime,
+		})
 	}
 
 	for _, labels := range m.certificatestatusrequests {
@@ -573,11 +711,36 @@ func (m *statusManager) syncState() {
 		progressing = append(progressing, reason)
 	}
 
+	// Sort the workloads so that the reported status only changes when the workloads do.
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+
 	m.progressing = progressing
 	m.failing = failing
+	m.workloads = workloads
+	m.version = releaseVersion(m.variant)
+	m.reportedGeneration = generation
 	m.hasSynced = true
 }
 
+// releaseVersion returns the release of the given variant, or an empty string if no variant has been rendered.
+func releaseVersion(variant operator.ProductVariant) string {
+	switch variant {
+	case operator.Calico:
+		return components.CalicoRelease
+	case operator.TigeraSecureEnterprise:
+		return components.EnterpriseRelease
+	}
+	return ""
+}
+
 // isInitialized returns true if corresponding CR has been queried
 func (m *statusManager) isInitialized() bool {
 	m.lock.Lock()
@@ -605,28 +768,41 @@ func (m *statusManager) removeTigeraStatus() {
 	}
 }
 
-// podsFailing takes a selector and returns if any of the pods that match it are failing. Failing pods are defined
-// to be in CrashLoopBackOff state.
-func (m *statusManager) podsFailing(selector *metav1.LabelSelector, namespace string) string {
+// podsFailing takes a selector and returns a message for the first of the pods that match it that is failing, along
+// with the names of all the failing pods. Failing pods are defined to be in CrashLoopBackOff state.
+func (m *statusManager) podsFailing(selector *metav1.LabelSelector, namespace string) (string, []string) {
 	l := corev1.PodList{}
 	s, err := metav1.LabelSelectorAsMap(selector)
 	if err != nil {
 		panic(err)
 	}
 	m.client.List(context.TODO(), &l, client.MatchingLabels(s), client.InNamespace(namespace))
+	msg := ""
+	var failingPods []string
 	for _, p := range l.Items {
-		if p.Status.Phase == corev1.PodFailed {
-			return fmt.Sprintf("Pod %s/%s has failed", p.Namespace, p.Name)
-		}
-		for _, c := range p.Status.InitContainerStatuses {
-			if msg := m.containerErrorMessage(p, c); msg != "" {
-				return msg
+		if podMsg := m.podErrorMessage(p); podMsg != "" {
+			if msg == "" {
+				msg = podMsg
 			}
+			failingPods = append(failingPods, p.Name)
 		}
-		for _, c := range p.Status.ContainerStatuses {
-			if msg := m.containerErrorMessage(p, c); msg != "" {
-				return msg
-			}
+	}
+	sort.Strings(failingPods)
+	return msg, failingPods
+}
+
+func (m *statusManager) podErrorMessage(p corev1.Pod) string {
+	if p.Status.Phase == corev1.PodFailed {
+		return fmt.Sprintf("Pod %s/%s has failed", p.Namespace, p.Name)
+	}
+	for _, c := range p.Status.InitContainerStatuses {
+		if msg := m.containerErrorMessage(p, c); msg != "" {
+			return msg
+		}
+	}
+	for _, c := range p.Status.ContainerStatuses {
+		if msg := m.containerErrorMessage(p, c); msg != "" {
+			return msg
 		}
 	}
 	return ""
@@ -692,8 +868,14 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 		}
 	}
 
+	ts.Status.ObservedGeneration = m.reportedGeneration
+	ts.Status.Version = m.version
+	ts.Status.Workloads = m.workloads
+	ts.Status.WireGuard = m.wireGuard
+	ts.Status.Dataplane = m.dataplane
+
 	// If nothing has changed, we don't need to update in the API.
-	if reflect.DeepEqual(ts.Status.Conditions, old.Status.Conditions) {
+	if reflect.DeepEqual(ts.Status, old.Status) {
 		return
 	}
 
@@ -716,6 +898,99 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 	m.crExists = true
 }
 
+// updateCRConditions reports the conditions of the TigeraStatus on the status of the CRs that configure
+// the component. The observedGeneration of the conditions is the most recently rolled out generation.
+func (m *statusManager) updateCRConditions() {
+	m.lock.Lock()
+	if len(m.crs) == 0 {
+		m.lock.Unlock()
+		return
+	}
+	var crs []ConditionsObject
+	for _, cr := range m.crs {
+		crs = append(crs, cr.DeepCopyObject().(ConditionsObject))
+	}
+	generation := m.reportedGeneration
+	m.lock.Unlock()
+
+	var ts operator.TigeraStatus
+	if err := m.client.Get(context.TODO(), types.NamespacedName{Name: m.component}, &ts); err != nil {
+		if !errors.IsNotFound(err) {
+			log.WithValues("reason", err).Info("Failed to get TigeraStatus", "component", m.component)
+		}
+		return
+	}
+	for _, cr := range crs {
+		m.updateConditions(cr, ts.Status.Conditions, generation)
+	}
+}
+
+// updateConditions reports the given TigeraStatus conditions on the status of the CR.
+func (m *statusManager) updateConditions(cr ConditionsObject, tsConditions []operator.TigeraStatusCondition, generation int64) {
+	if err := m.client.Get(context.TODO(), client.ObjectKeyFromObject(cr), cr); err != nil {
+		if !errors.IsNotFound(err) {
+			log.WithValues("reason", err).Info("Failed to get CR to update its status conditions", "component", m.component)
+		}
+		return
+	}
+
+	old := cr.GetConditions()
+	var conditions []metav1.Condition
+	conditions = append(conditions, old...)
+	for _, c := range tsConditions {
+		meta.SetStatusCondition(&conditions, crCondition(c, generation))
+	}
+	if reflect.DeepEqual(old, conditions) {
+		return
+	}
+	cr.SetConditions(conditions)
+	if err := m.client.Status().Update(context.TODO(), cr); err != nil {
+		log.WithValues("reason", err).Info("Failed to update CR status conditions", "component", m.component)
+	}
+}
+
+// crCondition converts a TigeraStatus condition to the condition reported on the CR. Condition reasons
+// on the CR must be CamelCase, so the TigeraStatus reason is included in the message instead.
+func crCondition(c operator.TigeraStatusCondition, generation int64) metav1.Condition {
+	conditionType := c.Type
+	if conditionType == operator.ComponentAvailable {
+		conditionType = operator.ComponentReady
+	}
+
+	reason := "Unknown"
+	switch {
+	case conditionType == operator.ComponentReady && c.Status == operator.ConditionTrue:
+		reason = "AllObjectsAvailable"
+	case conditionType == operator.ComponentReady && c.Status == operator.ConditionFalse:
+		reason = "ResourceNotReady"
+	case conditionType == operator.ComponentProgressing && c.Status == operator.ConditionTrue:
+		reason = "ResourceProgressing"
+	case conditionType == operator.ComponentProgressing && c.Status == operator.ConditionFalse:
+		reason = "AllObjectsRolledOut"
+	case conditionType == operator.ComponentDegraded && c.Status == operator.ConditionTrue:
+		reason = "ResourceDegraded"
+	case conditionType == operator.ComponentDegraded && c.Status == operator.ConditionFalse:
+		reason = "NotDegraded"
+	}
+
+	msg := c.Reason
+	if c.Message != "" {
+		if msg != "" {
+			msg += ": "
+		}
+		msg += c.Message
+	}
+
+	return metav1.Condition{
+		Type:               string(conditionType),
+		Status:             metav1.ConditionStatus(c.Status),
+		ObservedGeneration: generation,
+		LastTransitionTime: c.LastTransitionTime,
+		Reason:             reason,
+		Message:            msg,
+	}
+}
+
 func (m *statusManager) setAvailable(reason, msg string) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -17,11 +17,14 @@ package status
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	certV1 "k8s.io/api/certificates/v1"
 	certV1beta1 "k8s.io/api/certificates/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +39,7 @@ import (
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
 )
 
 var _ = Describe("Status reporting tests", func() {
@@ -109,7 +113,7 @@ var _ = Describe("Status reporting tests", func() {
 
 	Context("with CR found", func() {
 		BeforeEach(func() {
-			sm.OnCRFound()
+			sm.OnCRFound(nil)
 			// sync doesn't actually run so it needs to be set explicitly here.
 			sm.hasSynced = true
 		})
@@ -483,6 +487,204 @@ var _ = Describe("Status reporting tests", func() {
 				sm.SetWindowsUpgradeStatus([]string{}, []string{}, []string{"n1", "n2", "n3"}, nil)
 				Expect(sm.windowsNodeUpgrades.progressingReason()).To(Equal(""))
 			})
+
+			It("should report the windows node upgrade status when some node upgrades failed", func() {
+				sm.SetWindowsUpgradeStatus([]string{"n3"}, []string{"n2"}, []string{}, fmt.Errorf("Calico for Windows upgrade failed on nodes: n1"))
+				Expect(sm.IsDegraded()).To(Equal(true))
+				Expect(sm.degradedMessage()).To(Equal("Calico for Windows upgrade failed on nodes: n1"))
+				Expect(sm.windowsNodeUpgrades.progressingReason()).To(Equal("Waiting for Calico for Windows to be upgraded: 0/2 nodes have been upgraded, 1 in-progress"))
+
+				// A failure to determine the upgrade status keeps the last known node upgrades.
+				sm.SetWindowsUpgradeStatus(nil, nil, nil, fmt.Errorf("an error"))
+				Expect(sm.IsDegraded()).To(Equal(true))
+				Expect(sm.windowsNodeUpgrades.progressingReason()).To(Equal("Waiting for Calico for Windows to be upgraded: 0/2 nodes have been upgraded, 1 in-progress"))
+			})
+		})
+
+		Context("workload status", func() {
+			var replicas int32 = 2
+			selector := &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "test"}}
+			lastSchedule := metav1.NewTime(time.Date(2021, 11, 1, 10, 0, 0, 0, time.UTC))
+			lastSuccess := metav1.NewTime(time.Date(2021, 11, 1, 9, 0, 0, 0, time.UTC))
+
+			BeforeEach(func() {
+				scheme := runtime.NewScheme()
+				Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+				Expect(appsv1.AddToScheme(scheme)).NotTo(HaveOccurred())
+				Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+				Expect(batchv1beta1.AddToScheme(scheme)).NotTo(HaveOccurred())
+				client = fake.NewFakeClientWithScheme(scheme,
+					&batchv1beta1.CronJob{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "cj1"},
+						Status: batchv1beta1.CronJobStatus{
+							LastScheduleTime:   &lastSchedule,
+							LastSuccessfulTime: &lastSuccess,
+						},
+					},
+					&appsv1.DaemonSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ds1"},
+						Spec:       appsv1.DaemonSetSpec{Selector: selector},
+						Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2, UpdatedNumberScheduled: 1, NumberAvailable: 2, NumberUnavailable: 1},
+					},
+					&appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "dep1"},
+						Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "other"}}},
+						Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+					},
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "ss1"},
+						Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "other"}}},
+						Status:     appsv1.StatefulSetStatus{CurrentReplicas: 1, ReadyReplicas: 1, UpdatedReplicas: 1},
+					},
+					&corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod-b", Labels: map[string]string{"k8s-app": "test"}},
+						Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+							Name:  "c",
+							State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						}}},
+					},
+					&corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod-a", Labels: map[string]string{"k8s-app": "test"}},
+						Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+					},
+					&corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod-c", Labels: map[string]string{"k8s-app": "test"}},
+						Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+					},
+				)
+				sm = New(client, "test-component", &common.VersionInfo{Major: 1, Minor: 19}).(*statusManager)
+				sm.OnCRFound(nil)
+				sm.ReadyToMonitor()
+				sm.AddDaemonsets([]types.NamespacedName{{Namespace: "ns1", Name: "ds1"}})
+				sm.AddDeployments([]types.NamespacedName{{Namespace: "ns2", Name: "dep1"}})
+				sm.AddStatefulSets([]types.NamespacedName{{Namespace: "ns2", Name: "ss1"}})
+				sm.AddCronJobs([]types.NamespacedName{{Namespace: "ns2", Name: "cj1"}})
+				sm.SetRenderedVariant(operator.Calico)
+			})
+
+			It("should report each workload with its counts and failing pods", func() {
+				sm.updateStatus()
+
+				ts := &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				Expect(ts.Status.Version).To(Equal(components.CalicoRelease))
+				Expect(ts.Status.Workloads).To(HaveLen(4))
+				cj := ts.Status.Workloads[0]
+				Expect(cj.Kind).To(Equal("CronJob"))
+				Expect(cj.Desired).To(BeZero())
+				Expect(cj.Ready).To(BeZero())
+				Expect(cj.LastScheduleTime.Time).To(BeTemporally("==", lastSchedule.Time))
+				Expect(cj.LastSuccessfulTime.Time).To(BeTemporally("==", lastSuccess.Time))
+				Expect(ts.Status.Workloads[1:]).To(Equal([]operator.TigeraStatusWorkload{
+					{Kind: "DaemonSet", Namespace: "ns1", Name: "ds1", Desired: 3, Ready: 2, Updated: 1, FailingPods: []string{"pod-a", "pod-b"}},
+					{Kind: "Deployment", Namespace: "ns2", Name: "dep1", Desired: 2, Ready: 2, Updated: 2},
+					{Kind: "StatefulSet", Namespace: "ns2", Name: "ss1", Desired: 1, Ready: 1, Updated: 1},
+				}))
+				Expect(sm.degradedMessage()).To(Equal("Pod ns1/pod-a has failed"))
+			})
+
+			It("should report the WireGuard readiness of the nodes", func() {
+				sm.SetWireGuardStatus(&operator.TigeraStatusWireGuard{Desired: 2, Ready: 1, NotReadyNodes: []string{"node2"}})
+				sm.updateStatus()
+
+				ts := &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				Expect(ts.Status.WireGuard).To(Equal(&operator.TigeraStatusWireGuard{Desired: 2, Ready: 1, NotReadyNodes: []string{"node2"}}))
+
+				sm.SetWireGuardStatus(nil)
+				sm.updateStatus()
+				ts = &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				Expect(ts.Status.WireGuard).To(BeNil())
+			})
+
+			It("should report the observed generation once the workloads have been synced", func() {
+				sm.SetObservedGeneration(3)
+				Expect(sm.reportedGeneration).To(BeZero())
+
+				sm.updateStatus()
+				ts := &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				Expect(ts.Status.ObservedGeneration).To(Equal(int64(3)))
+
+				sm.SetObservedGeneration(4)
+				sm.updateStatus()
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				Expect(ts.Status.ObservedGeneration).To(Equal(int64(4)))
+			})
+		})
+
+		Context("CR conditions", func() {
+			var apiServer *operator.APIServer
+
+			BeforeEach(func() {
+				scheme := runtime.NewScheme()
+				Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+				apiServer = &operator.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure", Generation: 2}}
+				client = fake.NewFakeClientWithScheme(scheme, apiServer)
+				sm = New(client, "test-component", &common.VersionInfo{Major: 1, Minor: 19}).(*statusManager)
+				sm.OnCRFound(apiServer)
+			})
+
+			getConditions := func() []metav1.Condition {
+				cr := &operator.APIServer{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "tigera-secure"}, cr)).NotTo(HaveOccurred())
+				for i := range cr.Status.Conditions {
+					cr.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+				}
+				return cr.Status.Conditions
+			}
+
+			It("should report Ready, Progressing and Degraded with the rolled out generation", func() {
+				sm.SetObservedGeneration(2)
+				sm.ReadyToMonitor()
+				sm.updateStatus()
+
+				Expect(getConditions()).To(ConsistOf(
+					metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: "AllObjectsAvailable", Message: "All objects available"},
+					metav1.Condition{Type: "Progressing", Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: "AllObjectsRolledOut"},
+					metav1.Condition{Type: "Degraded", Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: "NotDegraded"},
+				))
+			})
+
+			It("should report the degraded reason and message", func() {
+				sm.SetDegraded("Error querying installation", "not found")
+				sm.updateStatus()
+
+				Expect(getConditions()).To(ConsistOf(
+					metav1.Condition{Type: "Degraded", Status: metav1.ConditionTrue, Reason: "ResourceDegraded", Message: "Error querying installation: not found"},
+				))
+
+				sm.ClearDegraded()
+				sm.SetObservedGeneration(2)
+				sm.ReadyToMonitor()
+				sm.updateStatus()
+				Expect(getConditions()).To(ContainElement(
+					metav1.Condition{Type: "Degraded", Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: "NotDegraded"},
+				))
+			})
+
+			It("should report the conditions on each of the CRs", func() {
+				gateways := []*operator.EgressGateway{
+					{ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "blue", Namespace: "app"}},
+				}
+				for _, gw := range gateways {
+					Expect(client.Create(ctx, gw)).NotTo(HaveOccurred())
+				}
+				sm.OnCRFound(gateways[0], gateways[1])
+				sm.SetDegraded("Error querying installation", "not found")
+				sm.updateStatus()
+
+				for _, gw := range gateways {
+					cr := &operator.EgressGateway{}
+					Expect(client.Get(ctx, types.NamespacedName{Name: gw.Name, Namespace: gw.Namespace}, cr)).NotTo(HaveOccurred())
+					Expect(cr.Status.Conditions).To(HaveLen(1))
+					Expect(cr.Status.Conditions[0].Type).To(Equal("Degraded"))
+					Expect(cr.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+				}
+				Expect(getConditions()).To(BeEmpty())
+			})
 		})
 	})
 })
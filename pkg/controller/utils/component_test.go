@@ -25,6 +25,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta "k8s.io/api/batch/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
@@ -79,6 +80,7 @@ var _ = Describe("Component handler tests", func() {
 		Expect(apps.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		Expect(batchv1beta.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		Expect(batchv1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(policyv1beta1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 
 		c = fake.NewFakeClientWithScheme(scheme)
 		ctx = context.Background()
@@ -215,6 +217,14 @@ var _ = Describe("Component handler tests", func() {
 		Expect(ns.GetAnnotations()).To(Equal(expectedAnnotations))
 	})
 
+	It("ignores objects to delete that do not exist", func() {
+		pdb := &policyv1beta1.PodDisruptionBudget{
+			TypeMeta:   metav1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1beta1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pdb", Namespace: "test-namespace"},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, &fakeComponent{objsToDelete: []client.Object{pdb}}, sm)).NotTo(HaveOccurred())
+	})
+
 	It("merges UISettings leaving owners unchanged", func() {
 		fc := &fakeComponent{
 			supportedOSType: rmeta.OSTypeLinux,
@@ -826,6 +836,7 @@ var _ = Describe("Component handler tests", func() {
 // A fake component that only returns ready and always creates the "test-namespace" Namespace.
 type fakeComponent struct {
 	objs            []client.Object
+	objsToDelete    []client.Object
 	supportedOSType rmeta.OSType
 }
 
@@ -838,7 +849,7 @@ func (c *fakeComponent) ResolveImages(is *operatorv1.ImageSet) error {
 }
 
 func (c *fakeComponent) Objects() ([]client.Object, []client.Object) {
-	return c.objs, nil
+	return c.objs, c.objsToDelete
 }
 
 func (c *fakeComponent) SupportedOSType() rmeta.OSType {
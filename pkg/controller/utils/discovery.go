@@ -57,6 +57,8 @@ func RequiresTigeraSecure(cfg *rest.Config) (bool, error) {
 			fallthrough
 		case "ApplicationLayer":
 			fallthrough
+		case "EgressGateway":
+			fallthrough
 		case "Monitor":
 			fallthrough
 		case "ManagementCluster":
@@ -89,6 +91,44 @@ func RequiresAmazonController(cfg *rest.Config) (bool, error) {
 	return false, nil
 }
 
+// SupportsPodSecurityPolicies determines if the cluster serves the PodSecurityPolicy API, which was
+// removed in Kubernetes v1.25.
+func SupportsPodSecurityPolicies(clientset kubernetes.Interface) (bool, error) {
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion("policy/v1beta1")
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// The whole group version has been removed.
+			return false, nil
+		}
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Kind == "PodSecurityPolicy" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SupportsPolicyV1PodDisruptionBudgets determines if the cluster serves PodDisruptionBudgets from the policy/v1 API,
+// which was added in Kubernetes v1.21.
+func SupportsPolicyV1PodDisruptionBudgets(clientset kubernetes.Interface) (bool, error) {
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion("policy/v1")
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// The group version is not served yet.
+			return false, nil
+		}
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Kind == "PodDisruptionBudget" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func AutoDiscoverProvider(ctx context.Context, clientset kubernetes.Interface) (operatorv1.Provider, error) {
 	// First, try to determine the platform based on the present API groups.
 	if platform, err := autodetectFromGroup(clientset); err != nil {
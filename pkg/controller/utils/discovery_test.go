@@ -85,3 +85,40 @@ var _ = Describe("provider discovery", func() {
 		Expect(p).To(Equal(operatorv1.ProviderEKS))
 	})
 })
+
+var _ = Describe("PodSecurityPolicy discovery", func() {
+	It("should detect the PodSecurityPolicy API when it is served", func() {
+		c := fake.NewSimpleClientset()
+		c.Resources = []*metav1.APIResourceList{{
+			GroupVersion: "policy/v1beta1",
+			APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}, {Name: "podsecuritypolicies", Kind: "PodSecurityPolicy"}},
+		}}
+		Expect(SupportsPodSecurityPolicies(c)).To(BeTrue())
+	})
+
+	It("should not detect the PodSecurityPolicy API once it has been removed", func() {
+		c := fake.NewSimpleClientset()
+		c.Resources = []*metav1.APIResourceList{{
+			GroupVersion: "policy/v1beta1",
+			APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}},
+		}}
+		Expect(SupportsPodSecurityPolicies(c)).To(BeFalse())
+	})
+})
+
+var _ = Describe("PodDisruptionBudget discovery", func() {
+	It("should detect the policy/v1 PodDisruptionBudget API when it is served", func() {
+		c := fake.NewSimpleClientset()
+		c.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "policy/v1beta1",
+				APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}},
+			},
+			{
+				GroupVersion: "policy/v1",
+				APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}},
+			},
+		}
+		Expect(SupportsPolicyV1PodDisruptionBudgets(c)).To(BeTrue())
+	})
+})
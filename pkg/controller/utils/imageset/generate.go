@@ -0,0 +1,210 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
+)
+
+const (
+	// OCILayoutSourcePrefix is the prefix of an ImageSet source that refers to a local
+	// OCI image layout directory.
+	OCILayoutSourcePrefix = "oci:"
+
+	ociIndexFile             = "index.json"
+	ociRefNameAnnotation     = "org.opencontainers.image.ref.name"
+	containerdNameAnnotation = "io.containerd.image.name"
+)
+
+// DigestSource looks up image digests.
+type DigestSource interface {
+	// Digest returns the digest of the image with the given name and tag,
+	// for example calico/node and v3.21.0.
+	Digest(image, tag string) (string, error)
+}
+
+// NewDigestSource returns the DigestSource for the given location. A location prefixed with
+// "oci:" is read as a local OCI image layout directory, anything else is treated as a registry
+// mirror in the same format as the Installation registry field, e.g. "myregistry.com/mirror/".
+// A registry mirror is accessed over https unless the location is prefixed with "http://".
+func NewDigestSource(location string) (DigestSource, error) {
+	if strings.HasPrefix(location, OCILayoutSourcePrefix) {
+		return newOCILayoutSource(strings.TrimPrefix(location, OCILayoutSourcePrefix))
+	}
+	return newRegistrySource(location)
+}
+
+// GenerateImageSet builds the ImageSet for the given variant using the digests from src.
+// The generated ImageSet is named so that the running operator will use it.
+func GenerateImageSet(v operator.ProductVariant, src DigestSource) (*operator.ImageSet, error) {
+	is := &operator.ImageSet{
+		TypeMeta:   metav1.TypeMeta{Kind: "ImageSet", APIVersion: "operator.tigera.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: getSetName(v)},
+	}
+
+	missing := []string{}
+	for _, c := range variantComponents(v) {
+		digest, err := src.Digest(c.Image, c.Version)
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("%s:%s (%v)", c.Image, c.Version, err))
+			continue
+		}
+		is.Spec.Images = append(is.Spec.Images, operator.Image{Image: c.Image, Digest: digest})
+	}
+
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("failed to get digests for images: %s", strings.Join(missing, ", "))
+	}
+
+	if err := ValidateImageSet(is); err != nil {
+		return nil, err
+	}
+	return is, nil
+}
+
+// variantImage is the name and tag of an image deployed for a variant.
+type variantImage struct {
+	Image   string
+	Version string
+}
+
+// variantComponents returns the images the operator deploys for the given variant.
+func variantComponents(v operator.ProductVariant) []variantImage {
+	imgs := []variantImage{}
+	if v == operator.TigeraSecureEnterprise {
+		for _, c := range components.EnterpriseComponents {
+			imgs = append(imgs, variantImage{c.Image, c.Version})
+		}
+		// Enterprise installs still deploy these images from the Calico components.
+		imgs = append(imgs,
+			variantImage{components.ComponentFlexVolume.Image, components.ComponentFlexVolume.Version},
+			variantImage{components.ComponentOperatorInit.Image, components.ComponentOperatorInit.Version},
+		)
+	} else {
+		for _, c := range components.CalicoComponents {
+			imgs = append(imgs, variantImage{c.Image, c.Version})
+		}
+	}
+	for _, c := range components.CommonComponents {
+		imgs = append(imgs, variantImage{c.Image, c.Version})
+	}
+	return imgs
+}
+
+// ociLayoutSource reads digests from the index of a local OCI image layout directory, as
+// written by tools such as skopeo or crane.
+type ociLayoutSource struct {
+	// refs maps the image references found in the layout index to their digests.
+	refs map[string]string
+}
+
+type ociIndex struct {
+	Manifests []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"manifests"`
+}
+
+func newOCILayoutSource(dir string) (*ociLayoutSource, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, ociIndexFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OCI layout %s: %v", dir, err)
+	}
+
+	idx := ociIndex{}
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse OCI layout index %s: %v", filepath.Join(dir, ociIndexFile), err)
+	}
+
+	src := &ociLayoutSource{refs: map[string]string{}}
+	for _, m := range idx.Manifests {
+		for _, a := range []string{ociRefNameAnnotation, containerdNameAnnotation} {
+			if ref, ok := m.Annotations[a]; ok && ref != "" {
+				src.refs[ref] = m.Digest
+			}
+		}
+	}
+	return src, nil
+}
+
+func (s *ociLayoutSource) Digest(image, tag string) (string, error) {
+	name := fmt.Sprintf("%s:%s", image, tag)
+	if d, ok := s.refs[name]; ok {
+		return d, nil
+	}
+	// The reference may also include the registry the image was copied from. The layout may hold
+	// the image from more than one registry, in which case it is ambiguous unless they agree.
+	var matches []string
+	for ref := range s.refs {
+		if strings.HasSuffix(ref, "/"+name) {
+			matches = append(matches, ref)
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("not found in OCI layout")
+	}
+	sort.Strings(matches)
+	d := s.refs[matches[0]]
+	for _, ref := range matches[1:] {
+		if s.refs[ref] != d {
+			return "", fmt.Errorf("ambiguous in OCI layout, found with different digests as %s", strings.Join(matches, ", "))
+		}
+	}
+	return d, nil
+}
+
+// registrySource reads digests from a registry mirror.
+type registrySource struct {
+	registry *registryClient
+	// path is the path prefix the images are mirrored under, if any.
+	path string
+}
+
+func newRegistrySource(location string) (*registrySource, error) {
+	scheme := "https"
+	if strings.HasPrefix(location, "http://") {
+		scheme = "http"
+	}
+	location = strings.TrimPrefix(strings.TrimPrefix(location, "http://"), "https://")
+	location = strings.TrimSuffix(location, "/")
+	if location == "" {
+		return nil, fmt.Errorf("a registry or OCI layout must be specified")
+	}
+
+	host, path := location, ""
+	if i := strings.Index(location, "/"); i != -1 {
+		host = location[:i]
+		path = location[i+1:]
+	}
+	return &registrySource{registry: newRegistryClient(scheme, host), path: path}, nil
+}
+
+func (s *registrySource) Digest(image, tag string) (string, error) {
+	repo := image
+	if s.path != "" {
+		repo = fmt.Sprintf("%s/%s", s.path, image)
+	}
+	return s.registry.manifestDigest(repo, tag)
+}
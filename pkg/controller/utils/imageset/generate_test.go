@@ -0,0 +1,212 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// digestFor returns a fake, but stable, digest for the given image.
+func digestFor(image string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(image)))
+}
+
+var _ = Describe("imageset generation", func() {
+	Context("from an OCI layout", func() {
+		var dir string
+
+		writeLayout := func(refs map[string]string) {
+			idx := ociIndex{}
+			for ref, digest := range refs {
+				idx.Manifests = append(idx.Manifests, struct {
+					Digest      string            `json:"digest"`
+					Annotations map[string]string `json:"annotations"`
+				}{Digest: digest, Annotations: map[string]string{ociRefNameAnnotation: ref}})
+			}
+			b, err := json.Marshal(idx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(dir, ociIndexFile), b, 0644)).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "oci-layout")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).NotTo(HaveOccurred())
+		})
+
+		It("should generate a valid ImageSet for Calico", func() {
+			refs := map[string]string{}
+			for _, c := range variantComponents(operator.Calico) {
+				// Include the source registry in some of the references.
+				if strings.HasPrefix(c.Image, "calico/") {
+					refs[fmt.Sprintf("docker.io/%s:%s", c.Image, c.Version)] = digestFor(c.Image)
+				} else {
+					refs[fmt.Sprintf("%s:%s", c.Image, c.Version)] = digestFor(c.Image)
+				}
+			}
+			writeLayout(refs)
+
+			src, err := NewDigestSource(OCILayoutSourcePrefix + dir)
+			Expect(err).NotTo(HaveOccurred())
+			is, err := GenerateImageSet(operator.Calico, src)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(is.Name).To(Equal(fmt.Sprintf("calico-%s", components.CalicoRelease)))
+			Expect(is.Kind).To(Equal("ImageSet"))
+			Expect(is.Spec.Images).To(HaveLen(len(components.CalicoComponents) + len(components.CommonComponents)))
+			Expect(is.Spec.Images).To(ContainElement(operator.Image{Image: "calico/node", Digest: digestFor("calico/node")}))
+			Expect(is.Spec.Images).To(ContainElement(operator.Image{Image: "tigera/operator", Digest: digestFor("tigera/operator")}))
+
+			By("resolving the images of the VPP dataplane from the generated ImageSet")
+			vpp := operator.LinuxDataplaneVPP
+			vppComponent := render.VPP(&render.VPPConfiguration{Installation: &operator.InstallationSpec{
+				CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &vpp, VPP: &operator.VPPSpec{}},
+			}})
+			Expect(vppComponent.ResolveImages(is)).NotTo(HaveOccurred())
+		})
+
+		It("should report the images missing from the layout", func() {
+			writeLayout(map[string]string{
+				fmt.Sprintf("calico/node:%s", components.ComponentCalicoNode.Version): digestFor("calico/node"),
+			})
+
+			src, err := NewDigestSource(OCILayoutSourcePrefix + dir)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = GenerateImageSet(operator.Calico, src)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("calico/cni:%s", components.ComponentCalicoCNI.Version)))
+			Expect(err.Error()).NotTo(ContainSubstring("calico/node:"))
+		})
+
+		It("should match the references of an image that include a registry consistently", func() {
+			name := fmt.Sprintf("calico/node:%s", components.ComponentCalicoNode.Version)
+			writeLayout(map[string]string{
+				"docker.io/" + name: digestFor("calico/node"),
+				"quay.io/" + name:   digestFor("calico/node"),
+			})
+			src, err := NewDigestSource(OCILayoutSourcePrefix + dir)
+			Expect(err).NotTo(HaveOccurred())
+			d, err := src.Digest("calico/node", components.ComponentCalicoNode.Version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d).To(Equal(digestFor("calico/node")))
+
+			By("rejecting references whose digests differ")
+			writeLayout(map[string]string{
+				"docker.io/" + name: digestFor("calico/node"),
+				"quay.io/" + name:   digestFor("other/node"),
+			})
+			src, err = NewDigestSource(OCILayoutSourcePrefix + dir)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = src.Digest("calico/node", components.ComponentCalicoNode.Version)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("ambiguous"))
+		})
+
+		It("should error if the directory is not an OCI layout", func() {
+			_, err := NewDigestSource(OCILayoutSourcePrefix + filepath.Join(dir, "missing"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("from a registry mirror", func() {
+		var server *httptest.Server
+		var requireToken bool
+		var omitDigestHeader bool
+
+		BeforeEach(func() {
+			requireToken = false
+			omitDigestHeader = false
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				if r.URL.Path == "/token" {
+					Expect(r.URL.Query().Get("service")).To(Equal("test-registry"))
+					Expect(r.URL.Query().Get("scope")).To(HavePrefix("repository:mirror/"))
+					_, _ = w.Write([]byte(`{"token":"test-token"}`))
+					return
+				}
+				if requireToken && r.Header.Get("Authorization") != "Bearer test-token" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="test-registry"`, r.Host))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				Expect(r.Header.Get("Accept")).To(ContainSubstring("application/vnd.oci.image.index.v1+json"))
+
+				// Serve /v2/mirror/<image>/manifests/<tag>
+				path := strings.TrimPrefix(r.URL.Path, "/v2/mirror/")
+				parts := strings.Split(path, "/manifests/")
+				if len(parts) != 2 {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				manifest := []byte(fmt.Sprintf(`{"image":"%s"}`, parts[0]))
+				if !omitDigestHeader {
+					w.Header().Set("Docker-Content-Digest", fmt.Sprintf("sha256:%x", sha256.Sum256(manifest)))
+				}
+				if r.Method == http.MethodGet {
+					_, _ = w.Write(manifest)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		expectImageSet := func(v operator.ProductVariant) {
+			src, err := NewDigestSource(server.URL + "/mirror/")
+			Expect(err).NotTo(HaveOccurred())
+			is, err := GenerateImageSet(v, src)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(is.Name).To(Equal(getSetName(v)))
+			for _, c := range variantComponents(v) {
+				manifest := []byte(fmt.Sprintf(`{"image":"%s"}`, c.Image))
+				Expect(is.Spec.Images).To(ContainElement(operator.Image{Image: c.Image, Digest: fmt.Sprintf("sha256:%x", sha256.Sum256(manifest))}))
+			}
+		}
+
+		It("should generate a valid ImageSet for Enterprise", func() {
+			expectImageSet(operator.TigeraSecureEnterprise)
+		})
+
+		It("should use an anonymous token when the registry requires one", func() {
+			requireToken = true
+			expectImageSet(operator.Calico)
+		})
+
+		It("should compute the digest when the registry does not return it", func() {
+			omitDigestHeader = true
+			expectImageSet(operator.Calico)
+		})
+	})
+})
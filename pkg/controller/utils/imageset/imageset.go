@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operator "github.com/tigera/operator/api/v1"
@@ -30,8 +32,9 @@ import (
 )
 
 // ApplyImageSet gets the appropriate ImageSet, validates the ImageSet, and calls ResolveImages
-// passing in the ImageSet on each of the comps.
-func ApplyImageSet(ctx context.Context, c client.Client, v operator.ProductVariant, comps ...render.Component) error {
+// passing in the ImageSet on each of the comps. If image verification is configured on the
+// Installation, the signatures of the resolved images are then verified.
+func ApplyImageSet(ctx context.Context, c client.Client, v operator.ProductVariant, install *operator.InstallationSpec, comps ...render.Component) error {
 	imageSet, err := GetImageSet(ctx, c, v)
 	if err != nil {
 		return err
@@ -41,12 +44,20 @@ func ApplyImageSet(ctx context.Context, c client.Client, v operator.ProductVaria
 		return err
 	}
 
-	return ResolveImages(imageSet, comps...)
+	if err = ResolveImages(imageSet, comps...); err != nil {
+		return err
+	}
+
+	return VerifyImages(ctx, c, install, imageSet, comps...)
 }
 
-// Utility function to add a watch on ImageSet resources.
+// Utility function to add a watch on ImageSet resources. The image verification keys Secret
+// is also watched since it affects the same images.
 func AddImageSetWatch(c controller.Controller) error {
-	return c.Watch(&source.Kind{Type: &operator.ImageSet{}}, &handler.EnqueueRequestForObject{})
+	if err := c.Watch(&source.Kind{Type: &operator.ImageSet{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}, predicate.NewPredicateFuncs(isKeysSecret))
 }
 
 func getSetName(v operator.ProductVariant) string {
@@ -85,7 +96,7 @@ func GetImageSet(ctx context.Context, cli client.Client, v operator.ProductVaria
 }
 
 // ValidateImageSet validates that all the images in an ImageSet are images the operator uses
-// and that the Digest is in an allowed format.
+// and that the Digest and any per-image registry or repository overrides are in an allowed format.
 func ValidateImageSet(is *operator.ImageSet) error {
 	// None is valid
 	if is == nil {
@@ -131,7 +142,17 @@ func ValidateImageSet(is *operator.ImageSet) error {
 		}
 	}
 
-	if len(unknownImages) == 0 && len(invalidDigests) == 0 {
+	invalidOverrides := []string{}
+	for _, img := range is.Spec.Images {
+		if img.Registry != "" && !strings.HasSuffix(img.Registry, "/") {
+			invalidOverrides = append(invalidOverrides, fmt.Sprintf("%s registry %s must end with a slash", img.Image, img.Registry))
+		}
+		if strings.ContainsAny(img.Repository, "@:") || strings.HasPrefix(img.Repository, "/") || strings.HasSuffix(img.Repository, "/") {
+			invalidOverrides = append(invalidOverrides, fmt.Sprintf("%s repository %s must not include a registry, tag or digest", img.Image, img.Repository))
+		}
+	}
+
+	if len(unknownImages) == 0 && len(invalidDigests) == 0 && len(invalidOverrides) == 0 {
 		return nil
 	}
 
@@ -144,6 +165,10 @@ func ValidateImageSet(is *operator.ImageSet) error {
 	if len(invalidDigests) != 0 {
 		errMsgs = append(errMsgs, fmt.Sprintf("bad digest images: %s", strings.Join(invalidDigests, ", ")))
 	}
+
+	if len(invalidOverrides) != 0 {
+		errMsgs = append(errMsgs, fmt.Sprintf("bad image overrides: %s", strings.Join(invalidOverrides, ", ")))
+	}
 	return fmt.Errorf("ImageSet %s: %s", is.Name, strings.Join(errMsgs, "; "))
 }
 
@@ -44,11 +44,11 @@ var _ = Describe("imageset tests", func() {
 			c = fake.NewFakeClientWithScheme(kscheme.Scheme)
 		})
 		It("should not error for Calico", func() {
-			e := ApplyImageSet(context.Background(), c, operator.Calico)
+			e := ApplyImageSet(context.Background(), c, operator.Calico, nil)
 			Expect(e).To(BeNil())
 		})
 		It("should not error for Enterprise", func() {
-			e := ApplyImageSet(context.Background(), c, operator.TigeraSecureEnterprise)
+			e := ApplyImageSet(context.Background(), c, operator.TigeraSecureEnterprise, nil)
 			Expect(e).To(BeNil())
 		})
 	})
@@ -73,7 +73,7 @@ var _ = Describe("imageset tests", func() {
 					},
 				},
 			)
-			Expect(ApplyImageSet(context.Background(), c, v)).To(BeNil())
+			Expect(ApplyImageSet(context.Background(), c, v, nil)).To(BeNil())
 			c = fake.NewFakeClientWithScheme(kscheme.Scheme,
 				&operator.ImageSet{
 					ObjectMeta: metav1.ObjectMeta{
@@ -89,7 +89,7 @@ var _ = Describe("imageset tests", func() {
 					},
 				},
 			)
-			err := ApplyImageSet(context.Background(), c, v)
+			err := ApplyImageSet(context.Background(), c, v, nil)
 			Expect(err).ToNot(BeNil())
 			Expect(err.Error()).To(ContainSubstring("unexpected images"))
 			c = fake.NewFakeClientWithScheme(kscheme.Scheme,
@@ -104,9 +104,40 @@ var _ = Describe("imageset tests", func() {
 					},
 				},
 			)
-			err = ApplyImageSet(context.Background(), c, v)
+			err = ApplyImageSet(context.Background(), c, v, nil)
 			Expect(err).ToNot(BeNil())
 			Expect(err.Error()).To(ContainSubstring("bad digest images"))
+			c = fake.NewFakeClientWithScheme(kscheme.Scheme,
+				&operator.ImageSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nm,
+					},
+					Spec: operator.ImageSetSpec{
+						Images: []operator.Image{
+							{Image: "calico/cni", Digest: "sha256:xxxxxxxxx", Registry: "mirror.io/", Repository: "mirror/cni"},
+						},
+					},
+				},
+			)
+			Expect(ApplyImageSet(context.Background(), c, v, nil)).To(BeNil())
+			c = fake.NewFakeClientWithScheme(kscheme.Scheme,
+				&operator.ImageSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nm,
+					},
+					Spec: operator.ImageSetSpec{
+						Images: []operator.Image{
+							{Image: "calico/cni", Digest: "sha256:xxxxxxxxx", Registry: "mirror.io"},
+							{Image: "calico/typha", Digest: "sha256:xxxxxxxxx", Repository: "mirror/typha:v1"},
+						},
+					},
+				},
+			)
+			err = ApplyImageSet(context.Background(), c, v, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("bad image overrides"))
+			Expect(err.Error()).To(ContainSubstring("calico/cni registry mirror.io must end with a slash"))
+			Expect(err.Error()).To(ContainSubstring("calico/typha repository mirror/typha:v1"))
 		},
 			Entry("Calico variant", operator.Calico),
 			Entry("Enterprise variant", operator.TigeraSecureEnterprise),
@@ -0,0 +1,254 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// manifestMediaTypes are the manifest types accepted when querying a registry for a manifest.
+// Manifest lists and indexes are preferred so that digests cover every platform.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// errNotFound is returned when the registry does not have the requested manifest or blob.
+var errNotFound = errors.New("not found")
+
+// registryClient is a minimal, read only, client for registries implementing the Docker
+// registry HTTP API V2. Only anonymous access is supported.
+type registryClient struct {
+	// scheme is the scheme used to access the registry.
+	scheme string
+	// host is the registry host (and port).
+	host string
+	// tokens holds the anonymous pull tokens for each repository.
+	tokens     map[string]string
+	tokensLock sync.Mutex
+	client     *http.Client
+}
+
+// registryClients holds the clients used to verify images, keyed by registry host, so that
+// connections and pull tokens are reused across reconciles.
+var registryClients = struct {
+	sync.Mutex
+	m map[string]*registryClient
+}{m: map[string]*registryClient{}}
+
+func newRegistryClient(scheme, host string) *registryClient {
+	if host == "docker.io" {
+		// Docker Hub does not serve the registry API on the docker.io hostname.
+		host = "registry-1.docker.io"
+	}
+	return &registryClient{
+		scheme: scheme,
+		host:   host,
+		tokens: map[string]string{},
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// registryClientForHost returns the client for the registry host of an image reference.
+// Like the container runtimes, plain http is only used for registries on the loopback address.
+func registryClientForHost(host string) *registryClient {
+	registryClients.Lock()
+	defer registryClients.Unlock()
+	if r, ok := registryClients.m[host]; ok {
+		return r
+	}
+
+	scheme := "https"
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if ip := net.ParseIP(hostname); hostname == "localhost" || (ip != nil && ip.IsLoopback()) {
+		scheme = "http"
+	}
+	r := newRegistryClient(scheme, host)
+	registryClients.m[host] = r
+	return r
+}
+
+// manifestDigest returns the digest of the manifest for ref, a tag or digest, in repo.
+func (r *registryClient) manifestDigest(repo, ref string) (string, error) {
+	resp, err := r.do(http.MethodHead, repo, "manifests/"+ref)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if d := resp.Header.Get("Docker-Content-Digest"); d != "" {
+		return d, nil
+	}
+
+	// Not all registries return the digest header, in which case the digest is computed
+	// from the manifest itself.
+	b, err := r.manifest(repo, ref)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b)), nil
+}
+
+// manifest returns the manifest for ref, a tag or digest, in repo.
+func (r *registryClient) manifest(repo, ref string) ([]byte, error) {
+	resp, err := r.do(http.MethodGet, repo, "manifests/"+ref)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s:%s: %v", repo, ref, err)
+	}
+	return b, nil
+}
+
+// blob returns the content of the blob with the given digest in repo. The content is checked
+// against the digest.
+func (r *registryClient) blob(repo, digest string) ([]byte, error) {
+	resp, err := r.do(http.MethodGet, repo, "blobs/"+digest)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s@%s: %v", repo, digest, err)
+	}
+	if d := fmt.Sprintf("sha256:%x", sha256.Sum256(b)); d != digest {
+		return nil, fmt.Errorf("blob %s@%s has unexpected digest %s", repo, digest, d)
+	}
+	return b, nil
+}
+
+// do sends a request for path under the repo API path and returns the response if it was successful.
+func (r *registryClient) do(method, repo, path string) (*http.Response, error) {
+	url := fmt.Sprintf("%s://%s/v2/%s/%s", r.scheme, r.host, repo, path)
+
+	r.tokensLock.Lock()
+	token := r.tokens[repo]
+	r.tokensLock.Unlock()
+
+	resp, err := r.send(method, url, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// Registries like docker.io require a token even for anonymous pulls.
+		resp.Body.Close()
+		token, err := r.anonymousToken(resp.Header.Get("WWW-Authenticate"), repo)
+		if err != nil {
+			return nil, err
+		}
+		r.tokensLock.Lock()
+		r.tokens[repo] = token
+		r.tokensLock.Unlock()
+		if resp, err = r.send(method, url, token); err != nil {
+			return nil, err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %w", url, errNotFound)
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status querying %s: %s", url, resp.Status)
+	}
+}
+
+func (r *registryClient) send(method, url, token string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %v", url, err)
+	}
+	return resp, nil
+}
+
+// anonymousToken requests a pull token for repo from the token service described by the
+// WWW-Authenticate challenge of the registry.
+func (r *registryClient) anonymousToken(challenge, repo string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("registry %s requires unsupported authentication %q", r.host, challenge)
+	}
+	params := map[string]string{}
+	for _, p := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("registry %s authentication challenge has no realm", r.host)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, realm, nil)
+	if err != nil {
+		return "", err
+	}
+	q := req.URL.Query()
+	if svc, ok := params["service"]; ok {
+		q.Set("service", svc)
+	}
+	q.Set("scope", fmt.Sprintf("repository:%s:pull", repo))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token from %s: %v", realm, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status getting token from %s: %s", realm, resp.Status)
+	}
+
+	tr := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", fmt.Errorf("failed to parse token from %s: %v", realm, err)
+	}
+	if tr.Token != "" {
+		return tr.Token, nil
+	}
+	return tr.AccessToken, nil
+}
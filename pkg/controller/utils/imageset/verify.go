@@ -0,0 +1,389 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	// cosignSignatureAnnotation is the annotation on a signature layer holding the base64
+	// encoded signature of the layer content.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// verifiedDigests records the digests of the image references that have already been verified
+// against the keys Secret, so that the registry is not queried on every reconcile. A verified tag
+// keeps resolving to the digest that was verified, even if it is later moved. The records are
+// dropped whenever the keys Secret changes, so that rotating or revoking a key verifies every
+// image again.
+var verifiedDigests = struct {
+	sync.Mutex
+	// keysVersion identifies the keys Secret, and its resourceVersion, the records were verified with.
+	keysVersion string
+	m           map[string]string
+}{m: map[string]string{}}
+
+// keysSecretName is the name of the image verification keys Secret most recently configured
+// on the Installation. Changes to that Secret trigger reconciles of the controllers that
+// watch ImageSets.
+var keysSecretName = struct {
+	sync.RWMutex
+	name string
+}{}
+
+// VerifyImages verifies the signatures of all the images the given components deploy when
+// image verification is configured on the Installation. The images must already be resolved
+// with the ImageSet is, which may be nil. Once verified, the components are resolved again so
+// that they deploy the verified digests rather than tags.
+func VerifyImages(ctx context.Context, cli client.Client, install *operator.InstallationSpec, is *operator.ImageSet, comps ...render.Component) error {
+	if install == nil || install.ImageVerification == nil {
+		return nil
+	}
+
+	name := install.ImageVerification.PublicKeysSecretName
+	keysSecretName.Lock()
+	keysSecretName.name = name
+	keysSecretName.Unlock()
+
+	secret, err := utils.GetSecret(ctx, cli, name, common.OperatorNamespace())
+	if err != nil {
+		return fmt.Errorf("failed to get image verification keys secret %s: %v", name, err)
+	}
+	if secret == nil {
+		return fmt.Errorf("image verification keys secret %s not found in namespace %s", name, common.OperatorNamespace())
+	}
+	keys, err := parsePublicKeys(secret)
+	if err != nil {
+		return fmt.Errorf("invalid image verification keys secret %s: %v", name, err)
+	}
+	resetVerifiedDigests(fmt.Sprintf("%s/%s", secret.Name, secret.ResourceVersion))
+
+	digests := map[string]string{}
+	failed := []string{}
+	for _, img := range componentImages(comps...) {
+		digest, err := verifyImage(img, keys)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", img, err))
+			continue
+		}
+		digests[img] = digest
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("image signature verification failed: %s", strings.Join(failed, ", "))
+	}
+
+	if err := ResolveImages(pinnedImageSet(install, is, digests), comps...); err != nil {
+		return err
+	}
+	unpinned := []string{}
+	for _, img := range componentImages(comps...) {
+		if !strings.Contains(img, "@") {
+			unpinned = append(unpinned, img)
+		}
+	}
+	if len(unpinned) != 0 {
+		return fmt.Errorf("images could not be pinned to their verified digests: %s", strings.Join(unpinned, ", "))
+	}
+	return nil
+}
+
+// resetVerifiedDigests drops the verified digests unless they were verified with the given version
+// of the keys Secret.
+func resetVerifiedDigests(keysVersion string) {
+	verifiedDigests.Lock()
+	defer verifiedDigests.Unlock()
+	if verifiedDigests.keysVersion != keysVersion {
+		verifiedDigests.keysVersion = keysVersion
+		verifiedDigests.m = map[string]string{}
+	}
+}
+
+// isKeysSecret returns whether the object is the image verification keys Secret.
+func isKeysSecret(obj client.Object) bool {
+	keysSecretName.RLock()
+	defer keysSecretName.RUnlock()
+	return keysSecretName.name != "" && obj.GetNamespace() == common.OperatorNamespace() && obj.GetName() == keysSecretName.name
+}
+
+// pinnedImageSet returns an ImageSet resolving the images in digests, references resolved with
+// the Installation and is, to their verified digests. Other images resolve as they do with is.
+func pinnedImageSet(install *operator.InstallationSpec, is *operator.ImageSet, digests map[string]string) *operator.ImageSet {
+	pinned := map[string]operator.Image{}
+	pin := func(image, ref string, err error) {
+		if err != nil {
+			// The image is not in the ImageSet, so no component can resolve it.
+			return
+		}
+		digest, ok := digests[ref]
+		if !ok {
+			if _, ok := pinned[image]; !ok {
+				pinned[image] = imageSetEntry(is, image)
+			}
+			return
+		}
+		host, repo, _, _, _ := parseReference(ref)
+		pinned[image] = operator.Image{Image: image, Registry: host + "/", Repository: repo, Digest: digest}
+	}
+	for _, c := range components.CalicoComponents {
+		ref, err := components.GetReference(c, install.Registry, install.ImagePath, install.ImagePrefix, is)
+		pin(c.Image, ref, err)
+	}
+	for _, c := range components.EnterpriseComponents {
+		ref, err := components.GetReference(c, install.Registry, install.ImagePath, install.ImagePrefix, is)
+		pin(c.Image, ref, err)
+	}
+	for _, c := range components.CommonComponents {
+		ref, err := components.GetReference(c, install.Registry, install.ImagePath, install.ImagePrefix, is)
+		pin(c.Image, ref, err)
+	}
+
+	out := &operator.ImageSet{}
+	if is != nil {
+		out.ObjectMeta = is.ObjectMeta
+	}
+	for _, img := range pinned {
+		out.Spec.Images = append(out.Spec.Images, img)
+	}
+	sort.Slice(out.Spec.Images, func(i, j int) bool { return out.Spec.Images[i].Image < out.Spec.Images[j].Image })
+	return out
+}
+
+// imageSetEntry returns the entry for image in is. Without an ImageSet the entry has no digest
+// so that the image keeps its version tag.
+func imageSetEntry(is *operator.ImageSet, image string) operator.Image {
+	if is != nil {
+		for _, img := range is.Spec.Images {
+			if img.Image == image {
+				return img
+			}
+		}
+	}
+	return operator.Image{Image: image}
+}
+
+// parsePublicKeys parses the PEM encoded public keys in the secret.
+func parsePublicKeys(secret *corev1.Secret) ([]crypto.PublicKey, error) {
+	names := []string{}
+	for k := range secret.Data {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	keys := []crypto.PublicKey{}
+	for _, n := range names {
+		block, _ := pem.Decode(secret.Data[n])
+		if block == nil {
+			return nil, fmt.Errorf("%s does not contain PEM data", n)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s does not contain a valid public key: %v", n, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found")
+	}
+	return keys, nil
+}
+
+// componentImages returns the images of the workloads rendered by the given components.
+func componentImages(comps ...render.Component) []string {
+	images := map[string]bool{}
+	addPodSpec := func(spec corev1.PodSpec) {
+		for _, c := range spec.InitContainers {
+			images[c.Image] = true
+		}
+		for _, c := range spec.Containers {
+			images[c.Image] = true
+		}
+	}
+
+	for _, comp := range comps {
+		objs, _ := comp.Objects()
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *appsv1.DaemonSet:
+				addPodSpec(o.Spec.Template.Spec)
+			case *appsv1.Deployment:
+				addPodSpec(o.Spec.Template.Spec)
+			case *appsv1.StatefulSet:
+				addPodSpec(o.Spec.Template.Spec)
+			case *batchv1.Job:
+				addPodSpec(o.Spec.Template.Spec)
+			case *batchv1beta1.CronJob:
+				addPodSpec(o.Spec.JobTemplate.Spec.Template.Spec)
+			case *corev1.Pod:
+				addPodSpec(o.Spec)
+			case *corev1.PodTemplate:
+				addPodSpec(o.Template.Spec)
+			}
+		}
+	}
+
+	out := []string{}
+	for img := range images {
+		if img != "" {
+			out = append(out, img)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// verifyImage checks that the image has a cosign signature, stored in the registry of the image,
+// that verifies against one of the keys. It returns the digest of the verified image.
+func verifyImage(image string, keys []crypto.PublicKey) (string, error) {
+	verifiedDigests.Lock()
+	digest, verified := verifiedDigests.m[image]
+	verifiedDigests.Unlock()
+	if verified {
+		return digest, nil
+	}
+
+	host, repo, tag, digest, err := parseReference(image)
+	if err != nil {
+		return "", err
+	}
+	registry := registryClientForHost(host)
+	if digest == "" {
+		if digest, err = registry.manifestDigest(repo, tag); err != nil {
+			return "", err
+		}
+	}
+
+	// Cosign stores the signatures of an image under a tag derived from its digest.
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	b, err := registry.manifest(repo, sigTag)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			return "", fmt.Errorf("no signatures found")
+		}
+		return "", err
+	}
+
+	manifest := struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}{}
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse signature manifest: %v", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		sig, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := registry.blob(repo, layer.Digest)
+		if err != nil {
+			return "", err
+		}
+		if verifySignature(payload, sig, digest, keys) {
+			verifiedDigests.Lock()
+			verifiedDigests.m[image] = digest
+			verifiedDigests.Unlock()
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("no signature verified with the configured keys")
+}
+
+// verifySignature returns true if the payload is a signature payload for the digest and the
+// base64 encoded signature of the payload verifies against one of the keys.
+func verifySignature(payload []byte, signature, digest string, keys []crypto.PublicKey) bool {
+	p := struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}{}
+	if err := json.Unmarshal(payload, &p); err != nil || p.Critical.Image.DockerManifestDigest != digest {
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	h := sha256.Sum256(payload)
+	for _, key := range keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, h[:], sig) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, sig) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseReference splits an image reference of the form <host>/<repository>[:<tag>][@<digest>].
+func parseReference(image string) (host, repo, tag, digest string, err error) {
+	name := image
+	if i := strings.Index(name, "@"); i != -1 {
+		name, digest = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i+1:]
+	}
+	i := strings.Index(name, "/")
+	if i == -1 {
+		return "", "", "", "", fmt.Errorf("reference does not include a registry")
+	}
+	host, repo = name[:i], name[i+1:]
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+	return host, repo, tag, digest, nil
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// testRegistry is a registry serving images and their cosign signatures.
+type testRegistry struct {
+	// manifests maps <repo>/manifests/<ref> to manifest content.
+	manifests map[string][]byte
+	// blobs maps <repo>/blobs/<digest> to blob content.
+	blobs map[string][]byte
+}
+
+func (t *testRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	content, ok := t.manifests[path]
+	if !ok {
+		content, ok = t.blobs[path]
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", fmt.Sprintf("sha256:%x", sha256.Sum256(content)))
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(content)
+	}
+}
+
+// addImage adds an image to the registry and returns its digest.
+func (t *testRegistry) addImage(repo, tag string) string {
+	manifest := []byte(fmt.Sprintf(`{"image":"%s:%s"}`, repo, tag))
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(manifest))
+	t.manifests[fmt.Sprintf("%s/manifests/%s", repo, tag)] = manifest
+	t.manifests[fmt.Sprintf("%s/manifests/%s", repo, digest)] = manifest
+	return digest
+}
+
+// sign adds a cosign signature for the digest signed by key, with a payload for payloadDigest.
+func (t *testRegistry) sign(repo, digest, payloadDigest string, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, repo, payloadDigest))
+	payloadDigestHash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, payloadDigestHash[:])
+	Expect(err).NotTo(HaveOccurred())
+
+	layerDigest := fmt.Sprintf("sha256:%x", payloadDigestHash)
+	t.blobs[fmt.Sprintf("%s/blobs/%s", repo, layerDigest)] = payload
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]interface{}{{
+			"mediaType":   "application/vnd.dev.cosign.simplesigning.v1+json",
+			"digest":      layerDigest,
+			"annotations": map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+		}},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	t.manifests[fmt.Sprintf("%s/manifests/%s.sig", repo, strings.Replace(digest, ":", "-", 1))] = manifest
+}
+
+func publicKeyPEM(key *ecdsa.PrivateKey) []byte {
+	b, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b})
+}
+
+func deploymentWithImages(images ...string) *appsv1.Deployment {
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	for i, img := range images {
+		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: fmt.Sprintf("c%d", i), Image: img})
+	}
+	return d
+}
+
+// imageComponent deploys calico/node and calico/cni, resolving them like the rendered components.
+type imageComponent struct {
+	install   *operator.InstallationSpec
+	nodeImage string
+	cniImage  string
+}
+
+func (c *imageComponent) ResolveImages(is *operator.ImageSet) error {
+	var err error
+	c.nodeImage, err = components.GetReference(components.ComponentCalicoNode, c.install.Registry, c.install.ImagePath, c.install.ImagePrefix, is)
+	if err != nil {
+		return err
+	}
+	c.cniImage, err = components.GetReference(components.ComponentCalicoCNI, c.install.Registry, c.install.ImagePath, c.install.ImagePrefix, is)
+	return err
+}
+
+func (c *imageComponent) Objects() ([]client.Object, []client.Object) {
+	return []client.Object{deploymentWithImages(c.nodeImage, c.cniImage)}, nil
+}
+
+func (c *imageComponent) Ready() bool {
+	return true
+}
+
+func (c *imageComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+var _ = Describe("image signature verification", func() {
+	var server *httptest.Server
+	var registry *testRegistry
+	var host string
+	var key *ecdsa.PrivateKey
+	var cli client.Client
+	var install *operator.InstallationSpec
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(kscheme.Scheme)).NotTo(HaveOccurred())
+		verifiedDigests.keysVersion = ""
+		verifiedDigests.m = map[string]string{}
+
+		registry = &testRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+		server = httptest.NewServer(registry)
+		host = strings.TrimPrefix(server.URL, "http://")
+
+		var err error
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		cli = fake.NewFakeClientWithScheme(kscheme.Scheme, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "image-keys", Namespace: common.OperatorNamespace()},
+			Data:       map[string][]byte{"cosign.pub": publicKeyPEM(key)},
+		})
+		install = &operator.InstallationSpec{
+			Registry:          host + "/",
+			ImageVerification: &operator.ImageVerification{PublicKeysSecretName: "image-keys"},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should not verify anything when verification is not configured", func() {
+		comp := render.NewPassthrough(deploymentWithImages(host + "/calico/node:v1"))
+		Expect(VerifyImages(context.Background(), cli, &operator.InstallationSpec{}, nil, comp)).NotTo(HaveOccurred())
+	})
+
+	It("should verify images referenced by tag and pin their digests", func() {
+		nodeDigest := registry.addImage("calico/node", components.ComponentCalicoNode.Version)
+		registry.sign("calico/node", nodeDigest, nodeDigest, key)
+		cniDigest := registry.addImage("calico/cni", components.ComponentCalicoCNI.Version)
+		registry.sign("calico/cni", cniDigest, cniDigest, key)
+
+		comp := &imageComponent{install: install}
+		Expect(ResolveImages(nil, comp)).NotTo(HaveOccurred())
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).NotTo(HaveOccurred())
+		Expect(comp.nodeImage).To(Equal(fmt.Sprintf("%s/calico/node@%s", host, nodeDigest)))
+		Expect(comp.cniImage).To(Equal(fmt.Sprintf("%s/calico/cni@%s", host, cniDigest)))
+	})
+
+	It("should verify images referenced by digest from an ImageSet", func() {
+		nodeDigest := registry.addImage("mirror/node", "v1")
+		registry.sign("mirror/node", nodeDigest, nodeDigest, key)
+		cniDigest := registry.addImage("calico/cni", "v1")
+		registry.sign("calico/cni", cniDigest, cniDigest, key)
+		is := &operator.ImageSet{Spec: operator.ImageSetSpec{Images: []operator.Image{
+			{Image: "calico/node", Digest: nodeDigest, Repository: "mirror/node"},
+			{Image: "calico/cni", Digest: cniDigest},
+		}}}
+
+		comp := &imageComponent{install: install}
+		Expect(ResolveImages(is, comp)).NotTo(HaveOccurred())
+		Expect(VerifyImages(context.Background(), cli, install, is, comp)).NotTo(HaveOccurred())
+		Expect(comp.nodeImage).To(Equal(fmt.Sprintf("%s/mirror/node@%s", host, nodeDigest)))
+		Expect(comp.cniImage).To(Equal(fmt.Sprintf("%s/calico/cni@%s", host, cniDigest)))
+	})
+
+	It("should not query the registry again for verified images", func() {
+		nodeDigest := registry.addImage("calico/node", components.ComponentCalicoNode.Version)
+		registry.sign("calico/node", nodeDigest, nodeDigest, key)
+		cniDigest := registry.addImage("calico/cni", components.ComponentCalicoCNI.Version)
+		registry.sign("calico/cni", cniDigest, cniDigest, key)
+
+		comp := &imageComponent{install: install}
+		Expect(ResolveImages(nil, comp)).NotTo(HaveOccurred())
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).NotTo(HaveOccurred())
+
+		registry.manifests = map[string][]byte{}
+		registry.blobs = map[string][]byte{}
+		Expect(ResolveImages(nil, comp)).NotTo(HaveOccurred())
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).NotTo(HaveOccurred())
+		Expect(comp.nodeImage).To(Equal(fmt.Sprintf("%s/calico/node@%s", host, nodeDigest)))
+
+		// The images are verified again when the keys change.
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Update(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "image-keys", Namespace: common.OperatorNamespace()},
+			Data:       map[string][]byte{"cosign.pub": publicKeyPEM(otherKey)},
+		})).NotTo(HaveOccurred())
+		Expect(ResolveImages(nil, comp)).NotTo(HaveOccurred())
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).To(HaveOccurred())
+	})
+
+	It("should verify the images again when the keys secret changes", func() {
+		nodeDigest := registry.addImage("calico/node", components.ComponentCalicoNode.Version)
+		registry.sign("calico/node", nodeDigest, nodeDigest, key)
+		cniDigest := registry.addImage("calico/cni", components.ComponentCalicoCNI.Version)
+		registry.sign("calico/cni", cniDigest, cniDigest, key)
+
+		comp := &imageComponent{install: install}
+		Expect(ResolveImages(nil, comp)).NotTo(HaveOccurred())
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).NotTo(HaveOccurred())
+
+		// The signatures are revoked and the secret is updated, even though it holds the same key.
+		registry.manifests = map[string][]byte{}
+		registry.blobs = map[string][]byte{}
+		secret := &corev1.Secret{}
+		Expect(cli.Get(context.Background(), client.ObjectKey{Name: "image-keys", Namespace: common.OperatorNamespace()}, secret)).NotTo(HaveOccurred())
+		secret.Labels = map[string]string{"rotated": "true"}
+		Expect(cli.Update(context.Background(), secret)).NotTo(HaveOccurred())
+
+		Expect(ResolveImages(nil, comp)).NotTo(HaveOccurred())
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).To(HaveOccurred())
+	})
+
+	It("should watch the configured keys secret", func() {
+		comp := render.NewPassthrough(deploymentWithImages(host + "/calico/node:v1"))
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).To(HaveOccurred())
+
+		Expect(isKeysSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "image-keys", Namespace: common.OperatorNamespace()}})).To(BeTrue())
+		Expect(isKeysSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "image-keys", Namespace: "default"}})).To(BeFalse())
+		Expect(isKeysSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: common.OperatorNamespace()}})).To(BeFalse())
+	})
+
+	It("should fail for images that are not signed", func() {
+		nodeDigest := registry.addImage("calico/node", "v1")
+		registry.sign("calico/node", nodeDigest, nodeDigest, key)
+		registry.addImage("calico/cni", "v1")
+
+		comp := render.NewPassthrough(deploymentWithImages(host+"/calico/node:v1", host+"/calico/cni:v1"))
+		err := VerifyImages(context.Background(), cli, install, nil, comp)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(host + "/calico/cni:v1 (no signatures found)"))
+		Expect(err.Error()).NotTo(ContainSubstring("calico/node"))
+	})
+
+	It("should fail for images signed with another key", func() {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		digest := registry.addImage("calico/node", "v1")
+		registry.sign("calico/node", digest, digest, otherKey)
+
+		comp := render.NewPassthrough(deploymentWithImages(host + "/calico/node:v1"))
+		err = VerifyImages(context.Background(), cli, install, nil, comp)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no signature verified with the configured keys"))
+	})
+
+	It("should fail for signatures of a different image", func() {
+		digest := registry.addImage("calico/node", "v1")
+		otherDigest := registry.addImage("calico/node", "v2")
+		registry.sign("calico/node", digest, otherDigest, key)
+
+		comp := render.NewPassthrough(deploymentWithImages(host + "/calico/node:v1"))
+		Expect(VerifyImages(context.Background(), cli, install, nil, comp)).To(HaveOccurred())
+	})
+
+	It("should fail when the keys secret does not exist", func() {
+		install.ImageVerification.PublicKeysSecretName = "missing"
+		comp := render.NewPassthrough(deploymentWithImages(host + "/calico/node:v1"))
+		err := VerifyImages(context.Background(), cli, install, nil, comp)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("image verification keys secret missing not found"))
+	})
+
+	It("should block ApplyImageSet when verification fails", func() {
+		registry.addImage("calico/node", "v1")
+
+		comp := render.NewPassthrough(deploymentWithImages(host + "/calico/node:v1"))
+		err := ApplyImageSet(context.Background(), cli, operator.Calico, install, comp)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("image signature verification failed"))
+	})
+})
@@ -141,6 +141,47 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		inst.NonPrivileged = override.NonPrivileged
 	}
 
+	switch compareFields(inst.CalicoWindowsUpgrade, override.CalicoWindowsUpgrade) {
+	case BOnlySet, Different:
+		inst.CalicoWindowsUpgrade = override.CalicoWindowsUpgrade.DeepCopy()
+	}
+
+	switch compareFields(inst.ImageVerification, override.ImageVerification) {
+	case BOnlySet, Different:
+		inst.ImageVerification = override.ImageVerification.DeepCopy()
+	}
+
+	switch compareFields(inst.PodDisruptionBudgets, override.PodDisruptionBudgets) {
+	case BOnlySet, Different:
+		inst.PodDisruptionBudgets = override.PodDisruptionBudgets.DeepCopy()
+	}
+
+	switch compareFields(inst.ComponentNetworkPolicies, override.ComponentNetworkPolicies) {
+	case BOnlySet, Different:
+		inst.ComponentNetworkPolicies = override.ComponentNetworkPolicies
+	}
+
+	switch compareFields(inst.KubeControllers, override.KubeControllers) {
+	case BOnlySet, Different:
+		inst.KubeControllers = override.KubeControllers.DeepCopy()
+	}
+
+	switch compareFields(inst.Felix, override.Felix) {
+	case BOnlySet, Different:
+		inst.Felix = override.Felix.DeepCopy()
+	}
+
+	switch compareFields(inst.CalicoNodePools, override.CalicoNodePools) {
+	case BOnlySet, Different:
+		inst.CalicoNodePools = make([]operatorv1.CalicoNodePool, len(override.CalicoNodePools))
+		copy(inst.CalicoNodePools, override.CalicoNodePools)
+	}
+
+	switch compareFields(inst.HostProtection, override.HostProtection) {
+	case BOnlySet, Different:
+		inst.HostProtection = override.HostProtection.DeepCopy()
+	}
+
 	return inst
 }
 
@@ -225,9 +266,29 @@ func mergeCalicoNetwork(cfg, override *operatorv1.CalicoNetworkSpec) *operatorv1
 		out.MultiInterfaceMode = override.MultiInterfaceMode
 	}
 
+	switch compareFields(out.Multus, override.Multus) {
+	case BOnlySet, Different:
+		out.Multus = override.Multus.DeepCopy()
+	}
+
 	switch compareFields(out.ContainerIPForwarding, override.ContainerIPForwarding) {
 	case BOnlySet, Different:
 		out.ContainerIPForwarding = override.ContainerIPForwarding
 	}
+
+	switch compareFields(out.WireGuard, override.WireGuard) {
+	case BOnlySet, Different:
+		out.WireGuard = override.WireGuard.DeepCopy()
+	}
+
+	switch compareFields(out.ServiceAdvertisement, override.ServiceAdvertisement) {
+	case BOnlySet, Different:
+		out.ServiceAdvertisement = override.ServiceAdvertisement.DeepCopy()
+	}
+
+	switch compareFields(out.VPP, override.VPP) {
+	case BOnlySet, Different:
+		out.VPP = override.VPP.DeepCopy()
+	}
 	return out
 }
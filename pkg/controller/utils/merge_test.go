@@ -503,6 +503,31 @@ var _ = Describe("Installation merge tests", func() {
 		Entry("Both set not matching", intPtr(1460), intPtr(8981), intPtr(8981)),
 	)
 
+	_cnpE := opv1.ComponentNetworkPoliciesEnabled
+	_cnpD := opv1.ComponentNetworkPoliciesDisabled
+	DescribeTable("merge ComponentNetworkPolicies", func(main, second, expect *opv1.ComponentNetworkPoliciesType) {
+		m := opv1.InstallationSpec{}
+		s := opv1.InstallationSpec{}
+		if main != nil {
+			m.ComponentNetworkPolicies = main
+		}
+		if second != nil {
+			s.ComponentNetworkPolicies = second
+		}
+		inst := OverrideInstallationSpec(m, s)
+		if expect == nil {
+			Expect(inst.ComponentNetworkPolicies).To(BeNil())
+		} else {
+			Expect(*inst.ComponentNetworkPolicies).To(Equal(*expect))
+		}
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", &_cnpE, nil, &_cnpE),
+		Entry("Second only set", nil, &_cnpD, &_cnpD),
+		Entry("Both set equal", &_cnpE, &_cnpE, &_cnpE),
+		Entry("Both set not matching", &_cnpE, &_cnpD, &_cnpD),
+	)
+
 	DescribeTable("merge FlexVolumePath", func(main, second, expect string) {
 		m := opv1.InstallationSpec{}
 		s := opv1.InstallationSpec{}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -34,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
@@ -41,6 +43,7 @@ import (
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 )
 
 const (
@@ -185,6 +188,55 @@ func LogStorageExists(ctx context.Context, cli client.Client) (bool, error) {
 	return true, nil
 }
 
+// AllowTigeraTier returns the state of the tier the component network policies are rendered in. The tier cannot exist
+// before the Calico API server serves it, so that case is reported as the tier not existing. When the Installation
+// disables the policies, the policies of the given components are read by name, so that only those still present are
+// deleted.
+func AllowTigeraTier(ctx context.Context, cli client.Client, install *operatorv1.InstallationSpec, comps ...render.Component) (networkpolicy.TierState, error) {
+	state := networkpolicy.TierState{}
+	tier := &v3.Tier{}
+	err := cli.Get(ctx, client.ObjectKey{Name: networkpolicy.TigeraComponentTierName}, tier)
+	if err != nil {
+		if kerrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return state, nil
+		}
+		return state, err
+	}
+	state.Exists = true
+	if networkpolicy.Enabled(install) {
+		return state, nil
+	}
+
+	state.Policies = map[types.NamespacedName]bool{}
+	for _, comp := range comps {
+		npc, ok := comp.(render.NetworkPolicyComponent)
+		if !ok {
+			continue
+		}
+		for _, p := range npc.NetworkPolicies() {
+			key := client.ObjectKeyFromObject(p)
+			if err := cli.Get(ctx, key, &v3.NetworkPolicy{}); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return state, err
+			}
+			state.Policies[key] = true
+		}
+	}
+	return state, nil
+}
+
+// AllowTigeraTierRequeue returns true, with the result the reconcile should return, when the Installation enables the
+// component network policies but the allow-tigera tier they are rendered in does not exist yet. The API server
+// controller creates the tier once the API server is available.
+func AllowTigeraTierRequeue(install *operatorv1.InstallationSpec, tier networkpolicy.TierState) (reconcile.Result, bool) {
+	if networkpolicy.Enabled(install) && !tier.Exists {
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, true
+	}
+	return reconcile.Result{}, false
+}
+
 func GetLogCollector(ctx context.Context, cli client.Client) (*operatorv1.LogCollector, error) {
 	logCollector := &operatorv1.LogCollector{}
 	err := cli.Get(ctx, DefaultTSEEInstanceKey, logCollector)
@@ -258,7 +310,9 @@ func ValidateCertPair(client client.Client, namespace, certPairSecretName, keyNa
 
 // GetK8sServiceEndPoint reads the kubernetes-service-endpoint configmap and pushes
 // KUBERNETES_SERVICE_HOST, KUBERNETES_SERVICE_PORT to calico-node daemonset, typha
-// apiserver deployments
+// apiserver deployments. The controllers rendering these components watch the configmap
+// so changes to it are rolled out without restarting the operator. If the configmap is
+// removed, the endpoint learned from the operator's environment is used again.
 func GetK8sServiceEndPoint(client client.Client) error {
 	cmName := render.K8sSvcEndpointConfigMapName
 	cm := &corev1.ConfigMap{}
@@ -271,9 +325,12 @@ func GetK8sServiceEndPoint(client client.Client) error {
 		if !kerrors.IsNotFound(err) {
 			return fmt.Errorf("Failed to read ConfigMap %q: %s", cmName, err)
 		}
+		k8sapi.ResetEndpoint()
 	} else {
-		k8sapi.Endpoint.Host = cm.Data["KUBERNETES_SERVICE_HOST"]
-		k8sapi.Endpoint.Port = cm.Data["KUBERNETES_SERVICE_PORT"]
+		k8sapi.SetEndpoint(k8sapi.ServiceEndpoint{
+			Host: cm.Data["KUBERNETES_SERVICE_HOST"],
+			Port: cm.Data["KUBERNETES_SERVICE_PORT"],
+		})
 	}
 	return nil
 }
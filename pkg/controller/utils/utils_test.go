@@ -29,7 +29,9 @@ import (
 
 	opv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 
 	apps "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -38,6 +40,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -183,6 +186,59 @@ var _ = Describe("Utils APIServer type tests", func() {
 	)
 })
 
+var _ = Describe("allow-tigera tier tests", func() {
+	var c client.Client
+	var ctx context.Context
+	var install *opv1.InstallationSpec
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+
+		disabled := opv1.ComponentNetworkPoliciesDisabled
+		install = &opv1.InstallationSpec{Variant: opv1.TigeraSecureEnterprise, ComponentNetworkPolicies: &disabled}
+	})
+
+	It("should report a missing tier", func() {
+		tier, err := AllowTigeraTier(ctx, c, install)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tier.Exists).To(BeFalse())
+	})
+
+	It("should read the policies of the components by name when they are disabled", func() {
+		packetCapture := render.PacketCaptureAPI(&render.PacketCaptureApiConfiguration{
+			Installation: install,
+			ServerCertSecret: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.PacketCaptureCertSecret, Namespace: common.OperatorNamespace()},
+			},
+		})
+		Expect(c.Create(ctx, networkpolicy.Tier())).ShouldNot(HaveOccurred())
+
+		tier, err := AllowTigeraTier(ctx, c, install, packetCapture)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tier.Exists).To(BeTrue())
+		Expect(tier.Policies).To(BeEmpty())
+
+		By("reading the policy once it is present")
+		Expect(c.Create(ctx, packetCapture.(render.NetworkPolicyComponent).NetworkPolicies()[0])).ShouldNot(HaveOccurred())
+		tier, err = AllowTigeraTier(ctx, c, install, packetCapture)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tier.Policies).To(Equal(map[types.NamespacedName]bool{
+			{Namespace: render.PacketCaptureNamespace, Name: "allow-tigera." + render.PacketCaptureName}: true,
+		}))
+
+		By("not reading them when they are enabled")
+		enabled := opv1.ComponentNetworkPoliciesEnabled
+		install.ComponentNetworkPolicies = &enabled
+		tier, err = AllowTigeraTier(ctx, c, install, packetCapture)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tier.Exists).To(BeTrue())
+		Expect(tier.Policies).To(BeNil())
+	})
+})
+
 type fakeClient struct {
 	discovery discovery.DiscoveryInterface
 	kubernetes.Interface
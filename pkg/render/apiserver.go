@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ghodss/yaml"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
@@ -29,12 +31,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/ptr"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
+	"github.com/tigera/operator/pkg/render/common/poddisruptionbudget"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
@@ -45,6 +50,10 @@ const (
 	queryServerPort         = 8080
 	APIServerSecretKeyName  = "apiserver.key"
 	APIServerSecretCertName = "apiserver.crt"
+
+	// AuditPolicyConfigMapKey is the key of the audit policy in the audit policy ConfigMaps.
+	AuditPolicyConfigMapKey   = "config"
+	auditPolicyHashAnnotation = "hash.operator.tigera.io/audit-policy"
 )
 
 // The following functions are helpers for determining resource names based on
@@ -98,14 +107,29 @@ func APIServer(cfg *APIServerConfiguration) (Component, error) {
 			cfg.TunnelCASecret = voltronTunnelSecret()
 			tlsSecrets = append(tlsSecrets, cfg.TunnelCASecret)
 		}
-		tlsSecrets = append(tlsSecrets, secret.CopyToNamespace(rmeta.APIServerNamespace(cfg.Installation.Variant), cfg.TunnelCASecret)...)
-		tlsHashAnnotations[voltronTunnelHashAnnotation] = rmeta.AnnotationHash(cfg.TunnelCASecret.Data)
+		if cfg.NextTunnelCASecret != nil {
+			tlsSecrets = append(tlsSecrets, cfg.NextTunnelCASecret)
+		}
+		// The API server signs the certificates of the managed clusters with the CA that is presented to them.
+		signingCA := tunnelPresentedCA(cfg.ManagementCluster, cfg.TunnelCASecret, cfg.NextTunnelCASecret, rmeta.APIServerNamespace(cfg.Installation.Variant))
+		tlsSecrets = append(tlsSecrets, signingCA)
+		tlsHashAnnotations[voltronTunnelHashAnnotation] = rmeta.AnnotationHash(signingCA.Data)
+	}
+
+	auditPolicy := defaultAuditPolicy
+	if cfg.AuditPolicy != nil {
+		var err error
+		if auditPolicy, err = mergeAuditPolicy(cfg.AuditPolicy.Data[AuditPolicyConfigMapKey], defaultAuditPolicy); err != nil {
+			return nil, fmt.Errorf("invalid audit policy in ConfigMap %s: %v", cfg.AuditPolicy.Name, err)
+		}
+		tlsHashAnnotations[auditPolicyHashAnnotation] = rmeta.AnnotationHash(auditPolicy)
 	}
 
 	return &apiServerComponent{
 		cfg:            cfg,
 		tlsSecrets:     tlsSecrets,
 		tlsAnnotations: tlsHashAnnotations,
+		auditPolicy:    auditPolicy,
 	}, nil
 }
 
@@ -120,14 +144,26 @@ type APIServerConfiguration struct {
 	TLSKeyPair                  *corev1.Secret
 	PullSecrets                 []*corev1.Secret
 	Openshift                   bool
+	UsePSP                      bool
+	UsePolicyV1PDB              bool
 	TunnelCASecret              *corev1.Secret
 	ClusterDomain               string
+
+	// NextTunnelCASecret is the CA a rotation of the tunnel CA moves to, while the rotation is in progress.
+	NextTunnelCASecret *corev1.Secret
+
+	// APIServer is the spec of the APIServer CR. It may be nil.
+	APIServer *operatorv1.APIServerSpec
+
+	// AuditPolicy is the ConfigMap with the user supplied audit policy, if any. Calico Enterprise only.
+	AuditPolicy *corev1.ConfigMap
 }
 
 type apiServerComponent struct {
 	cfg              *APIServerConfiguration
 	tlsSecrets       []*corev1.Secret
 	tlsAnnotations   map[string]string
+	auditPolicy      string
 	isManagement     bool
 	apiServerImage   string
 	queryServerImage string
@@ -198,7 +234,7 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 	globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.authReaderRoleBinding)
 	globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.webhookReaderClusterRole)
 	globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.webhookReaderClusterRoleBinding)
-	if !c.cfg.Openshift {
+	if !c.cfg.Openshift && c.cfg.UsePSP {
 		globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.apiServerPodSecurityPolicy)
 	}
 
@@ -209,11 +245,17 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 	secrets := secret.CopyToNamespace(rmeta.APIServerNamespace(c.cfg.Installation.Variant), c.cfg.PullSecrets...)
 	namespacedObjects = append(namespacedObjects, secret.ToRuntimeObjects(secrets...)...)
 
+	deployment := c.apiServerDeployment()
 	namespacedObjects = append(namespacedObjects,
 		c.apiServerServiceAccount(),
-		c.apiServerDeployment(),
+		deployment,
 		c.apiServerService(),
 	)
+	if pdb, del := poddisruptionbudget.ForDeployment(c.cfg.Installation, deployment, c.cfg.UsePolicyV1PDB); pdb != nil {
+		namespacedObjects = append(namespacedObjects, pdb)
+	} else {
+		objsToDelete = append(objsToDelete, del)
+	}
 
 	// Add in certificates for API server TLS.
 	if c.cfg.Installation.CertificateManagement == nil {
@@ -226,7 +268,7 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 
 	// Global enterprise-only objects.
 	globalEnterpriseObjects := []client.Object{
-		CreateNamespace(rmeta.APIServerNamespace(operatorv1.TigeraSecureEnterprise), c.cfg.Installation.KubernetesProvider),
+		CreateNamespace(rmeta.APIServerNamespace(operatorv1.TigeraSecureEnterprise), c.cfg.Installation.KubernetesProvider, PSSPrivileged),
 		c.tigeraCustomResourcesClusterRole(),
 		c.tigeraCustomResourcesClusterRoleBinding(),
 		c.tierGetterClusterRole(),
@@ -245,10 +287,13 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 	namespacedEnterpriseObjects := []client.Object{
 		c.auditPolicyConfigMap(),
 	}
+	if c.cfg.ManagementCluster != nil {
+		namespacedEnterpriseObjects = append(namespacedEnterpriseObjects, managementClusterCABundle(c.cfg.TunnelCASecret, c.cfg.NextTunnelCASecret))
+	}
 
 	// Global OSS-only objects.
 	globalCalicoObjects := []client.Object{
-		CreateNamespace(rmeta.APIServerNamespace(operatorv1.Calico), c.cfg.Installation.KubernetesProvider),
+		CreateNamespace(rmeta.APIServerNamespace(operatorv1.Calico), c.cfg.Installation.KubernetesProvider, PSSPrivileged),
 	}
 
 	// Compile the final arrays based on the variant.
@@ -790,7 +835,7 @@ func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: c.cfg.Installation.ControlPlaneReplicas,
+			Replicas: c.replicas(),
 			Strategy: appsv1.DeploymentStrategy{
 				Type: appsv1.RecreateDeploymentStrategyType,
 			},
@@ -807,7 +852,7 @@ func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
 				},
 				Spec: corev1.PodSpec{
 					DNSPolicy:          dnsPolicy,
-					NodeSelector:       c.cfg.Installation.ControlPlaneNodeSelector,
+					NodeSelector:       c.nodeSelector(),
 					HostNetwork:        hostNetwork,
 					ServiceAccountName: serviceAccountName(c.cfg.Installation.Variant),
 					Tolerations:        c.tolerations(),
@@ -822,7 +867,9 @@ func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
 		},
 	}
 
-	if c.cfg.Installation.ControlPlaneReplicas != nil && *c.cfg.Installation.ControlPlaneReplicas > 1 {
+	if c.cfg.APIServer != nil && c.cfg.APIServer.Affinity != nil {
+		d.Spec.Template.Spec.Affinity = c.cfg.APIServer.Affinity
+	} else if replicas := c.replicas(); replicas != nil && *replicas > 1 {
 		d.Spec.Template.Spec.Affinity = podaffinity.NewPodAntiAffinity(name, rmeta.APIServerNamespace(c.cfg.Installation.Variant))
 	}
 
@@ -833,6 +880,29 @@ func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
 	return d
 }
 
+// replicas returns the number of API server replicas, which defaults to the control plane replicas.
+func (c *apiServerComponent) replicas() *int32 {
+	if c.cfg.APIServer != nil && c.cfg.APIServer.Replicas != nil {
+		return c.cfg.APIServer.Replicas
+	}
+	return c.cfg.Installation.ControlPlaneReplicas
+}
+
+func (c *apiServerComponent) nodeSelector() map[string]string {
+	if c.cfg.APIServer != nil && c.cfg.APIServer.NodeSelector != nil {
+		return c.cfg.APIServer.NodeSelector
+	}
+	return c.cfg.Installation.ControlPlaneNodeSelector
+}
+
+// logLevel returns the log level of the API server and query server, as understood by their LOGLEVEL variable.
+func (c *apiServerComponent) logLevel() string {
+	if c.cfg.APIServer != nil && c.cfg.APIServer.LogLevel != nil {
+		return strings.ToLower(string(*c.cfg.APIServer.LogLevel))
+	}
+	return "info"
+}
+
 func (c *apiServerComponent) hostNetwork() bool {
 	hostNetwork := c.cfg.ForceHostNetwork
 	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderEKS &&
@@ -885,6 +955,10 @@ func (c *apiServerComponent) apiServerContainer() corev1.Container {
 		env = append(env, corev1.EnvVar{Name: "MULTI_INTERFACE_MODE", Value: c.cfg.Installation.CalicoNetwork.MultiInterfaceMode.Value()})
 	}
 
+	if c.cfg.APIServer != nil && c.cfg.APIServer.LogLevel != nil {
+		env = append(env, corev1.EnvVar{Name: "LOGLEVEL", Value: c.logLevel()})
+	}
+
 	var name string
 	switch c.cfg.Installation.Variant {
 	case operatorv1.TigeraSecureEnterprise:
@@ -957,8 +1031,7 @@ func (c *apiServerComponent) startUpArgs() []string {
 // queryServerContainer creates the query server container.
 func (c *apiServerComponent) queryServerContainer() corev1.Container {
 	env := []corev1.EnvVar{
-		// Set queryserver logging to "info"
-		{Name: "LOGLEVEL", Value: "info"},
+		{Name: "LOGLEVEL", Value: c.logLevel()},
 		{Name: "DATASTORE_TYPE", Value: "kubernetes"},
 	}
 
@@ -973,6 +1046,7 @@ func (c *apiServerComponent) queryServerContainer() corev1.Container {
 		Name:  "tigera-queryserver",
 		Image: c.queryServerImage,
 		Env:   env,
+		Args:  c.queryServerArgs(),
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
@@ -989,6 +1063,14 @@ func (c *apiServerComponent) queryServerContainer() corev1.Container {
 	return container
 }
 
+// queryServerArgs returns the additional flags configured for the query server.
+func (c *apiServerComponent) queryServerArgs() []string {
+	if c.cfg.APIServer == nil || c.cfg.APIServer.QueryServer == nil {
+		return nil
+	}
+	return c.cfg.APIServer.QueryServer.ExtraArgs
+}
+
 // apiServerVolumes creates the volumes used by the API server deployment.
 func (c *apiServerComponent) apiServerVolumes() []corev1.Volume {
 	volumes := []corev1.Volume{}
@@ -1045,6 +1127,9 @@ func (c *apiServerComponent) apiServerVolumes() []corev1.Volume {
 
 // tolerations creates the tolerations used by the API server deployment.
 func (c *apiServerComponent) tolerations() []corev1.Toleration {
+	if c.cfg.APIServer != nil && c.cfg.APIServer.Tolerations != nil {
+		return c.cfg.APIServer.Tolerations
+	}
 	if c.hostNetwork() {
 		return rmeta.TolerateAll
 	}
@@ -1121,6 +1206,28 @@ func (c *apiServerComponent) networkPolicy() *netv1.NetworkPolicy {
 	}
 }
 
+// NetworkPolicies returns the allow-tigera policy for the API server. It accepts connections from anywhere, since
+// the main API server may be host networked, and only reaches out to the Kubernetes API and Prometheus.
+//
+// Calico Enterprise only.
+func (c *apiServerComponent) NetworkPolicies() []client.Object {
+	if c.cfg.Installation.Variant != operatorv1.TigeraSecureEnterprise {
+		return nil
+	}
+
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(c.cfg.K8SServiceEndpoint)...)
+	egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(common.TigeraPrometheusNamespace, "all()")))
+	return []client.Object{networkpolicy.New(
+		"apiserver",
+		rmeta.APIServerNamespace(c.cfg.Installation.Variant),
+		"apiserver == 'true'",
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(apiServerPort, queryServerPort))},
+		egress,
+	)}
+}
+
 // tigeraCustomResourcesClusterRole creates a clusterrole that gives permissions to access backing CRDs
 //
 // Calico Enterprise only
@@ -1680,12 +1787,9 @@ func (c *apiServerComponent) uiSettingsPassthruClusterRolebinding() *rbacv1.Clus
 	}
 }
 
-// auditPolicyConfigMap returns a configmap with contents to configure audit logging for
-// projectcalico.org/v3 APIs.
-//
-// Calico Enterprise only
-func (c *apiServerComponent) auditPolicyConfigMap() *corev1.ConfigMap {
-	const defaultAuditPolicy = `apiVersion: audit.k8s.io/v1beta1
+// defaultAuditPolicy is the audit policy for projectcalico.org/v3 APIs used when no policy is configured
+// on the APIServer, and appended to the rules of the configured policy otherwise.
+const defaultAuditPolicy = `apiVersion: audit.k8s.io/v1beta1
 kind: Policy
 rules:
 - level: RequestResponse
@@ -1709,6 +1813,11 @@ rules:
     - tiers
     - hostendpoints`
 
+// auditPolicyConfigMap returns a configmap with contents to configure audit logging for
+// projectcalico.org/v3 APIs.
+//
+// Calico Enterprise only
+func (c *apiServerComponent) auditPolicyConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -1717,7 +1826,36 @@ rules:
 			Name:      "tigera-audit-policy",
 		},
 		Data: map[string]string{
-			"config": defaultAuditPolicy,
+			AuditPolicyConfigMapKey: c.auditPolicy,
 		},
 	}
 }
+
+// mergeAuditPolicy merges the given audit policy with the default one. Audit policy rules are evaluated
+// in order, so the rules of the given policy come first and take precedence over the default rules. Any
+// other fields of the policy, such as omitStages, are taken from the given policy.
+func mergeAuditPolicy(policy, defaultPolicy string) (string, error) {
+	merged := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(policy), &merged); err != nil {
+		return "", err
+	}
+	if kind, ok := merged["kind"]; !ok || kind != "Policy" {
+		return "", fmt.Errorf("expected an audit.k8s.io Policy in the %q key", AuditPolicyConfigMapKey)
+	}
+	rules, ok := merged["rules"].([]interface{})
+	if !ok && merged["rules"] != nil {
+		return "", fmt.Errorf("audit policy rules must be a list")
+	}
+
+	defaults := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(defaultPolicy), &defaults); err != nil {
+		return "", err
+	}
+	merged["rules"] = append(rules, defaults["rules"].([]interface{})...)
+
+	b, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
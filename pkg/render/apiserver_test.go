@@ -25,13 +25,17 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	"github.com/ghodss/yaml"
 	"github.com/onsi/gomega/gstruct"
 	"github.com/openshift/library-go/pkg/crypto"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -80,6 +84,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			TLSKeyPair:         tlsKeyPair,
 			Openshift:          openshift,
 			ClusterDomain:      dns.DefaultClusterDomain,
+			UsePSP:             true,
 		}
 	})
 
@@ -109,6 +114,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: "tigera-apiserver-certs", ns: "tigera-system", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -308,6 +314,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: "tigera-apiserver-certs", ns: "tigera-system", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -361,6 +368,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: "tigera-apiserver-certs", ns: "tigera-system", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -435,6 +443,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: "tigera-apiserver-certs", ns: "tigera-system", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -500,6 +509,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: "tigera-apiserver-certs", ns: "tigera-system", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -650,6 +660,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 		}{
 			{name: "tigera-system", ns: "", group: "", version: "v1", kind: "Namespace"},
 			{name: "tigera-audit-policy", ns: "tigera-system", group: "", version: "v1", kind: "ConfigMap"},
+			{name: render.ManagementClusterCABundleConfigMapName, ns: common.OperatorNamespace(), group: "", version: "v1", kind: "ConfigMap"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "", version: "v1", kind: "ServiceAccount"},
 			{name: "tigera-crds", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "calico-crds", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
@@ -668,6 +679,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: render.VoltronTunnelSecretName, ns: "tigera-system", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -740,6 +752,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 		}{
 			{name: "tigera-system", ns: "", group: "", version: "v1", kind: "Namespace"},
 			{name: "tigera-audit-policy", ns: "tigera-system", group: "", version: "v1", kind: "ConfigMap"},
+			{name: render.ManagementClusterCABundleConfigMapName, ns: common.OperatorNamespace(), group: "", version: "v1", kind: "ConfigMap"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "", version: "v1", kind: "ServiceAccount"},
 			{name: "tigera-crds", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "calico-crds", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
@@ -757,6 +770,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: render.VoltronTunnelSecretName, ns: "tigera-system", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -789,6 +803,35 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 		Expect((dep.(*appsv1.Deployment)).Spec.Template.Spec.Containers[0].Args).To(ConsistOf(expectedArgs))
 	})
 
+	It("should sign with the new tunnel CA and publish both CAs while the tunnel CA is rotated", func() {
+		next := testutils.VoltronTunnelSecret.DeepCopy()
+		next.Name = render.VoltronTunnelNextSecretName
+		next.Data[render.VoltronTunnelSecretCertName] = []byte("next-cert")
+
+		for _, tc := range []struct {
+			phase      operatorv1.TunnelCARotationPhase
+			signingPEM string
+		}{
+			{operatorv1.TunnelCARotationPublish, "cert"},
+			{operatorv1.TunnelCARotationReissue, "next-cert"},
+		} {
+			phase := tc.phase
+			cfg.ManagementCluster = &operatorv1.ManagementCluster{Spec: operatorv1.ManagementClusterSpec{TunnelCARotation: &phase}}
+			cfg.TunnelCASecret = &testutils.VoltronTunnelSecret
+			cfg.NextTunnelCASecret = next
+			component, err := render.APIServer(cfg)
+			Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+			resources, _ := component.Objects()
+
+			Expect(rtest.GetResource(resources, render.VoltronTunnelNextSecretName, common.OperatorNamespace(), "", "v1", "Secret")).NotTo(BeNil())
+			signing := rtest.GetResource(resources, render.VoltronTunnelSecretName, "tigera-system", "", "v1", "Secret").(*corev1.Secret)
+			Expect(string(signing.Data[render.VoltronTunnelSecretCertName])).To(Equal(tc.signingPEM), string(phase))
+
+			bundle := rtest.GetResource(resources, render.ManagementClusterCABundleConfigMapName, common.OperatorNamespace(), "", "v1", "ConfigMap").(*corev1.ConfigMap)
+			Expect(bundle.Data[render.ManagementClusterCABundleKey]).To(Equal("cert\nnext-cert\n"))
+		}
+	})
+
 	It("should add an init container if certificate management is enabled", func() {
 		cfg.Installation.CertificateManagement = &operatorv1.CertificateManagement{SignerName: "a.b/c"}
 		component, err := render.APIServer(cfg)
@@ -819,6 +862,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "tigera-apiserver:csr-creator", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
 			{name: "tigera-apiserver", ns: "tigera-system", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-apiserver", ns: "tigera-system", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "tigera-api", ns: "tigera-system", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-tier-getter", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -869,6 +913,159 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 		Expect(deploy.Spec.Template.Spec.Affinity).NotTo(BeNil())
 		Expect(deploy.Spec.Template.Spec.Affinity).To(Equal(podaffinity.NewPodAntiAffinity("tigera-apiserver", "tigera-system")))
 	})
+
+	It("should render a PodDisruptionBudget when ControlPlaneReplicas is greater than 1", func() {
+		var replicas int32 = 2
+		cfg.Installation.ControlPlaneReplicas = &replicas
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		pdb, ok := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget").(*policyv1beta1.PodDisruptionBudget)
+		Expect(ok).To(BeTrue())
+		Expect(pdb.Spec.MaxUnavailable).To(Equal(&intstr.IntOrString{Type: intstr.Int, IntVal: 1}))
+		Expect(pdb.Spec.MinAvailable).To(BeNil())
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"apiserver": "true"}))
+	})
+
+	It("should render a policy/v1 PodDisruptionBudget when the cluster serves it", func() {
+		var replicas int32 = 2
+		cfg.Installation.ControlPlaneReplicas = &replicas
+		cfg.UsePolicyV1PDB = true
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		Expect(rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget")).To(BeNil())
+		pdb, ok := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "policy", "v1", "PodDisruptionBudget").(*policyv1.PodDisruptionBudget)
+		Expect(ok).To(BeTrue())
+		Expect(pdb.Spec.MaxUnavailable).To(Equal(&intstr.IntOrString{Type: intstr.Int, IntVal: 1}))
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"apiserver": "true"}))
+	})
+
+	It("should delete the PodDisruptionBudget when ControlPlaneReplicas is 1", func() {
+		var replicas int32 = 1
+		cfg.Installation.ControlPlaneReplicas = &replicas
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, toDelete := component.Objects()
+
+		Expect(rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget")).To(BeNil())
+		rtest.ExpectResourceInList(toDelete, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget")
+	})
+
+	It("should not render a PodSecurityPolicy when PodSecurityPolicies are not supported", func() {
+		cfg.UsePSP = false
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, toDelete := component.Objects()
+
+		Expect(rtest.GetResource(resources, "tigera-apiserver", "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+		Expect(rtest.GetResource(toDelete, "calico-apiserver", "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+	})
+
+	It("should render the PodDisruptionBudget configured on the Installation", func() {
+		var replicas int32 = 3
+		minAvailable := intstr.FromString("50%")
+		cfg.Installation.ControlPlaneReplicas = &replicas
+		cfg.Installation.PodDisruptionBudgets = &operatorv1.PodDisruptionBudgets{
+			Components: []operatorv1.ComponentPodDisruptionBudget{{Name: "tigera-apiserver", MinAvailable: &minAvailable}},
+		}
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		pdb, ok := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget").(*policyv1beta1.PodDisruptionBudget)
+		Expect(ok).To(BeTrue())
+		Expect(pdb.Spec.MinAvailable).To(Equal(&minAvailable))
+		Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+
+		cfg.Installation.PodDisruptionBudgets.Components[0].Disabled = true
+		component, err = render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, toDelete := component.Objects()
+		Expect(rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget")).To(BeNil())
+		rtest.ExpectResourceInList(toDelete, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget")
+	})
+
+	It("should render the replicas, log level, query server flags and scheduling from the APIServer", func() {
+		var apiServerReplicas int32 = 5
+		logLevel := operatorv1.APIServerLogLevelDebug
+		affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "api", Operator: corev1.NodeSelectorOpExists}},
+			}}},
+		}}
+		toleration := corev1.Toleration{Key: "api", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+		cfg.Installation.ControlPlaneNodeSelector = map[string]string{"control": "plane"}
+		cfg.APIServer = &operatorv1.APIServerSpec{
+			Replicas:     &apiServerReplicas,
+			LogLevel:     &logLevel,
+			QueryServer:  &operatorv1.QueryServerSpec{ExtraArgs: []string{"--max-results=1000"}},
+			NodeSelector: map[string]string{"api": "heavy"},
+			Tolerations:  []corev1.Toleration{toleration},
+			Affinity:     affinity,
+		}
+		component, err := render.APIServer(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		resources, _ := component.Objects()
+
+		deploy, ok := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(ok).To(BeTrue())
+		Expect(*deploy.Spec.Replicas).To(Equal(apiServerReplicas))
+		Expect(deploy.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"api": "heavy"}))
+		Expect(deploy.Spec.Template.Spec.Tolerations).To(ConsistOf(toleration))
+		Expect(deploy.Spec.Template.Spec.Affinity).To(Equal(affinity))
+
+		Expect(deploy.Spec.Template.Spec.Containers).To(HaveLen(2))
+		rtest.ExpectEnv(deploy.Spec.Template.Spec.Containers[0].Env, "LOGLEVEL", "debug")
+		rtest.ExpectEnv(deploy.Spec.Template.Spec.Containers[1].Env, "LOGLEVEL", "debug")
+		Expect(deploy.Spec.Template.Spec.Containers[1].Args).To(Equal([]string{"--max-results=1000"}))
+	})
+
+	It("should merge a custom audit policy with the default one", func() {
+		cfg.AuditPolicy = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-audit-policy", Namespace: common.OperatorNamespace()},
+			Data: map[string]string{"config": `apiVersion: audit.k8s.io/v1beta1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: None
+  resources:
+  - group: projectcalico.org
+    resources:
+    - networksets
+`},
+		}
+		component, err := render.APIServer(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		resources, _ := component.Objects()
+
+		cm, ok := rtest.GetResource(resources, "tigera-audit-policy", "tigera-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(ok).To(BeTrue())
+		policy := map[string]interface{}{}
+		Expect(yaml.Unmarshal([]byte(cm.Data["config"]), &policy)).NotTo(HaveOccurred())
+		Expect(policy["omitStages"]).To(Equal([]interface{}{"RequestReceived"}))
+		rules := policy["rules"].([]interface{})
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0].(map[string]interface{})["level"]).To(Equal("None"))
+		Expect(rules[1].(map[string]interface{})["level"]).To(Equal("RequestResponse"))
+
+		deploy, ok := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(ok).To(BeTrue())
+		Expect(deploy.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/audit-policy"))
+	})
+
+	It("should reject an audit policy that is not a Policy", func() {
+		cfg.AuditPolicy = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-audit-policy", Namespace: common.OperatorNamespace()},
+			Data:       map[string]string{"config": "kind: ConfigMap"},
+		}
+		_, err := render.APIServer(cfg)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid audit policy in ConfigMap my-audit-policy"))
+	})
 })
 
 func verifyAPIService(service *apiregv1.APIService, enterprise bool, clusterDomain string) {
@@ -1186,6 +1383,7 @@ var _ = Describe("API server rendering tests (Calico)", func() {
 			TLSKeyPair:         tlsKeyPair,
 			Openshift:          openshift,
 			ClusterDomain:      dns.DefaultClusterDomain,
+			UsePSP:             true,
 		}
 	})
 
@@ -1208,6 +1406,7 @@ var _ = Describe("API server rendering tests (Calico)", func() {
 			{name: "calico-apiserver-certs", ns: "calico-apiserver", group: "", version: "v1", kind: "Secret"},
 			{name: "v3.projectcalico.org", ns: "", group: "apiregistration.k8s.io", version: "v1", kind: "APIService"},
 			{name: "calico-apiserver", ns: "calico-apiserver", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "calico-apiserver", ns: "calico-apiserver", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{name: "calico-api", ns: "calico-apiserver", group: "", version: "v1", kind: "Service"},
 			{name: "calico-webhook-reader", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "calico-apiserver-webhook-reader", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
@@ -1333,6 +1532,7 @@ var _ = Describe("API server rendering tests (Calico)", func() {
 			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "calico-apiserver-certs", Namespace: "calico-apiserver"}, TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}},
 			&apiregv1.APIService{ObjectMeta: metav1.ObjectMeta{Name: "v3.projectcalico.org"}, TypeMeta: metav1.TypeMeta{APIVersion: "apiregistration.k8s.io/v1", Kind: "APIService"}},
 			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "calico-apiserver", Namespace: "calico-apiserver"}, TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}},
+			&policyv1beta1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "calico-apiserver", Namespace: "calico-apiserver"}, TypeMeta: metav1.TypeMeta{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget"}},
 			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "calico-api", Namespace: "calico-apiserver"}, TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}},
 			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "calico-webhook-reader"}, TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"}},
 			&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "calico-apiserver-webhook-reader"}, TypeMeta: metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"}},
@@ -0,0 +1,166 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/api/pkg/lib/numorstring"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
+)
+
+const (
+	// TigeraComponentTierName is the tier the network policies of the components are rendered in.
+	TigeraComponentTierName = "allow-tigera"
+	// TigeraComponentTierOrder places the tier ahead of the tiers created by users.
+	TigeraComponentTierOrder = 100.0
+
+	// The Elasticsearch gateway is the entry point to Elasticsearch and Kibana for the other components. These
+	// are defined here, rather than referenced, since the gateway render package depends on this one.
+	esGatewayNamespace = "tigera-elasticsearch"
+	esGatewaySelector  = "k8s-app == 'tigera-secure-es-gateway'"
+	esGatewayPort      = 5554
+)
+
+var tcpProtocol = numorstring.ProtocolFromString(numorstring.ProtocolTCP)
+var udpProtocol = numorstring.ProtocolFromString(numorstring.ProtocolUDP)
+
+// TierState is the state of the allow-tigera tier read from the cluster.
+type TierState struct {
+	// Exists is true once the tier has been created. The policies can be neither created nor deleted without it.
+	Exists bool
+	// Policies are the component policies still present in the tier. They are only read when the policies are
+	// disabled, so that only those are deleted.
+	Policies map[types.NamespacedName]bool
+}
+
+// Enabled returns true if the Installation configures the operator to render the component network policies.
+func Enabled(install *operatorv1.InstallationSpec) bool {
+	return install != nil &&
+		install.Variant == operatorv1.TigeraSecureEnterprise &&
+		install.ComponentNetworkPolicies != nil &&
+		*install.ComponentNetworkPolicies == operatorv1.ComponentNetworkPoliciesEnabled
+}
+
+// Tier returns the tier the component network policies are rendered in.
+func Tier() *v3.Tier {
+	order := TigeraComponentTierOrder
+	return &v3.Tier{
+		TypeMeta:   metav1.TypeMeta{Kind: v3.KindTier, APIVersion: v3.GroupVersionCurrent},
+		ObjectMeta: metav1.ObjectMeta{Name: TigeraComponentTierName},
+		Spec:       v3.TierSpec{Order: &order},
+	}
+}
+
+// New returns a network policy in the allow-tigera tier for the pods matched by the selector in the namespace.
+// Traffic in the directions given by types that is not allowed by one of the rules is denied.
+func New(name, namespace, selector string, types []v3.PolicyType, ingress, egress []v3.Rule) *v3.NetworkPolicy {
+	return &v3.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{Kind: v3.KindNetworkPolicy, APIVersion: v3.GroupVersionCurrent},
+		ObjectMeta: metav1.ObjectMeta{
+			// Calico requires the names of policies outside the default tier to be prefixed by the tier.
+			Name:      fmt.Sprintf("%s.%s", TigeraComponentTierName, name),
+			Namespace: namespace,
+		},
+		Spec: v3.NetworkPolicySpec{
+			Tier:     TigeraComponentTierName,
+			Selector: selector,
+			Types:    types,
+			Ingress:  ingress,
+			Egress:   egress,
+		},
+	}
+}
+
+// Pods returns an entity rule matching the pods matched by the selector in the namespace, on the given ports.
+func Pods(namespace, selector string, ports ...uint16) v3.EntityRule {
+	return v3.EntityRule{
+		NamespaceSelector: fmt.Sprintf("projectcalico.org/name == '%s'", namespace),
+		Selector:          selector,
+		Ports:             toPorts(ports),
+	}
+}
+
+// Ports returns an entity rule matching any endpoint on the given ports.
+func Ports(ports ...uint16) v3.EntityRule {
+	return v3.EntityRule{Ports: toPorts(ports)}
+}
+
+// AllowTCP returns a rule allowing TCP traffic from the source to the destination.
+func AllowTCP(source, destination v3.EntityRule) v3.Rule {
+	return v3.Rule{Action: v3.Allow, Protocol: &tcpProtocol, Source: source, Destination: destination}
+}
+
+// DNSEgressRules returns the rules allowing pods to query the cluster DNS of the provider.
+func DNSEgressRules(provider operatorv1.Provider) []v3.Rule {
+	dst := Pods("kube-system", "k8s-app == 'kube-dns'", 53)
+	if provider == operatorv1.ProviderOpenShift {
+		dst = Pods("openshift-dns", "dns.operator.openshift.io/daemonset-dns == 'default'", 5353)
+	}
+	return []v3.Rule{
+		{Action: v3.Allow, Protocol: &udpProtocol, Destination: dst},
+		{Action: v3.Allow, Protocol: &tcpProtocol, Destination: dst},
+	}
+}
+
+// KubeAPIEgressRules returns the rules allowing pods to reach the Kubernetes API server, through the kubernetes
+// service and, when it is configured, the endpoint the components are given to reach it directly.
+func KubeAPIEgressRules(ep k8sapi.ServiceEndpoint) []v3.Rule {
+	rules := []v3.Rule{{
+		Action:      v3.Allow,
+		Protocol:    &tcpProtocol,
+		Destination: v3.EntityRule{Services: &v3.ServiceMatch{Namespace: "default", Name: "kubernetes"}},
+	}}
+
+	if ep.Host == "" || ep.Port == "" {
+		return rules
+	}
+	port, err := strconv.ParseUint(ep.Port, 10, 16)
+	if err != nil {
+		return rules
+	}
+	dst := Ports(uint16(port))
+	if ip := net.ParseIP(ep.Host); ip == nil {
+		dst.Domains = []string{ep.Host}
+	} else if ip.To4() != nil {
+		dst.Nets = []string{ip.String() + "/32"}
+	} else {
+		dst.Nets = []string{ip.String() + "/128"}
+	}
+	return append(rules, AllowTCP(v3.EntityRule{}, dst))
+}
+
+// ESGatewayEgressRule returns the rule allowing pods to reach Elasticsearch and Kibana through the gateway.
+func ESGatewayEgressRule() v3.Rule {
+	return AllowTCP(v3.EntityRule{}, Pods(esGatewayNamespace, esGatewaySelector, esGatewayPort))
+}
+
+func toPorts(ports []uint16) []numorstring.Port {
+	if len(ports) == 0 {
+		return nil
+	}
+	out := make([]numorstring.Port, 0, len(ports))
+	for _, p := range ports {
+		out = append(out, numorstring.SinglePort(p))
+	}
+	return out
+}
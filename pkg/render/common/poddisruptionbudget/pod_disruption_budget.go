@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poddisruptionbudget
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+// New returns the PodDisruptionBudget with the given name for the pods matching the selector, as configured by the
+// Installation. Exactly one of the returned objects is non-nil: the budget to create, or the budget to delete
+// because it has been disabled. The budget is rendered as policy/v1 when usePolicyV1 is set, and as policy/v1beta1
+// for clusters that do not serve policy/v1 (Kubernetes before v1.21).
+func New(install *operatorv1.InstallationSpec, name, namespace string, selector *metav1.LabelSelector, usePolicyV1 bool) (client.Object, client.Object) {
+	maxUnavailable := intstr.FromInt(1)
+	spec := policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable, Selector: selector}
+	disabled := false
+	if install != nil && install.PodDisruptionBudgets != nil {
+		disabled = install.PodDisruptionBudgets.Disabled
+		for _, c := range install.PodDisruptionBudgets.Components {
+			if c.Name != name {
+				continue
+			}
+			if c.Disabled {
+				disabled = true
+			}
+			if c.MinAvailable != nil {
+				spec.MinAvailable = c.MinAvailable
+				spec.MaxUnavailable = nil
+			} else if c.MaxUnavailable != nil {
+				spec.MaxUnavailable = c.MaxUnavailable
+			}
+		}
+	}
+
+	var pdb client.Object
+	if usePolicyV1 {
+		pdb = &policyv1.PodDisruptionBudget{
+			TypeMeta:   metav1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       spec,
+		}
+	} else {
+		pdb = &policyv1beta1.PodDisruptionBudget{
+			TypeMeta:   metav1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1beta1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				MinAvailable:   spec.MinAvailable,
+				MaxUnavailable: spec.MaxUnavailable,
+				Selector:       spec.Selector,
+			},
+		}
+	}
+	if disabled {
+		return nil, pdb
+	}
+	return pdb, nil
+}
+
+// ForDeployment returns the PodDisruptionBudget for the Deployment, named after it, as configured by the Installation.
+// Exactly one of the returned objects is non-nil: the budget to create, or the budget to delete because the Deployment
+// runs a single replica or the budget has been disabled. A single replica Deployment never gets a budget, since it
+// would either not protect the pod or block node drains.
+func ForDeployment(install *operatorv1.InstallationSpec, d *appsv1.Deployment, usePolicyV1 bool) (client.Object, client.Object) {
+	toCreate, toDelete := New(install, d.Name, d.Namespace, d.Spec.Selector, usePolicyV1)
+	if toCreate != nil && (d.Spec.Replicas == nil || *d.Spec.Replicas < 2) {
+		return nil, toCreate
+	}
+	return toCreate, toDelete
+}
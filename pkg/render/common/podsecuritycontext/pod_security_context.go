@@ -6,10 +6,16 @@ import (
 )
 
 // NewBaseContext returns the non root non privileged security context that most of the containers running should
-// be using.
+// be using. It satisfies the restricted Pod Security Standard.
 func NewBaseContext() *corev1.SecurityContext {
 	return &corev1.SecurityContext{
 		RunAsNonRoot:             ptr.BoolToPtr(true),
 		AllowPrivilegeEscalation: ptr.BoolToPtr(false),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
 	}
 }
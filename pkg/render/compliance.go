@@ -32,9 +32,11 @@ import (
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/render/common/authentication"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
@@ -79,6 +81,7 @@ type ComplianceConfiguration struct {
 	ESClusterConfig             *relasticsearch.ClusterConfig
 	PullSecrets                 []*corev1.Secret
 	Openshift                   bool
+	UsePSP                      bool
 	ManagementCluster           *operatorv1.ManagementCluster
 	ManagementClusterConnection *operatorv1.ManagementClusterConnection
 	KeyValidatorConfig          authentication.KeyValidatorConfig
@@ -148,7 +151,7 @@ func (c *complianceComponent) SupportedOSType() rmeta.OSType {
 
 func (c *complianceComponent) Objects() ([]client.Object, []client.Object) {
 	complianceObjs := append(
-		[]client.Object{CreateNamespace(ComplianceNamespace, c.cfg.Installation.KubernetesProvider)},
+		[]client.Object{CreateNamespace(ComplianceNamespace, c.cfg.Installation.KubernetesProvider, PSSPrivileged)},
 		secret.ToRuntimeObjects(secret.CopyToNamespace(ComplianceNamespace, c.cfg.PullSecrets...)...)...,
 	)
 	complianceObjs = append(complianceObjs,
@@ -220,7 +223,7 @@ func (c *complianceComponent) Objects() ([]client.Object, []client.Object) {
 
 	if c.cfg.Openshift {
 		complianceObjs = append(complianceObjs, c.complianceBenchmarkerSecurityContextConstraints())
-	} else {
+	} else if c.cfg.UsePSP {
 		complianceObjs = append(complianceObjs,
 			c.complianceBenchmarkerPodSecurityPolicy(),
 			c.complianceControllerPodSecurityPolicy(),
@@ -248,6 +251,32 @@ func (c *complianceComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policies for compliance. The server is only reached through the manager,
+// and all the compliance pods read from the Kubernetes API and write their results to Elasticsearch.
+func (c *complianceComponent) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{})...)
+	egress = append(egress, networkpolicy.ESGatewayEgressRule())
+	return []client.Object{
+		networkpolicy.New(
+			"compliance",
+			ComplianceNamespace,
+			"all()",
+			[]v3.PolicyType{v3.PolicyTypeEgress},
+			nil,
+			egress,
+		),
+		networkpolicy.New(
+			ComplianceServerName,
+			ComplianceNamespace,
+			fmt.Sprintf("k8s-app == '%s'", ComplianceServerName),
+			[]v3.PolicyType{v3.PolicyTypeIngress},
+			[]v3.Rule{networkpolicy.AllowTCP(networkpolicy.Pods(ManagerNamespace, "all()"), networkpolicy.Ports(complianceServerPort))},
+			nil,
+		),
+	}
+}
+
 var complianceBoolTrue = true
 var complianceReplicas int32 = 1
 
@@ -47,6 +47,7 @@ var _ = Describe("compliance rendering tests", func() {
 			ESClusterConfig:            relasticsearch.NewClusterConfig("cluster", 1, 1, 1),
 			Openshift:                  notOpenshift,
 			ClusterDomain:              clusterDomain,
+			UsePSP:                     true,
 		}
 	})
 
@@ -16,11 +16,13 @@ package render
 
 import (
 	"bytes"
+	gocrypto "crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"fmt"
 	"math/big"
 	mrand "math/rand"
 	"strings"
@@ -44,11 +46,17 @@ const (
 // Creates a secret that will store the CA needed to generated certificates
 // for managed cluster registration
 func voltronTunnelSecret() *corev1.Secret {
+	return CreateVoltronTunnelSecret(VoltronTunnelSecretName)
+}
+
+// CreateVoltronTunnelSecret creates a tunnel CA secret with the given name in the operator namespace, such as
+// the CA a rotation of the tunnel CA moves to.
+func CreateVoltronTunnelSecret(name string) *corev1.Secret {
 	key, cert := createSelfSignedSecret("tigera-voltron", []string{VoltronDnsName})
 	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      VoltronTunnelSecretName,
+			Name:      name,
 			Namespace: common.OperatorNamespace(),
 		},
 		Data: map[string][]byte{
@@ -102,6 +110,62 @@ func createSelfSignedSecret(cn string, altNames []string) (string, string) {
 	return keyPem.String(), certPem.String()
 }
 
+// createCASignedCert creates a client certificate for the given common name, signed by the tunnel CA in the secret.
+func createCASignedCert(cn string, ca *corev1.Secret) (string, string, error) {
+	caCert, caKey, err := parseCertPair(ca.Data[VoltronTunnelSecretCertName], ca.Data[VoltronTunnelSecretKeyName])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid CA in secret %s: %w", ca.Name, err)
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, VoltronKeySizeBits)
+	if err != nil {
+		return "", "", err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              caCert.NotAfter,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &privateKey.PublicKey, caKey)
+	if err != nil {
+		return "", "", err
+	}
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: blockTypePrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	certPem := pem.EncodeToMemory(&pem.Block{Type: blockTypeCert, Bytes: cert})
+	return string(keyPem), string(certPem), nil
+}
+
+// parseCertPair parses the PEM encoded certificate and its private key.
+func parseCertPair(certPem, keyPem []byte) (*x509.Certificate, gocrypto.PrivateKey, error) {
+	certBlock, _ := pem.Decode(certPem)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("no certificate found")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBlock, _ := pem.Decode(keyPem)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("no private key found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes); err == nil {
+		return cert, key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
 func template(cn string, altNames []string) *x509.Certificate {
 	return &x509.Certificate{
 		IsCA:                  true,
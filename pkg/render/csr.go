@@ -20,7 +20,7 @@ import (
 	"strings"
 
 	"github.com/tigera/operator/pkg/components"
-	"github.com/tigera/operator/pkg/ptr"
+	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -81,10 +81,7 @@ func CreateCSRInitContainer(
 				},
 			}},
 		},
-		SecurityContext: &corev1.SecurityContext{
-			Privileged:               ptr.BoolToPtr(false),
-			AllowPrivilegeEscalation: ptr.BoolToPtr(false),
-		},
+		SecurityContext: podsecuritycontext.NewBaseContext(),
 	}
 }
 
@@ -18,12 +18,16 @@ import (
 	"fmt"
 	"strings"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/dns"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
+	"github.com/tigera/operator/pkg/render/common/poddisruptionbudget"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/secret"
 	"gopkg.in/yaml.v2"
@@ -69,6 +73,9 @@ type DexComponentConfiguration struct {
 	DexConfig     DexConfig
 	ClusterDomain string
 	DeleteDex     bool
+
+	// UsePolicyV1PDB is true if the cluster serves PodDisruptionBudgets from the policy/v1 API.
+	UsePolicyV1PDB bool
 }
 
 type dexComponent struct {
@@ -108,9 +115,10 @@ func (*dexComponent) SupportedOSType() rmeta.OSType {
 }
 
 func (c *dexComponent) Objects() ([]client.Object, []client.Object) {
+	deployment := c.deployment()
 	objs := []client.Object{
 		c.serviceAccount(),
-		c.deployment(),
+		deployment,
 		c.service(),
 		c.clusterRole(),
 		c.clusterRoleBinding(),
@@ -132,11 +140,18 @@ func (c *dexComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, CSRClusterRoleBinding(DexObjectName, DexNamespace))
 	}
 
+	var toDelete []client.Object
+	if pdb, del := poddisruptionbudget.ForDeployment(c.cfg.Installation, deployment, c.cfg.UsePolicyV1PDB); pdb != nil {
+		objs = append(objs, pdb)
+	} else {
+		toDelete = append(toDelete, del)
+	}
+
 	if c.cfg.DeleteDex {
-		return nil, objs
+		return nil, append(objs, toDelete...)
 	}
 
-	return objs, nil
+	return objs, toDelete
 }
 
 // Method to satisfy the Component interface.
@@ -144,6 +159,22 @@ func (c *dexComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for Dex, which is reached by the manager, Kibana and the users they
+// redirect to it. The identity providers it connects to, such as LDAP servers, can be served on any port, so its TCP
+// egress is not restricted to particular destinations.
+func (c *dexComponent) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, v3.EntityRule{}))
+	return []client.Object{networkpolicy.New(
+		DexObjectName,
+		DexNamespace,
+		fmt.Sprintf("k8s-app == '%s'", DexObjectName),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(DexPort))},
+		egress,
+	)}
+}
+
 func (c *dexComponent) serviceAccount() *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
 		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
@@ -193,7 +224,7 @@ func (c *dexComponent) clusterRoleBinding() client.Object {
 	}
 }
 
-func (c *dexComponent) deployment() client.Object {
+func (c *dexComponent) deployment() *appsv1.Deployment {
 	var initContainers []corev1.Container
 	if c.cfg.Installation.CertificateManagement != nil {
 		initContainers = append(initContainers, CreateCSRInitContainer(
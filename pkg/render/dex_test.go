@@ -120,6 +120,7 @@ var _ = Describe("dex rendering tests", func() {
 				{render.DexObjectName, render.DexNamespace, "", "v1", "Secret"},
 				{render.OIDCSecretName, render.DexNamespace, "", "v1", "Secret"},
 				{pullSecretName, render.DexNamespace, "", "v1", "Secret"},
+				{render.DexObjectName, render.DexNamespace, "policy", "v1beta1", "PodDisruptionBudget"},
 			}
 
 			for i, expectedRes := range expectedResources {
@@ -188,6 +189,7 @@ var _ = Describe("dex rendering tests", func() {
 				{render.OIDCSecretName, render.DexNamespace, "", "v1", "Secret"},
 				{pullSecretName, render.DexNamespace, "", "v1", "Secret"},
 				{"tigera-dex:csr-creator", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding"},
+				{render.DexObjectName, render.DexNamespace, "policy", "v1beta1", "PodDisruptionBudget"},
 			}
 
 			for i, expectedRes := range expectedResources {
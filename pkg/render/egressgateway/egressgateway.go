@@ -0,0 +1,282 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/ptr"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/poddisruptionbudget"
+	"github.com/tigera/operator/pkg/render/common/secret"
+)
+
+const (
+	// GatewayLabel is set on the pods of an egress gateway to the name of its EgressGateway.
+	GatewayLabel = "operator.tigera.io/egress-gateway"
+
+	// IPPoolManagedByLabelValue is the value of render.IPPoolManagedByLabel on the dedicated IPPools of the egress
+	// gateways, which sets them apart from the IPPools of the Installation.
+	IPPoolManagedByLabelValue = "tigera-operator-egress-gateway"
+	IPPoolNamePrefix          = "egress-gateway."
+
+	// The VXLAN port and VNI of the egress gateway tunnels, which are the Felix defaults.
+	VXLANPort = 4790
+	VXLANVNI  = 4097
+
+	HealthPort = 8080
+
+	PolicySyncVolumeName = "policysync"
+	PolicySyncPathPrefix = "/var/run/nodeagent"
+
+	DefaultBlockSize                     = 31
+	DefaultLogSeverity                   = "Info"
+	DefaultHealthTimeoutDataStoreSeconds = 90
+	DefaultICMPProbeIntervalSeconds      = 5
+	DefaultICMPProbeTimeoutSeconds       = 15
+	DefaultHTTPProbeIntervalSeconds      = 10
+	DefaultHTTPProbeTimeoutSeconds       = 30
+)
+
+// Config contains all the config information needed to render the component.
+type Config struct {
+	Installation  *operatorv1.InstallationSpec
+	PullSecrets   []*corev1.Secret
+	EgressGateway *operatorv1.EgressGateway
+
+	// UsePolicyV1PDB is true if the cluster serves PodDisruptionBudgets from the policy/v1 API.
+	UsePolicyV1PDB bool
+}
+
+// EgressGateway renders the Deployment of an egress gateway in the namespace of its EgressGateway. The defaults
+// are expected to be set on the EgressGateway.
+func EgressGateway(cfg *Config) render.Component {
+	return &component{cfg: cfg}
+}
+
+type component struct {
+	cfg   *Config
+	image string
+}
+
+func (c *component) ResolveImages(is *operatorv1.ImageSet) error {
+	reg := c.cfg.Installation.Registry
+	path := c.cfg.Installation.ImagePath
+	prefix := c.cfg.Installation.ImagePrefix
+	var err error
+	c.image, err = components.GetReference(components.ComponentEgressGateway, reg, path, prefix, is)
+	return err
+}
+
+func (c *component) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *component) Ready() bool {
+	return true
+}
+
+func (c *component) Objects() ([]client.Object, []client.Object) {
+	gw := c.cfg.EgressGateway
+	objs := secret.ToRuntimeObjects(secret.CopyToNamespace(gw.Namespace, c.cfg.PullSecrets...)...)
+	deployment := c.deployment()
+	objs = append(objs, deployment)
+
+	var toDelete []client.Object
+	if pdb, del := poddisruptionbudget.ForDeployment(c.cfg.Installation, deployment, c.cfg.UsePolicyV1PDB); pdb != nil {
+		objs = append(objs, pdb)
+	} else {
+		toDelete = append(toDelete, del)
+	}
+	return objs, toDelete
+}
+
+func (c *component) deployment() *appsv1.Deployment {
+	gw := c.cfg.EgressGateway
+	labels := map[string]string{}
+	for k, v := range gw.Spec.Labels {
+		labels[k] = v
+	}
+	labels[GatewayLabel] = gw.Name
+
+	podIP := corev1.EnvVar{Name: "EGRESS_POD_IP", ValueFrom: &corev1.EnvVarSource{
+		FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+	}}
+	vni := corev1.EnvVar{Name: "EGRESS_VXLAN_VNI", Value: fmt.Sprint(VXLANVNI)}
+
+	container := corev1.Container{
+		Name:  "egress-gateway",
+		Image: c.image,
+		Env: append([]corev1.EnvVar{
+			podIP,
+			vni,
+			{Name: "LOG_SEVERITY", Value: *gw.Spec.LogSeverity},
+			{Name: "HEALTH_PORT", Value: fmt.Sprint(HealthPort)},
+		}, failureDetectionEnvVars(gw.Spec.FailureDetection)...),
+		SecurityContext: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+		},
+		Ports: []corev1.ContainerPort{{Name: "health", ContainerPort: HealthPort}},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/readiness", Port: intstr.FromInt(HealthPort)},
+			},
+			InitialDelaySeconds: 3,
+			PeriodSeconds:       3,
+		},
+		// Felix reports the health of the egress gateway to the pod through the policy sync socket.
+		VolumeMounts: []corev1.VolumeMount{{Name: PolicySyncVolumeName, MountPath: "/var/run/calico"}},
+	}
+	if gw.Spec.Resources != nil {
+		container.Resources = *gw.Spec.Resources
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gw.Name,
+			Namespace: gw.Namespace,
+			Labels:    map[string]string{GatewayLabel: gw.Name},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: gw.Spec.Replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{GatewayLabel: gw.Name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"cni.projectcalico.org/ipv4pools": fmt.Sprintf(`["%s"]`, IPPoolName(gw)),
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:     gw.Spec.NodeSelector,
+					Tolerations:      gw.Spec.Tolerations,
+					ImagePullSecrets: secret.GetReferenceList(c.cfg.PullSecrets),
+					InitContainers: []corev1.Container{{
+						Name:    "egress-gateway-init",
+						Image:   c.image,
+						Command: []string{"/init-gateway.sh"},
+						Env: []corev1.EnvVar{
+							podIP,
+							{Name: "EGRESS_VXLAN_PORT", Value: fmt.Sprint(VXLANPort)},
+							vni,
+						},
+						SecurityContext: &corev1.SecurityContext{Privileged: ptr.BoolToPtr(true)},
+					}},
+					Containers:                    []corev1.Container{container},
+					TerminationGracePeriodSeconds: ptr.Int64ToPtr(0),
+					Volumes: []corev1.Volume{{
+						Name: PolicySyncVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							FlexVolume: &corev1.FlexVolumeSource{Driver: "nodeagent/uds"},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func failureDetectionEnvVars(fd *operatorv1.EgressGatewayFailureDetection) []corev1.EnvVar {
+	seconds := func(s *int32) string { return fmt.Sprintf("%ds", *s) }
+	env := []corev1.EnvVar{{Name: "HEALTH_TIMEOUT_DATASTORE", Value: seconds(fd.HealthTimeoutDataStoreSeconds)}}
+	if p := fd.ICMPProbe; p != nil {
+		env = append(env,
+			corev1.EnvVar{Name: "ICMP_PROBE_IPS", Value: strings.Join(p.IPs, ",")},
+			corev1.EnvVar{Name: "ICMP_PROBE_INTERVAL", Value: seconds(p.IntervalSeconds)},
+			corev1.EnvVar{Name: "ICMP_PROBE_TIMEOUT", Value: seconds(p.TimeoutSeconds)},
+		)
+	}
+	if p := fd.HTTPProbe; p != nil {
+		env = append(env,
+			corev1.EnvVar{Name: "HTTP_PROBE_URLS", Value: strings.Join(p.URLs, ",")},
+			corev1.EnvVar{Name: "HTTP_PROBE_INTERVAL", Value: seconds(p.IntervalSeconds)},
+			corev1.EnvVar{Name: "HTTP_PROBE_TIMEOUT", Value: seconds(p.TimeoutSeconds)},
+		)
+	}
+	return env
+}
+
+// IPPoolName returns the name of the dedicated IPPool of the egress gateway. Namespaces cannot contain dots, so the
+// name is unique.
+func IPPoolName(gw *operatorv1.EgressGateway) string {
+	return IPPoolNamePrefix + gw.Namespace + "." + gw.Name
+}
+
+// IPPools renders the dedicated IPPool of each egress gateway. The IPPools don't select any node, so that other
+// pods are not assigned addresses from them, and the stale IPPools of egress gateways that no longer exist are
+// deleted.
+func IPPools(gateways []operatorv1.EgressGateway, stalePools []string) render.Component {
+	return &ipPoolsComponent{gateways: gateways, stalePools: stalePools}
+}
+
+type ipPoolsComponent struct {
+	gateways   []operatorv1.EgressGateway
+	stalePools []string
+}
+
+func (c *ipPoolsComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on IP pools.
+	return nil
+}
+
+func (c *ipPoolsComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *ipPoolsComponent) Ready() bool {
+	return true
+}
+
+func (c *ipPoolsComponent) Objects() ([]client.Object, []client.Object) {
+	var objs []client.Object
+	for i := range c.gateways {
+		gw := &c.gateways[i]
+		objs = append(objs, &crdv1.IPPool{
+			TypeMeta: metav1.TypeMeta{Kind: crdv1.KindIPPool, APIVersion: "crd.projectcalico.org/v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   IPPoolName(gw),
+				Labels: map[string]string{render.IPPoolManagedByLabel: IPPoolManagedByLabelValue},
+			},
+			Spec: crdv1.IPPoolSpec{
+				CIDR:         gw.Spec.IPPool.CIDR,
+				BlockSize:    int(*gw.Spec.IPPool.BlockSize),
+				IPIPMode:     crdv1.IPIPModeNever,
+				VXLANMode:    crdv1.VXLANModeNever,
+				NodeSelector: "!all()",
+			},
+		})
+	}
+
+	var toDelete []client.Object
+	for _, name := range c.stalePools {
+		toDelete = append(toDelete, &crdv1.IPPool{
+			TypeMeta:   metav1.TypeMeta{Kind: crdv1.KindIPPool, APIVersion: "crd.projectcalico.org/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		})
+	}
+	return objs, toDelete
+}
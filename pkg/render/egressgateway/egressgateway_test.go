@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+	"github.com/tigera/operator/pkg/render/egressgateway"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Egress gateway rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+	var gw *operatorv1.EgressGateway
+
+	BeforeEach(func() {
+		installation = &operatorv1.InstallationSpec{
+			KubernetesProvider: operatorv1.ProviderNone,
+		}
+		var replicas int32 = 2
+		var blockSize int32 = 31
+		var healthTimeout int32 = 90
+		logSeverity := "Info"
+		gw = &operatorv1.EgressGateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "red", Namespace: "app"},
+			Spec: operatorv1.EgressGatewaySpec{
+				Replicas:    &replicas,
+				IPPool:      operatorv1.EgressGatewayIPPool{CIDR: "10.10.10.0/30", BlockSize: &blockSize},
+				Labels:      map[string]string{"egress-code": "red"},
+				LogSeverity: &logSeverity,
+				FailureDetection: &operatorv1.EgressGatewayFailureDetection{
+					HealthTimeoutDataStoreSeconds: &healthTimeout,
+				},
+			},
+		}
+	})
+
+	It("should render the Deployment of the egress gateway", func() {
+		component := egressgateway.EgressGateway(&egressgateway.Config{
+			Installation: installation,
+			PullSecrets: []*corev1.Secret{{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: "tigera-operator"},
+			}},
+			EgressGateway: gw,
+		})
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		resources, _ := component.Objects()
+		rtest.ExpectResourceInList(resources, "pull-secret", "app", "", "v1", "Secret")
+		rtest.ExpectResourceInList(resources, "red", "app", "apps", "v1", "Deployment")
+		rtest.ExpectResourceInList(resources, "red", "app", "policy", "v1beta1", "PodDisruptionBudget")
+
+		d := rtest.GetResource(resources, "red", "app", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(*d.Spec.Replicas).To(BeEquivalentTo(2))
+		Expect(d.Spec.Selector.MatchLabels).To(Equal(map[string]string{egressgateway.GatewayLabel: "red"}))
+		Expect(d.Spec.Template.Labels).To(Equal(map[string]string{egressgateway.GatewayLabel: "red", "egress-code": "red"}))
+		Expect(d.Spec.Template.Annotations).To(HaveKeyWithValue("cni.projectcalico.org/ipv4pools", `["egress-gateway.app.red"]`))
+		Expect(d.Spec.Template.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "pull-secret"}}))
+
+		Expect(d.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		init := d.Spec.Template.Spec.InitContainers[0]
+		Expect(*init.SecurityContext.Privileged).To(BeTrue())
+		rtest.ExpectEnv(init.Env, "EGRESS_VXLAN_PORT", "4790")
+		rtest.ExpectEnv(init.Env, "EGRESS_VXLAN_VNI", "4097")
+
+		Expect(d.Spec.Template.Spec.Containers).To(HaveLen(1))
+		container := d.Spec.Template.Spec.Containers[0]
+		Expect(container.SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("NET_ADMIN")))
+		rtest.ExpectEnv(container.Env, "LOG_SEVERITY", "Info")
+		rtest.ExpectEnv(container.Env, "HEALTH_TIMEOUT_DATASTORE", "90s")
+		rtest.ExpectVolumeMount(container.VolumeMounts, egressgateway.PolicySyncVolumeName, "/var/run/calico")
+		Expect(container.ReadinessProbe.HTTPGet.Path).To(Equal("/readiness"))
+		for _, env := range container.Env {
+			Expect(env.Name).NotTo(HavePrefix("ICMP_PROBE"))
+			Expect(env.Name).NotTo(HavePrefix("HTTP_PROBE"))
+		}
+	})
+
+	It("should render the probes of the egress gateway", func() {
+		var interval, timeout int32 = 5, 15
+		gw.Spec.FailureDetection.ICMPProbe = &operatorv1.EgressGatewayICMPProbe{
+			IPs:             []string{"10.0.0.1", "10.0.0.2"},
+			IntervalSeconds: &interval,
+			TimeoutSeconds:  &timeout,
+		}
+		gw.Spec.FailureDetection.HTTPProbe = &operatorv1.EgressGatewayHTTPProbe{
+			URLs:            []string{"http://example.com/health"},
+			IntervalSeconds: &interval,
+			TimeoutSeconds:  &timeout,
+		}
+		component := egressgateway.EgressGateway(&egressgateway.Config{
+			Installation:  installation,
+			EgressGateway: gw,
+		})
+		resources, _ := component.Objects()
+
+		d := rtest.GetResource(resources, "red", "app", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		env := d.Spec.Template.Spec.Containers[0].Env
+		rtest.ExpectEnv(env, "ICMP_PROBE_IPS", "10.0.0.1,10.0.0.2")
+		rtest.ExpectEnv(env, "ICMP_PROBE_INTERVAL", "5s")
+		rtest.ExpectEnv(env, "ICMP_PROBE_TIMEOUT", "15s")
+		rtest.ExpectEnv(env, "HTTP_PROBE_URLS", "http://example.com/health")
+		rtest.ExpectEnv(env, "HTTP_PROBE_INTERVAL", "5s")
+		rtest.ExpectEnv(env, "HTTP_PROBE_TIMEOUT", "15s")
+	})
+
+	It("should render the IPPools of the egress gateways and delete the stale ones", func() {
+		component := egressgateway.IPPools([]operatorv1.EgressGateway{*gw}, []string{"egress-gateway.app.blue"})
+		resources, toDelete := component.Objects()
+		Expect(resources).To(HaveLen(1))
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].GetName()).To(Equal("egress-gateway.app.blue"))
+
+		pool := resources[0].(*crdv1.IPPool)
+		Expect(pool.Name).To(Equal("egress-gateway.app.red"))
+		Expect(pool.Labels).To(Equal(map[string]string{render.IPPoolManagedByLabel: egressgateway.IPPoolManagedByLabelValue}))
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "10.10.10.0/30",
+			BlockSize:    31,
+			IPIPMode:     crdv1.IPIPModeNever,
+			VXLANMode:    crdv1.VXLANModeNever,
+			NodeSelector: "!all()",
+		}))
+	})
+})
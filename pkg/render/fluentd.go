@@ -21,6 +21,7 @@ import (
 
 	"github.com/tigera/operator/pkg/url"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -30,9 +31,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/resourcequota"
 	"github.com/tigera/operator/pkg/render/common/secret"
@@ -77,6 +81,8 @@ const (
 	probeFailureThreshold        int32 = 3
 	startupProbeFailureThreshold int32 = 10
 
+	fluentdMetricsPort = 9081
+
 	fluentdName        = "tigera-fluentd"
 	fluentdWindowsName = "tigera-fluentd-windows"
 
@@ -143,6 +149,9 @@ type FluentdConfiguration struct {
 	OSType          rmeta.OSType
 	TLS             *corev1.Secret
 	TrustedBundle   *corev1.ConfigMap
+
+	// UsePSP is true if the cluster serves the PodSecurityPolicy API and PSPs should be rendered.
+	UsePSP bool
 }
 
 type fluentdComponent struct {
@@ -237,7 +246,8 @@ func (c *fluentdComponent) Objects() ([]client.Object, []client.Object) {
 	objs = append(objs,
 		CreateNamespace(
 			LogCollectorNamespace,
-			c.cfg.Installation.KubernetesProvider))
+			c.cfg.Installation.KubernetesProvider,
+			PSSPrivileged))
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(LogCollectorNamespace, c.cfg.PullSecrets...)...)...)
 	objs = append(objs, c.metricsService())
 
@@ -258,7 +268,7 @@ func (c *fluentdComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, c.filtersConfigMap())
 	}
 	if c.cfg.EKSConfig != nil && c.cfg.OSType == rmeta.OSTypeLinux {
-		if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+		if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift && c.cfg.UsePSP {
 			objs = append(objs,
 				c.eksLogForwarderClusterRole(),
 				c.eksLogForwarderClusterRoleBinding(),
@@ -271,7 +281,7 @@ func (c *fluentdComponent) Objects() ([]client.Object, []client.Object) {
 
 	// Windows PSP does not support allowedHostPaths yet.
 	// See: https://github.com/kubernetes/kubernetes/issues/93165#issuecomment-693049808
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift && c.cfg.OSType == rmeta.OSTypeLinux {
+	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift && c.cfg.OSType == rmeta.OSTypeLinux && c.cfg.UsePSP {
 		objs = append(objs,
 			c.fluentdClusterRole(),
 			c.fluentdClusterRoleBinding(),
@@ -302,6 +312,30 @@ func (c *fluentdComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for fluentd, which Prometheus scrapes the metrics of. Fluentd sends
+// the logs to Elasticsearch, through guardian in a managed cluster, and to the additional stores configured by users.
+// Syslog can be reached over UDP and the stores on any port, so egress is not restricted when they are configured.
+func (c *fluentdComponent) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{})...)
+	egress = append(egress,
+		networkpolicy.ESGatewayEgressRule(),
+		networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(GuardianNamespace, fmt.Sprintf("k8s-app == '%s'", GuardianName), guardianTargetPort)),
+	)
+	if c.cfg.LogCollector != nil && c.cfg.LogCollector.Spec.AdditionalStores != nil {
+		egress = append(egress, v3.Rule{Action: v3.Allow})
+	}
+
+	return []client.Object{networkpolicy.New(
+		c.fluentdNodeName(),
+		LogCollectorNamespace,
+		fmt.Sprintf("k8s-app == '%s'", c.fluentdNodeName()),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(networkpolicy.Pods(common.TigeraPrometheusNamespace, "all()"), networkpolicy.Ports(fluentdMetricsPort))},
+		egress,
+	)}
+}
+
 func (c *fluentdComponent) fluentdResourceQuota() *corev1.ResourceQuota {
 	criticalPriorityClasses := []string{NodePriorityClassName}
 	return resourcequota.ResourceQuotaForPriorityClassScope(resourcequota.TigeraCriticalResourceQuotaName, LogCollectorNamespace, criticalPriorityClasses)
@@ -584,7 +618,7 @@ func (c *fluentdComponent) container() corev1.Container {
 		ReadinessProbe:  c.readiness(),
 		Ports: []corev1.ContainerPort{{
 			Name:          "metrics-port",
-			ContainerPort: 9081,
+			ContainerPort: fluentdMetricsPort,
 		}},
 	}, c.cfg.ESClusterConfig.ClusterName(), ElasticsearchLogCollectorUserSecret, c.cfg.ClusterDomain, c.cfg.OSType)
 }
@@ -603,8 +637,8 @@ func (c *fluentdComponent) metricsService() *corev1.Service {
 			Ports: []corev1.ServicePort{
 				{
 					Name:       FluentdMetricsPort,
-					Port:       int32(9081),
-					TargetPort: intstr.FromInt(9081),
+					Port:       int32(fluentdMetricsPort),
+					TargetPort: intstr.FromInt(fluentdMetricsPort),
 					Protocol:   corev1.ProtocolTCP,
 				},
 			},
@@ -49,6 +49,7 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 			},
 			TLS:           rtest.CreateCertSecret(render.FluentdPrometheusTLSSecretName, common.OperatorNamespace()),
 			TrustedBundle: render.CreateCertificateConfigMap("test", render.PrometheusCABundle, render.LogCollectorNamespace),
+			UsePSP:        true,
 		}
 	})
 
@@ -155,6 +156,16 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 		Expect(rtest.GetResource(resources, "tigera-critical-pods", "tigera-fluentd", "", "v1", "ResourceQuota")).ToNot(BeNil())
 	})
 
+	It("should not render PodSecurityPolicies when they are not supported", func() {
+		cfg.UsePSP = false
+		component := render.Fluentd(cfg)
+		resources, _ := component.Objects()
+
+		Expect(rtest.GetResource(resources, "tigera-fluentd", "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+		Expect(rtest.GetResource(resources, "tigera-fluentd", "", "rbac.authorization.k8s.io", "v1", "ClusterRole")).To(BeNil())
+		Expect(rtest.GetResource(resources, "fluentd-node", "tigera-fluentd", "apps", "v1", "DaemonSet")).NotTo(BeNil())
+	})
+
 	It("should render for Windows nodes", func() {
 		expectedResources := []struct {
 			name    string
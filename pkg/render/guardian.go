@@ -17,6 +17,10 @@
 package render
 
 import (
+	"fmt"
+	"strings"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,6 +31,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/components"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
@@ -42,6 +47,23 @@ const (
 	GuardianServiceName            = "tigera-guardian"
 	GuardianVolumeName             = "tigera-guardian-certs"
 	GuardianSecretName             = "tigera-managed-cluster-connection"
+
+	// The secrets in the operator namespace that hold the CA certificate of the proxy guardian reaches the
+	// management cluster through, and the credentials it authenticates to the proxy with.
+	GuardianProxyCASecretName          = "tigera-guardian-proxy-ca"
+	GuardianProxyCredentialsSecretName = "tigera-guardian-proxy-credentials"
+	GuardianProxyUsernameKey           = "username"
+	GuardianProxyPasswordKey           = "password"
+
+	// guardianTargetPort is the port guardian serves the Elasticsearch and Kibana requests of the managed cluster on.
+	guardianTargetPort = 8080
+
+	// GuardianAddrAnnotation records on the guardian pods the management cluster address they connect to, so the
+	// clusterconnection controller can tell how long guardian has been failing to reach it.
+	GuardianAddrAnnotation = "operator.tigera.io/management-cluster-addr"
+
+	guardianProxyCAHashAnnotation          = "hash.operator.tigera.io/guardian-proxy-ca"
+	guardianProxyCredentialsHashAnnotation = "hash.operator.tigera.io/guardian-proxy-credentials"
 )
 
 func Guardian(cfg *GuardianConfiguration) Component {
@@ -59,6 +81,12 @@ type GuardianConfiguration struct {
 	TunnelSecret         *corev1.Secret
 	PacketCaptureSecret  *corev1.Secret
 	PrometheusCertSecret *corev1.Secret
+	ClusterDomain        string
+
+	// ProxyURL is the HTTP CONNECT proxy guardian opens the tunnel through, if any.
+	ProxyURL               string
+	ProxyCASecret          *corev1.Secret
+	ProxyCredentialsSecret *corev1.Secret
 }
 
 type GuardianComponent struct {
@@ -81,7 +109,7 @@ func (c *GuardianComponent) SupportedOSType() rmeta.OSType {
 
 func (c *GuardianComponent) Objects() ([]client.Object, []client.Object) {
 	objs := []client.Object{
-		CreateNamespace(GuardianNamespace, c.cfg.Installation.KubernetesProvider),
+		CreateNamespace(GuardianNamespace, c.cfg.Installation.KubernetesProvider, PSSRestricted),
 	}
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(GuardianNamespace, c.cfg.PullSecrets...)...)...)
 	objs = append(objs,
@@ -93,7 +121,7 @@ func (c *GuardianComponent) Objects() ([]client.Object, []client.Object) {
 		secret.CopyToNamespace(GuardianNamespace, c.cfg.TunnelSecret)[0],
 		secret.CopyToNamespace(GuardianNamespace, c.cfg.PacketCaptureSecret)[0],
 		// Add tigera-manager service account for impersonation
-		CreateNamespace(ManagerNamespace, c.cfg.Installation.KubernetesProvider),
+		CreateNamespace(ManagerNamespace, c.cfg.Installation.KubernetesProvider, PSSRestricted),
 		managerServiceAccount(),
 		managerClusterRole(false, true, c.cfg.Openshift),
 		managerClusterRoleBinding(),
@@ -106,13 +134,48 @@ func (c *GuardianComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(GuardianNamespace, c.cfg.PrometheusCertSecret)...)...)
 	}
 
-	return objs, nil
+	var toDelete []client.Object
+	if c.cfg.ProxyCASecret != nil {
+		objs = append(objs, secret.CopyToNamespace(GuardianNamespace, c.cfg.ProxyCASecret)[0])
+	} else {
+		toDelete = append(toDelete, &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: GuardianProxyCASecretName, Namespace: GuardianNamespace},
+		})
+	}
+	if c.cfg.ProxyCredentialsSecret != nil {
+		objs = append(objs, secret.CopyToNamespace(GuardianNamespace, c.cfg.ProxyCredentialsSecret)[0])
+	} else {
+		toDelete = append(toDelete, &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: GuardianProxyCredentialsSecretName, Namespace: GuardianNamespace},
+		})
+	}
+
+	return objs, toDelete
 }
 
 func (c *GuardianComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for guardian, which the components of the managed cluster reach
+// Elasticsearch and Kibana through. Guardian opens the tunnel to the management cluster, possibly through a proxy,
+// and serves the requests of the management cluster manager from the APIs of the managed cluster, so its TCP egress
+// is not restricted to particular destinations.
+func (c *GuardianComponent) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, v3.EntityRule{}))
+	return []client.Object{networkpolicy.New(
+		GuardianName,
+		GuardianNamespace,
+		fmt.Sprintf("k8s-app == '%s'", GuardianName),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(guardianTargetPort))},
+		egress,
+	)}
+}
+
 func (c *GuardianComponent) service() *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -129,7 +192,7 @@ func (c *GuardianComponent) service() *corev1.Service {
 					Port: 9200,
 					TargetPort: intstr.IntOrString{
 						Type:   intstr.Int,
-						IntVal: 8080,
+						IntVal: guardianTargetPort,
 					},
 					Protocol: corev1.ProtocolTCP,
 				},
@@ -138,7 +201,7 @@ func (c *GuardianComponent) service() *corev1.Service {
 					Port: 5601,
 					TargetPort: intstr.IntOrString{
 						Type:   intstr.Int,
-						IntVal: 8080,
+						IntVal: guardianTargetPort,
 					},
 					Protocol: corev1.ProtocolTCP,
 				},
@@ -221,6 +284,7 @@ func (c *GuardianComponent) deployment() client.Object {
 					Labels: map[string]string{
 						"k8s-app": GuardianName,
 					},
+					Annotations: c.annotations(),
 				},
 				Spec: corev1.PodSpec{
 					NodeSelector:       c.cfg.Installation.ControlPlaneNodeSelector,
@@ -269,19 +333,29 @@ func (c *GuardianComponent) volumes() []corev1.Volume {
 			},
 		})
 	}
+	if c.cfg.ProxyCASecret != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: GuardianProxyCASecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					Items: []corev1.KeyToPath{{
+						Key:  corev1.TLSCertKey,
+						Path: corev1.TLSCertKey,
+					}},
+					SecretName: GuardianProxyCASecretName,
+				},
+			},
+		})
+	}
 	return volumes
 }
 
 func (c *GuardianComponent) container() []corev1.Container {
 	return []corev1.Container{
 		{
-			Name:  GuardianDeploymentName,
-			Image: c.image,
-			Env: []corev1.EnvVar{
-				{Name: "GUARDIAN_PORT", Value: "9443"},
-				{Name: "GUARDIAN_LOGLEVEL", Value: "INFO"},
-				{Name: "GUARDIAN_VOLTRON_URL", Value: c.cfg.URL},
-			},
+			Name:         GuardianDeploymentName,
+			Image:        c.image,
+			Env:          c.env(),
 			VolumeMounts: c.volumeMounts(),
 			LivenessProbe: &corev1.Probe{
 				Handler: corev1.Handler{
@@ -304,10 +378,47 @@ func (c *GuardianComponent) container() []corev1.Container {
 				PeriodSeconds:       5,
 			},
 			SecurityContext: podsecuritycontext.NewBaseContext(),
+			// Report the last log lines of guardian as its termination message, so the controller can surface
+			// why the tunnel went down.
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		},
 	}
 }
 
+func (c *GuardianComponent) env() []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "GUARDIAN_PORT", Value: "9443"},
+		{Name: "GUARDIAN_LOGLEVEL", Value: "INFO"},
+		{Name: "GUARDIAN_VOLTRON_URL", Value: c.cfg.URL},
+	}
+	if c.cfg.ProxyURL == "" {
+		return env
+	}
+
+	// Guardian honours the standard proxy variables. The credentials are spliced into the proxy URL by Kubernetes,
+	// which expands references to the variables defined before, and to the service variables, when starting guardian.
+	proxyURL := c.cfg.ProxyURL
+	if c.cfg.ProxyCredentialsSecret != nil {
+		env = append(env,
+			corev1.EnvVar{Name: "PROXY_USERNAME", ValueFrom: secret.GetEnvVarSource(GuardianProxyCredentialsSecretName, GuardianProxyUsernameKey, false)},
+			corev1.EnvVar{Name: "PROXY_PASSWORD", ValueFrom: secret.GetEnvVarSource(GuardianProxyCredentialsSecretName, GuardianProxyPasswordKey, false)},
+		)
+		if i := strings.Index(proxyURL, "://"); i >= 0 {
+			proxyURL = proxyURL[:i+3] + "$(PROXY_USERNAME):$(PROXY_PASSWORD)@" + proxyURL[i+3:]
+		}
+	}
+	env = append(env,
+		corev1.EnvVar{Name: "HTTPS_PROXY", Value: proxyURL},
+		// Guardian reaches the Kubernetes API and the services of the cluster directly.
+		corev1.EnvVar{Name: "NO_PROXY", Value: fmt.Sprintf("$(KUBERNETES_SERVICE_HOST),.svc,.svc.%s", c.cfg.ClusterDomain)},
+	)
+	if c.cfg.ProxyCASecret != nil {
+		// The certificates in this directory are trusted in addition to those of the system bundle.
+		env = append(env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/certs/proxy"})
+	}
+	return env
+}
+
 func (c *GuardianComponent) volumeMounts() []corev1.VolumeMount {
 	mounts := []corev1.VolumeMount{
 		{
@@ -324,6 +435,9 @@ func (c *GuardianComponent) volumeMounts() []corev1.VolumeMount {
 	if c.cfg.PrometheusCertSecret != nil {
 		mounts = append(mounts, corev1.VolumeMount{Name: PrometheusTLSSecretName, MountPath: "/certs/prometheus", ReadOnly: true})
 	}
+	if c.cfg.ProxyCASecret != nil {
+		mounts = append(mounts, corev1.VolumeMount{Name: GuardianProxyCASecretName, MountPath: "/certs/proxy", ReadOnly: true})
+	}
 
 	return mounts
 }
@@ -331,10 +445,18 @@ func (c *GuardianComponent) volumeMounts() []corev1.VolumeMount {
 func (c *GuardianComponent) annotations() map[string]string {
 	var annotations = make(map[string]string)
 
+	annotations[GuardianAddrAnnotation] = c.cfg.URL
+
 	annotations[PacketCaptureTLSHashAnnotation] = rmeta.AnnotationHash(c.cfg.PacketCaptureSecret.Data)
 
 	if c.cfg.PrometheusCertSecret != nil {
 		annotations[prometheusTLSHashAnnotation] = rmeta.AnnotationHash(c.cfg.PrometheusCertSecret.Data)
 	}
+	if c.cfg.ProxyCASecret != nil {
+		annotations[guardianProxyCAHashAnnotation] = rmeta.AnnotationHash(c.cfg.ProxyCASecret.Data)
+	}
+	if c.cfg.ProxyCredentialsSecret != nil {
+		annotations[guardianProxyCredentialsHashAnnotation] = rmeta.AnnotationHash(c.cfg.ProxyCredentialsSecret.Data)
+	}
 	return annotations
 }
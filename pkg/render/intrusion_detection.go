@@ -33,6 +33,7 @@ import (
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rkibana "github.com/tigera/operator/pkg/render/common/kibana"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
 	"github.com/tigera/operator/pkg/url"
@@ -64,6 +65,7 @@ type IntrusionDetectionConfiguration struct {
 	ESClusterConfig          *relasticsearch.ClusterConfig
 	PullSecrets              []*corev1.Secret
 	Openshift                bool
+	UsePSP                   bool
 	ClusterDomain            string
 	ESLicenseType            ElasticsearchLicenseType
 	ManagedCluster           bool
@@ -106,7 +108,7 @@ func (c *intrusionDetectionComponent) SupportedOSType() rmeta.OSType {
 }
 
 func (c *intrusionDetectionComponent) Objects() ([]client.Object, []client.Object) {
-	objs := []client.Object{CreateNamespace(IntrusionDetectionNamespace, c.cfg.Installation.KubernetesProvider)}
+	objs := []client.Object{CreateNamespace(IntrusionDetectionNamespace, c.cfg.Installation.KubernetesProvider, PSSPrivileged)}
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(IntrusionDetectionNamespace, c.cfg.PullSecrets...)...)...)
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(IntrusionDetectionNamespace, c.cfg.ESSecrets...)...)...)
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(IntrusionDetectionNamespace, c.cfg.KibanaCertSecret)...)...)
@@ -129,7 +131,7 @@ func (c *intrusionDetectionComponent) Objects() ([]client.Object, []client.Objec
 
 	objs = append(objs, c.globalAlertTemplates()...)
 
-	if !c.cfg.Openshift {
+	if !c.cfg.Openshift && c.cfg.UsePSP {
 		objs = append(objs,
 			c.intrusionDetectionPodSecurityPolicy(),
 			c.intrusionDetectionPSPClusterRole(),
@@ -147,6 +149,22 @@ func (c *intrusionDetectionComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for intrusion detection. Nothing connects to its pods, which read
+// from the Kubernetes API and Elasticsearch and pull the threat feeds and send the alerts configured by users. Feeds
+// and alert webhooks can be served on any port, so TCP egress is not restricted to particular destinations.
+func (c *intrusionDetectionComponent) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, v3.EntityRule{}))
+	return []client.Object{networkpolicy.New(
+		"intrusion-detection",
+		IntrusionDetectionNamespace,
+		"all()",
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		nil,
+		egress,
+	)}
+}
+
 func (c *intrusionDetectionComponent) intrusionDetectionElasticsearchJob() *batchv1.Job {
 	podTemplate := relasticsearch.DecorateAnnotations(&corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
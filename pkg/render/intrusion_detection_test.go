@@ -49,6 +49,7 @@ var _ = Describe("Intrusion Detection rendering tests", func() {
 			ClusterDomain:    dns.DefaultClusterDomain,
 			ESLicenseType:    render.ElasticsearchLicenseTypeUnknown,
 			ManagedCluster:   notManagedCluster,
+			UsePSP:           true,
 		}
 	})
 
@@ -86,7 +86,7 @@ func (d *dpiComponent) ResolveImages(is *operatorv1.ImageSet) error {
 func (d *dpiComponent) Objects() (objsToCreate, objsToDelete []client.Object) {
 	var commonObjs []client.Object
 
-	nsObj := []client.Object{render.CreateNamespace(DeepPacketInspectionNamespace, d.cfg.Installation.KubernetesProvider)}
+	nsObj := []client.Object{render.CreateNamespace(DeepPacketInspectionNamespace, d.cfg.Installation.KubernetesProvider, render.PSSPrivileged)}
 
 	if d.cfg.HasNoDPIResource || d.cfg.HasNoLicense {
 		// create empty secrets and configMap when resource needs to be deleted.
@@ -0,0 +1,206 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"net"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+const (
+	// IPPoolManagedByLabel is set on the IPPools that are reconciled from the IP pools of the Installation.
+	IPPoolManagedByLabel      = "app.kubernetes.io/managed-by"
+	IPPoolManagedByLabelValue = "tigera-operator"
+
+	// IPPoolDisabledAnnotation is set to true on the IPPools that the operator disabled because their IP pool was
+	// removed from the Installation, so that they are enabled again if the IP pool is added back. It is then set to
+	// false, since the annotations that the operator no longer sets are kept on update.
+	IPPoolDisabledAnnotation = "operator.tigera.io/ippool-disabled"
+)
+
+// IPPoolsConfiguration contains all the config information needed to render the component.
+type IPPoolsConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+
+	// ExistingPools are the IPPools in the cluster, by name. Only the fields of an existing IPPool that are set from
+	// the Installation are updated, the others are left as they are.
+	ExistingPools map[string]*crdv1.IPPool
+
+	// DisabledPools are the names of the IPPools created from IP pools that are no longer in the Installation, which
+	// the addresses of workloads may still be assigned from. They are disabled, so that no more addresses are
+	// assigned from them.
+	DisabledPools []string
+
+	// StalePools are the names of the IPPools created from IP pools that are no longer in the Installation, which
+	// are disabled and have no IPAM blocks left, so they are deleted.
+	StalePools []string
+}
+
+// IPPools renders an IPPool for each of the IP pools of the Installation. The IPPools must not be owned by the
+// Installation, since the addresses of the workloads are allocated from them.
+func IPPools(cfg *IPPoolsConfiguration) Component {
+	return &ipPoolsComponent{cfg: cfg}
+}
+
+type ipPoolsComponent struct {
+	cfg *IPPoolsConfiguration
+}
+
+func (c *ipPoolsComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on IP pools.
+	return nil
+}
+
+func (c *ipPoolsComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *ipPoolsComponent) Ready() bool {
+	return true
+}
+
+func (c *ipPoolsComponent) Objects() ([]client.Object, []client.Object) {
+	var objs []client.Object
+	if c.cfg.Installation.CalicoNetwork != nil {
+		for _, p := range c.cfg.Installation.CalicoNetwork.IPPools {
+			objs = append(objs, ipPool(p, c.cfg.ExistingPools[p.Name]))
+		}
+	}
+	if lb := LoadBalancerIPPool(c.cfg.Installation); lb != nil {
+		objs = append(objs, loadBalancerIPPool(lb, c.cfg.ExistingPools[lb.Name]))
+	}
+	for _, name := range c.cfg.DisabledPools {
+		if existing, ok := c.cfg.ExistingPools[name]; ok {
+			objs = append(objs, disabledIPPool(existing))
+		}
+	}
+
+	var toDelete []client.Object
+	for _, name := range c.cfg.StalePools {
+		toDelete = append(toDelete, &crdv1.IPPool{
+			TypeMeta:   metav1.TypeMeta{Kind: crdv1.KindIPPool, APIVersion: "crd.projectcalico.org/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		})
+	}
+	return objs, toDelete
+}
+
+// ipPool returns the IPPool of the IP pool, with the fields set from the IP pool applied to the existing IPPool, if
+// any. The CIDR and block size of an existing IPPool are never changed.
+func ipPool(p operatorv1.IPPool, existing *crdv1.IPPool) *crdv1.IPPool {
+	pool := &crdv1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: p.Name},
+		Spec: crdv1.IPPoolSpec{
+			CIDR: p.CIDR,
+		},
+	}
+	if p.BlockSize != nil {
+		pool.Spec.BlockSize = int(*p.BlockSize)
+	}
+	if existing != nil {
+		pool = existing.DeepCopy()
+	}
+	pool.TypeMeta = metav1.TypeMeta{Kind: crdv1.KindIPPool, APIVersion: "crd.projectcalico.org/v1"}
+	if pool.Labels == nil {
+		pool.Labels = map[string]string{}
+	}
+	pool.Labels[IPPoolManagedByLabel] = IPPoolManagedByLabelValue
+	enableIPPool(pool)
+
+	pool.Spec.IPIPMode = crdv1.IPIPModeNever
+	pool.Spec.VXLANMode = crdv1.VXLANModeNever
+	pool.Spec.NATOutgoing = p.NATOutgoing == operatorv1.NATOutgoingEnabled
+	pool.Spec.NodeSelector = p.NodeSelector
+	switch p.Encapsulation {
+	case operatorv1.EncapsulationIPIP:
+		pool.Spec.IPIPMode = crdv1.IPIPModeAlways
+	case operatorv1.EncapsulationIPIPCrossSubnet:
+		pool.Spec.IPIPMode = crdv1.IPIPModeCrossSubnet
+	case operatorv1.EncapsulationVXLAN:
+		pool.Spec.VXLANMode = crdv1.VXLANModeAlways
+	case operatorv1.EncapsulationVXLANCrossSubnet:
+		pool.Spec.VXLANMode = crdv1.VXLANModeCrossSubnet
+	}
+	return pool
+}
+
+// loadBalancerIPPool returns the IPPool that the LoadBalancer Service IPs are assigned from, which is not used for
+// workloads or tunnels. As for the other IP pools, only its allowed uses are applied to the existing IPPool, if any.
+// A new IPPool has the default block size of its IP family, /26 or /122.
+func loadBalancerIPPool(p *operatorv1.LoadBalancerIPPool, existing *crdv1.IPPool) *crdv1.IPPool {
+	pool := &crdv1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: p.Name},
+		Spec: crdv1.IPPoolSpec{
+			CIDR:         p.CIDR,
+			IPIPMode:     crdv1.IPIPModeNever,
+			VXLANMode:    crdv1.VXLANModeNever,
+			NodeSelector: "all()",
+			BlockSize:    26,
+		},
+	}
+	if ip, _, err := net.ParseCIDR(p.CIDR); err == nil && ip.To4() == nil {
+		pool.Spec.BlockSize = 122
+	}
+	if existing != nil {
+		pool = existing.DeepCopy()
+	}
+	pool.TypeMeta = metav1.TypeMeta{Kind: crdv1.KindIPPool, APIVersion: "crd.projectcalico.org/v1"}
+	if pool.Labels == nil {
+		pool.Labels = map[string]string{}
+	}
+	pool.Labels[IPPoolManagedByLabel] = IPPoolManagedByLabelValue
+	enableIPPool(pool)
+	pool.Spec.AllowedUses = []crdv1.IPPoolAllowedUse{crdv1.IPPoolAllowedUseLoadBalancer}
+	return pool
+}
+
+// disabledIPPool returns the existing IPPool disabled, so that no more addresses are assigned from it while the
+// addresses that were are released.
+func disabledIPPool(existing *crdv1.IPPool) *crdv1.IPPool {
+	pool := existing.DeepCopy()
+	pool.TypeMeta = metav1.TypeMeta{Kind: crdv1.KindIPPool, APIVersion: "crd.projectcalico.org/v1"}
+	if !pool.Spec.Disabled {
+		if pool.Annotations == nil {
+			pool.Annotations = map[string]string{}
+		}
+		pool.Annotations[IPPoolDisabledAnnotation] = "true"
+		pool.Spec.Disabled = true
+	}
+	return pool
+}
+
+// enableIPPool enables the IPPool again if it was disabled by the operator. An IPPool that was disabled otherwise is
+// left disabled.
+func enableIPPool(pool *crdv1.IPPool) {
+	if pool.Annotations[IPPoolDisabledAnnotation] == "true" {
+		pool.Annotations[IPPoolDisabledAnnotation] = "false"
+		pool.Spec.Disabled = false
+	}
+}
+
+// LoadBalancerIPPool returns the IP pool of the LoadBalancer Service IPs of the Installation, or nil if
+// kube-controllers does not assign LoadBalancer IPs.
+func LoadBalancerIPPool(instance *operatorv1.InstallationSpec) *operatorv1.LoadBalancerIPPool {
+	if instance.CalicoNetwork == nil || instance.CalicoNetwork.ServiceAdvertisement == nil {
+		return nil
+	}
+	return instance.CalicoNetwork.ServiceAdvertisement.LoadBalancerIPPool
+}
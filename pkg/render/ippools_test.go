@@ -0,0 +1,254 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("IP pools rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+
+	BeforeEach(func() {
+		var twentySix int32 = 26
+		var oneTwentyTwo int32 = 122
+		installation = &operatorv1.InstallationSpec{
+			CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+				IPPools: []operatorv1.IPPool{
+					{
+						Name:          "zone-a",
+						CIDR:          "192.168.0.0/24",
+						Encapsulation: operatorv1.EncapsulationVXLANCrossSubnet,
+						NATOutgoing:   operatorv1.NATOutgoingEnabled,
+						NodeSelector:  "zone == 'a'",
+						BlockSize:     &twentySix,
+					},
+					{
+						Name:          "zone-b",
+						CIDR:          "192.168.1.0/24",
+						Encapsulation: operatorv1.EncapsulationIPIP,
+						NATOutgoing:   operatorv1.NATOutgoingDisabled,
+						NodeSelector:  "zone == 'b'",
+						BlockSize:     &twentySix,
+					},
+					{
+						Name:          "default-ipv6-ippool",
+						CIDR:          "fd00::/64",
+						Encapsulation: operatorv1.EncapsulationNone,
+						NATOutgoing:   operatorv1.NATOutgoingDisabled,
+						NodeSelector:  "all()",
+						BlockSize:     &oneTwentyTwo,
+					},
+				},
+			},
+		}
+	})
+
+	It("should render an IPPool for each IP pool", func() {
+		component := render.IPPools(&render.IPPoolsConfiguration{Installation: installation})
+		toCreate, toDelete := component.Objects()
+		Expect(toCreate).To(HaveLen(3))
+		Expect(toDelete).To(BeEmpty())
+
+		pool := toCreate[0].(*crdv1.IPPool)
+		Expect(pool.Name).To(Equal("zone-a"))
+		Expect(pool.Labels).To(HaveKeyWithValue(render.IPPoolManagedByLabel, render.IPPoolManagedByLabelValue))
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "192.168.0.0/24",
+			VXLANMode:    crdv1.VXLANModeCrossSubnet,
+			IPIPMode:     crdv1.IPIPModeNever,
+			NATOutgoing:  true,
+			BlockSize:    26,
+			NodeSelector: "zone == 'a'",
+		}))
+
+		pool = toCreate[1].(*crdv1.IPPool)
+		Expect(pool.Name).To(Equal("zone-b"))
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "192.168.1.0/24",
+			VXLANMode:    crdv1.VXLANModeNever,
+			IPIPMode:     crdv1.IPIPModeAlways,
+			BlockSize:    26,
+			NodeSelector: "zone == 'b'",
+		}))
+
+		pool = toCreate[2].(*crdv1.IPPool)
+		Expect(pool.Name).To(Equal("default-ipv6-ippool"))
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "fd00::/64",
+			VXLANMode:    crdv1.VXLANModeNever,
+			IPIPMode:     crdv1.IPIPModeNever,
+			BlockSize:    122,
+			NodeSelector: "all()",
+		}))
+	})
+
+	It("should only set the fields of the existing IPPools that come from the Installation", func() {
+		existing := &crdv1.IPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "zone-b", ResourceVersion: "1", Labels: map[string]string{"team": "a"}},
+			Spec:       crdv1.IPPoolSpec{CIDR: "192.168.1.0/24", BlockSize: 28, Disabled: true},
+		}
+		component := render.IPPools(&render.IPPoolsConfiguration{
+			Installation:  installation,
+			ExistingPools: map[string]*crdv1.IPPool{"zone-b": existing},
+		})
+		toCreate, _ := component.Objects()
+
+		pool := toCreate[1].(*crdv1.IPPool)
+		Expect(pool.ResourceVersion).To(Equal("1"))
+		Expect(pool.Labels).To(Equal(map[string]string{"team": "a", render.IPPoolManagedByLabel: render.IPPoolManagedByLabelValue}))
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "192.168.1.0/24",
+			VXLANMode:    crdv1.VXLANModeNever,
+			IPIPMode:     crdv1.IPIPModeAlways,
+			BlockSize:    28,
+			Disabled:     true,
+			NodeSelector: "zone == 'b'",
+		}))
+		Expect(existing.Labels).To(HaveLen(1))
+	})
+
+	It("should render the LoadBalancer IP pool", func() {
+		installation.CalicoNetwork.ServiceAdvertisement = &operatorv1.ServiceAdvertisementSpec{
+			LoadBalancerIPPool: &operatorv1.LoadBalancerIPPool{Name: "loadbalancer-ippool", CIDR: "172.17.0.0/24"},
+		}
+		component := render.IPPools(&render.IPPoolsConfiguration{Installation: installation})
+		toCreate, _ := component.Objects()
+		Expect(toCreate).To(HaveLen(4))
+
+		pool := toCreate[3].(*crdv1.IPPool)
+		Expect(pool.Name).To(Equal("loadbalancer-ippool"))
+		Expect(pool.Labels).To(HaveKeyWithValue(render.IPPoolManagedByLabel, render.IPPoolManagedByLabelValue))
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "172.17.0.0/24",
+			VXLANMode:    crdv1.VXLANModeNever,
+			IPIPMode:     crdv1.IPIPModeNever,
+			NodeSelector: "all()",
+			BlockSize:    26,
+			AllowedUses:  []crdv1.IPPoolAllowedUse{crdv1.IPPoolAllowedUseLoadBalancer},
+		}))
+	})
+
+	It("should render the LoadBalancer IP pool with the IPv6 block size", func() {
+		installation.CalicoNetwork.ServiceAdvertisement = &operatorv1.ServiceAdvertisementSpec{
+			LoadBalancerIPPool: &operatorv1.LoadBalancerIPPool{Name: "loadbalancer-ippool", CIDR: "fd00:10:96::/112"},
+		}
+		component := render.IPPools(&render.IPPoolsConfiguration{Installation: installation})
+		toCreate, _ := component.Objects()
+		Expect(toCreate).To(HaveLen(4))
+
+		pool := toCreate[3].(*crdv1.IPPool)
+		Expect(pool.Spec.CIDR).To(Equal("fd00:10:96::/112"))
+		Expect(pool.Spec.BlockSize).To(Equal(122))
+	})
+
+	It("should apply only the allowed uses to the existing LoadBalancer IPPool", func() {
+		installation.CalicoNetwork.ServiceAdvertisement = &operatorv1.ServiceAdvertisementSpec{
+			LoadBalancerIPPool: &operatorv1.LoadBalancerIPPool{Name: "loadbalancer-ippool", CIDR: "172.17.0.0/24"},
+		}
+		existing := &crdv1.IPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "loadbalancer-ippool", ResourceVersion: "1"},
+			Spec: crdv1.IPPoolSpec{
+				CIDR:         "172.17.0.0/24",
+				VXLANMode:    crdv1.VXLANModeNever,
+				IPIPMode:     crdv1.IPIPModeNever,
+				NodeSelector: "rack == 'a'",
+				Disabled:     true,
+			},
+		}
+		component := render.IPPools(&render.IPPoolsConfiguration{
+			Installation:  installation,
+			ExistingPools: map[string]*crdv1.IPPool{"loadbalancer-ippool": existing},
+		})
+		toCreate, _ := component.Objects()
+
+		pool := toCreate[3].(*crdv1.IPPool)
+		Expect(pool.ResourceVersion).To(Equal("1"))
+		Expect(pool.Labels).To(Equal(map[string]string{render.IPPoolManagedByLabel: render.IPPoolManagedByLabelValue}))
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "172.17.0.0/24",
+			VXLANMode:    crdv1.VXLANModeNever,
+			IPIPMode:     crdv1.IPIPModeNever,
+			NodeSelector: "rack == 'a'",
+			Disabled:     true,
+			AllowedUses:  []crdv1.IPPoolAllowedUse{crdv1.IPPoolAllowedUseLoadBalancer},
+		}))
+		Expect(existing.Spec.AllowedUses).To(BeEmpty())
+	})
+
+	It("should delete the stale IPPools", func() {
+		component := render.IPPools(&render.IPPoolsConfiguration{
+			Installation: installation,
+			StalePools:   []string{"zone-c"},
+		})
+		_, toDelete := component.Objects()
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].GetName()).To(Equal("zone-c"))
+		Expect(toDelete[0]).To(BeAssignableToTypeOf(&crdv1.IPPool{}))
+	})
+
+	It("should disable the IPPools of removed IP pools and enable them again when they are added back", func() {
+		existing := &crdv1.IPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "zone-c",
+				Labels: map[string]string{render.IPPoolManagedByLabel: render.IPPoolManagedByLabelValue},
+			},
+			Spec: crdv1.IPPoolSpec{CIDR: "192.168.2.0/24", BlockSize: 26},
+		}
+		component := render.IPPools(&render.IPPoolsConfiguration{
+			Installation:  installation,
+			ExistingPools: map[string]*crdv1.IPPool{"zone-c": existing},
+			DisabledPools: []string{"zone-c"},
+		})
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(4))
+		disabled := toCreate[3].(*crdv1.IPPool)
+		Expect(disabled.Name).To(Equal("zone-c"))
+		Expect(disabled.Annotations).To(HaveKeyWithValue(render.IPPoolDisabledAnnotation, "true"))
+		Expect(disabled.Spec).To(Equal(crdv1.IPPoolSpec{CIDR: "192.168.2.0/24", BlockSize: 26, Disabled: true}))
+		Expect(existing.Spec.Disabled).To(BeFalse())
+
+		var twentySix int32 = 26
+		installation.CalicoNetwork.IPPools = append(installation.CalicoNetwork.IPPools, operatorv1.IPPool{
+			Name:          "zone-c",
+			CIDR:          "192.168.2.0/24",
+			Encapsulation: operatorv1.EncapsulationNone,
+			NodeSelector:  "zone == 'c'",
+			BlockSize:     &twentySix,
+		})
+		component = render.IPPools(&render.IPPoolsConfiguration{
+			Installation:  installation,
+			ExistingPools: map[string]*crdv1.IPPool{"zone-c": disabled},
+		})
+		toCreate, _ = component.Objects()
+		enabled := toCreate[3].(*crdv1.IPPool)
+		Expect(enabled.Annotations).To(HaveKeyWithValue(render.IPPoolDisabledAnnotation, "false"))
+		Expect(enabled.Spec.Disabled).To(BeFalse())
+	})
+
+	It("should render nothing without IP pools", func() {
+		component := render.IPPools(&render.IPPoolsConfiguration{Installation: &operatorv1.InstallationSpec{}})
+		toCreate, toDelete := component.Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(BeEmpty())
+	})
+})
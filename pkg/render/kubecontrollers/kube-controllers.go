@@ -15,11 +15,13 @@
 package kubecontrollers
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/tigera/operator/pkg/render"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -33,6 +35,7 @@ import (
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/k8sapi"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
@@ -76,6 +79,9 @@ type KubeControllersConfiguration struct {
 	// For details on why this is needed see 'Node and Installation finalizer' in the core_controller.
 	Terminating bool
 
+	// UsePSP is true if the cluster serves the PodSecurityPolicy API and PSPs should be rendered.
+	UsePSP bool
+
 	// Secrets - provided by the caller. Used to generate secrets in the destination
 	// namespace to be returned by the rendered. Expected that the calling code
 	// take care to pass the same secret on each reconcile where possible.
@@ -86,8 +92,41 @@ type KubeControllersConfiguration struct {
 }
 
 func NewCalicoKubeControllers(cfg *KubeControllersConfiguration) *kubeControllersComponent {
+	var node, service, federatedServices *operatorv1.KubeControllerState
+	if kc := cfg.Installation.KubeControllers; kc != nil && kc.Controllers != nil {
+		if kc.Controllers.Node != nil {
+			node = kc.Controllers.Node.State
+		}
+		if kc.Controllers.Service != nil {
+			service = kc.Controllers.Service.State
+		}
+		if kc.Controllers.FederatedServices != nil {
+			federatedServices = kc.Controllers.FederatedServices.State
+		}
+	}
+
 	kubeControllerRolePolicyRules := kubeControllersRoleCommonRules(cfg, KubeController)
-	enabledControllers := []string{"node"}
+	enabledControllers := []string{}
+	if enabled(node) {
+		enabledControllers = append(enabledControllers, "node")
+	}
+	if render.LoadBalancerIPPool(cfg.Installation) != nil {
+		enabledControllers = append(enabledControllers, "loadbalancer")
+		kubeControllerRolePolicyRules = append(kubeControllerRolePolicyRules,
+			rbacv1.PolicyRule{
+				// The LoadBalancer controller assigns the IPs of the LoadBalancer Services.
+				APIGroups: []string{""},
+				Resources: []string{"services/status"},
+				Verbs:     []string{"get", "update"},
+			},
+			rbacv1.PolicyRule{
+				// It assigns them from the IP pools with the LoadBalancer allowed use.
+				APIGroups: []string{"crd.projectcalico.org"},
+				Resources: []string{"ippools", "ipamconfigs"},
+				Verbs:     []string{"get", "watch"},
+			},
+		)
+	}
 	if cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		kubeControllerRolePolicyRules = append(kubeControllerRolePolicyRules, kubeControllersRoleEnterpriseCommonRules(cfg)...)
 		kubeControllerRolePolicyRules = append(kubeControllerRolePolicyRules,
@@ -107,7 +146,12 @@ func NewCalicoKubeControllers(cfg *KubeControllersConfiguration) *kubeController
 				Verbs:     []string{"create", "update", "delete"},
 			},
 		)
-		enabledControllers = append(enabledControllers, "service", "federatedservices")
+		if enabled(service) {
+			enabledControllers = append(enabledControllers, "service")
+		}
+		if enabled(federatedServices) {
+			enabledControllers = append(enabledControllers, "federatedservices")
+		}
 	}
 
 	return &kubeControllersComponent{
@@ -172,6 +216,11 @@ func NewElasticsearchKubeControllers(cfg *KubeControllersConfiguration) *kubeCon
 	}
 }
 
+// enabled returns true unless the state disables the controller.
+func enabled(state *operatorv1.KubeControllerState) bool {
+	return state == nil || *state != operatorv1.KubeControllerDisabled
+}
+
 type kubeControllersComponent struct {
 	// cfg is caller-supplied configuration for building kube-controllers Kubernetes resources.
 	cfg *KubeControllersConfiguration
@@ -235,7 +284,7 @@ func (c *kubeControllersComponent) Objects() ([]client.Object, []client.Object)
 			secret.CopyToNamespace(common.CalicoNamespace, c.cfg.KubeControllersGatewaySecret)...)...)
 	}
 
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift && c.cfg.UsePSP {
 		objectsToCreate = append(objectsToCreate, c.controllersPodSecurityPolicy())
 	}
 
@@ -257,6 +306,37 @@ func (c *kubeControllersComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for kube-controllers. Only the metrics port is reached, and the
+// controllers talk to the Kubernetes API, Elasticsearch and, in a management cluster, to the manager.
+//
+// Calico Enterprise only.
+func (c *kubeControllersComponent) NetworkPolicies() []client.Object {
+	if c.cfg.Installation.Variant != operatorv1.TigeraSecureEnterprise {
+		return nil
+	}
+
+	var ingress []v3.Rule
+	if c.cfg.MetricsPort != 0 {
+		ingress = append(ingress, networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(uint16(c.cfg.MetricsPort))))
+	}
+
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(c.cfg.K8sServiceEp)...)
+	egress = append(egress, networkpolicy.ESGatewayEgressRule())
+	if c.cfg.ManagementCluster != nil {
+		egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(render.ManagerNamespace, "k8s-app == 'tigera-manager'")))
+	}
+
+	return []client.Object{networkpolicy.New(
+		c.kubeControllerName,
+		common.CalicoNamespace,
+		fmt.Sprintf("k8s-app == '%s'", c.kubeControllerName),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		ingress,
+		egress,
+	)}
+}
+
 func kubeControllersRoleCommonRules(cfg *KubeControllersConfiguration, kubeControllerName string) []rbacv1.PolicyRule {
 	rules := []rbacv1.PolicyRule{
 		{
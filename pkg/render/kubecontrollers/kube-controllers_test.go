@@ -107,6 +107,7 @@ var _ = Describe("kube-controllers rendering tests", func() {
 			Installation:  instance,
 			ClusterDomain: dns.DefaultClusterDomain,
 			MetricsPort:   9094,
+			UsePSP:        true,
 		}
 
 	})
@@ -130,6 +131,7 @@ var _ = Describe("kube-controllers rendering tests", func() {
 			K8sServiceEp:  k8sServiceEp,
 			Installation:  instance,
 			ClusterDomain: dns.DefaultClusterDomain,
+			UsePSP:        true,
 		}
 		component := kubecontrollers.NewCalicoKubeControllers(&cfg)
 		Expect(component.ResolveImages(nil)).To(BeNil())
@@ -157,13 +159,22 @@ var _ = Describe("kube-controllers rendering tests", func() {
 			{Name: "ENABLED_CONTROLLERS", Value: "node"},
 			{Name: "KUBE_CONTROLLERS_CONFIG_NAME", Value: "default"},
 		}
-		expectedEnv = append(expectedEnv)
 		Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(expectedEnv))
 
 		// Verify tolerations.
 		Expect(ds.Spec.Template.Spec.Tolerations).To(ConsistOf(rmeta.TolerateCriticalAddonsOnly, rmeta.TolerateMaster))
 	})
 
+	It("should not render a PodSecurityPolicy when PodSecurityPolicies are not supported", func() {
+		cfg.UsePSP = false
+		component := kubecontrollers.NewCalicoKubeControllers(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		Expect(rtest.GetResource(resources, kubecontrollers.KubeControllerPodSecurityPolicy, "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+		Expect(rtest.GetResource(resources, kubecontrollers.KubeController, common.CalicoNamespace, "apps", "v1", "Deployment")).NotTo(BeNil())
+	})
+
 	It("should render all calico kube-controllers resources for a default configuration (standalone) using TigeraSecureEnterprise", func() {
 		expectedResources := []struct {
 			name    string
@@ -218,6 +229,50 @@ var _ = Describe("kube-controllers rendering tests", func() {
 		Expect(len(clusterRole.Rules)).To(Equal(18))
 	})
 
+	It("should only enable the controllers that are not disabled", func() {
+		disabled := operatorv1.KubeControllerDisabled
+		instance.Variant = operatorv1.TigeraSecureEnterprise
+		instance.KubeControllers = &operatorv1.KubeControllersSpec{
+			Controllers: &operatorv1.KubeControllersControllers{
+				Node:              &operatorv1.KubeControllersNodeController{},
+				FederatedServices: &operatorv1.KubeControllersController{State: &disabled},
+			},
+		}
+
+		component := kubecontrollers.NewCalicoKubeControllers(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		dp := rtest.GetResource(resources, kubecontrollers.KubeController, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(dp.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: "ENABLED_CONTROLLERS", Value: "node,service",
+		}))
+	})
+
+	It("should enable the LoadBalancer controller with a LoadBalancer IP pool", func() {
+		instance.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+			ServiceAdvertisement: &operatorv1.ServiceAdvertisementSpec{
+				LoadBalancerIPPool: &operatorv1.LoadBalancerIPPool{Name: "loadbalancer-ippool", CIDR: "172.17.0.0/24"},
+			},
+		}
+
+		component := kubecontrollers.NewCalicoKubeControllers(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		dp := rtest.GetResource(resources, kubecontrollers.KubeController, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(dp.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: "ENABLED_CONTROLLERS", Value: "node,loadbalancer",
+		}))
+
+		clusterRole := rtest.GetResource(resources, kubecontrollers.KubeControllerRole, "", "rbac.authorization.k8s.io", "v1", "ClusterRole").(*rbacv1.ClusterRole)
+		Expect(clusterRole.Rules).To(ContainElement(rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"services/status"},
+			Verbs:     []string{"get", "update"},
+		}))
+	})
+
 	It("should render all es-calico-kube-controllers resources for a default configuration (standalone) using TigeraSecureEnterprise when logstorage and secrets exist", func() {
 		expectedResources := []struct {
 			name    string
@@ -26,6 +26,7 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	"gopkg.in/inf.v0"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -46,6 +47,7 @@ import (
 	"github.com/tigera/operator/pkg/ptr"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
@@ -69,8 +71,11 @@ const (
 	ElasticsearchSecureSettingsSecretName = "tigera-elasticsearch-secure-settings"
 	ElasticsearchOperatorUserSecret       = "tigera-ee-operator-elasticsearch-access"
 	ElasticsearchAdminUserSecret          = "tigera-secure-es-elastic-user"
+	ElasticsearchDefaultPort              = 9200
+	ElasticsearchSelector                 = "elasticsearch.k8s.elastic.co/cluster-name == 'tigera-secure'"
 
 	KibanaName               = "tigera-secure"
+	KibanaSelector           = "kibana.k8s.elastic.co/name == 'tigera-secure'"
 	KibanaNamespace          = "tigera-kibana"
 	KibanaPublicCertSecret   = "tigera-secure-es-gateway-http-certs-public"
 	KibanaInternalCertSecret = "tigera-secure-kb-http-certs-public"
@@ -208,6 +213,9 @@ type ElasticsearchConfiguration struct {
 	ClusterDomain               string
 	DexCfg                      DexRelyingPartyConfig
 	ElasticLicenseType          ElasticsearchLicenseType
+
+	// UsePSP is true if the cluster serves the PodSecurityPolicy API and PSPs should be rendered.
+	UsePSP bool
 }
 
 type elasticsearchComponent struct {
@@ -289,7 +297,7 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 
 		// ECK CRs
 		toCreate = append(toCreate,
-			CreateNamespace(ECKOperatorNamespace, es.cfg.Installation.KubernetesProvider),
+			CreateNamespace(ECKOperatorNamespace, es.cfg.Installation.KubernetesProvider, PSSBaseline),
 		)
 
 		toCreate = append(toCreate, secret.ToRuntimeObjects(secret.CopyToNamespace(ECKOperatorNamespace, es.cfg.PullSecrets...)...)...)
@@ -305,8 +313,8 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 			toCreate = append(toCreate, es.eckOperatorClusterAdminClusterRoleBinding())
 		}
 
-		// Apply the pod security policies for all providers except OpenShift
-		if es.cfg.Provider != operatorv1.ProviderOpenShift {
+		// Apply the pod security policies for all providers except OpenShift, if they are supported.
+		if es.cfg.Provider != operatorv1.ProviderOpenShift && es.cfg.UsePSP {
 			toCreate = append(toCreate,
 				es.eckOperatorPodSecurityPolicy(),
 				es.elasticsearchClusterRoleBinding(),
@@ -320,7 +328,7 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 		toCreate = append(toCreate, es.eckOperatorStatefulSet())
 
 		// Elasticsearch CRs
-		toCreate = append(toCreate, CreateNamespace(ElasticsearchNamespace, es.cfg.Installation.KubernetesProvider))
+		toCreate = append(toCreate, CreateNamespace(ElasticsearchNamespace, es.cfg.Installation.KubernetesProvider, PSSPrivileged))
 
 		if len(es.cfg.PullSecrets) > 0 {
 			toCreate = append(toCreate, secret.ToRuntimeObjects(secret.CopyToNamespace(ElasticsearchNamespace, es.cfg.PullSecrets...)...)...)
@@ -341,7 +349,7 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 		toCreate = append(toCreate, es.elasticsearchCluster(len(secureSettings.Data) > 0))
 
 		// Kibana CRs
-		toCreate = append(toCreate, CreateNamespace(KibanaNamespace, es.cfg.Installation.KubernetesProvider))
+		toCreate = append(toCreate, CreateNamespace(KibanaNamespace, es.cfg.Installation.KubernetesProvider, PSSBaseline))
 		toCreate = append(toCreate, es.kibanaServiceAccount())
 
 		if len(es.cfg.PullSecrets) > 0 {
@@ -360,8 +368,8 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 			toCreate = append(toCreate, secret.ToRuntimeObjects(secret.CopyToNamespace(ElasticsearchNamespace, es.cfg.CuratorSecrets...)...)...)
 			toCreate = append(toCreate, es.esCuratorServiceAccount())
 
-			// If the provider is not OpenShift apply the pod security policy for the curator.
-			if es.cfg.Provider != operatorv1.ProviderOpenShift {
+			// If the provider is not OpenShift apply the pod security policy for the curator, if they are supported.
+			if es.cfg.Provider != operatorv1.ProviderOpenShift && es.cfg.UsePSP {
 				toCreate = append(toCreate,
 					es.curatorClusterRole(),
 					es.curatorClusterRoleBinding(),
@@ -385,7 +393,7 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 		}
 	} else {
 		toCreate = append(toCreate,
-			CreateNamespace(ElasticsearchNamespace, es.cfg.Installation.KubernetesProvider),
+			CreateNamespace(ElasticsearchNamespace, es.cfg.Installation.KubernetesProvider, PSSPrivileged),
 			es.elasticsearchExternalService(),
 		)
 	}
@@ -406,6 +414,32 @@ func (es *elasticsearchComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for the Elasticsearch cluster. Its HTTP port is reached by the
+// gateway and the other pods in its namespace, by Kibana and by the ECK operator, and its nodes talk to each other.
+func (es *elasticsearchComponent) NetworkPolicies() []client.Object {
+	if es.cfg.ManagementClusterConnection != nil {
+		// Managed clusters send their logs to the management cluster.
+		return nil
+	}
+
+	transport := networkpolicy.Pods(ElasticsearchNamespace, ElasticsearchSelector, 9300)
+	egress := networkpolicy.DNSEgressRules(es.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, transport))
+	return []client.Object{networkpolicy.New(
+		ElasticsearchName,
+		ElasticsearchNamespace,
+		ElasticsearchSelector,
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{
+			networkpolicy.AllowTCP(networkpolicy.Pods(ElasticsearchNamespace, "all()"), networkpolicy.Ports(ElasticsearchDefaultPort)),
+			networkpolicy.AllowTCP(networkpolicy.Pods(KibanaNamespace, KibanaSelector), networkpolicy.Ports(ElasticsearchDefaultPort)),
+			networkpolicy.AllowTCP(networkpolicy.Pods(ECKOperatorNamespace, "all()"), networkpolicy.Ports(ElasticsearchDefaultPort)),
+			networkpolicy.AllowTCP(networkpolicy.Pods(ElasticsearchNamespace, ElasticsearchSelector), networkpolicy.Ports(9300)),
+		},
+		egress,
+	)}
+}
+
 func (es elasticsearchComponent) elasticsearchExternalService() *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,11 +28,14 @@ import (
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
+	"github.com/tigera/operator/pkg/render/common/poddisruptionbudget"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
 
@@ -75,6 +79,7 @@ func EsGateway(c *Config) render.Component {
 		tlsAnnotations:  tlsAnnotations,
 		clusterDomain:   c.ClusterDomain,
 		esAdminUserName: c.EsAdminUserName,
+		usePolicyV1PDB:  c.UsePolicyV1PDB,
 	}
 }
 
@@ -87,6 +92,7 @@ type esGateway struct {
 	csrImage        string
 	esGatewayImage  string
 	esAdminUserName string
+	usePolicyV1PDB  bool
 }
 
 // Config contains all the config information needed to render the EsGateway component.
@@ -99,6 +105,7 @@ type Config struct {
 	EsInternalCertSecret       *corev1.Secret
 	ClusterDomain              string
 	EsAdminUserName            string
+	UsePolicyV1PDB             bool
 }
 
 func (e *esGateway) ResolveImages(is *operatorv1.ImageSet) error {
@@ -130,7 +137,13 @@ func (e *esGateway) Objects() (toCreate, toDelete []client.Object) {
 	toCreate = append(toCreate, e.esGatewayRole())
 	toCreate = append(toCreate, e.esGatewayRoleBinding())
 	toCreate = append(toCreate, e.esGatewayServiceAccount())
-	toCreate = append(toCreate, e.esGatewayDeployment())
+	deployment := e.esGatewayDeployment()
+	toCreate = append(toCreate, deployment)
+	if pdb, del := poddisruptionbudget.ForDeployment(e.installation, deployment, e.usePolicyV1PDB); pdb != nil {
+		toCreate = append(toCreate, pdb)
+	} else {
+		toDelete = append(toDelete, del)
+	}
 	if e.installation.CertificateManagement != nil {
 		toCreate = append(toCreate, render.CSRClusterRoleBinding(RoleName, render.ElasticsearchNamespace))
 	}
@@ -141,6 +154,25 @@ func (e *esGateway) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for the gateway, which every component that uses Elasticsearch
+// or Kibana goes through.
+func (e *esGateway) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(e.installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{})...)
+	egress = append(egress,
+		networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(render.ElasticsearchNamespace, render.ElasticsearchSelector, ElasticsearchPort)),
+		networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(render.KibanaNamespace, render.KibanaSelector, KibanaPort)),
+	)
+	return []client.Object{networkpolicy.New(
+		DeploymentName,
+		render.ElasticsearchNamespace,
+		fmt.Sprintf("k8s-app == '%s'", DeploymentName),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(Port))},
+		egress,
+	)}
+}
+
 func (e *esGateway) SupportedOSType() rmeta.OSType {
 	return rmeta.OSTypeLinux
 }
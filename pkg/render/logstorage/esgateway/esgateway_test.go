@@ -22,6 +22,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -72,6 +73,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				{RoleName, render.ElasticsearchNamespace, &rbacv1.RoleBinding{}, nil},
 				{ServiceAccountName, render.ElasticsearchNamespace, &corev1.ServiceAccount{}, nil},
 				{DeploymentName, render.ElasticsearchNamespace, &appsv1.Deployment{}, nil},
+				{DeploymentName, render.ElasticsearchNamespace, &policyv1beta1.PodDisruptionBudget{}, nil},
 			}
 
 			component := EsGateway(&Config{
@@ -90,7 +92,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.KibanaInternalCertSecret, Namespace: common.OperatorNamespace()}},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: relasticsearch.InternalCertSecret, Namespace: render.ElasticsearchNamespace}},
-				clusterDomain, "elastic",
+				clusterDomain, "elastic", false,
 			})
 
 			createResources, _ := component.Objects()
@@ -112,6 +114,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				{RoleName, render.ElasticsearchNamespace, &rbacv1.RoleBinding{}, nil},
 				{ServiceAccountName, render.ElasticsearchNamespace, &corev1.ServiceAccount{}, nil},
 				{DeploymentName, render.ElasticsearchNamespace, &appsv1.Deployment{}, nil},
+				{DeploymentName, render.ElasticsearchNamespace, &policyv1beta1.PodDisruptionBudget{}, nil},
 				{RoleName + ":csr-creator", "", &rbacv1.ClusterRoleBinding{}, nil},
 			}
 
@@ -131,7 +134,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.KibanaInternalCertSecret, Namespace: common.OperatorNamespace()}},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: relasticsearch.InternalCertSecret, Namespace: render.ElasticsearchNamespace}},
-				clusterDomain, "elastic",
+				clusterDomain, "elastic", false,
 			})
 
 			createResources, _ := component.Objects()
@@ -158,7 +161,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.KibanaInternalCertSecret, Namespace: common.OperatorNamespace()}},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: relasticsearch.InternalCertSecret, Namespace: render.ElasticsearchNamespace}},
-				clusterDomain, "elastic",
+				clusterDomain, "elastic", false,
 			})
 
 			resources, _ := component.Objects()
@@ -187,7 +190,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.KibanaInternalCertSecret, Namespace: common.OperatorNamespace()}},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: relasticsearch.InternalCertSecret, Namespace: render.ElasticsearchNamespace}},
-				clusterDomain, "elastic",
+				clusterDomain, "elastic", false,
 			})
 
 			resources, _ := component.Objects()
@@ -216,7 +219,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.KibanaInternalCertSecret, Namespace: common.OperatorNamespace()}},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: relasticsearch.InternalCertSecret, Namespace: render.ElasticsearchNamespace}},
-				clusterDomain, "elastic",
+				clusterDomain, "elastic", false,
 			})
 
 			resources, _ := component.Objects()
@@ -249,7 +252,7 @@ var _ = Describe("ES Gateway rendering tests", func() {
 				},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.KibanaInternalCertSecret, Namespace: common.OperatorNamespace()}},
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: relasticsearch.InternalCertSecret, Namespace: render.ElasticsearchNamespace}},
-				clusterDomain, "elastic",
+				clusterDomain, "elastic", false,
 			})
 
 			resources, _ := component.Objects()
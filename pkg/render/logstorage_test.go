@@ -103,6 +103,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				Provider:           operatorv1.ProviderNone,
 				ClusterDomain:      "cluster.local",
 				ElasticLicenseType: render.ElasticsearchLicenseTypeEnterpriseTrial,
+				UsePSP:             true,
 			}
 		})
 
@@ -620,6 +621,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				Provider:           operatorv1.ProviderNone,
 				ClusterDomain:      "cluster.local",
 				ElasticLicenseType: render.ElasticsearchLicenseTypeEnterpriseTrial,
+				UsePSP:             true,
 			}
 		})
 		Context("Initial creation", func() {
@@ -692,6 +694,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				Provider:           operatorv1.ProviderNone,
 				ClusterDomain:      "cluster.local",
 				ElasticLicenseType: render.ElasticsearchLicenseTypeEnterpriseTrial,
+				UsePSP:             true,
 			}
 		})
 		Context("Node distribution", func() {
@@ -1180,6 +1183,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				Provider:           operatorv1.ProviderNone,
 				ClusterDomain:      "cluster.local",
 				ElasticLicenseType: render.ElasticsearchLicenseTypeEnterpriseTrial,
+				UsePSP:             true,
 			}
 		})
 
@@ -1287,6 +1291,7 @@ var deleteLogStorageTests = func(managementCluster *operatorv1.ManagementCluster
 				Provider:           operatorv1.ProviderNone,
 				ClusterDomain:      "cluster.local",
 				ElasticLicenseType: render.ElasticsearchLicenseTypeEnterpriseTrial,
+				UsePSP:             true,
 			}
 		})
 		It("returns Elasticsearch and Kibana CR's to delete and keeps the finalizers on the LogStorage CR", func() {
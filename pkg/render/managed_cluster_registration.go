@@ -0,0 +1,234 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// Managed cluster registration related constants.
+const (
+	// ManagedClusterLabel is set on the secrets of the registered managed clusters, to the name of the cluster.
+	ManagedClusterLabel = "operator.tigera.io/managed-cluster"
+
+	// The keys of the guardian credentials, in the secret of a registered managed cluster and in the secret guardian
+	// reads them from on the managed cluster.
+	ManagedClusterCertKey = "managed-cluster.crt"
+	ManagedClusterKeyKey  = "managed-cluster.key"
+
+	// ManagedClusterManifestKey holds the manifest to apply on the managed cluster to connect it.
+	ManagedClusterManifestKey = "manifest.yaml"
+
+	managedClusterSecretPrefix          = "tigera-managed-cluster-"
+	managedClusterFingerprintAnnotation = "certs.tigera.io/active-fingerprint"
+)
+
+// ManagedClusterSecretName returns the name of the secret in the operator namespace that holds the credentials and
+// the manifest of the registered managed cluster.
+func ManagedClusterSecretName(cluster string) string {
+	return managedClusterSecretPrefix + cluster
+}
+
+// ManagedClusterCredentials returns the secret holding the guardian credentials of the managed cluster. The
+// credentials in the existing secret are kept as long as they were issued by the signing CA and have not expired,
+// otherwise new ones are issued.
+func ManagedClusterCredentials(cluster string, signingCA, existing *corev1.Secret) (*corev1.Secret, error) {
+	if existing != nil && issuedBy(existing.Data[ManagedClusterCertKey], signingCA.Data[VoltronTunnelSecretCertName]) {
+		return &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: managedClusterSecretMeta(cluster),
+			Data: map[string][]byte{
+				ManagedClusterCertKey: existing.Data[ManagedClusterCertKey],
+				ManagedClusterKeyKey:  existing.Data[ManagedClusterKeyKey],
+			},
+		}, nil
+	}
+
+	key, cert, err := createCASignedCert(cluster, signingCA)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: managedClusterSecretMeta(cluster),
+		Data: map[string][]byte{
+			ManagedClusterCertKey: []byte(cert),
+			ManagedClusterKeyKey:  []byte(key),
+		},
+	}, nil
+}
+
+// ManagedClusterRegistrationConfiguration contains all the config information needed to render the component.
+type ManagedClusterRegistrationConfiguration struct {
+	ManagementCluster  *operatorv1.ManagementCluster
+	TunnelCASecret     *corev1.Secret
+	NextTunnelCASecret *corev1.Secret
+
+	// Credentials are the secrets returned by ManagedClusterCredentials for the registered managed clusters.
+	Credentials []*corev1.Secret
+	// StaleClusters are the names of the managed clusters that are no longer registered.
+	StaleClusters []string
+}
+
+// ManagedClusterRegistration renders the ManagedCluster resources of the managed clusters registered on the
+// ManagementCluster, along with the secrets holding the manifests that connect them.
+func ManagedClusterRegistration(cfg *ManagedClusterRegistrationConfiguration) (Component, error) {
+	caBundle := tunnelCABundle(cfg.TunnelCASecret, cfg.NextTunnelCASecret)
+
+	var secrets []*corev1.Secret
+	for _, creds := range cfg.Credentials {
+		s := creds.DeepCopy()
+		s.Data[ManagementClusterCABundleKey] = caBundle
+		m, err := managedClusterManifest(cfg.ManagementCluster, s.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render the manifest of managed cluster %s: %w", creds.Labels[ManagedClusterLabel], err)
+		}
+		s.Data[ManagedClusterManifestKey] = m
+		secrets = append(secrets, s)
+	}
+	return &managedClusterRegistrationComponent{cfg: cfg, secrets: secrets}, nil
+}
+
+type managedClusterRegistrationComponent struct {
+	cfg *ManagedClusterRegistrationConfiguration
+
+	// secrets are the credentials of the registered managed clusters, along with their manifests.
+	secrets []*corev1.Secret
+}
+
+func (c *managedClusterRegistrationComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on a managed cluster registration.
+	return nil
+}
+
+func (c *managedClusterRegistrationComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *managedClusterRegistrationComponent) Ready() bool {
+	return true
+}
+
+func (c *managedClusterRegistrationComponent) Objects() ([]client.Object, []client.Object) {
+	var objs []client.Object
+	for _, s := range c.secrets {
+		cluster := s.Labels[ManagedClusterLabel]
+		objs = append(objs, managedCluster(cluster, fingerprint(s.Data[ManagedClusterCertKey])), s)
+	}
+
+	var toDelete []client.Object
+	for _, cluster := range c.cfg.StaleClusters {
+		toDelete = append(toDelete,
+			managedCluster(cluster, ""),
+			&corev1.Secret{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: managedClusterSecretMeta(cluster),
+			},
+		)
+	}
+	return objs, toDelete
+}
+
+// managedClusterManifest returns the manifest that connects the managed cluster to the management cluster, with the
+// given credentials.
+func managedClusterManifest(mc *operatorv1.ManagementCluster, data map[string][]byte) ([]byte, error) {
+	mcc := &operatorv1.ManagementClusterConnection{
+		TypeMeta:   metav1.TypeMeta{Kind: "ManagementClusterConnection", APIVersion: "operator.tigera.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+		Spec:       operatorv1.ManagementClusterConnectionSpec{ManagementClusterAddr: mc.Spec.Address},
+	}
+	s := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: GuardianSecretName, Namespace: common.OperatorNamespace()},
+		Data: map[string][]byte{
+			ManagedClusterCertKey:        data[ManagedClusterCertKey],
+			ManagedClusterKeyKey:         data[ManagedClusterKeyKey],
+			ManagementClusterCABundleKey: data[ManagementClusterCABundleKey],
+		},
+	}
+
+	var docs []string
+	for _, obj := range []interface{}{mcc, s} {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, string(b))
+	}
+	return []byte(strings.Join(docs, "---\n")), nil
+}
+
+func managedCluster(name, fingerprint string) *v3.ManagedCluster {
+	mc := &v3.ManagedCluster{
+		TypeMeta:   metav1.TypeMeta{Kind: v3.KindManagedCluster, APIVersion: v3.GroupVersionCurrent},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	if fingerprint != "" {
+		// Voltron only accepts the guardian whose certificate matches the active fingerprint of the cluster.
+		mc.Annotations = map[string]string{managedClusterFingerprintAnnotation: fingerprint}
+	}
+	return mc
+}
+
+func managedClusterSecretMeta(cluster string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      ManagedClusterSecretName(cluster),
+		Namespace: common.OperatorNamespace(),
+		Labels:    map[string]string{ManagedClusterLabel: cluster},
+	}
+}
+
+// fingerprint returns the SHA-256 fingerprint of the PEM encoded certificate.
+func fingerprint(certPem []byte) string {
+	block, _ := pem.Decode(certPem)
+	if block == nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(block.Bytes))
+}
+
+// issuedBy returns true if the PEM encoded certificate was signed by the PEM encoded CA certificate and has not
+// expired.
+func issuedBy(certPem, caPem []byte) bool {
+	certBlock, _ := pem.Decode(certPem)
+	caBlock, _ := pem.Decode(caPem)
+	if certBlock == nil || caBlock == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return false
+	}
+	ca, err := x509.ParseCertificate(caBlock.Bytes)
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(ca) == nil && time.Now().Before(cert.NotAfter)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ghodss/yaml"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Managed cluster registration rendering tests", func() {
+	var ca *corev1.Secret
+	var mc *operatorv1.ManagementCluster
+
+	BeforeEach(func() {
+		ca = render.CreateVoltronTunnelSecret(render.VoltronTunnelSecretName)
+		mc = &operatorv1.ManagementCluster{Spec: operatorv1.ManagementClusterSpec{
+			Address:         "example.com:1234",
+			ManagedClusters: []operatorv1.ManagedClusterRegistration{{Name: "cluster-a"}},
+		}}
+	})
+
+	parseCert := func(pemBytes []byte) *x509.Certificate {
+		block, _ := pem.Decode(pemBytes)
+		Expect(block).NotTo(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		return cert
+	}
+
+	It("should issue guardian credentials signed by the tunnel CA", func() {
+		creds, err := render.ManagedClusterCredentials("cluster-a", ca, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.Name).To(Equal("tigera-managed-cluster-cluster-a"))
+		Expect(creds.Namespace).To(Equal(common.OperatorNamespace()))
+		Expect(creds.Labels).To(HaveKeyWithValue(render.ManagedClusterLabel, "cluster-a"))
+
+		cert := parseCert(creds.Data[render.ManagedClusterCertKey])
+		Expect(cert.Subject.CommonName).To(Equal("cluster-a"))
+		Expect(cert.CheckSignatureFrom(parseCert(ca.Data[render.VoltronTunnelSecretCertName]))).NotTo(HaveOccurred())
+	})
+
+	It("should keep the credentials until the signing CA changes", func() {
+		creds, err := render.ManagedClusterCredentials("cluster-a", ca, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		kept, err := render.ManagedClusterCredentials("cluster-a", ca, creds)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kept.Data).To(Equal(creds.Data))
+
+		reissued, err := render.ManagedClusterCredentials("cluster-a", render.CreateVoltronTunnelSecret(render.VoltronTunnelNextSecretName), creds)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reissued.Data[render.ManagedClusterCertKey]).NotTo(Equal(creds.Data[render.ManagedClusterCertKey]))
+	})
+
+	It("should render the ManagedCluster and the manifest that connects it", func() {
+		creds, err := render.ManagedClusterCredentials("cluster-a", ca, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		component, err := render.ManagedClusterRegistration(&render.ManagedClusterRegistrationConfiguration{
+			ManagementCluster: mc,
+			TunnelCASecret:    ca,
+			Credentials:       []*corev1.Secret{creds},
+			StaleClusters:     []string{"cluster-b"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		toCreate, toDelete := component.Objects()
+		Expect(toCreate).To(HaveLen(2))
+
+		managed := rtest.GetResource(toCreate, "cluster-a", "", "projectcalico.org", "v3", "ManagedCluster").(*v3.ManagedCluster)
+		Expect(managed.Annotations).To(HaveKey("certs.tigera.io/active-fingerprint"))
+
+		s := rtest.GetResource(toCreate, "tigera-managed-cluster-cluster-a", common.OperatorNamespace(), "", "v1", "Secret").(*corev1.Secret)
+		Expect(s.Data[render.ManagementClusterCABundleKey]).To(Equal(ca.Data[render.VoltronTunnelSecretCertName]))
+
+		docs := strings.Split(string(s.Data[render.ManagedClusterManifestKey]), "---\n")
+		Expect(docs).To(HaveLen(2))
+		mcc := &operatorv1.ManagementClusterConnection{}
+		Expect(yaml.Unmarshal([]byte(docs[0]), mcc)).NotTo(HaveOccurred())
+		Expect(mcc.Name).To(Equal("tigera-secure"))
+		Expect(mcc.Spec.ManagementClusterAddr).To(Equal("example.com:1234"))
+		guardianSecret := &corev1.Secret{}
+		Expect(yaml.Unmarshal([]byte(docs[1]), guardianSecret)).NotTo(HaveOccurred())
+		Expect(guardianSecret.Name).To(Equal(render.GuardianSecretName))
+		Expect(guardianSecret.Data[render.ManagedClusterCertKey]).To(Equal(creds.Data[render.ManagedClusterCertKey]))
+		Expect(guardianSecret.Data[render.ManagedClusterKeyKey]).To(Equal(creds.Data[render.ManagedClusterKeyKey]))
+		Expect(guardianSecret.Data[render.ManagementClusterCABundleKey]).To(Equal(ca.Data[render.VoltronTunnelSecretCertName]))
+
+		Expect(toDelete).To(HaveLen(2))
+		rtest.ExpectResourceInList(toDelete, "cluster-b", "", "projectcalico.org", "v3", "ManagedCluster")
+		rtest.ExpectResourceInList(toDelete, "tigera-managed-cluster-cluster-b", common.OperatorNamespace(), "", "v1", "Secret")
+	})
+})
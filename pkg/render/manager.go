@@ -26,6 +26,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render/common/authentication"
 	tigerakvc "github.com/tigera/operator/pkg/render/common/authentication/tigera/key_validator_config"
@@ -33,13 +34,16 @@ import (
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rkibana "github.com/tigera/operator/pkg/render/common/kibana"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
+	"github.com/tigera/operator/pkg/render/common/poddisruptionbudget"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -51,6 +55,7 @@ const (
 	managerPort                      = 9443
 	managerTargetPort                = 9443
 	ManagerServiceName               = "tigera-manager"
+	ManagerTunnelServiceName         = "tigera-manager-tunnel"
 	ManagerNamespace                 = "tigera-manager"
 	ManagerServiceIP                 = "localhost"
 	ManagerServiceAccount            = "tigera-manager"
@@ -114,9 +119,12 @@ func Manager(cfg *ManagerConfiguration) (Component, error) {
 		// Copy tunnelSecret and internalTrafficSecret to TLS secrets
 		// tunnelSecret contains the ca cert to generate guardian certificates
 		// internalTrafficCert containts the cert used to communicated within the management K8S cluster
-		tlsSecrets = append(tlsSecrets, secret.CopyToNamespace(ManagerNamespace, cfg.TunnelSecret)...)
+		// While the tunnel CA is rotated, voltron switches to the new CA in the Reissue phase, and trusts both CAs
+		// until the Retire phase.
+		tunnelSecret := tunnelPresentedCA(cfg.ManagementCluster, cfg.TunnelSecret, cfg.NextTunnelSecret, ManagerNamespace)
+		tlsSecrets = append(tlsSecrets, tunnelSecret)
 		tlsSecrets = append(tlsSecrets, secret.CopyToNamespace(ManagerNamespace, cfg.InternalTrafficSecret)...)
-		tlsAnnotations[voltronTunnelHashAnnotation] = rmeta.AnnotationHash(cfg.TunnelSecret.Data)
+		tlsAnnotations[voltronTunnelHashAnnotation] = rmeta.AnnotationHash(tunnelSecret.Data)
 		tlsAnnotations[ManagerInternalTLSHashAnnotation] = rmeta.AnnotationHash(cfg.InternalTrafficSecret.Data)
 	}
 	return &managerComponent{
@@ -138,9 +146,12 @@ type ManagerConfiguration struct {
 	TLSKeyPair                    *corev1.Secret
 	PullSecrets                   []*corev1.Secret
 	Openshift                     bool
+	UsePSP                        bool
+	UsePolicyV1PDB                bool
 	Installation                  *operatorv1.InstallationSpec
 	ManagementCluster             *operatorv1.ManagementCluster
 	TunnelSecret                  *corev1.Secret
+	NextTunnelSecret              *corev1.Secret
 	InternalTrafficSecret         *corev1.Secret
 	ClusterDomain                 string
 	ESLicenseType                 ElasticsearchLicenseType
@@ -197,7 +208,7 @@ func (c *managerComponent) SupportedOSType() rmeta.OSType {
 
 func (c *managerComponent) Objects() ([]client.Object, []client.Object) {
 	objs := []client.Object{
-		CreateNamespace(ManagerNamespace, c.cfg.Installation.KubernetesProvider),
+		CreateNamespace(ManagerNamespace, c.cfg.Installation.KubernetesProvider, PSSRestricted),
 	}
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.PullSecrets...)...)...)
 
@@ -214,12 +225,14 @@ func (c *managerComponent) Objects() ([]client.Object, []client.Object) {
 	objs = append(objs,
 		c.managerService(),
 	)
+	tunnelObjs, tunnelToDelete := c.tunnelObjects()
+	objs = append(objs, tunnelObjs...)
 
 	// If we're running on openshift, we need to add in an SCC.
 	if c.cfg.Openshift {
 		objs = append(objs, c.securityContextConstraints())
-	} else {
-		// If we're not running openshift, we need to add pod security policies.
+	} else if c.cfg.UsePSP {
+		// If we're not running openshift, we need to add pod security policies where they are supported.
 		objs = append(objs, c.managerPodSecurityPolicy())
 	}
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.ESSecrets...)...)...)
@@ -233,12 +246,18 @@ func (c *managerComponent) Objects() ([]client.Object, []client.Object) {
 	if c.cfg.PrometheusCertSecret != nil {
 		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.PrometheusCertSecret)...)...)
 	}
-	objs = append(objs, c.managerDeployment())
+	toDelete := tunnelToDelete
+	deployment := c.managerDeployment()
+	objs = append(objs, deployment)
+	if pdb, del := poddisruptionbudget.ForDeployment(c.cfg.Installation, deployment, c.cfg.UsePolicyV1PDB); pdb != nil {
+		objs = append(objs, pdb)
+	} else {
+		toDelete = append(toDelete, del)
+	}
 	if c.cfg.KeyValidatorConfig != nil {
 		objs = append(objs, configmap.ToRuntimeObjects(c.cfg.KeyValidatorConfig.RequiredConfigMaps(ManagerNamespace)...)...)
 	}
 
-	var toDelete []client.Object
 	if c.cfg.Installation.CertificateManagement != nil {
 		objs = append(objs, CSRClusterRoleBinding(ManagerServiceName, ManagerNamespace))
 		// If we want to use certificate management, we should clean up any existing secrets that have been created by the operator.
@@ -260,6 +279,40 @@ func (c *managerComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for the manager. The manager is reached by users and, in a
+// management cluster, by the guardians of the managed clusters. It reaches out to the other components whose APIs
+// it proxies and, when authentication is configured, to the identity provider.
+func (c *managerComponent) NetworkPolicies() []client.Object {
+	ingress := []v3.Rule{networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(managerTargetPort))}
+	if c.cfg.ManagementCluster != nil {
+		tunnelPort, _ := strconv.Atoi(defaultTunnelVoltronPort)
+		ingress = append(ingress, networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(uint16(tunnelPort))))
+	}
+
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{})...)
+	egress = append(egress,
+		networkpolicy.ESGatewayEgressRule(),
+		networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(ComplianceNamespace, fmt.Sprintf("k8s-app == '%s'", ComplianceServerName), complianceServerPort)),
+		networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(PacketCaptureNamespace, fmt.Sprintf("k8s-app == '%s'", PacketCaptureName), PacketCapturePort)),
+		networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(common.TigeraPrometheusNamespace, "all()")),
+		networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Pods(DexNamespace, "all()", DexPort)),
+	)
+	if c.cfg.KeyValidatorConfig != nil {
+		// External identity providers are reached over https.
+		egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(443)))
+	}
+
+	return []client.Object{networkpolicy.New(
+		ManagerServiceName,
+		ManagerNamespace,
+		"k8s-app == 'tigera-manager'",
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		ingress,
+		egress,
+	)}
+}
+
 // managerDeployment creates a deployment for the Tigera Secure manager component.
 func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 	annotations := make(map[string]string)
@@ -578,6 +631,10 @@ func (c *managerComponent) managerProxyContainer() corev1.Container {
 		{Name: "VOLTRON_DEFAULT_FORWARD_SERVER", Value: "tigera-secure-es-gateway-http.tigera-elasticsearch.svc:9200"},
 	}
 
+	if c.cfg.ManagementCluster != nil {
+		env = append(env, corev1.EnvVar{Name: "VOLTRON_TUNNEL_CA_BUNDLE_PATH", Value: "/certs/tunnel/" + VoltronTunnelCABundleKey})
+	}
+
 	if c.cfg.KeyValidatorConfig != nil {
 		env = append(env, c.cfg.KeyValidatorConfig.RequiredEnv("VOLTRON_")...)
 	}
@@ -683,6 +740,81 @@ func (c *managerComponent) managerService() *corev1.Service {
 	}
 }
 
+// tunnelObjects returns the objects exposing the tunnel the managed clusters connect to, and those to delete since
+// the tunnel is no longer exposed that way.
+func (c *managerComponent) tunnelObjects() ([]client.Object, []client.Object) {
+	svc := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: ManagerTunnelServiceName, Namespace: ManagerNamespace},
+	}
+	ing := &networkingv1.Ingress{
+		TypeMeta:   metav1.TypeMeta{Kind: "Ingress", APIVersion: "networking.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: ManagerTunnelServiceName, Namespace: ManagerNamespace},
+	}
+
+	if c.cfg.ManagementCluster == nil || c.cfg.ManagementCluster.Spec.TunnelService == nil {
+		return nil, []client.Object{svc, ing}
+	}
+	ts := c.cfg.ManagementCluster.Spec.TunnelService
+
+	tunnelPort, _ := strconv.Atoi(defaultTunnelVoltronPort)
+	svc.Spec = corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			{
+				Name:       "tunnel",
+				Port:       int32(tunnelPort),
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt(tunnelPort),
+			},
+		},
+		Selector: map[string]string{
+			"k8s-app": "tigera-manager",
+		},
+	}
+
+	switch ts.Type {
+	case operatorv1.TunnelServiceLoadBalancer:
+		svc.Annotations = ts.Annotations
+		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+	case operatorv1.TunnelServiceNodePort:
+		svc.Annotations = ts.Annotations
+		svc.Spec.Type = corev1.ServiceTypeNodePort
+		if ts.NodePort != nil {
+			svc.Spec.Ports[0].NodePort = *ts.NodePort
+		}
+	case operatorv1.TunnelServiceIngress:
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+		pathType := networkingv1.PathTypePrefix
+		ing.Annotations = ts.Annotations
+		ing.Spec = networkingv1.IngressSpec{
+			IngressClassName: ts.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: ts.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: ManagerTunnelServiceName,
+											Port: networkingv1.ServiceBackendPort{Name: "tunnel"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		return []client.Object{svc, ing}, nil
+	}
+	return []client.Object{svc}, []client.Object{ing}
+}
+
 // managerServiceAccount creates the serviceaccount used by the Tigera Secure web app.
 func managerServiceAccount() *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
@@ -15,12 +15,17 @@
 package render_test
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -44,7 +49,7 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 	}
 	var replicas int32 = 2
 	installation := &operatorv1.InstallationSpec{ControlPlaneReplicas: &replicas}
-	const expectedResourcesNumber = 15
+	const expectedResourcesNumber = 16
 
 	expectedDNSNames := dns.GetServiceDNSNames(render.ManagerServiceName, render.ManagerNamespace, dns.DefaultClusterDomain)
 	expectedDNSNames = append(expectedDNSNames, "localhost")
@@ -75,6 +80,7 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 			{name: render.PacketCaptureCertSecret, ns: render.ManagerNamespace, group: "", version: "v1", kind: "Secret"},
 			{name: render.PrometheusTLSSecretName, ns: render.ManagerNamespace, group: "", version: "v1", kind: "Secret"},
 			{name: "tigera-manager", ns: render.ManagerNamespace, group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-manager", ns: render.ManagerNamespace, group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 		}
 
 		i := 0
@@ -271,6 +277,7 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 			{name: render.PacketCaptureCertSecret, ns: "tigera-manager", group: "", version: "v1", kind: "Secret"},
 			{name: render.PrometheusTLSSecretName, ns: "tigera-manager", group: "", version: "v1", kind: "Secret"},
 			{name: "tigera-manager", ns: "tigera-manager", group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-manager", ns: "tigera-manager", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 		}
 
 		Expect(len(resources)).To(Equal(len(expectedResources)))
@@ -433,6 +440,7 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 			Installation:                  i,
 			ESLicenseType:                 render.ElasticsearchLicenseTypeUnknown,
 			Replicas:                      &replicas,
+			UsePSP:                        true,
 		}
 		component, err := render.Manager(cfg)
 		Expect(err).To(BeNil(), "Expected Manager to create successfully %s", err)
@@ -440,6 +448,148 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 		return rtest.GetResource(resources, "tigera-manager", render.ManagerNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
 	}
 
+	It("should expose the tunnel with a LoadBalancer service", func() {
+		mc := &operatorv1.ManagementCluster{Spec: operatorv1.ManagementClusterSpec{
+			TunnelService: &operatorv1.ManagementClusterTunnelService{
+				Type:        operatorv1.TunnelServiceLoadBalancer,
+				Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+			},
+		}}
+		resources := renderObjects(false, mc, installation, true)
+
+		svc := rtest.GetResource(resources, render.ManagerTunnelServiceName, render.ManagerNamespace, "", "v1", "Service").(*corev1.Service)
+		Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(svc.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-type", "nlb"))
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{"k8s-app": "tigera-manager"}))
+		Expect(svc.Spec.Ports).To(HaveLen(1))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(9449)))
+		Expect(svc.Spec.Ports[0].TargetPort).To(Equal(intstr.FromInt(9449)))
+		Expect(rtest.GetResource(resources, render.ManagerTunnelServiceName, render.ManagerNamespace, "networking.k8s.io", "v1", "Ingress")).To(BeNil())
+	})
+
+	It("should expose the tunnel with a NodePort service", func() {
+		nodePort := int32(31000)
+		mc := &operatorv1.ManagementCluster{Spec: operatorv1.ManagementClusterSpec{
+			TunnelService: &operatorv1.ManagementClusterTunnelService{Type: operatorv1.TunnelServiceNodePort, NodePort: &nodePort},
+		}}
+		resources := renderObjects(false, mc, installation, true)
+
+		svc := rtest.GetResource(resources, render.ManagerTunnelServiceName, render.ManagerNamespace, "", "v1", "Service").(*corev1.Service)
+		Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+		Expect(svc.Spec.Ports[0].NodePort).To(Equal(nodePort))
+	})
+
+	It("should expose the tunnel with an Ingress", func() {
+		className := "nginx"
+		mc := &operatorv1.ManagementCluster{Spec: operatorv1.ManagementClusterSpec{
+			TunnelService: &operatorv1.ManagementClusterTunnelService{
+				Type:             operatorv1.TunnelServiceIngress,
+				Host:             "tunnel.example.com",
+				IngressClassName: &className,
+				Annotations:      map[string]string{"nginx.ingress.kubernetes.io/ssl-passthrough": "true"},
+			},
+		}}
+		resources := renderObjects(false, mc, installation, true)
+
+		svc := rtest.GetResource(resources, render.ManagerTunnelServiceName, render.ManagerNamespace, "", "v1", "Service").(*corev1.Service)
+		Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(svc.Annotations).To(BeEmpty())
+
+		ing := rtest.GetResource(resources, render.ManagerTunnelServiceName, render.ManagerNamespace, "networking.k8s.io", "v1", "Ingress").(*networkingv1.Ingress)
+		Expect(ing.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-passthrough", "true"))
+		Expect(ing.Spec.IngressClassName).To(Equal(&className))
+		Expect(ing.Spec.Rules).To(HaveLen(1))
+		Expect(ing.Spec.Rules[0].Host).To(Equal("tunnel.example.com"))
+		backend := ing.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+		Expect(backend.Name).To(Equal(render.ManagerTunnelServiceName))
+		Expect(backend.Port.Name).To(Equal("tunnel"))
+	})
+
+	It("should not expose the tunnel unless configured to", func() {
+		resources := renderObjects(false, &operatorv1.ManagementCluster{}, installation, true)
+		Expect(rtest.GetResource(resources, render.ManagerTunnelServiceName, render.ManagerNamespace, "", "v1", "Service")).To(BeNil())
+
+		component, err := render.Manager(&render.ManagerConfiguration{
+			ESClusterConfig:       relasticsearch.NewClusterConfig("clusterTestName", 1, 1, 1),
+			TLSKeyPair:            rtest.CreateCertSecret(render.ManagerTLSSecretName, common.OperatorNamespace()),
+			Installation:          installation,
+			ManagementCluster:     &operatorv1.ManagementCluster{},
+			TunnelSecret:          &testutils.VoltronTunnelSecret,
+			InternalTrafficSecret: &testutils.InternalManagerTLSSecret,
+			ClusterDomain:         dns.DefaultClusterDomain,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, toDelete := component.Objects()
+		rtest.ExpectResourceInList(toDelete, render.ManagerTunnelServiceName, render.ManagerNamespace, "", "v1", "Service")
+		rtest.ExpectResourceInList(toDelete, render.ManagerTunnelServiceName, render.ManagerNamespace, "networking.k8s.io", "v1", "Ingress")
+	})
+
+	It("should present the new tunnel CA once the rotation is reissued", func() {
+		next := testutils.VoltronTunnelSecret.DeepCopy()
+		next.Name = render.VoltronTunnelNextSecretName
+		next.Data[render.VoltronTunnelSecretCertName] = []byte("next-cert")
+		phase := operatorv1.TunnelCARotationReissue
+		component, err := render.Manager(&render.ManagerConfiguration{
+			ESClusterConfig:       relasticsearch.NewClusterConfig("clusterTestName", 1, 1, 1),
+			TLSKeyPair:            rtest.CreateCertSecret(render.ManagerTLSSecretName, common.OperatorNamespace()),
+			Installation:          installation,
+			ManagementCluster:     &operatorv1.ManagementCluster{Spec: operatorv1.ManagementClusterSpec{TunnelCARotation: &phase}},
+			TunnelSecret:          &testutils.VoltronTunnelSecret,
+			NextTunnelSecret:      next,
+			InternalTrafficSecret: &testutils.InternalManagerTLSSecret,
+			ClusterDomain:         dns.DefaultClusterDomain,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		By("presenting the new CA to the managed clusters")
+		tunnel := rtest.GetResource(resources, render.VoltronTunnelSecretName, render.ManagerNamespace, "", "v1", "Secret").(*corev1.Secret)
+		Expect(tunnel.Data[render.VoltronTunnelSecretCertName]).To(Equal([]byte("next-cert")))
+
+		By("leaving the source secret untouched")
+		Expect(testutils.VoltronTunnelSecret.Data[render.VoltronTunnelSecretCertName]).NotTo(Equal([]byte("next-cert")))
+	})
+
+	It("should trust the certificates signed by either tunnel CA until the rotation is retired", func() {
+		current := render.CreateVoltronTunnelSecret(render.VoltronTunnelSecretName)
+		next := render.CreateVoltronTunnelSecret(render.VoltronTunnelNextSecretName)
+		creds, err := render.ManagedClusterCredentials("cluster", current, nil)
+		Expect(err).NotTo(HaveOccurred())
+		block, _ := pem.Decode(creds.Data[render.ManagedClusterCertKey])
+		Expect(block).NotTo(BeNil())
+		clientCert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		verify := func(phase operatorv1.TunnelCARotationPhase, current, next *corev1.Secret) error {
+			component, err := render.Manager(&render.ManagerConfiguration{
+				ESClusterConfig:       relasticsearch.NewClusterConfig("clusterTestName", 1, 1, 1),
+				TLSKeyPair:            rtest.CreateCertSecret(render.ManagerTLSSecretName, common.OperatorNamespace()),
+				Installation:          installation,
+				ManagementCluster:     &operatorv1.ManagementCluster{Spec: operatorv1.ManagementClusterSpec{TunnelCARotation: &phase}},
+				TunnelSecret:          current,
+				NextTunnelSecret:      next,
+				InternalTrafficSecret: &testutils.InternalManagerTLSSecret,
+				ClusterDomain:         dns.DefaultClusterDomain,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			resources, _ := component.Objects()
+			tunnel := rtest.GetResource(resources, render.VoltronTunnelSecretName, render.ManagerNamespace, "", "v1", "Secret").(*corev1.Secret)
+			pool := x509.NewCertPool()
+			Expect(pool.AppendCertsFromPEM(tunnel.Data[render.VoltronTunnelCABundleKey])).To(BeTrue())
+			_, err = clientCert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+			return err
+		}
+
+		Expect(verify(operatorv1.TunnelCARotationPublish, current, next)).To(Succeed())
+		Expect(verify(operatorv1.TunnelCARotationReissue, current, next)).To(Succeed())
+
+		By("no longer trusting the old CA once the new CA replaces it")
+		retired := next.DeepCopy()
+		retired.Name = render.VoltronTunnelSecretName
+		Expect(verify(operatorv1.TunnelCARotationRetire, retired, nil)).NotTo(Succeed())
+	})
+
 	It("should apply controlPlaneNodeSelectors", func() {
 		deployment := renderManager(&operatorv1.InstallationSpec{
 			ControlPlaneNodeSelector: map[string]string{
@@ -491,6 +641,7 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 			{name: render.PacketCaptureCertSecret, ns: render.ManagerNamespace, group: "", version: "v1", kind: "Secret"},
 			{name: render.PrometheusTLSSecretName, ns: render.ManagerNamespace, group: "", version: "v1", kind: "Secret"},
 			{name: "tigera-manager", ns: render.ManagerNamespace, group: "apps", version: "v1", kind: "Deployment"},
+			{name: "tigera-manager", ns: render.ManagerNamespace, group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 			{"tigera-manager:csr-creator", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding"},
 		}
 
@@ -588,6 +739,7 @@ func renderObjects(oidc bool, managementCluster *operatorv1.ManagementCluster, i
 		ClusterDomain:                 dns.DefaultClusterDomain,
 		ESLicenseType:                 render.ElasticsearchLicenseTypeEnterpriseTrial,
 		Replicas:                      installation.ControlPlaneReplicas,
+		UsePSP:                        true,
 	}
 	component, err := render.Manager(cfg)
 	Expect(err).To(BeNil(), "Expected Manager to create successfully %s", err)
@@ -22,6 +22,7 @@ import (
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render/common/authentication"
 	"github.com/tigera/operator/pkg/render/common/configmap"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -37,6 +38,7 @@ import (
 	"github.com/tigera/operator/pkg/ptr"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
 	"github.com/tigera/operator/pkg/render/logstorage/esmetrics"
 )
@@ -154,7 +156,7 @@ func (mc *monitorComponent) SupportedOSType() rmeta.OSType {
 
 func (mc *monitorComponent) Objects() ([]client.Object, []client.Object) {
 	toCreate := []client.Object{
-		render.CreateNamespace(common.TigeraPrometheusNamespace, mc.cfg.Installation.KubernetesProvider),
+		render.CreateNamespace(common.TigeraPrometheusNamespace, mc.cfg.Installation.KubernetesProvider, render.PSSBaseline),
 		mc.cfg.TrustedCertBundle,
 	}
 
@@ -252,6 +254,22 @@ func (mc *monitorComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for Prometheus, which is queried through its authenticating proxy.
+// The metrics endpoints it scrapes include those of the host networked components, so its TCP egress is not restricted
+// to particular destinations.
+func (mc *monitorComponent) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(mc.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.AllowTCP(v3.EntityRule{}, v3.EntityRule{}))
+	return []client.Object{networkpolicy.New(
+		CalicoNodePrometheus,
+		common.TigeraPrometheusNamespace,
+		fmt.Sprintf("prometheus == '%s'", calicoNodePrometheusServiceName),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(PrometheusProxyPort))},
+		egress,
+	)}
+}
+
 func (mc *monitorComponent) alertmanager() *monitoringv1.Alertmanager {
 	return &monitoringv1.Alertmanager{
 		TypeMeta: metav1.TypeMeta{Kind: monitoringv1.AlertmanagersKind, APIVersion: MonitoringAPIVersion},
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	nadv1 "github.com/tigera/operator/pkg/apis/k8s.cni.cncf.io/v1"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+const (
+	// MultusNetworkAttachmentDefinitionsNamespace is the namespace of the NetworkAttachmentDefinitions of the IP pools.
+	// Multus lets pods of any namespace reference the NetworkAttachmentDefinitions of the default namespace.
+	MultusNetworkAttachmentDefinitionsNamespace = "default"
+)
+
+// MultusConfiguration contains all the config information needed to render the component.
+type MultusConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+
+	// StaleNetworkAttachmentDefinitions are the names of the NetworkAttachmentDefinitions created for IP pools that
+	// are no longer Manual, or no longer in the Installation.
+	StaleNetworkAttachmentDefinitions []string
+}
+
+// Multus renders a NetworkAttachmentDefinition with the Calico CNI plugin for each of the secondary IP pools of the
+// Installation, when they are enabled.
+func Multus(cfg *MultusConfiguration) Component {
+	return &multusComponent{cfg: cfg}
+}
+
+type multusComponent struct {
+	cfg *MultusConfiguration
+}
+
+func (c *multusComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on NetworkAttachmentDefinitions.
+	return nil
+}
+
+func (c *multusComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *multusComponent) Ready() bool {
+	return true
+}
+
+func (c *multusComponent) Objects() ([]client.Object, []client.Object) {
+	var objs []client.Object
+	if MultusNetworkAttachmentDefinitionsEnabled(c.cfg.Installation) {
+		for _, p := range SecondaryIPPools(c.cfg.Installation.CalicoNetwork.IPPools) {
+			objs = append(objs, c.networkAttachmentDefinition(p))
+		}
+	}
+
+	var toDelete []client.Object
+	for _, name := range c.cfg.StaleNetworkAttachmentDefinitions {
+		toDelete = append(toDelete, &nadv1.NetworkAttachmentDefinition{
+			TypeMeta:   metav1.TypeMeta{Kind: nadv1.KindNetworkAttachmentDefinition, APIVersion: "k8s.cni.cncf.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: MultusNetworkAttachmentDefinitionsNamespace},
+		})
+	}
+	return objs, toDelete
+}
+
+// networkAttachmentDefinition renders the NetworkAttachmentDefinition of the IP pool, whose Calico CNI configuration
+// assigns the address of the additional interface from the IP pool.
+func (c *multusComponent) networkAttachmentDefinition(p *operatorv1.IPPool) *nadv1.NetworkAttachmentDefinition {
+	var mtu int32 = 0
+	if m := getMTU(c.cfg.Installation); m != nil {
+		mtu = *m
+	}
+
+	ipam := fmt.Sprintf(`{ "type": "calico-ipam", "assign_ipv4" : "true", "assign_ipv6" : "false", "ipv4_pools": ["%s"] }`, p.Name)
+	if len(GetIPv6Pools([]operatorv1.IPPool{*p})) > 0 {
+		ipam = fmt.Sprintf(`{ "type": "calico-ipam", "assign_ipv4" : "false", "assign_ipv6" : "true", "ipv6_pools": ["%s"] }`, p.Name)
+	}
+
+	// install-cni writes the kubeconfig of the CNI plugin next to the CNI network configuration on each node.
+	cniNetDir, _, _ := cniDirectories(c.cfg.Installation.KubernetesProvider)
+
+	config := fmt.Sprintf(`{
+  "name": "%s",
+  "cniVersion": "0.3.1",
+  "type": "calico",
+  "datastore_type": "kubernetes",
+  "mtu": %d,
+  "log_level": "Info",
+  "log_file_path": "/var/log/calico/cni/cni.log",
+  "ipam": %s,
+  "policy": {
+      "type": "k8s"
+  },
+  "kubernetes": {
+      "kubeconfig": "%s"
+  }
+}`, p.Name, mtu, ipam, filepath.Join(cniNetDir, "calico-kubeconfig"))
+
+	return &nadv1.NetworkAttachmentDefinition{
+		TypeMeta: metav1.TypeMeta{Kind: nadv1.KindNetworkAttachmentDefinition, APIVersion: "k8s.cni.cncf.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.Name,
+			Namespace: MultusNetworkAttachmentDefinitionsNamespace,
+			Labels:    map[string]string{IPPoolManagedByLabel: IPPoolManagedByLabelValue},
+		},
+		Spec: nadv1.NetworkAttachmentDefinitionSpec{Config: config},
+	}
+}
+
+// MultusEnabled returns true if the Installation provides multiple pod interfaces through Multus.
+func MultusEnabled(instance *operatorv1.InstallationSpec) bool {
+	return instance.CalicoNetwork != nil && instance.CalicoNetwork.MultiInterfaceMode != nil &&
+		*instance.CalicoNetwork.MultiInterfaceMode == operatorv1.MultiInterfaceModeMultus
+}
+
+// MultusNetworkAttachmentDefinitionsEnabled returns true if the operator creates the NetworkAttachmentDefinitions of
+// the secondary IP pools of the Installation.
+func MultusNetworkAttachmentDefinitionsEnabled(instance *operatorv1.InstallationSpec) bool {
+	return MultusEnabled(instance) && instance.CalicoNetwork.Multus != nil &&
+		instance.CalicoNetwork.Multus.NetworkAttachmentDefinitions != nil &&
+		*instance.CalicoNetwork.Multus.NetworkAttachmentDefinitions == operatorv1.NetworkAttachmentDefinitionsEnabled
+}
+
+// SecondaryIPPools returns the IP pools with the Manual assignment mode, which the additional pod interfaces are
+// assigned their addresses from, in the order they are specified.
+func SecondaryIPPools(pools []operatorv1.IPPool) []*operatorv1.IPPool {
+	var secondary []*operatorv1.IPPool
+	for ii, pool := range pools {
+		if pool.AssignmentMode != nil && *pool.AssignmentMode == operatorv1.IPPoolAssignmentManual {
+			secondary = append(secondary, &pools[ii])
+		}
+	}
+	return secondary
+}
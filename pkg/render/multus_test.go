@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	nadv1 "github.com/tigera/operator/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("Multus rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+
+	BeforeEach(func() {
+		multus := operatorv1.MultiInterfaceModeMultus
+		manual := operatorv1.IPPoolAssignmentManual
+		nads := operatorv1.NetworkAttachmentDefinitionsEnabled
+		var mtu int32 = 1400
+		installation = &operatorv1.InstallationSpec{
+			KubernetesProvider: operatorv1.ProviderNone,
+			CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+				MTU:                &mtu,
+				MultiInterfaceMode: &multus,
+				Multus:             &operatorv1.MultusSpec{NetworkAttachmentDefinitions: &nads},
+				IPPools: []operatorv1.IPPool{
+					{Name: "default-ipv4-ippool", CIDR: "192.168.0.0/24"},
+					{Name: "secondary", CIDR: "172.16.0.0/24", AssignmentMode: &manual},
+					{Name: "default-ipv6-ippool", CIDR: "fd00::/64"},
+					{Name: "secondary-v6", CIDR: "fd01::/64", AssignmentMode: &manual},
+				},
+			},
+		}
+	})
+
+	It("should render a NetworkAttachmentDefinition for each secondary IP pool", func() {
+		objs, toDelete := render.Multus(&render.MultusConfiguration{
+			Installation:                      installation,
+			StaleNetworkAttachmentDefinitions: []string{"removed"},
+		}).Objects()
+		Expect(objs).To(HaveLen(2))
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].GetName()).To(Equal("removed"))
+		Expect(toDelete[0].GetNamespace()).To(Equal("default"))
+
+		nad := objs[0].(*nadv1.NetworkAttachmentDefinition)
+		Expect(nad.Name).To(Equal("secondary"))
+		Expect(nad.Namespace).To(Equal("default"))
+		Expect(nad.Labels).To(Equal(map[string]string{render.IPPoolManagedByLabel: render.IPPoolManagedByLabelValue}))
+
+		var config map[string]interface{}
+		Expect(json.Unmarshal([]byte(nad.Spec.Config), &config)).NotTo(HaveOccurred())
+		Expect(config["name"]).To(Equal("secondary"))
+		Expect(config["type"]).To(Equal("calico"))
+		Expect(config["mtu"]).To(BeEquivalentTo(1400))
+		Expect(config["ipam"]).To(Equal(map[string]interface{}{
+			"type":        "calico-ipam",
+			"assign_ipv4": "true",
+			"assign_ipv6": "false",
+			"ipv4_pools":  []interface{}{"secondary"},
+		}))
+		Expect(config["kubernetes"]).To(Equal(map[string]interface{}{"kubeconfig": "/etc/cni/net.d/calico-kubeconfig"}))
+
+		nad = objs[1].(*nadv1.NetworkAttachmentDefinition)
+		Expect(nad.Name).To(Equal("secondary-v6"))
+		Expect(json.Unmarshal([]byte(nad.Spec.Config), &config)).NotTo(HaveOccurred())
+		Expect(config["ipam"]).To(Equal(map[string]interface{}{
+			"type":        "calico-ipam",
+			"assign_ipv4": "false",
+			"assign_ipv6": "true",
+			"ipv6_pools":  []interface{}{"secondary-v6"},
+		}))
+	})
+
+	It("should only delete the stale NetworkAttachmentDefinitions when they are disabled", func() {
+		nads := operatorv1.NetworkAttachmentDefinitionsDisabled
+		installation.CalicoNetwork.Multus.NetworkAttachmentDefinitions = &nads
+		objs, toDelete := render.Multus(&render.MultusConfiguration{
+			Installation:                      installation,
+			StaleNetworkAttachmentDefinitions: []string{"secondary", "secondary-v6"},
+		}).Objects()
+		Expect(objs).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(2))
+	})
+})
@@ -53,11 +53,11 @@ func (c *namespaceComponent) SupportedOSType() rmeta.OSType {
 
 func (c *namespaceComponent) Objects() ([]client.Object, []client.Object) {
 	ns := []client.Object{
-		CreateNamespace(common.CalicoNamespace, c.cfg.Installation.KubernetesProvider),
+		CreateNamespace(common.CalicoNamespace, c.cfg.Installation.KubernetesProvider, PSSPrivileged),
 	}
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		// We need to always have ns tigera-dex even when the Authentication CR is not present, so policies can be added to this namespace.
-		ns = append(ns, CreateNamespace(DexObjectName, c.cfg.Installation.KubernetesProvider))
+		ns = append(ns, CreateNamespace(DexObjectName, c.cfg.Installation.KubernetesProvider, PSSRestricted))
 	}
 	if len(c.cfg.PullSecrets) > 0 {
 		ns = append(ns, secret.ToRuntimeObjects(secret.CopyToNamespace(common.CalicoNamespace, c.cfg.PullSecrets...)...)...)
@@ -74,12 +74,32 @@ func (c *namespaceComponent) Ready() bool {
 	return true
 }
 
-func CreateNamespace(name string, provider operatorv1.Provider) *corev1.Namespace {
+// PodSecurityStandard is a Pod Security Admission level that is enforced on a namespace.
+type PodSecurityStandard string
+
+const (
+	PSSPrivileged PodSecurityStandard = "privileged"
+	PSSBaseline   PodSecurityStandard = "baseline"
+	PSSRestricted PodSecurityStandard = "restricted"
+)
+
+const (
+	PSSEnforceLabel        = "pod-security.kubernetes.io/enforce"
+	PSSEnforceVersionLabel = "pod-security.kubernetes.io/enforce-version"
+)
+
+// CreateNamespace returns the namespace with the given name, labelled so that Pod Security Admission
+// enforces the given Pod Security Standard on the pods in it.
+func CreateNamespace(name string, provider operatorv1.Provider, pss PodSecurityStandard) *corev1.Namespace {
 	ns := &corev1.Namespace{
 		TypeMeta: metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        name,
-			Labels:      map[string]string{"name": name},
+			Name: name,
+			Labels: map[string]string{
+				"name":                 name,
+				PSSEnforceLabel:        string(pss),
+				PSSEnforceVersionLabel: "latest",
+			},
 			Annotations: map[string]string{},
 		},
 	}
@@ -44,6 +44,19 @@ var _ = Describe("Namespace rendering tests", func() {
 		Expect(meta.GetAnnotations()).NotTo(ContainElement("openshift.io/node-selector"))
 	})
 
+	It("should label the namespaces with the Pod Security Standard to enforce", func() {
+		cfg.Installation.Variant = operatorv1.TigeraSecureEnterprise
+		component := render.Namespaces(cfg)
+		resources, _ := component.Objects()
+		Expect(len(resources)).To(Equal(2))
+		meta := resources[0].(metav1.ObjectMetaAccessor).GetObjectMeta()
+		Expect(meta.GetLabels()["pod-security.kubernetes.io/enforce"]).To(Equal("privileged"))
+		Expect(meta.GetLabels()["pod-security.kubernetes.io/enforce-version"]).To(Equal("latest"))
+		meta = resources[1].(metav1.ObjectMetaAccessor).GetObjectMeta()
+		Expect(meta.GetLabels()["pod-security.kubernetes.io/enforce"]).To(Equal("restricted"))
+		Expect(meta.GetLabels()["pod-security.kubernetes.io/enforce-version"]).To(Equal("latest"))
+	})
+
 	It("should render a namespace for openshift", func() {
 		cfg.Installation.KubernetesProvider = operatorv1.ProviderOpenShift
 		component := render.Namespaces(cfg)
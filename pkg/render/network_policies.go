@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
+)
+
+// NetworkPolicyComponent is implemented by the components that declare the traffic their pods need, as network
+// policies in the allow-tigera tier.
+type NetworkPolicyComponent interface {
+	NetworkPolicies() []client.Object
+}
+
+// NetworkPolicies returns a component that creates the network policies declared by the given components when the
+// Installation enables them, and deletes those still present in the tier otherwise. Nothing is rendered until the
+// allow-tigera tier exists, since the policies can be neither created nor deleted without it.
+func NetworkPolicies(install *operatorv1.InstallationSpec, tier networkpolicy.TierState, comps ...Component) Component {
+	return &networkPoliciesComponent{install: install, tier: tier, comps: comps}
+}
+
+type networkPoliciesComponent struct {
+	install *operatorv1.InstallationSpec
+	tier    networkpolicy.TierState
+	comps   []Component
+}
+
+func (c *networkPoliciesComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on a network policy.
+	return nil
+}
+
+func (c *networkPoliciesComponent) Objects() ([]client.Object, []client.Object) {
+	if !c.tier.Exists {
+		return nil, nil
+	}
+
+	policies := []client.Object{}
+	for _, comp := range c.comps {
+		if npc, ok := comp.(NetworkPolicyComponent); ok {
+			policies = append(policies, npc.NetworkPolicies()...)
+		}
+	}
+
+	if networkpolicy.Enabled(c.install) {
+		return policies, nil
+	}
+
+	// Deleting the policies that are already gone would cost an API call for each of them on every reconcile.
+	present := []client.Object{}
+	for _, p := range policies {
+		if c.tier.Policies[types.NamespacedName{Namespace: p.GetNamespace(), Name: p.GetName()}] {
+			present = append(present, p)
+		}
+	}
+	return nil, present
+}
+
+func (c *networkPoliciesComponent) Ready() bool {
+	return true
+}
+
+func (c *networkPoliciesComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/api/pkg/lib/numorstring"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
+)
+
+var _ = Describe("Component network policy rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+	var packetCapture render.Component
+	var tier networkpolicy.TierState
+
+	BeforeEach(func() {
+		tier = networkpolicy.TierState{Exists: true}
+		enabled := operatorv1.ComponentNetworkPoliciesEnabled
+		installation = &operatorv1.InstallationSpec{
+			Variant:                  operatorv1.TigeraSecureEnterprise,
+			ComponentNetworkPolicies: &enabled,
+		}
+		packetCapture = render.PacketCaptureAPI(&render.PacketCaptureApiConfiguration{
+			Installation: installation,
+			ServerCertSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.PacketCaptureCertSecret, Namespace: common.OperatorNamespace()},
+			},
+		})
+	})
+
+	It("should render the policies of the components in the allow-tigera tier", func() {
+		toCreate, toDelete := render.NetworkPolicies(installation, tier, packetCapture, render.NewPassthrough()).Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(1))
+
+		policy := toCreate[0].(*v3.NetworkPolicy)
+		Expect(policy.Name).To(Equal("allow-tigera.tigera-packetcapture"))
+		Expect(policy.Namespace).To(Equal(render.PacketCaptureNamespace))
+		Expect(policy.Spec.Tier).To(Equal(networkpolicy.TigeraComponentTierName))
+		Expect(policy.Spec.Selector).To(Equal("k8s-app == 'tigera-packetcapture'"))
+		Expect(policy.Spec.Ingress).To(HaveLen(1))
+		Expect(policy.Spec.Ingress[0].Source.NamespaceSelector).To(Equal("projectcalico.org/name == 'tigera-manager'"))
+		Expect(policy.Spec.Ingress[0].Destination.Ports).To(ConsistOf(numorstring.SinglePort(render.PacketCapturePort)))
+	})
+
+	It("should delete the policies that are present when they are disabled", func() {
+		disabled := operatorv1.ComponentNetworkPoliciesDisabled
+		installation.ComponentNetworkPolicies = &disabled
+		tier.Policies = map[types.NamespacedName]bool{
+			{Namespace: render.PacketCaptureNamespace, Name: "allow-tigera." + render.PacketCaptureName}: true,
+		}
+
+		toCreate, toDelete := render.NetworkPolicies(installation, tier, packetCapture).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(1))
+
+		By("not deleting them again once they are gone")
+		tier.Policies = map[types.NamespacedName]bool{}
+		toCreate, toDelete = render.NetworkPolicies(installation, tier, packetCapture).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(BeEmpty())
+	})
+
+	It("should render nothing until the tier exists", func() {
+		toCreate, toDelete := render.NetworkPolicies(installation, networkpolicy.TierState{}, packetCapture).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(BeEmpty())
+	})
+
+	It("should only be enabled for Calico Enterprise", func() {
+		Expect(networkpolicy.Enabled(installation)).To(BeTrue())
+		installation.Variant = operatorv1.Calico
+		Expect(networkpolicy.Enabled(installation)).To(BeFalse())
+		installation.Variant = operatorv1.TigeraSecureEnterprise
+		installation.ComponentNetworkPolicies = nil
+		Expect(networkpolicy.Enabled(installation)).To(BeFalse())
+	})
+
+	It("should allow DNS through the cluster DNS of the provider", func() {
+		rules := networkpolicy.DNSEgressRules(operatorv1.ProviderNone)
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0].Destination.NamespaceSelector).To(Equal("projectcalico.org/name == 'kube-system'"))
+		Expect(rules[0].Destination.Ports).To(ConsistOf(numorstring.SinglePort(53)))
+
+		rules = networkpolicy.DNSEgressRules(operatorv1.ProviderOpenShift)
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0].Destination.NamespaceSelector).To(Equal("projectcalico.org/name == 'openshift-dns'"))
+		Expect(rules[0].Destination.Ports).To(ConsistOf(numorstring.SinglePort(5353)))
+	})
+
+	It("should allow the configured API server endpoint", func() {
+		rules := networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{})
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].Destination.Services).To(Equal(&v3.ServiceMatch{Namespace: "default", Name: "kubernetes"}))
+
+		rules = networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{Host: "10.0.0.1", Port: "6443"})
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[1].Destination.Nets).To(ConsistOf("10.0.0.1/32"))
+		Expect(rules[1].Destination.Ports).To(ConsistOf(numorstring.SinglePort(6443)))
+
+		rules = networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{Host: "api.example.com", Port: "443"})
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[1].Destination.Domains).To(ConsistOf("api.example.com"))
+	})
+
+	It("should render the typha policy in the calico-system namespace", func() {
+		metricsPort := int32(9093)
+		installation.TyphaMetricsPort = &metricsPort
+		typha := render.Typha(&render.TyphaConfiguration{
+			Installation: installation,
+			K8sServiceEp: k8sapi.ServiceEndpoint{Host: "10.0.0.1", Port: "6443"},
+		})
+
+		toCreate, _ := render.NetworkPolicies(installation, tier, typha).Objects()
+		Expect(toCreate).To(HaveLen(1))
+		policy := toCreate[0].(*v3.NetworkPolicy)
+		Expect(policy.Name).To(Equal("allow-tigera.calico-typha"))
+		Expect(policy.Namespace).To(Equal(common.CalicoNamespace))
+		Expect(policy.Spec.Selector).To(Equal("k8s-app == 'calico-typha'"))
+		Expect(policy.Spec.Ingress).To(HaveLen(1))
+		Expect(policy.Spec.Ingress[0].Destination.Ports).To(ConsistOf(
+			numorstring.SinglePort(uint16(render.TyphaPort)), numorstring.SinglePort(9093)))
+		Expect(policy.Spec.Egress[len(policy.Spec.Egress)-1].Destination.Nets).To(ConsistOf("10.0.0.1/32"))
+	})
+
+	It("should only open the egress of fluentd when additional stores are configured", func() {
+		logCollector := &operatorv1.LogCollector{}
+		fluentd := render.Fluentd(&render.FluentdConfiguration{
+			LogCollector: logCollector,
+			Installation: installation,
+			OSType:       rmeta.OSTypeLinux,
+		})
+
+		toCreate, _ := render.NetworkPolicies(installation, tier, fluentd).Objects()
+		Expect(toCreate).To(HaveLen(1))
+		policy := toCreate[0].(*v3.NetworkPolicy)
+		Expect(policy.Name).To(Equal("allow-tigera.fluentd-node"))
+		Expect(policy.Namespace).To(Equal(render.LogCollectorNamespace))
+		Expect(policy.Spec.Egress).NotTo(ContainElement(v3.Rule{Action: v3.Allow}))
+
+		logCollector.Spec.AdditionalStores = &operatorv1.AdditionalLogStoreSpec{
+			Syslog: &operatorv1.SyslogStoreSpec{Endpoint: "udp://syslog.example.com:514"},
+		}
+		toCreate, _ = render.NetworkPolicies(installation, tier, fluentd).Objects()
+		Expect(toCreate[0].(*v3.NetworkPolicy).Spec.Egress).To(ContainElement(v3.Rule{Action: v3.Allow}))
+	})
+
+	It("should render the policies of guardian and dex", func() {
+		guardian := render.Guardian(&render.GuardianConfiguration{Installation: installation})
+		authentication := &operatorv1.Authentication{Spec: operatorv1.AuthenticationSpec{
+			ManagerDomain: "https://example.com",
+			OIDC:          &operatorv1.AuthenticationOIDC{IssuerURL: "https://example.com", UsernameClaim: "email"},
+		}}
+		dex := render.Dex(&render.DexComponentConfiguration{
+			Installation: installation,
+			DexConfig:    render.NewDexConfig(nil, authentication, nil, nil, nil, "cluster.local"),
+		})
+
+		toCreate, _ := render.NetworkPolicies(installation, tier, guardian, dex).Objects()
+		Expect(toCreate).To(HaveLen(2))
+		Expect(toCreate[0].GetName()).To(Equal("allow-tigera.tigera-guardian"))
+		Expect(toCreate[0].GetNamespace()).To(Equal(render.GuardianNamespace))
+		Expect(toCreate[1].GetName()).To(Equal("allow-tigera.tigera-dex"))
+		Expect(toCreate[1].(*v3.NetworkPolicy).Spec.Ingress[0].Destination.Ports).To(ConsistOf(numorstring.SinglePort(render.DexPort)))
+	})
+})
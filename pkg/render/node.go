@@ -63,6 +63,12 @@ const (
 	NodePrometheusTLSServerAnnotation = "hash.operator.tigera.io/calico-node-prometheus-server-tls"
 	PrometheusCABundle                = "tigera-prometheus-metrics-ca-bundle"
 	PrometheusCABundleAnnotation      = "hash.operator.tigera.io/tigera-prometheus-metrics-ca-bundle"
+
+	// CalicoNodePoolLabel is set on the calico-node DaemonSets of the CalicoNodePools, on their pods and on the nodes
+	// of the pools to the name of the pool.
+	CalicoNodePoolLabel = "operator.tigera.io/calico-node-pool"
+
+	nodeCNIConfigMapName = "cni-config"
 )
 
 var (
@@ -104,11 +110,23 @@ type NodeConfiguration struct {
 	PrometheusServerTLS       *corev1.Secret
 	PrometheusMetricsCABundle *corev1.ConfigMap
 
+	// UsePSP is true if the cluster serves the PodSecurityPolicy API and PSPs should be rendered.
+	UsePSP bool
+
 	// BGPLayouts is returned by the rendering code after modifying its namespace
 	// so that it can be deployed into the cluster.
 	// TODO: The controller should pass the contents, the renderer should build its own
 	// configmap, rather than this "copy" semantic.
 	BGPLayouts *corev1.ConfigMap
+
+	// StaleNodePools are the names of the CalicoNodePools that are no longer in the Installation, whose
+	// calico-node DaemonSets are deleted.
+	StaleNodePools []string
+}
+
+// NodePoolDaemonSetName returns the name of the calico-node DaemonSet of a CalicoNodePool.
+func NodePoolDaemonSetName(pool string) string {
+	return fmt.Sprintf("%s-%s", common.NodeDaemonSetName, pool)
 }
 
 // Node creates the node daemonset and other resources for the daemonset to operate normally.
@@ -120,6 +138,10 @@ type nodeComponent struct {
 	// Input configuration from the controller.
 	cfg *NodeConfiguration
 
+	// pool is set when rendering the calico-node DaemonSet of a CalicoNodePool. The overrides of the pool
+	// are already applied to cfg.Installation.
+	pool *operatorv1.CalicoNodePool
+
 	// Calculated internal fields based on the given information.
 	cniImage         string
 	flexvolImage     string
@@ -204,6 +226,9 @@ func (c *nodeComponent) Objects() ([]client.Object, []client.Object) {
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		// Include Service for exposing node metrics.
 		objs = append(objs, c.nodeMetricsService())
+		for _, pool := range c.cfg.Installation.CalicoNodePools {
+			objs = append(objs, c.poolComponent(pool).nodeMetricsService())
+		}
 	}
 
 	cniConfig := c.nodeCNIConfigMap()
@@ -219,18 +244,44 @@ func (c *nodeComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, c.clusterAdminClusterRoleBinding())
 	}
 
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift && c.cfg.UsePSP {
 		objs = append(objs, c.nodePodSecurityPolicy())
 	}
 
 	objs = append(objs, c.nodeDaemonset(cniConfig))
 
+	// Each CalicoNodePool gets its own DaemonSet and, since its MTU may differ, its own CNI configuration.
+	for _, pool := range c.cfg.Installation.CalicoNodePools {
+		pc := c.poolComponent(pool)
+		poolCNIConfig := pc.nodeCNIConfigMap()
+		if poolCNIConfig != nil {
+			objs = append(objs, poolCNIConfig)
+		}
+		objs = append(objs, pc.nodeDaemonset(poolCNIConfig))
+	}
+
 	if c.cfg.Installation.CertificateManagement != nil {
 		objs = append(objs, csrClusterRole())
 		objs = append(objs, CSRClusterRoleBinding("calico-node", common.CalicoNamespace))
 	}
 
 	var objsToDelete []client.Object
+	for _, pool := range c.cfg.StaleNodePools {
+		objsToDelete = append(objsToDelete,
+			&appsv1.DaemonSet{
+				TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: NodePoolDaemonSetName(pool), Namespace: common.CalicoNamespace},
+			},
+			&corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: nodePoolCNIConfigMapName(pool), Namespace: common.CalicoNamespace},
+			},
+			&corev1.Service{
+				TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: nodePoolMetricsServiceName(pool), Namespace: common.CalicoNamespace},
+			},
+		)
+	}
 	if c.cfg.PrometheusServerTLS != nil {
 		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(common.CalicoNamespace, c.cfg.PrometheusServerTLS)...)...)
 	} else {
@@ -251,6 +302,72 @@ func (c *nodeComponent) Ready() bool {
 	return true
 }
 
+// poolComponent returns a copy of the component that renders the calico-node DaemonSet of a CalicoNodePool.
+func (c *nodeComponent) poolComponent(pool operatorv1.CalicoNodePool) *nodeComponent {
+	install := c.cfg.Installation.DeepCopy()
+	if install.CalicoNetwork != nil {
+		if pool.MTU != nil {
+			install.CalicoNetwork.MTU = pool.MTU
+		}
+		if pool.NodeAddressAutodetectionV4 != nil {
+			install.CalicoNetwork.NodeAddressAutodetectionV4 = pool.NodeAddressAutodetectionV4
+		}
+		if pool.NodeAddressAutodetectionV6 != nil {
+			install.CalicoNetwork.NodeAddressAutodetectionV6 = pool.NodeAddressAutodetectionV6
+		}
+	}
+	if pool.Resources != nil {
+		resources := []operatorv1.ComponentResource{{ComponentName: operatorv1.ComponentNameNode, ResourceRequirements: pool.Resources}}
+		for _, cr := range install.ComponentResources {
+			if cr.ComponentName != operatorv1.ComponentNameNode {
+				resources = append(resources, cr)
+			}
+		}
+		install.ComponentResources = resources
+	}
+
+	cfg := *c.cfg
+	cfg.Installation = install
+	pc := *c
+	pc.cfg = &cfg
+	pc.pool = &pool
+	return &pc
+}
+
+// daemonSetName returns the name of the calico-node DaemonSet rendered by the component.
+func (c *nodeComponent) daemonSetName() string {
+	if c.pool != nil {
+		return NodePoolDaemonSetName(c.pool.Name)
+	}
+	return common.NodeDaemonSetName
+}
+
+// cniConfigMapName returns the name of the CNI configuration ConfigMap used by the calico-node DaemonSet
+// rendered by the component.
+func (c *nodeComponent) cniConfigMapName() string {
+	if c.pool != nil {
+		return nodePoolCNIConfigMapName(c.pool.Name)
+	}
+	return nodeCNIConfigMapName
+}
+
+func nodePoolCNIConfigMapName(pool string) string {
+	return fmt.Sprintf("%s-%s", nodeCNIConfigMapName, pool)
+}
+
+// metricsServiceName returns the name of the Service exposing the metrics of the calico-node DaemonSet rendered by
+// the component.
+func (c *nodeComponent) metricsServiceName() string {
+	if c.pool != nil {
+		return nodePoolMetricsServiceName(c.pool.Name)
+	}
+	return CalicoNodeMetricsService
+}
+
+func nodePoolMetricsServiceName(pool string) string {
+	return fmt.Sprintf("%s-%s", CalicoNodeMetricsService, pool)
+}
+
 // nodeServiceAccount creates the node's service account.
 func (c *nodeComponent) nodeServiceAccount() *corev1.ServiceAccount {
 	finalizer := []string{}
@@ -580,7 +697,7 @@ func (c *nodeComponent) nodeCNIConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "cni-config",
+			Name:      c.cniConfigMapName(),
 			Namespace: common.CalicoNamespace,
 			Labels:    map[string]string{},
 		},
@@ -592,21 +709,30 @@ func (c *nodeComponent) nodeCNIConfigMap() *corev1.ConfigMap {
 
 func (c *nodeComponent) getCalicoIPAM() string {
 	// Determine what address families to enable.
-	var assign_ipv4 string
-	var assign_ipv6 string
-	if v4pool := GetIPv4Pool(c.cfg.Installation.CalicoNetwork.IPPools); v4pool != nil {
-		assign_ipv4 = "true"
-	} else {
-		assign_ipv4 = "false"
+	v4pools := GetIPv4Pools(c.cfg.Installation.CalicoNetwork.IPPools)
+	v6pools := GetIPv6Pools(c.cfg.Installation.CalicoNetwork.IPPools)
+	ipam := fmt.Sprintf(`{ "type": "calico-ipam", "assign_ipv4" : "%t", "assign_ipv6" : "%t"`, len(v4pools) > 0, len(v6pools) > 0)
+
+	// Calico IPAM assigns addresses from any pool by default, so when there are Manual pools the Automatic ones
+	// are listed explicitly. Pools requested through annotations take precedence over these.
+	if pools := automaticIPPoolNames(v4pools); len(pools) < len(v4pools) {
+		ipam += fmt.Sprintf(`, "ipv4_pools": ["%s"]`, strings.Join(pools, `", "`))
 	}
-	if v6pool := GetIPv6Pool(c.cfg.Installation.CalicoNetwork.IPPools); v6pool != nil {
-		assign_ipv6 = "true"
-	} else {
-		assign_ipv6 = "false"
+	if pools := automaticIPPoolNames(v6pools); len(pools) < len(v6pools) {
+		ipam += fmt.Sprintf(`, "ipv6_pools": ["%s"]`, strings.Join(pools, `", "`))
+	}
+	return ipam + "}"
+}
+
+// automaticIPPoolNames returns the names of the pools that addresses are assigned from automatically.
+func automaticIPPoolNames(pools []*operatorv1.IPPool) []string {
+	var names []string
+	for _, p := range pools {
+		if p.AssignmentMode == nil || *p.AssignmentMode == operatorv1.IPPoolAssignmentAutomatic {
+			names = append(names, p.Name)
+		}
 	}
-	return fmt.Sprintf(`{ "type": "calico-ipam", "assign_ipv4" : "%s", "assign_ipv6" : "%s"}`,
-		assign_ipv4, assign_ipv6,
-	)
+	return names
 }
 
 func buildHostLocalIPAM(cns *operatorv1.CalicoNetworkSpec) string {
@@ -744,56 +870,77 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 		initContainers = append(initContainers, c.hostPathInitContainer())
 	}
 
-	var affinity *corev1.Affinity
+	var nodeRequirements []corev1.NodeSelectorRequirement
 	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderAKS {
-		affinity = &corev1.Affinity{
-			NodeAffinity: &corev1.NodeAffinity{
-				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
-						MatchExpressions: []corev1.NodeSelectorRequirement{{
-							Key:      "type",
-							Operator: corev1.NodeSelectorOpNotIn,
-							Values:   []string{"virtual-kubelet"},
-						}},
-					}},
-				},
-			},
-		}
+		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
+			Key:      "type",
+			Operator: corev1.NodeSelectorOpNotIn,
+			Values:   []string{"virtual-kubelet"},
+		})
 	} else if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderEKS {
+		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
+			Key:      "eks.amazonaws.com/compute-type",
+			Operator: corev1.NodeSelectorOpNotIn,
+			Values:   []string{"fargate"},
+		})
+	}
+
+	// The operator labels the nodes of each CalicoNodePool with CalicoNodePoolLabel. The calico-node DaemonSet of a
+	// pool runs on the nodes with the label of the pool, and the calico-node DaemonSet on the nodes without the label.
+	// The calico-node DaemonSet excludes the labeled nodes even when there are no pools, so that adding the first
+	// pool or removing the last one does not restart calico-node on every node.
+	var nodeSelector map[string]string
+	if c.pool != nil {
+		nodeSelector = map[string]string{CalicoNodePoolLabel: c.pool.Name}
+	} else {
+		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
+			Key:      CalicoNodePoolLabel,
+			Operator: corev1.NodeSelectorOpDoesNotExist,
+		})
+	}
+
+	var affinity *corev1.Affinity
+	if len(nodeRequirements) > 0 {
 		affinity = &corev1.Affinity{
 			NodeAffinity: &corev1.NodeAffinity{
 				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
-						MatchExpressions: []corev1.NodeSelectorRequirement{{
-							Key:      "eks.amazonaws.com/compute-type",
-							Operator: corev1.NodeSelectorOpNotIn,
-							Values:   []string{"fargate"},
-						}},
-					}},
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: nodeRequirements}},
 				},
 			},
 		}
 	}
 
+	// The pods of each DaemonSet have their own k8s-app label, so that the selectors of the DaemonSets don't overlap.
+	tolerations := rmeta.TolerateAll
+	podLabels := map[string]string{"k8s-app": c.daemonSetName()}
+	var labels map[string]string
+	if c.pool != nil {
+		if len(c.pool.Tolerations) > 0 {
+			tolerations = c.pool.Tolerations
+		}
+		podLabels[CalicoNodePoolLabel] = c.pool.Name
+		labels = map[string]string{CalicoNodePoolLabel: c.pool.Name}
+	}
+
 	// Determine the name to use for the calico/node daemonset. For mixed-mode, we run the enterprise DaemonSet
 	// with its own name so as to not conflict.
 	ds := appsv1.DaemonSet{
 		TypeMeta: metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      common.NodeDaemonSetName,
+			Name:      c.daemonSetName(),
 			Namespace: common.CalicoNamespace,
+			Labels:    labels,
 		},
 		Spec: appsv1.DaemonSetSpec{
-			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "calico-node"}},
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"k8s-app": "calico-node",
-					},
+					Labels:      podLabels,
 					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
-					Tolerations:                   rmeta.TolerateAll,
+					NodeSelector:                  nodeSelector,
+					Tolerations:                   tolerations,
 					Affinity:                      affinity,
 					ImagePullSecrets:              c.cfg.Installation.ImagePullSecrets,
 					ServiceAccountName:            "calico-node",
@@ -823,10 +970,10 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 	return &ds
 }
 
-// cniDirectories returns the binary and network config directories for the configured platform.
-func (c *nodeComponent) cniDirectories() (string, string, string) {
+// cniDirectories returns the binary and network config directories for the platform.
+func cniDirectories(provider operatorv1.Provider) (string, string, string) {
 	var cniBinDir, cniNetDir, cniLogDir string
-	switch c.cfg.Installation.KubernetesProvider {
+	switch provider {
 	case operatorv1.ProviderOpenShift:
 		cniNetDir = "/var/run/multus/cni/net.d"
 		cniBinDir = "/var/lib/cni/bin"
@@ -901,7 +1048,7 @@ func (c *nodeComponent) nodeVolumes() []corev1.Volume {
 	// If needed for this configuration, then include the CNI volumes.
 	if c.cfg.Installation.CNI.Type == operatorv1.PluginCalico {
 		// Determine directories to use for CNI artifacts based on the provider.
-		cniNetDir, cniBinDir, cniLogDir := c.cniDirectories()
+		cniNetDir, cniBinDir, cniLogDir := cniDirectories(c.cfg.Installation.KubernetesProvider)
 		volumes = append(volumes, corev1.Volume{Name: "cni-bin-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cniBinDir}}})
 		volumes = append(volumes, corev1.Volume{Name: "cni-net-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cniNetDir}}})
 		volumes = append(volumes, corev1.Volume{Name: "cni-log-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cniLogDir}}})
@@ -1060,7 +1207,7 @@ func (c *nodeComponent) cniEnvvars() []corev1.EnvVar {
 	}
 
 	// Determine directories to use for CNI artifacts based on the provider.
-	cniNetDir, _, _ := c.cniDirectories()
+	cniNetDir, _, _ := cniDirectories(c.cfg.Installation.KubernetesProvider)
 
 	envVars := []corev1.EnvVar{
 		{Name: "CNI_CONF_NAME", Value: "10-calico.conflist"},
@@ -1072,7 +1219,7 @@ func (c *nodeComponent) cniEnvvars() []corev1.EnvVar {
 				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
 					Key: "config",
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "cni-config",
+						Name: c.cniConfigMapName(),
 					},
 				},
 			},
@@ -1397,6 +1544,9 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 		wireguardMtu := strconv.Itoa(int(*mtu))
 		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_VXLANMTU", Value: vxlanMtu})
 		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDMTU", Value: wireguardMtu})
+		if wireGuardEnabled(c.cfg.Installation, 6) {
+			nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDMTUV6", Value: wireguardMtu})
+		}
 	}
 
 	// If host-local IPAM is in use, we need to configure calico/node to use the Kubernetes pod CIDR.
@@ -1513,6 +1663,25 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 		nodeEnv = append(nodeEnv, extraNodeEnv...)
 	}
 
+	if felix := c.cfg.Installation.Felix; felix != nil && len(felix.HealthTimeoutOverrides) > 0 {
+		// The FelixConfiguration CRDs bundled with the operator do not have the health timeout overrides, so they
+		// are set through the environment instead. The Installation validation rejects them for the releases whose
+		// Felix does not read them.
+		var overrides []string
+		for _, o := range felix.HealthTimeoutOverrides {
+			overrides = append(overrides, fmt.Sprintf("%s=%s", o.Name, o.Timeout.Duration))
+		}
+		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_HEALTHTIMEOUTOVERRIDES", Value: strings.Join(overrides, ",")})
+	}
+
+	if c.cfg.Installation.CalicoNetwork != nil && c.cfg.Installation.CalicoNetwork.WireGuard != nil &&
+		c.cfg.Installation.CalicoNetwork.WireGuard.IPv6 != nil {
+		// Not every FelixConfiguration CRD bundled with the operator has the IPv6 WireGuard setting, so it is set
+		// through the environment instead. The Installation validation rejects enabling it for the releases whose
+		// Felix does not read it.
+		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDENABLEDV6", Value: strconv.FormatBool(wireGuardEnabled(c.cfg.Installation, 6))})
+	}
+
 	// Configure provider specific environment variables here.
 	switch c.cfg.Installation.KubernetesProvider {
 	case operatorv1.ProviderOpenShift:
@@ -1549,14 +1718,20 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 
 	if c.cfg.AmazonCloudIntegration != nil {
 		nodeEnv = append(nodeEnv, GetTigeraSecurityGroupEnvVariables(c.cfg.AmazonCloudIntegration)...)
-		nodeEnv = append(nodeEnv, corev1.EnvVar{
-			Name:  "FELIX_FAILSAFEINBOUNDHOSTPORTS",
-			Value: "tcp:22,udp:68,tcp:179,tcp:443,tcp:5473,tcp:6443",
-		})
-		nodeEnv = append(nodeEnv, corev1.EnvVar{
-			Name:  "FELIX_FAILSAFEOUTBOUNDHOSTPORTS",
-			Value: "udp:53,udp:67,tcp:179,tcp:443,tcp:5473,tcp:6443",
-		})
+		// The failsafe ports of spec.felix are set on the FelixConfiguration, so don't override them.
+		felix := c.cfg.Installation.Felix
+		if felix == nil || felix.FailsafeInboundHostPorts == nil {
+			nodeEnv = append(nodeEnv, corev1.EnvVar{
+				Name:  "FELIX_FAILSAFEINBOUNDHOSTPORTS",
+				Value: "tcp:22,udp:68,tcp:179,tcp:443,tcp:5473,tcp:6443",
+			})
+		}
+		if felix == nil || felix.FailsafeOutboundHostPorts == nil {
+			nodeEnv = append(nodeEnv, corev1.EnvVar{
+				Name:  "FELIX_FAILSAFEOUTBOUNDHOSTPORTS",
+				Value: "udp:53,udp:67,tcp:179,tcp:443,tcp:5473,tcp:6443",
+			})
+		}
 	}
 
 	nodeEnv = append(nodeEnv, c.cfg.K8sServiceEp.EnvVars(true, c.cfg.Installation.KubernetesProvider)...)
@@ -1624,17 +1799,18 @@ func (c *nodeComponent) nodeLivenessReadinessProbes() (*corev1.Probe, *corev1.Pr
 // nodeMetricsService creates a Service which exposes two endpoints on calico/node for
 // reporting Prometheus metrics (for policy enforcement activity and BGP stats).
 // This service is used internally by Calico Enterprise and is separate from general
-// Prometheus metrics which are user-configurable.
+// Prometheus metrics which are user-configurable. The Services of the CalicoNodePools keep the
+// calico-node k8s-app label, so that the calico-node ServiceMonitor selects them too.
 func (c *nodeComponent) nodeMetricsService() *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      CalicoNodeMetricsService,
+			Name:      c.metricsServiceName(),
 			Namespace: common.CalicoNamespace,
 			Labels:    map[string]string{"k8s-app": "calico-node"},
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{"k8s-app": "calico-node"},
+			Selector: map[string]string{"k8s-app": c.daemonSetName()},
 			Type:     corev1.ServiceTypeClusterIP,
 			Ports: []corev1.ServicePort{
 				{
@@ -1770,6 +1946,30 @@ func GetIPv6Pool(pools []operatorv1.IPPool) *operatorv1.IPPool {
 	return nil
 }
 
+// GetIPv4Pools returns the IPv4 IPPools in an installation, in the order they are specified.
+func GetIPv4Pools(pools []operatorv1.IPPool) []*operatorv1.IPPool {
+	var v4pools []*operatorv1.IPPool
+	for ii, pool := range pools {
+		addr, _, err := net.ParseCIDR(pool.CIDR)
+		if err == nil && addr.To4() != nil {
+			v4pools = append(v4pools, &pools[ii])
+		}
+	}
+	return v4pools
+}
+
+// GetIPv6Pools returns the IPv6 IPPools in an installation, in the order they are specified.
+func GetIPv6Pools(pools []operatorv1.IPPool) []*operatorv1.IPPool {
+	var v6pools []*operatorv1.IPPool
+	for ii, pool := range pools {
+		addr, _, err := net.ParseCIDR(pool.CIDR)
+		if err == nil && addr.To4() == nil {
+			v6pools = append(v6pools, &pools[ii])
+		}
+	}
+	return v6pools
+}
+
 // bgpEnabled returns true if the given Installation enables BGP, false otherwise.
 func bgpEnabled(instance *operatorv1.InstallationSpec) bool {
 	return instance.CalicoNetwork != nil &&
@@ -1777,6 +1977,18 @@ func bgpEnabled(instance *operatorv1.InstallationSpec) bool {
 		*instance.CalicoNetwork.BGP == operatorv1.BGPEnabled
 }
 
+// wireGuardEnabled returns true if WireGuard encryption is enabled in the Installation for the given IP version.
+func wireGuardEnabled(instance *operatorv1.InstallationSpec, version int) bool {
+	if instance.CalicoNetwork == nil || instance.CalicoNetwork.WireGuard == nil {
+		return false
+	}
+	state := instance.CalicoNetwork.WireGuard.IPv4
+	if version == 6 {
+		state = instance.CalicoNetwork.WireGuard.IPv6
+	}
+	return state != nil && *state == operatorv1.WireGuardEnabled
+}
+
 // getMTU returns the MTU configured in the Installation if there is one, nil otherwise.
 func getMTU(instance *operatorv1.InstallationSpec) *int32 {
 	var mtu *int32
@@ -15,8 +15,10 @@
 package render_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -28,6 +30,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
@@ -104,6 +108,7 @@ var _ = Describe("Node rendering tests", func() {
 			Installation:  defaultInstance,
 			TLS:           typhaNodeTLS,
 			ClusterDomain: defaultClusterDomain,
+			UsePSP:        true,
 		}
 	})
 
@@ -643,6 +648,89 @@ var _ = Describe("Node rendering tests", func() {
 		for _, e := range expectedNodeEnv {
 			Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(e))
 		}
+		Expect(ds.Spec.Template.Spec.Containers[0].Env).NotTo(ContainElement(corev1.EnvVar{Name: "FELIX_WIREGUARDMTUV6", Value: "1450"}))
+	})
+
+	Describe("BPF dataplane with IPv6", func() {
+		var ff = true
+
+		BeforeEach(func() {
+			dpBPF := operatorv1.LinuxDataplaneBPF
+			defaultInstance.CalicoNetwork.LinuxDataplane = &dpBPF
+			defaultInstance.CalicoNetwork.NodeAddressAutodetectionV6 = &operatorv1.NodeAddressAutodetection{FirstFound: &ff}
+		})
+
+		renderNode := func() (*appsv1.DaemonSet, *corev1.ConfigMap) {
+			component := render.Node(&cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			resources, _ := component.Objects()
+			ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+			cm := rtest.GetResource(resources, "cni-config", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+			return ds, cm
+		}
+
+		It("should render a dual-stack node", func() {
+			defaultInstance.CalicoNetwork.IPPools = append(defaultInstance.CalicoNetwork.IPPools, operatorv1.IPPool{CIDR: "fd00::/64"})
+			ds, cm := renderNode()
+
+			env := ds.Spec.Template.Spec.Containers[0].Env
+			rtest.ExpectEnv(env, "FELIX_BPFENABLED", "true")
+			rtest.ExpectEnv(env, "FELIX_IPV6SUPPORT", "true")
+			rtest.ExpectEnv(env, "IP", "autodetect")
+			rtest.ExpectEnv(env, "IP6", "autodetect")
+			Expect(env).NotTo(ContainElement(corev1.EnvVar{Name: "CALICO_ROUTER_ID", Value: "hash"}))
+			Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "mount-bpffs")).NotTo(BeNil())
+			Expect(cm.Data["config"]).To(ContainSubstring(`"assign_ipv4" : "true"`))
+			Expect(cm.Data["config"]).To(ContainSubstring(`"assign_ipv6" : "true"`))
+		})
+
+		It("should render an IPv6-only node", func() {
+			defaultInstance.CalicoNetwork.IPPools = []operatorv1.IPPool{{CIDR: "fd00::/64"}}
+			defaultInstance.CalicoNetwork.NodeAddressAutodetectionV4 = nil
+			ds, cm := renderNode()
+
+			env := ds.Spec.Template.Spec.Containers[0].Env
+			rtest.ExpectEnv(env, "FELIX_BPFENABLED", "true")
+			rtest.ExpectEnv(env, "FELIX_IPV6SUPPORT", "true")
+			rtest.ExpectEnv(env, "IP", "none")
+			rtest.ExpectEnv(env, "IP6", "autodetect")
+			rtest.ExpectEnv(env, "CALICO_ROUTER_ID", "hash")
+			Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "mount-bpffs")).NotTo(BeNil())
+			Expect(cm.Data["config"]).To(ContainSubstring(`"assign_ipv4" : "false"`))
+			Expect(cm.Data["config"]).To(ContainSubstring(`"assign_ipv6" : "true"`))
+		})
+	})
+
+	It("should enable IPv6 WireGuard encryption through the environment", func() {
+		enabled := operatorv1.WireGuardEnabled
+		defaultInstance.CalicoNetwork.WireGuard = &operatorv1.WireGuardSpec{IPv6: &enabled}
+
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		rtest.ExpectEnv(ds.Spec.Template.Spec.Containers[0].Env, "FELIX_WIREGUARDENABLEDV6", "true")
+
+		disabled := operatorv1.WireGuardDisabled
+		defaultInstance.CalicoNetwork.WireGuard.IPv6 = &disabled
+		resources, _ = render.Node(&cfg).Objects()
+		ds = rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		rtest.ExpectEnv(ds.Spec.Template.Spec.Containers[0].Env, "FELIX_WIREGUARDENABLEDV6", "false")
+	})
+
+	It("should set the IPv6 WireGuard MTU when IPv6 WireGuard encryption is enabled", func() {
+		mtu := int32(1400)
+		enabled := operatorv1.WireGuardEnabled
+		defaultInstance.CalicoNetwork.MTU = &mtu
+		defaultInstance.CalicoNetwork.WireGuard = &operatorv1.WireGuardSpec{IPv6: &enabled}
+
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		rtest.ExpectEnv(ds.Spec.Template.Spec.Containers[0].Env, "FELIX_WIREGUARDMTUV6", "1400")
 	})
 
 	It("should render all resources for a default configuration using TigeraSecureEnterprise", func() {
@@ -2046,11 +2134,14 @@ var _ = Describe("Node rendering tests", func() {
 			ds := dsResource.(*appsv1.DaemonSet)
 			Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ContainElement(
 				corev1.NodeSelectorTerm{
-					MatchExpressions: []corev1.NodeSelectorRequirement{{
-						Key:      "type",
-						Operator: corev1.NodeSelectorOpNotIn,
-						Values:   []string{"virtual-kubelet"},
-					}},
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "type",
+							Operator: corev1.NodeSelectorOpNotIn,
+							Values:   []string{"virtual-kubelet"},
+						},
+						{Key: render.CalicoNodePoolLabel, Operator: corev1.NodeSelectorOpDoesNotExist},
+					},
 				},
 			))
 		})
@@ -2068,11 +2159,14 @@ var _ = Describe("Node rendering tests", func() {
 			ds := dsResource.(*appsv1.DaemonSet)
 			Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ContainElement(
 				corev1.NodeSelectorTerm{
-					MatchExpressions: []corev1.NodeSelectorRequirement{{
-						Key:      "eks.amazonaws.com/compute-type",
-						Operator: corev1.NodeSelectorOpNotIn,
-						Values:   []string{"fargate"},
-					}},
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "eks.amazonaws.com/compute-type",
+							Operator: corev1.NodeSelectorOpNotIn,
+							Values:   []string{"fargate"},
+						},
+						{Key: render.CalicoNodePoolLabel, Operator: corev1.NodeSelectorOpDoesNotExist},
+					},
 				},
 			))
 		})
@@ -2349,6 +2443,25 @@ var _ = Describe("Node rendering tests", func() {
 		Expect(ds.Spec.Template.Spec.Containers[0].Env).ToNot(ContainElement(expected))
 	})
 
+	It("should set FELIX_HEALTHTIMEOUTOVERRIDES from the Felix health timeout overrides", func() {
+		defaultInstance.Felix = &operatorv1.FelixSpec{
+			HealthTimeoutOverrides: []operatorv1.FelixHealthTimeoutOverride{
+				{Name: "InternalDataplaneMainLoop", Timeout: metav1.Duration{Duration: 2 * time.Minute}},
+				{Name: "CalculationGraph", Timeout: metav1.Duration{Duration: 90 * time.Second}},
+			},
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+		Expect(dsResource).ToNot(BeNil())
+		ds := dsResource.(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: "FELIX_HEALTHTIMEOUTOVERRIDES", Value: "InternalDataplaneMainLoop=2m0s,CalculationGraph=1m30s",
+		}))
+	})
+
 	It("should set FELIX_PROMETHEUSMETRICSPORT with a custom value if NodeMetricsPort is set", func() {
 		var nodeMetricsPort int32 = 1234
 		defaultInstance.Variant = operatorv1.TigeraSecureEnterprise
@@ -2559,6 +2672,34 @@ var _ = Describe("Node rendering tests", func() {
 }`))
 	})
 
+	It("should list the Automatic IP pools in the cni config when there are Manual IP pools", func() {
+		manual := operatorv1.IPPoolAssignmentManual
+		defaultInstance.CalicoNetwork.IPPools = []operatorv1.IPPool{
+			{Name: "zone-a", CIDR: "192.168.0.0/24"},
+			{Name: "zone-b", CIDR: "192.168.1.0/24"},
+			{Name: "reserved", CIDR: "192.168.2.0/24", AssignmentMode: &manual},
+			{Name: "default-ipv6-ippool", CIDR: "fd00::/64"},
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		cniCmResource := rtest.GetResource(resources, "cni-config", "calico-system", "", "v1", "ConfigMap")
+		Expect(cniCmResource).ToNot(BeNil())
+		var config struct {
+			Plugins []struct {
+				IPAM json.RawMessage `json:"ipam"`
+			} `json:"plugins"`
+		}
+		Expect(json.Unmarshal([]byte(cniCmResource.(*corev1.ConfigMap).Data["config"]), &config)).To(Succeed())
+		Expect(config.Plugins[0].IPAM).To(MatchJSON(`{
+  "type": "calico-ipam",
+  "assign_ipv4": "true",
+  "assign_ipv6": "true",
+  "ipv4_pools": ["zone-a", "zone-b"]
+}`))
+	})
+
 	It("should render cni config with host-local", func() {
 		defaultInstance.CNI.IPAM.Type = operatorv1.IPAMPluginHostLocal
 		component := render.Node(&cfg)
@@ -2786,6 +2927,29 @@ var _ = Describe("Node rendering tests", func() {
 		}
 	})
 
+	It("should not override the failsafe ports of spec.felix when AmazonCloudIntegration is defined", func() {
+		cfg.AmazonCloudIntegration = &operatorv1.AmazonCloudIntegration{
+			Spec: operatorv1.AmazonCloudIntegrationSpec{
+				NodeSecurityGroupIDs: []string{"sg-nodeid"},
+				PodSecurityGroupID:   "sg-podsgid",
+			},
+		}
+		defaultInstance.Felix = &operatorv1.FelixSpec{
+			FailsafeInboundHostPorts: &[]operatorv1.FelixProtoPort{{Protocol: "TCP", Port: 6443}},
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		var names []string
+		for _, e := range ds.Spec.Template.Spec.Containers[0].Env {
+			names = append(names, e.Name)
+		}
+		Expect(names).NotTo(ContainElement("FELIX_FAILSAFEINBOUNDHOSTPORTS"))
+		Expect(names).To(ContainElement("FELIX_FAILSAFEOUTBOUNDHOSTPORTS"))
+	})
+
 	It("should render resourcerequirements", func() {
 		rr := &corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
@@ -3116,6 +3280,15 @@ var _ = Describe("Node rendering tests", func() {
 		rtest.ExpectEnv(deploy.Spec.Template.Spec.InitContainers[0].Env, "SIGNER", "a.b/c")
 	})
 
+	It("should not render a PodSecurityPolicy when PodSecurityPolicies are not supported", func() {
+		cfg.UsePSP = false
+		component := render.Node(&cfg)
+		resources, _ := component.Objects()
+
+		Expect(rtest.GetResource(resources, common.NodeDaemonSetName, "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+		Expect(rtest.GetResource(resources, common.NodeDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet")).NotTo(BeNil())
+	})
+
 	It("should handle BGP layout", func() {
 		cfg.BGPLayouts = &corev1.ConfigMap{Data: map[string]string{"test": "data"}}
 		component := render.Node(&cfg)
@@ -3145,6 +3318,168 @@ var _ = Describe("Node rendering tests", func() {
 		}))
 		rtest.ExpectEnv(deploy.Spec.Template.Spec.Containers[0].Env, "CALICO_EARLY_NETWORKING", render.BGPLayoutPath)
 	})
+
+	Describe("with CalicoNodePools", func() {
+		var gpuResources corev1.ResourceRequirements
+		var edgeTolerations []corev1.Toleration
+
+		BeforeEach(func() {
+			mtu := int32(1450)
+			gpuMTU := int32(8950)
+			defaultInstance.CalicoNetwork.MTU = &mtu
+			gpuResources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			}
+			edgeTolerations = []corev1.Toleration{{Key: "edge", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}
+			defaultInstance.CalicoNodePools = []operatorv1.CalicoNodePool{
+				{
+					Name:                       "gpu",
+					NodeSelector:               map[string]string{"pool": "gpu"},
+					MTU:                        &gpuMTU,
+					NodeAddressAutodetectionV4: &operatorv1.NodeAddressAutodetection{Interface: "ib.*"},
+					Resources:                  &gpuResources,
+				},
+				{
+					Name:         "edge",
+					NodeSelector: map[string]string{"pool": "edge", "zone": "edge-1"},
+					Tolerations:  edgeTolerations,
+				},
+			}
+		})
+
+		getDaemonSet := func(resources []client.Object, name string) *appsv1.DaemonSet {
+			ds := rtest.GetResource(resources, name, common.CalicoNamespace, "apps", "v1", "DaemonSet")
+			Expect(ds).NotTo(BeNil())
+			return ds.(*appsv1.DaemonSet)
+		}
+
+		It("should render a calico-node DaemonSet for each pool", func() {
+			component := render.Node(&cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			resources, _ := component.Objects()
+
+			By("running calico-node on the nodes of no pool")
+			ds := getDaemonSet(resources, common.NodeDaemonSetName)
+			Expect(ds.Labels).NotTo(HaveKey(render.CalicoNodePoolLabel))
+			Expect(ds.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": "calico-node"}))
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(BeEmpty())
+			Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: render.CalicoNodePoolLabel, Operator: corev1.NodeSelectorOpDoesNotExist},
+				}},
+			}))
+			Expect(ds.Spec.Template.Spec.Tolerations).To(ConsistOf(rmeta.TolerateAll))
+			rtest.ExpectEnv(ds.Spec.Template.Spec.Containers[0].Env, "FELIX_VXLANMTU", "1450")
+			cniEnv := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "install-cni").Env
+			Expect(cniEnv).To(ContainElement(corev1.EnvVar{Name: "CNI_NETWORK_CONFIG", ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "config", LocalObjectReference: corev1.LocalObjectReference{Name: "cni-config"}},
+			}}))
+
+			By("overriding the configuration of the gpu pool")
+			gpu := getDaemonSet(resources, "calico-node-gpu")
+			Expect(gpu.Labels).To(Equal(map[string]string{render.CalicoNodePoolLabel: "gpu"}))
+			Expect(gpu.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": "calico-node-gpu", render.CalicoNodePoolLabel: "gpu"}))
+			Expect(gpu.Spec.Template.Labels).To(Equal(gpu.Spec.Selector.MatchLabels))
+			Expect(gpu.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{render.CalicoNodePoolLabel: "gpu"}))
+			Expect(gpu.Spec.Template.Spec.Affinity).To(BeNil())
+			Expect(gpu.Spec.Template.Spec.Tolerations).To(ConsistOf(rmeta.TolerateAll))
+			Expect(gpu.Spec.Template.Spec.Containers[0].Resources).To(Equal(gpuResources))
+			rtest.ExpectEnv(gpu.Spec.Template.Spec.Containers[0].Env, "FELIX_VXLANMTU", "8950")
+			rtest.ExpectEnv(gpu.Spec.Template.Spec.Containers[0].Env, "IP_AUTODETECTION_METHOD", "interface=ib.*")
+			cniEnv = rtest.GetContainer(gpu.Spec.Template.Spec.InitContainers, "install-cni").Env
+			Expect(cniEnv).To(ContainElement(corev1.EnvVar{Name: "CNI_NETWORK_CONFIG", ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "config", LocalObjectReference: corev1.LocalObjectReference{Name: "cni-config-gpu"}},
+			}}))
+
+			By("keeping the CNI configuration of the pools consistent")
+			cniConfig := rtest.GetResource(resources, "cni-config", common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+			gpuCNIConfig := rtest.GetResource(resources, "cni-config-gpu", common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+			Expect(cniConfig.Data["config"]).To(ContainSubstring(`"mtu": 1450,`))
+			Expect(gpuCNIConfig.Data["config"]).To(Equal(strings.Replace(cniConfig.Data["config"], `"mtu": 1450,`, `"mtu": 8950,`, 1)))
+			Expect(gpu.Spec.Template.Annotations["hash.operator.tigera.io/cni-config"]).NotTo(Equal(ds.Spec.Template.Annotations["hash.operator.tigera.io/cni-config"]))
+
+			By("overriding the tolerations of the edge pool")
+			edge := getDaemonSet(resources, "calico-node-edge")
+			Expect(edge.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{render.CalicoNodePoolLabel: "edge"}))
+			Expect(edge.Spec.Template.Spec.Tolerations).To(Equal(edgeTolerations))
+			Expect(edge.Spec.Template.Spec.Containers[0].Resources).To(Equal(ds.Spec.Template.Spec.Containers[0].Resources))
+			rtest.ExpectEnv(edge.Spec.Template.Spec.Containers[0].Env, "FELIX_VXLANMTU", "1450")
+			Expect(rtest.GetResource(resources, "cni-config-edge", common.CalicoNamespace, "", "v1", "ConfigMap")).NotTo(BeNil())
+		})
+
+		It("should exclude the nodes of the pools from the calico-node DaemonSet without pools", func() {
+			defaultInstance.CalicoNodePools = nil
+			component := render.Node(&cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			resources, _ := component.Objects()
+
+			ds := getDaemonSet(resources, common.NodeDaemonSetName)
+			Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: render.CalicoNodePoolLabel, Operator: corev1.NodeSelectorOpDoesNotExist},
+				}},
+			}))
+		})
+
+		It("should keep the provider node affinity", func() {
+			defaultInstance.KubernetesProvider = operatorv1.ProviderEKS
+			defaultInstance.CalicoNodePools = defaultInstance.CalicoNodePools[:1]
+			component := render.Node(&cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			resources, _ := component.Objects()
+
+			fargate := corev1.NodeSelectorRequirement{
+				Key:      "eks.amazonaws.com/compute-type",
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   []string{"fargate"},
+			}
+			ds := getDaemonSet(resources, common.NodeDaemonSetName)
+			Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					fargate,
+					{Key: render.CalicoNodePoolLabel, Operator: corev1.NodeSelectorOpDoesNotExist},
+				}},
+			}))
+			gpu := getDaemonSet(resources, "calico-node-gpu")
+			Expect(gpu.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{fargate}},
+			}))
+		})
+
+		It("should delete the DaemonSets of the stale pools", func() {
+			cfg.StaleNodePools = []string{"old"}
+			component := render.Node(&cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			_, toDelete := component.Objects()
+
+			Expect(rtest.GetResource(toDelete, "calico-node-old", common.CalicoNamespace, "apps", "v1", "DaemonSet")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "cni-config-old", common.CalicoNamespace, "", "v1", "ConfigMap")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "calico-node-metrics-old", common.CalicoNamespace, "", "v1", "Service")).NotTo(BeNil())
+		})
+
+		It("should render a metrics Service for each pool", func() {
+			defaultInstance.Variant = operatorv1.TigeraSecureEnterprise
+			component := render.Node(&cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			resources, _ := component.Objects()
+
+			svc := rtest.GetResource(resources, "calico-node-metrics-gpu", common.CalicoNamespace, "", "v1", "Service").(*corev1.Service)
+			Expect(svc.Labels).To(Equal(map[string]string{"k8s-app": "calico-node"}))
+			Expect(svc.Spec.Selector).To(Equal(map[string]string{"k8s-app": "calico-node-gpu"}))
+			svc = rtest.GetResource(resources, render.CalicoNodeMetricsService, common.CalicoNamespace, "", "v1", "Service").(*corev1.Service)
+			Expect(svc.Spec.Selector).To(Equal(map[string]string{"k8s-app": "calico-node"}))
+		})
+
+		It("should delete the DaemonSets of the pools when terminating", func() {
+			cfg.Terminating = true
+			component := render.Node(&cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			_, toDelete := component.Objects()
+
+			Expect(rtest.GetResource(toDelete, "calico-node-gpu", common.CalicoNamespace, "apps", "v1", "DaemonSet")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "calico-node-edge", common.CalicoNamespace, "apps", "v1", "DaemonSet")).NotTo(BeNil())
+		})
+	})
 })
 
 // verifyProbesAndLifecycle asserts the expected node liveness and readiness probe plus pod lifecycle settings.
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,11 +28,13 @@ import (
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/ptr"
 	"github.com/tigera/operator/pkg/render/common/authentication"
 	"github.com/tigera/operator/pkg/render/common/configmap"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
@@ -46,6 +49,7 @@ const (
 	PacketCaptureClusterRoleBindingName = PacketCaptureName
 	PacketCaptureDeploymentName         = PacketCaptureName
 	PacketCaptureServiceName            = PacketCaptureName
+	PacketCapturePort                   = 8444
 
 	PacketCaptureCertSecret        = "tigera-packetcapture-server-tls"
 	PacketCaptureTLSHashAnnotation = "hash.operator.tigera.io/packetcapture-certificate"
@@ -106,7 +110,7 @@ func (pc *packetCaptureApiComponent) SupportedOSType() rmeta.OSType {
 
 func (pc *packetCaptureApiComponent) Objects() ([]client.Object, []client.Object) {
 	objs := []client.Object{
-		CreateNamespace(PacketCaptureNamespace, pc.cfg.Installation.KubernetesProvider),
+		CreateNamespace(PacketCaptureNamespace, pc.cfg.Installation.KubernetesProvider, PSSRestricted),
 	}
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(PacketCaptureNamespace, pc.cfg.PullSecrets...)...)...)
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(PacketCaptureNamespace, pc.cfg.ServerCertSecret)...)...)
@@ -135,6 +139,21 @@ func (pc *packetCaptureApiComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for the packet capture API, which is only reached through the
+// manager and fetches the capture files through the Kubernetes API.
+func (pc *packetCaptureApiComponent) NetworkPolicies() []client.Object {
+	egress := networkpolicy.DNSEgressRules(pc.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(k8sapi.ServiceEndpoint{})...)
+	return []client.Object{networkpolicy.New(
+		PacketCaptureName,
+		PacketCaptureNamespace,
+		fmt.Sprintf("k8s-app == '%s'", PacketCaptureName),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(networkpolicy.Pods(ManagerNamespace, "all()"), networkpolicy.Ports(PacketCapturePort))},
+		egress,
+	)}
+}
+
 func (pc *packetCaptureApiComponent) service() *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
@@ -151,7 +170,7 @@ func (pc *packetCaptureApiComponent) service() *corev1.Service {
 					Name:       PacketCaptureName,
 					Port:       443,
 					Protocol:   corev1.ProtocolTCP,
-					TargetPort: intstr.FromInt(8444),
+					TargetPort: intstr.FromInt(PacketCapturePort),
 				},
 			},
 		},
@@ -310,7 +329,7 @@ func (pc *packetCaptureApiComponent) healthProbe() *corev1.Probe {
 		Handler: corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
 				Path:   "/health",
-				Port:   intstr.FromInt(8444),
+				Port:   intstr.FromInt(PacketCapturePort),
 				Scheme: corev1.URISchemeHTTPS,
 			},
 		},
@@ -28,6 +28,7 @@ import (
 	"github.com/tigera/operator/pkg/render"
 	"github.com/tigera/operator/pkg/render/common/authentication"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	rtest "github.com/tigera/operator/pkg/render/common/test"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -196,6 +197,12 @@ var _ = Describe("Rendering tests for PacketCapture API component", func() {
 				SecurityContext: &corev1.SecurityContext{
 					RunAsNonRoot:             ptr.BoolToPtr(true),
 					AllowPrivilegeEscalation: ptr.BoolToPtr(false),
+					Capabilities: &corev1.Capabilities{
+						Drop: []corev1.Capability{"ALL"},
+					},
+					SeccompProfile: &corev1.SeccompProfile{
+						Type: corev1.SeccompProfileTypeRuntimeDefault,
+					},
 				},
 				ReadinessProbe: &corev1.Probe{
 					Handler: corev1.Handler{
@@ -364,6 +371,17 @@ var _ = Describe("Rendering tests for PacketCapture API component", func() {
 		checkPacketCaptureResources(resources, true, false)
 	})
 
+	It("should render a CSR init container that is allowed in the restricted namespace", func() {
+		var resources = renderPacketCapture(operatorv1.InstallationSpec{CertificateManagement: &operatorv1.CertificateManagement{}}, nil)
+
+		ns := rtest.GetResource(resources, render.PacketCaptureNamespace, "", "", "v1", "Namespace").(*corev1.Namespace)
+		Expect(ns.Labels).To(HaveKeyWithValue(render.PSSEnforceLabel, string(render.PSSRestricted)))
+
+		deployment := rtest.GetResource(resources, render.PacketCaptureDeploymentName, render.PacketCaptureNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(deployment.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		Expect(deployment.Spec.Template.Spec.InitContainers[0].SecurityContext).To(Equal(podsecuritycontext.NewBaseContext()))
+	})
+
 	It("should render all resources for an installation with oidc configured", func() {
 		var authentication *operatorv1.Authentication
 		authentication = &operatorv1.Authentication{
@@ -95,6 +95,7 @@ func allCalicoComponents(
 		LogCollector:            logCollector,
 		BirdTemplates:           bt,
 		MigrateNamespaces:       up,
+		UsePSP:                  true,
 	}
 	typhaCfg := &render.TyphaConfiguration{
 		K8sServiceEp:           k8sServiceEp,
@@ -103,6 +104,7 @@ func allCalicoComponents(
 		ClusterDomain:          clusterDomain,
 		AmazonCloudIntegration: aci,
 		MigrateNamespaces:      up,
+		UsePSP:                 true,
 	}
 	kcCfg := &kubecontrollers.KubeControllersConfiguration{
 		K8sServiceEp:                k8sServiceEp,
@@ -112,6 +114,7 @@ func allCalicoComponents(
 		ManagerInternalSecret:       managerInternalTLSSecret,
 		ClusterDomain:               clusterDomain,
 		MetricsPort:                 kubeControllersMetricsPort,
+		UsePSP:                      true,
 	}
 	winCfg := &render.WindowsConfig{
 		Installation: cr,
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+)
+
+// Tunnel CA rotation related constants.
+const (
+	// VoltronTunnelNextSecretName holds the CA a rotation of the tunnel CA moves to, from the Publish phase until it
+	// replaces the current CA in the Retire phase.
+	VoltronTunnelNextSecretName = "tigera-management-cluster-connection-next"
+
+	// ManagementClusterCABundleConfigMapName is the ConfigMap the bundle of the tunnel CAs trusted by the
+	// management cluster is published in, for distribution to the managed clusters.
+	ManagementClusterCABundleConfigMapName = "tigera-management-cluster-ca-bundle"
+	ManagementClusterCABundleKey           = "management-cluster.crt"
+
+	// VoltronTunnelCABundleKey is the key of the tunnel secret mounted by voltron holding the bundle of the CAs the
+	// certificates of the managed clusters are verified with.
+	VoltronTunnelCABundleKey = "ca-bundle.crt"
+
+	// TunnelCAReissuedAnnotation is set on the next CA secret once the rotation has gone through the Reissue phase,
+	// which the Retire phase requires.
+	TunnelCAReissuedAnnotation = "operator.tigera.io/tunnel-ca-reissued"
+)
+
+// TunnelSigningCA returns the CA that signs the certificates of the managed clusters and that is presented to them:
+// the new CA once a rotation reaches the Reissue phase, the current one otherwise.
+func TunnelSigningCA(mc *operatorv1.ManagementCluster, current, next *corev1.Secret) *corev1.Secret {
+	if next != nil && mc.Spec.TunnelCARotation != nil && *mc.Spec.TunnelCARotation == operatorv1.TunnelCARotationReissue {
+		return next
+	}
+	return current
+}
+
+// tunnelPresentedCA returns the signing CA as a copy named after the current CA secret in the given namespace,
+// which is where the components mount it from. The copy also holds the bundle of the trusted CAs, so that the
+// managed clusters holding a certificate signed by either CA can connect from the Publish phase until the Retire
+// phase drops the current CA.
+func tunnelPresentedCA(mc *operatorv1.ManagementCluster, current, next *corev1.Secret, namespace string) *corev1.Secret {
+	x := TunnelSigningCA(mc, current, next).DeepCopy()
+	x.ObjectMeta = metav1.ObjectMeta{Name: VoltronTunnelSecretName, Namespace: namespace}
+	x.Data[VoltronTunnelCABundleKey] = tunnelCABundle(current, next)
+	return x
+}
+
+// tunnelCABundle returns the certificates of the CAs the management cluster trusts: the current CA and, while a
+// rotation is in progress, the new one.
+func tunnelCABundle(current, next *corev1.Secret) []byte {
+	certs := [][]byte{bytes.TrimSpace(current.Data[VoltronTunnelSecretCertName])}
+	if next != nil {
+		certs = append(certs, bytes.TrimSpace(next.Data[VoltronTunnelSecretCertName]))
+	}
+	return append(bytes.Join(certs, []byte("\n")), '\n')
+}
+
+// managementClusterCABundle returns the ConfigMap the bundle of trusted tunnel CAs is published in.
+func managementClusterCABundle(current, next *corev1.Secret) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ManagementClusterCABundleConfigMapName,
+			Namespace: common.OperatorNamespace(),
+		},
+		Data: map[string]string{
+			ManagementClusterCABundleKey: string(tunnelCABundle(current, next)),
+		},
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/tigera/operator/pkg/ptr"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -35,6 +36,8 @@ import (
 	"github.com/tigera/operator/pkg/controller/migration"
 	"github.com/tigera/operator/pkg/dns"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
+	"github.com/tigera/operator/pkg/render/common/poddisruptionbudget"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
@@ -65,6 +68,12 @@ type TyphaConfiguration struct {
 	AmazonCloudIntegration *operatorv1.AmazonCloudIntegration
 	MigrateNamespaces      bool
 	ClusterDomain          string
+
+	// UsePSP is true if the cluster serves the PodSecurityPolicy API and PSPs should be rendered.
+	UsePSP bool
+
+	// UsePolicyV1PDB is true if the cluster serves PodDisruptionBudgets from the policy/v1 API.
+	UsePolicyV1PDB bool
 }
 
 // Typha creates the typha daemonset and other resources for the daemonset to operate normally.
@@ -119,14 +128,20 @@ func (c *typhaComponent) Objects() ([]client.Object, []client.Object) {
 		c.typhaRole(),
 		c.typhaRoleBinding(),
 		c.typhaService(),
-		c.typhaPodDisruptionBudget(),
+	}
+
+	var objsToDelete []client.Object
+	if pdb, del := c.typhaPodDisruptionBudget(); pdb != nil {
+		objs = append(objs, pdb)
+	} else {
+		objsToDelete = append(objsToDelete, del)
 	}
 
 	if c.cfg.TLS.TyphaSecret != nil {
 		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(common.CalicoNamespace, c.cfg.TLS.TyphaSecret)...)...)
 	}
 
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift && c.cfg.UsePSP {
 		objs = append(objs, c.typhaPodSecurityPolicy())
 	}
 
@@ -137,32 +152,43 @@ func (c *typhaComponent) Objects() ([]client.Object, []client.Object) {
 	// Add deployment last, as it may depend on the creation of previous objects in the list.
 	objs = append(objs, c.typhaDeployment())
 
-	return objs, nil
+	return objs, objsToDelete
 }
 
-func (c *typhaComponent) typhaPodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
-	maxUnavailable := intstr.FromInt(1)
-	return &policyv1beta1.PodDisruptionBudget{
-		TypeMeta: metav1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1beta1"},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      common.TyphaDeploymentName,
-			Namespace: common.CalicoNamespace,
-		},
-		Spec: policyv1beta1.PodDisruptionBudgetSpec{
-			MaxUnavailable: &maxUnavailable,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					AppLabelName: TyphaK8sAppName,
-				},
-			},
+// typhaPodDisruptionBudget returns the PodDisruptionBudget for typha, or the budget to delete if it is disabled.
+// Unlike other control plane components, typha always has a budget since its replica count is scaled automatically.
+func (c *typhaComponent) typhaPodDisruptionBudget() (client.Object, client.Object) {
+	return poddisruptionbudget.New(c.cfg.Installation, common.TyphaDeploymentName, common.CalicoNamespace, &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			AppLabelName: TyphaK8sAppName,
 		},
-	}
+	}, c.cfg.UsePolicyV1PDB)
 }
 
 func (c *typhaComponent) Ready() bool {
 	return true
 }
 
+// NetworkPolicies returns the allow-tigera policy for typha, which serves the calico-node instances of the cluster and
+// its metrics and watches the datastore through the Kubernetes API.
+func (c *typhaComponent) NetworkPolicies() []client.Object {
+	ports := []uint16{uint16(TyphaPort)}
+	if c.cfg.Installation.TyphaMetricsPort != nil {
+		ports = append(ports, uint16(*c.cfg.Installation.TyphaMetricsPort))
+	}
+
+	egress := networkpolicy.DNSEgressRules(c.cfg.Installation.KubernetesProvider)
+	egress = append(egress, networkpolicy.KubeAPIEgressRules(c.cfg.K8sServiceEp)...)
+	return []client.Object{networkpolicy.New(
+		TyphaK8sAppName,
+		common.CalicoNamespace,
+		fmt.Sprintf("%s == '%s'", AppLabelName, TyphaK8sAppName),
+		[]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		[]v3.Rule{networkpolicy.AllowTCP(v3.EntityRule{}, networkpolicy.Ports(ports...))},
+		egress,
+	)}
+}
+
 // typhaServiceAccount creates the typha's service account.
 func (c *typhaComponent) typhaServiceAccount() *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
@@ -65,6 +65,7 @@ var _ = Describe("Typha rendering tests", func() {
 			TLS:           typhaNodeTLS,
 			Installation:  installation,
 			ClusterDomain: defaultClusterDomain,
+			UsePSP:        true,
 		}
 	})
 
@@ -316,6 +317,16 @@ var _ = Describe("Typha rendering tests", func() {
 		Expect(deploy.Spec.Template.Spec.InitContainers[0].Name).To(Equal(render.CSRInitContainerName))
 		rtest.ExpectEnv(deploy.Spec.Template.Spec.InitContainers[0].Env, "SIGNER", "a.b/c")
 	})
+	It("should delete the PodDisruptionBudget when PodDisruptionBudgets are disabled", func() {
+		installation.PodDisruptionBudgets = &operatorv1.PodDisruptionBudgets{Disabled: true}
+		component := render.Typha(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, toDelete := component.Objects()
+
+		Expect(rtest.GetResource(resources, "calico-typha", "calico-system", "policy", "v1beta1", "PodDisruptionBudget")).To(BeNil())
+		rtest.ExpectResourceInList(toDelete, "calico-typha", "calico-system", "policy", "v1beta1", "PodDisruptionBudget")
+	})
+
 	It("should not enable prometheus metrics if TyphaMetricsPort is nil", func() {
 		installation.Variant = operatorv1.TigeraSecureEnterprise
 		installation.TyphaMetricsPort = nil
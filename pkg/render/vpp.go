@@ -0,0 +1,359 @@
+// Copyright (c) 2022 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/ptr"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+const (
+	VPPNodeName         = "calico-vpp-node"
+	VPPConfigMapName    = "calico-vpp-config"
+	vppConfigAnnotation = "hash.operator.tigera.io/calico-vpp-config"
+	vppHugepageSize     = 2 * 1024 * 1024
+	vppHugepagesLimit   = corev1.ResourceName("hugepages-2Mi")
+)
+
+// VPPDrivers maps the drivers of the VPP uplink interface to the names that VPP knows them by.
+var VPPDrivers = map[operatorv1.VPPDriver]string{
+	operatorv1.VPPDriverAFPacket: "af_packet",
+	operatorv1.VPPDriverAFXDP:    "af_xdp",
+	operatorv1.VPPDriverAVF:      "avf",
+	operatorv1.VPPDriverDPDK:     "dpdk",
+	operatorv1.VPPDriverRDMA:     "rdma",
+	operatorv1.VPPDriverVirtio:   "virtio",
+	operatorv1.VPPDriverVMXNET3:  "vmxnet3",
+}
+
+// VPPConfiguration contains all the config information needed to render the VPP dataplane components.
+type VPPConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+	Terminating  bool
+}
+
+// VPP renders the calico-vpp-node DaemonSet, which runs VPP and the agent that programs it for calico-node on
+// each node. The objects are deleted when the Installation does not configure the VPP dataplane.
+func VPP(cfg *VPPConfiguration) Component {
+	return &vppComponent{cfg: cfg}
+}
+
+type vppComponent struct {
+	cfg        *VPPConfiguration
+	vppImage   string
+	agentImage string
+}
+
+func (c *vppComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// The VPP images are not required in the ImageSets of the clusters that don't run the VPP dataplane.
+	if !VPPEnabled(c.cfg.Installation) {
+		return nil
+	}
+	reg := c.cfg.Installation.Registry
+	path := c.cfg.Installation.ImagePath
+	prefix := c.cfg.Installation.ImagePrefix
+
+	var errMsgs []string
+	var err error
+	c.vppImage, err = components.GetReference(components.ComponentCalicoVPP, reg, path, prefix, is)
+	if err != nil {
+		errMsgs = append(errMsgs, err.Error())
+	}
+	c.agentImage, err = components.GetReference(components.ComponentCalicoVPPAgent, reg, path, prefix, is)
+	if err != nil {
+		errMsgs = append(errMsgs, err.Error())
+	}
+	if len(errMsgs) != 0 {
+		return fmt.Errorf(strings.Join(errMsgs, ","))
+	}
+	return nil
+}
+
+func (c *vppComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *vppComponent) Objects() ([]client.Object, []client.Object) {
+	if !VPPEnabled(c.cfg.Installation) || c.cfg.Terminating {
+		return nil, []client.Object{
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName, Namespace: common.CalicoNamespace}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: VPPConfigMapName, Namespace: common.CalicoNamespace}},
+			&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName}},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName, Namespace: common.CalicoNamespace}},
+		}
+	}
+	cm := c.configMap()
+	return []client.Object{
+		c.serviceAccount(),
+		c.roleBinding(),
+		cm,
+		c.daemonset(cm),
+	}, nil
+}
+
+func (c *vppComponent) Ready() bool {
+	return true
+}
+
+// VPPEnabled returns whether the operator manages the VPP dataplane components of the Installation.
+func VPPEnabled(instance *operatorv1.InstallationSpec) bool {
+	return instance.CalicoNetwork != nil &&
+		instance.CalicoNetwork.LinuxDataplane != nil &&
+		*instance.CalicoNetwork.LinuxDataplane == operatorv1.LinuxDataplaneVPP &&
+		instance.CalicoNetwork.VPP != nil
+}
+
+func (c *vppComponent) serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName, Namespace: common.CalicoNamespace},
+	}
+}
+
+// roleBinding grants the agent the permissions of calico-node, whose resources it watches to program VPP.
+func (c *vppComponent) roleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "calico-node",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      VPPNodeName,
+				Namespace: common.CalicoNamespace,
+			},
+		},
+	}
+}
+
+func (c *vppComponent) configMap() *corev1.ConfigMap {
+	vpp := c.cfg.Installation.CalicoNetwork.VPP
+	data := map[string]string{
+		"CALICOVPP_INTERFACE":       vpp.UplinkInterface,
+		"SERVICE_PREFIX":            strings.Join(vpp.ServiceCIDRs, ","),
+		"CALICOVPP_CONFIG_TEMPLATE": c.startupConfig(),
+		"CALICOVPP_CORE_PATTERN":    "/var/lib/vpp/vppcore.%e.%p",
+	}
+	if vpp.Driver != nil {
+		data["CALICOVPP_NATIVE_DRIVER"] = VPPDrivers[*vpp.Driver]
+	}
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPConfigMapName, Namespace: common.CalicoNamespace},
+		Data:       data,
+	}
+}
+
+// startupConfig returns the template of the VPP startup configuration, in which the VPP manager fills in the
+// settings of the uplink interface.
+func (c *vppComponent) startupConfig() string {
+	vpp := c.cfg.Installation.CalicoNetwork.VPP
+
+	cpu := "  workers 0"
+	if p := vpp.CPUPinning; p != nil {
+		cpu = fmt.Sprintf("  main-core %d", p.MainCore)
+		if p.WorkerCores != "" {
+			cpu += fmt.Sprintf("\n  corelist-workers %s", p.WorkerCores)
+		}
+	}
+
+	dpdkPlugin := "disable"
+	var dpdk string
+	if vpp.Driver != nil && *vpp.Driver == operatorv1.VPPDriverDPDK {
+		dpdkPlugin = "enable"
+		dpdk = `
+dpdk {
+  dev __PCI_DEVICE_ID__ { num-rx-queues 1 num-tx-queues 1 }
+}`
+	}
+
+	var buffers string
+	if !hugepagesEnabled(vpp) {
+		buffers = `
+buffers {
+  page-size 4K
+}`
+	}
+
+	return fmt.Sprintf(`unix {
+  nodaemon
+  full-coredump
+  cli-listen /var/run/vpp/cli.sock
+  pidfile /run/vpp/vpp.pid
+  exec /etc/vpp/startup.exec
+}
+api-trace { on }
+cpu {
+%s
+}
+socksvr {
+  socket-name /var/run/vpp/vpp-api.sock
+}
+plugins {
+  plugin default { enable }
+  plugin calico_plugin.so { enable }
+  plugin dpdk_plugin.so { %s }
+}%s%s
+`, cpu, dpdkPlugin, dpdk, buffers)
+}
+
+func hugepagesEnabled(vpp *operatorv1.VPPSpec) bool {
+	return vpp.Hugepages != nil && *vpp.Hugepages > 0
+}
+
+// daemonset renders the calico-vpp-node DaemonSet. The pods read their configuration from the environment,
+// so they are annotated with a hash of the config map to restart them when it changes.
+func (c *vppComponent) daemonset(cm *corev1.ConfigMap) *appsv1.DaemonSet {
+	var terminationGracePeriod int64 = 10
+	podLabels := map[string]string{"k8s-app": VPPNodeName}
+
+	ds := &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VPPNodeName,
+			Namespace: common.CalicoNamespace,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      podLabels,
+					Annotations: map[string]string{vppConfigAnnotation: rmeta.AnnotationHash(cm.Data)},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:                  map[string]string{"kubernetes.io/os": "linux"},
+					Tolerations:                   rmeta.TolerateAll,
+					ImagePullSecrets:              c.cfg.Installation.ImagePullSecrets,
+					ServiceAccountName:            VPPNodeName,
+					TerminationGracePeriodSeconds: &terminationGracePeriod,
+					HostNetwork:                   true,
+					// VPP moves the uplink interface out of the host network namespace and back on exit, which
+					// requires the host PID namespace.
+					HostPID:    true,
+					Containers: []corev1.Container{c.vppContainer(), c.agentContainer()},
+					Volumes:    c.volumes(),
+				},
+			},
+			UpdateStrategy: c.cfg.Installation.NodeUpdateStrategy,
+		},
+	}
+	setNodeCriticalPod(&ds.Spec.Template)
+	return ds
+}
+
+func (c *vppComponent) vppContainer() corev1.Container {
+	vpp := c.cfg.Installation.CalicoNetwork.VPP
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+	if hugepagesEnabled(vpp) {
+		hugepages := *resource.NewQuantity(int64(*vpp.Hugepages)*vppHugepageSize, resource.BinarySI)
+		resources.Limits = corev1.ResourceList{vppHugepagesLimit: hugepages}
+		resources.Requests[vppHugepagesLimit] = hugepages
+	}
+
+	bidirectional := corev1.MountPropagationBidirectional
+	return corev1.Container{
+		Name:            "vpp",
+		Image:           c.vppImage,
+		SecurityContext: &corev1.SecurityContext{Privileged: ptr.BoolToPtr(true)},
+		EnvFrom:         c.envFrom(),
+		Env: []corev1.EnvVar{
+			{Name: "DATASTORE_TYPE", Value: "kubernetes"},
+			{Name: "NODENAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+		},
+		Resources: resources,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "lib-firmware", MountPath: "/lib/firmware"},
+			{Name: "vpp-rundir", MountPath: "/var/run/vpp"},
+			{Name: "vpp-data", MountPath: "/var/lib/vpp"},
+			{Name: "devices", MountPath: "/dev"},
+			{Name: "hostsys", MountPath: "/sys"},
+			{Name: "netns", MountPath: "/run/netns", MountPropagation: &bidirectional},
+			{Name: "host-root", MountPath: "/host"},
+		},
+	}
+}
+
+func (c *vppComponent) agentContainer() corev1.Container {
+	hostToContainer := corev1.MountPropagationHostToContainer
+	return corev1.Container{
+		Name:            "agent",
+		Image:           c.agentImage,
+		SecurityContext: &corev1.SecurityContext{Privileged: ptr.BoolToPtr(true)},
+		EnvFrom:         c.envFrom(),
+		Env: []corev1.EnvVar{
+			{Name: "DATASTORE_TYPE", Value: "kubernetes"},
+			{Name: "WAIT_FOR_DATASTORE", Value: "true"},
+			{Name: "NODENAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "var-run-calico", MountPath: "/var/run/calico"},
+			{Name: "felix-plugins", MountPath: "/var/lib/calico/felix-plugins"},
+			{Name: "vpp-rundir", MountPath: "/var/run/vpp"},
+			{Name: "netns", MountPath: "/run/netns", MountPropagation: &hostToContainer},
+		},
+	}
+}
+
+func (c *vppComponent) envFrom() []corev1.EnvFromSource {
+	return []corev1.EnvFromSource{{
+		ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: VPPConfigMapName}},
+	}}
+}
+
+func (c *vppComponent) volumes() []corev1.Volume {
+	dirOrCreate := corev1.HostPathDirectoryOrCreate
+	hostPath := func(name, path string, t *corev1.HostPathType) corev1.Volume {
+		return corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: path, Type: t}},
+		}
+	}
+	return []corev1.Volume{
+		hostPath("lib-firmware", "/lib/firmware", nil),
+		hostPath("vpp-rundir", "/var/run/vpp", &dirOrCreate),
+		hostPath("vpp-data", "/var/lib/vpp", &dirOrCreate),
+		hostPath("devices", "/dev", nil),
+		hostPath("hostsys", "/sys", nil),
+		hostPath("netns", "/run/netns", &dirOrCreate),
+		hostPath("host-root", "/", nil),
+		hostPath("var-run-calico", "/var/run/calico", nil),
+		hostPath("felix-plugins", "/var/lib/calico/felix-plugins", &dirOrCreate),
+	}
+}
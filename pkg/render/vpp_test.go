@@ -0,0 +1,143 @@
+// Copyright (c) 2022 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("VPP rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+
+	BeforeEach(func() {
+		vpp := operatorv1.LinuxDataplaneVPP
+		driver := operatorv1.VPPDriverAFXDP
+		var hugepages int32 = 512
+		installation = &operatorv1.InstallationSpec{
+			Registry: "test-reg/",
+			CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+				LinuxDataplane: &vpp,
+				VPP: &operatorv1.VPPSpec{
+					UplinkInterface: "eth1",
+					Driver:          &driver,
+					Hugepages:       &hugepages,
+					CPUPinning:      &operatorv1.VPPCPUPinning{MainCore: 1, WorkerCores: "2-3"},
+					ServiceCIDRs:    []string{"10.96.0.0/12", "fd10::/112"},
+				},
+			},
+		}
+	})
+
+	It("should render the calico-vpp-node DaemonSet", func() {
+		component := render.VPP(&render.VPPConfiguration{Installation: installation})
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		resources, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+
+		expectedResources := []struct {
+			name    string
+			ns      string
+			group   string
+			version string
+			kind    string
+		}{
+			{name: render.VPPNodeName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "ServiceAccount"},
+			{name: render.VPPNodeName, ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
+			{name: render.VPPConfigMapName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "ConfigMap"},
+			{name: render.VPPNodeName, ns: common.CalicoNamespace, group: "apps", version: "v1", kind: "DaemonSet"},
+		}
+		Expect(resources).To(HaveLen(len(expectedResources)))
+		for i, expectedRes := range expectedResources {
+			rtest.ExpectResource(resources[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
+		}
+
+		cm := rtest.GetResource(resources, render.VPPConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(HaveKeyWithValue("CALICOVPP_INTERFACE", "eth1"))
+		Expect(cm.Data).To(HaveKeyWithValue("CALICOVPP_NATIVE_DRIVER", "af_xdp"))
+		Expect(cm.Data).To(HaveKeyWithValue("SERVICE_PREFIX", "10.96.0.0/12,fd10::/112"))
+		Expect(cm.Data["CALICOVPP_CONFIG_TEMPLATE"]).To(ContainSubstring("cpu {\n  main-core 1\n  corelist-workers 2-3\n}"))
+		Expect(cm.Data["CALICOVPP_CONFIG_TEMPLATE"]).To(ContainSubstring("plugin dpdk_plugin.so { disable }"))
+		Expect(cm.Data["CALICOVPP_CONFIG_TEMPLATE"]).NotTo(ContainSubstring("page-size 4K"))
+
+		ds := rtest.GetResource(resources, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.HostNetwork).To(BeTrue())
+		Expect(ds.Spec.Template.Spec.ServiceAccountName).To(Equal(render.VPPNodeName))
+		containers := ds.Spec.Template.Spec.Containers
+		Expect(containers).To(HaveLen(2))
+		Expect(containers[0].Image).To(Equal(fmt.Sprintf("test-reg/%s:%s", components.ComponentCalicoVPP.Image, components.ComponentCalicoVPP.Version)))
+		hugepages := containers[0].Resources.Limits[corev1.ResourceName("hugepages-2Mi")]
+		Expect(hugepages.String()).To(Equal("1Gi"))
+		Expect(containers[1].Image).To(Equal(fmt.Sprintf("test-reg/%s:%s", components.ComponentCalicoVPPAgent.Image, components.ComponentCalicoVPPAgent.Version)))
+		Expect(containers[1].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "felix-plugins", MountPath: "/var/lib/calico/felix-plugins"}))
+	})
+
+	It("should run VPP without hugepages and pinning", func() {
+		var zero int32
+		installation.CalicoNetwork.VPP.Hugepages = &zero
+		installation.CalicoNetwork.VPP.CPUPinning = nil
+		installation.CalicoNetwork.VPP.Driver = nil
+
+		component := render.VPP(&render.VPPConfiguration{Installation: installation})
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		resources, _ := component.Objects()
+
+		cm := rtest.GetResource(resources, render.VPPConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).NotTo(HaveKey("CALICOVPP_NATIVE_DRIVER"))
+		Expect(cm.Data["CALICOVPP_CONFIG_TEMPLATE"]).To(ContainSubstring("cpu {\n  workers 0\n}"))
+		Expect(cm.Data["CALICOVPP_CONFIG_TEMPLATE"]).To(ContainSubstring("page-size 4K"))
+
+		ds := rtest.GetResource(resources, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.Containers[0].Resources.Limits).To(BeEmpty())
+	})
+
+	It("should restart the VPP pods when their config changes", func() {
+		getAnnotations := func() map[string]string {
+			component := render.VPP(&render.VPPConfiguration{Installation: installation})
+			Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+			resources, _ := component.Objects()
+			ds := rtest.GetResource(resources, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+			return ds.Spec.Template.Annotations
+		}
+
+		before := getAnnotations()
+		Expect(before).To(HaveKey("hash.operator.tigera.io/calico-vpp-config"))
+		Expect(getAnnotations()).To(Equal(before))
+
+		installation.CalicoNetwork.VPP.UplinkInterface = "eth2"
+		Expect(getAnnotations()["hash.operator.tigera.io/calico-vpp-config"]).NotTo(Equal(before["hash.operator.tigera.io/calico-vpp-config"]))
+	})
+
+	It("should delete the VPP dataplane components without the VPP settings", func() {
+		installation.CalicoNetwork.VPP = nil
+
+		component := render.VPP(&render.VPPConfiguration{Installation: installation})
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		resources, toDelete := component.Objects()
+		Expect(resources).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(4))
+	})
+})
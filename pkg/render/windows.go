@@ -110,12 +110,14 @@ func (c *windowsComponent) windowsUpgradeDaemonset() *appsv1.DaemonSet {
 						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
 							// The Calico for Windows upgrade daemonset only
 							// runs:
-							// - on nodes with the upgrade script label
+							// - on nodes with the upgrade in-progress label. Nodes
+							//   being drained or whose upgrade failed are excluded.
 							// - on Windows nodes
 							MatchExpressions: []corev1.NodeSelectorRequirement{
 								{
 									Key:      common.CalicoWindowsUpgradeLabel,
-									Operator: corev1.NodeSelectorOpExists,
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{common.CalicoWindowsUpgradeLabelInProgress},
 								},
 								{
 									Key:      corev1.LabelOSStable,
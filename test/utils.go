@@ -192,12 +192,24 @@ func CreateNode(c kubernetes.Interface, name string, labels map[string]string, a
 }
 
 func CreateWindowsNode(cs kubernetes.Interface, name string, variant operator.ProductVariant, version string) *v1.Node {
-	return CreateNode(cs, name,
-		map[string]string{"kubernetes.io/os": "windows"},
-		map[string]string{
-			common.CalicoVersionAnnotation: version,
-			common.CalicoVariantAnnotation: string(variant),
-		})
+	node := &v1.Node{
+		TypeMeta: metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"kubernetes.io/os": "windows"},
+			Annotations: map[string]string{
+				common.CalicoVersionAnnotation: version,
+				common.CalicoVariantAnnotation: string(variant),
+			},
+		},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+
+	node, err := cs.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+	Expect(err).To(BeNil())
+	return node
 }
 
 func AssertNodesUnchanged(c kubernetes.Interface, nodes ...*v1.Node) error {